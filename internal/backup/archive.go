@@ -0,0 +1,415 @@
+// Package backup writes the whole hourgit data set — config, entries, watcher
+// state, journal and repo markers — to a single verified archive and
+// restores it, possibly on another machine.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/Flyrell/hourgit/internal/watch"
+)
+
+// FormatVersion is the version of the archive layout written by Create.
+const FormatVersion = 1
+
+// Paths inside the archive.
+const (
+	manifestName = "manifest.json"
+	configName   = "config.json"
+	stateName    = "watch.state"
+	journalName  = "journal.jsonl"
+	entriesDir   = "entries/" // entries/<slug>.jsonl, one entryLine per entry
+	reposDir     = "repos/"   // repos/<n>.json, the marker of the n-th repo
+)
+
+// sealedFormat identifies a sealed archive.
+const sealedFormat = "hourgit-sealed-backup"
+
+// archiveSealContext binds a sealed archive to being a backup.
+const archiveSealContext = "backup"
+
+// sealedArchive is what Create writes when hourgit data is encrypted: the
+// gzipped tar sealed with the data's key, next to the encryption header the
+// key is derived from, so that it can be opened on another machine.
+type sealedArchive struct {
+	Format     string       `json:"format"`
+	Encryption *seal.Header `json:"encryption"`
+	Archive    string       `json:"archive"`
+}
+
+// Manifest describes an archive's contents. It is stored as manifest.json.
+type Manifest struct {
+	FormatVersion  int           `json:"format_version"`
+	HourgitVersion string        `json:"hourgit_version"`
+	SchemaVersion  int           `json:"schema_version"`
+	CreatedAt      time.Time     `json:"created_at"`
+	Projects       []ProjectInfo `json:"projects"`
+	Repos          []RepoInfo    `json:"repos"`
+	Files          []FileInfo    `json:"files"`
+}
+
+// ProjectInfo summarises one project in the archive.
+type ProjectInfo struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+	Entries int    `json:"entries"`
+}
+
+// RepoInfo records an assigned repo and where its marker is in the archive.
+type RepoInfo struct {
+	Path      string `json:"path"`
+	ProjectID string `json:"project_id"`
+	Marker    string `json:"marker,omitempty"`
+}
+
+// FileInfo is the size and checksum of one file in the archive.
+type FileInfo struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// entryLine is one entry in an entries/<slug>.jsonl file.
+type entryLine struct {
+	ID   string          `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Archive is a verified archive held in memory.
+type Archive struct {
+	Manifest Manifest
+	files    map[string][]byte
+}
+
+// Create writes an archive of homeDir's data to w. version is the running
+// hourgit version, recorded in the manifest. When homeDir's data is
+// encrypted, the archive is sealed with the same key unless plaintext is set.
+// Unreadable entries are left out (run 'hourgit fsck' to find them).
+func Create(homeDir string, w io.Writer, version string, plaintext bool) (*Manifest, error) {
+	configData, err := os.ReadFile(project.ConfigPath(homeDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no hourgit data to back up")
+	}
+	if err != nil {
+		return nil, err
+	}
+	sealer, err := seal.Load(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	if configData, err = sealer.Open(configData, project.ConfigSealContext); err != nil {
+		return nil, err
+	}
+	cfg, err := project.ReadConfig(homeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Files hold plain data, so they can be restored under any key
+	files := map[string][]byte{configName: configData}
+	for name, path := range map[string]string{
+		stateName:   watch.StatePath(homeDir),
+		journalName: journal.Path(homeDir),
+	} {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if name == journalName {
+			data, err = sealer.OpenLines(data, journal.SealContext)
+		} else {
+			data, err = sealer.Open(data, watch.StateSealContext)
+		}
+		if err != nil {
+			return nil, err
+		}
+		files[name] = data
+	}
+
+	m := &Manifest{
+		FormatVersion:  FormatVersion,
+		HourgitVersion: version,
+		SchemaVersion:  cfg.SchemaVersion,
+		CreatedAt:      time.Now().UTC(),
+	}
+
+	slugs, err := entry.ProjectSlugs(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, slug := range slugs {
+		records, err := entry.QueryRecords(homeDir, slug, entry.Query{})
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			continue
+		}
+		var buf bytes.Buffer
+		for _, r := range records {
+			line, err := json.Marshal(entryLine{ID: r.File, Data: r.Data})
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		files[entriesDir+slug+".jsonl"] = buf.Bytes()
+		counts[slug] = len(records)
+	}
+
+	for _, p := range cfg.Projects {
+		m.Projects = append(m.Projects, ProjectInfo{ID: p.ID, Name: p.Name, Slug: p.Slug, Entries: counts[p.Slug]})
+		for _, repo := range p.Repos {
+			info := RepoInfo{Path: repo, ProjectID: p.ID}
+			if data, err := os.ReadFile(project.RepoConfigPath(repo)); err == nil {
+				info.Marker = fmt.Sprintf("%s%d.json", reposDir, len(m.Repos))
+				files[info.Marker] = data
+			}
+			m.Repos = append(m.Repos, info)
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		m.Files = append(m.Files, FileInfo{Path: name, Size: int64(len(files[name])), SHA256: checksum(files[name])})
+	}
+
+	manifestData, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := !plaintext && seal.Enabled(homeDir)
+	var buf bytes.Buffer
+	out := w
+	if sealed {
+		out = &buf
+	}
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	if err := writeTarFile(tw, manifestName, manifestData, m.CreatedAt); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if err := writeTarFile(tw, name, files[name], m.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	if sealed {
+		if err := writeSealed(homeDir, w, buf.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// writeSealed writes archive to w sealed with homeDir's key.
+func writeSealed(homeDir string, w io.Writer, archive []byte) error {
+	sealer, err := seal.Load(homeDir)
+	if err != nil {
+		return err
+	}
+	sealed, err := sealer.Seal(archive, archiveSealContext)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(sealedArchive{Format: sealedFormat, Encryption: sealer.Header(), Archive: string(sealed)})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// unseal returns the gzipped tar inside a sealed archive, and data itself
+// when it is not sealed. The key is derived from the archive's own header.
+func unseal(homeDir string, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte("{")) {
+		return data, nil
+	}
+	var s sealedArchive
+	if err := json.Unmarshal(data, &s); err != nil || s.Format != sealedFormat || s.Encryption == nil {
+		return nil, fmt.Errorf("not a hourgit backup")
+	}
+	archive, err := seal.OpenFor(homeDir, s.Encryption, []byte(s.Archive), archiveSealContext)
+	if err != nil {
+		return nil, fmt.Errorf("opening encrypted backup: %w", err)
+	}
+	return archive, nil
+}
+
+// Open reads an archive and verifies it against its manifest: every listed
+// file must be present with the recorded size and checksum, and nothing else
+// may be in the archive. A sealed archive is opened first, with a keyfile
+// looked for where homeDir's would be.
+func Open(homeDir string, r io.Reader) (*Archive, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading backup: %w", err)
+	}
+	if data, err = unseal(homeDir, data); err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("not a hourgit backup: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading backup: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading backup: %w", err)
+		}
+		files[hdr.Name] = data
+	}
+
+	manifestData, ok := files[manifestName]
+	if !ok {
+		return nil, fmt.Errorf("not a hourgit backup: %s is missing", manifestName)
+	}
+	delete(files, manifestName)
+
+	a := &Archive{files: files}
+	if err := json.Unmarshal(manifestData, &a.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if a.Manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("backup format %d is newer than this version of hourgit supports (%d) — please update hourgit", a.Manifest.FormatVersion, FormatVersion)
+	}
+	if a.Manifest.SchemaVersion > project.SchemaVersion {
+		return nil, fmt.Errorf("backup uses schema version %d, but this version of hourgit only supports up to %d — please update hourgit", a.Manifest.SchemaVersion, project.SchemaVersion)
+	}
+
+	listed := make(map[string]bool, len(a.Manifest.Files))
+	for _, f := range a.Manifest.Files {
+		listed[f.Path] = true
+		data, ok := files[f.Path]
+		if !ok {
+			return nil, fmt.Errorf("backup is incomplete: %s is missing", f.Path)
+		}
+		if int64(len(data)) != f.Size || checksum(data) != f.SHA256 {
+			return nil, fmt.Errorf("backup is corrupt: checksum mismatch for %s", f.Path)
+		}
+	}
+	for name := range files {
+		if !listed[name] {
+			return nil, fmt.Errorf("backup is corrupt: unexpected file %s", name)
+		}
+	}
+	if _, ok := files[configName]; !ok {
+		return nil, fmt.Errorf("backup is incomplete: %s is missing", configName)
+	}
+	return a, nil
+}
+
+// OpenFile opens and verifies the archive at path, like Open.
+func OpenFile(homeDir, path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return Open(homeDir, f)
+}
+
+// Config returns the config stored in the archive.
+func (a *Archive) Config() (*project.Config, error) {
+	var cfg project.Config
+	if err := json.Unmarshal(a.files[configName], &cfg); err != nil {
+		return nil, fmt.Errorf("invalid config in backup: %w", err)
+	}
+	return &cfg, nil
+}
+
+// entries returns the entries stored for each project slug.
+func (a *Archive) entries() (map[string][]entryLine, error) {
+	bySlug := make(map[string][]entryLine)
+	for name, data := range a.files {
+		if !strings.HasPrefix(name, entriesDir) || !strings.HasSuffix(name, ".jsonl") {
+			continue
+		}
+		slug := strings.TrimSuffix(strings.TrimPrefix(name, entriesDir), ".jsonl")
+		for _, raw := range bytes.Split(data, []byte("\n")) {
+			if len(bytes.TrimSpace(raw)) == 0 {
+				continue
+			}
+			var l entryLine
+			if err := json.Unmarshal(raw, &l); err != nil {
+				return nil, fmt.Errorf("invalid entry in %s: %w", name, err)
+			}
+			if !entry.IsValidID(l.ID) {
+				return nil, fmt.Errorf("backup is corrupt: invalid entry ID %q in %s", l.ID, name)
+			}
+			bySlug[slug] = append(bySlug[slug], l)
+		}
+	}
+	return bySlug, nil
+}
+
+// checksum returns the hex SHA-256 of data.
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeTarFile adds a regular file to the archive.
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
@@ -0,0 +1,211 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/Flyrell/hourgit/internal/watch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupData creates a project assigned to a repo, with two entries and
+// watcher state for the repo.
+func setupData(t *testing.T) (home, repo string, proj *project.ProjectEntry) {
+	t.Helper()
+	home = t.TempDir()
+	repo = t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".git"), 0755))
+
+	proj, err := project.CreateProject(home, "Acme")
+	require.NoError(t, err)
+	require.NoError(t, project.AssignProject(home, repo, proj))
+
+	start := time.Date(2025, 6, 16, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, entry.WriteEntry(home, proj.Slug, entry.Entry{ID: "aaaa11112222", Start: start, Minutes: 60, Message: "first"}))
+	require.NoError(t, entry.WriteEntry(home, proj.Slug, entry.Entry{ID: "bbbb11112222", Start: start.Add(2 * time.Hour), Minutes: 30, Message: "second"}))
+
+	state := watch.NewWatchState()
+	state.SetLastActivity(repo, start)
+	require.NoError(t, state.Flush(home))
+	return home, repo, proj
+}
+
+func createArchive(t *testing.T, home string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	_, err := Create(home, &buf, "1.2.3", false)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+// rewriteArchive copies an archive, passing every file through fn. A nil
+// result drops the file.
+func rewriteArchive(t *testing.T, data []byte, fn func(name string, content []byte) []byte) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	var out bytes.Buffer
+	gw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		if content = fn(hdr.Name, content); content != nil {
+			require.NoError(t, writeTarFile(tw, hdr.Name, content, hdr.ModTime))
+		}
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return out.Bytes()
+}
+
+func TestCreateWritesManifest(t *testing.T) {
+	home, repo, proj := setupData(t)
+
+	var buf bytes.Buffer
+	m, err := Create(home, &buf, "1.2.3", false)
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatVersion, m.FormatVersion)
+	assert.Equal(t, "1.2.3", m.HourgitVersion)
+	assert.Equal(t, project.SchemaVersion, m.SchemaVersion)
+	require.Len(t, m.Projects, 1)
+	assert.Equal(t, ProjectInfo{ID: proj.ID, Name: "Acme", Slug: proj.Slug, Entries: 2}, m.Projects[0])
+	require.Len(t, m.Repos, 1)
+	assert.Equal(t, repo, m.Repos[0].Path)
+	assert.Equal(t, proj.ID, m.Repos[0].ProjectID)
+	assert.NotEmpty(t, m.Repos[0].Marker)
+
+	var names []string
+	for _, f := range m.Files {
+		names = append(names, f.Path)
+		assert.Len(t, f.SHA256, 64)
+	}
+	assert.Contains(t, names, configName)
+	assert.Contains(t, names, stateName)
+	assert.Contains(t, names, entriesDir+proj.Slug+".jsonl")
+}
+
+func TestCreateWithoutData(t *testing.T) {
+	_, err := Create(t.TempDir(), io.Discard, "1.2.3", false)
+	assert.EqualError(t, err, "no hourgit data to back up")
+}
+
+func TestOpenRoundTrip(t *testing.T) {
+	home, _, proj := setupData(t)
+
+	a, err := Open(home, bytes.NewReader(createArchive(t, home)))
+	require.NoError(t, err)
+
+	cfg, err := a.Config()
+	require.NoError(t, err)
+	require.Len(t, cfg.Projects, 1)
+	assert.Equal(t, proj.ID, cfg.Projects[0].ID)
+
+	entries, err := a.entries()
+	require.NoError(t, err)
+	assert.Len(t, entries[proj.Slug], 2)
+}
+
+func TestCreateSealsWhenEncrypted(t *testing.T) {
+	home, _, proj := setupData(t)
+	_, err := seal.Setup(home, seal.KeyPassphrase, "secret")
+	require.NoError(t, err)
+
+	data := createArchive(t, home)
+	assert.NotContains(t, string(data), "Acme")
+	assert.True(t, bytes.HasPrefix(data, []byte("{")), "a sealed archive is not gzip")
+
+	// Opened with the archive's own header, as on a machine without hourgit data
+	a, err := Open(t.TempDir(), bytes.NewReader(data))
+	require.NoError(t, err)
+	entries, err := a.entries()
+	require.NoError(t, err)
+	assert.Len(t, entries[proj.Slug], 2)
+
+	tampered := bytes.Replace(data, []byte(`"archive":"hgseal2:`), []byte(`"archive":"hgseal2:AAAA`), 1)
+	_, err = Open(home, bytes.NewReader(tampered))
+	assert.ErrorIs(t, err, seal.ErrCorrupt)
+
+	var buf bytes.Buffer
+	_, err = Create(home, &buf, "1.2.3", true)
+	require.NoError(t, err)
+	_, err = gzip.NewReader(&buf)
+	assert.NoError(t, err, "a plaintext archive is gzip")
+}
+
+func TestOpenRejectsTamperedArchive(t *testing.T) {
+	home, _, _ := setupData(t)
+	data := createArchive(t, home)
+
+	tampered := rewriteArchive(t, data, func(name string, content []byte) []byte {
+		if name == configName {
+			return bytes.Replace(content, []byte("Acme"), []byte("Acmf"), 1)
+		}
+		return content
+	})
+	_, err := Open(home, bytes.NewReader(tampered))
+	assert.EqualError(t, err, "backup is corrupt: checksum mismatch for config.json")
+
+	truncated := rewriteArchive(t, data, func(name string, content []byte) []byte {
+		if name == stateName {
+			return nil
+		}
+		return content
+	})
+	_, err = Open(home, bytes.NewReader(truncated))
+	assert.EqualError(t, err, "backup is incomplete: watch.state is missing")
+
+	noManifest := rewriteArchive(t, data, func(name string, content []byte) []byte {
+		if name == manifestName {
+			return nil
+		}
+		return content
+	})
+	_, err = Open(home, bytes.NewReader(noManifest))
+	assert.EqualError(t, err, "not a hourgit backup: manifest.json is missing")
+
+	_, err = Open(home, bytes.NewReader([]byte("not gzip")))
+	assert.ErrorContains(t, err, "not a hourgit backup")
+}
+
+func TestEntriesRejectsInvalidIDs(t *testing.T) {
+	a := &Archive{files: map[string][]byte{
+		entriesDir + "acme.jsonl": []byte(`{"id":"aaaa11112222","data":{}}` + "\n" + `{"id":"../../../../.bashrc","data":{}}` + "\n"),
+	}}
+
+	_, err := a.entries()
+
+	assert.EqualError(t, err, `backup is corrupt: invalid entry ID "../../../../.bashrc" in entries/acme.jsonl`)
+}
+
+func TestOpenRejectsNewerSchema(t *testing.T) {
+	home, _, _ := setupData(t)
+
+	newer := rewriteArchive(t, createArchive(t, home), func(name string, content []byte) []byte {
+		if name == manifestName {
+			return bytes.Replace(content, []byte(fmt.Sprintf(`"schema_version": %d`, project.SchemaVersion)), []byte(`"schema_version": 99`), 1)
+		}
+		return content
+	})
+	_, err := Open(home, bytes.NewReader(newer))
+	assert.ErrorContains(t, err, "please update hourgit")
+}
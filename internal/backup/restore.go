@@ -0,0 +1,375 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/gitdir"
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/schema"
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/Flyrell/hourgit/internal/watch"
+)
+
+// PathMapping rewrites repo paths starting with From to start with To.
+type PathMapping struct {
+	From string
+	To   string
+}
+
+// ParsePathMappings parses comma-separated OLD=NEW pairs.
+func ParsePathMappings(s string) ([]PathMapping, error) {
+	var mappings []PathMapping
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(pair, "=")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid repo mapping %q: expected OLD=NEW", pair)
+		}
+		mappings = append(mappings, PathMapping{From: strings.TrimRight(from, `/\`), To: strings.TrimRight(to, `/\`)})
+	}
+	return mappings, nil
+}
+
+// remapPath applies the first mapping whose From is path or a parent of it.
+func remapPath(path string, mappings []PathMapping) string {
+	for _, m := range mappings {
+		if path == m.From {
+			return m.To
+		}
+		if rest, ok := strings.CutPrefix(path, m.From); ok && (rest[0] == '/' || rest[0] == '\\') {
+			return m.To + rest
+		}
+	}
+	return path
+}
+
+// Options controls a restore.
+type Options struct {
+	// Replace discards the current data (after backing it up) instead of
+	// merging the archive into it.
+	Replace bool
+	// RepoMap rewrites repo paths recorded in the archive.
+	RepoMap []PathMapping
+	// InstallHook installs the hourgit hook in a restored repo. Optional.
+	InstallHook func(repoDir string) error
+}
+
+// Repo statuses reported after a restore.
+const (
+	RepoRestored = "restored" // marker written and hook installed
+	RepoMissing  = "missing"  // the path is not a git repository on this machine
+	RepoFailed   = "failed"   // marker or hook could not be written
+)
+
+// RepoResult is the outcome of restoring one repo.
+type RepoResult struct {
+	Path   string
+	Status string
+	Err    error
+}
+
+// Result summarises a restore.
+type Result struct {
+	Backup          string // where the replaced data was backed up, if any
+	ProjectsAdded   int
+	ProjectsMerged  int
+	EntriesRestored int
+	EntriesSkipped  int      // already present with the same content
+	Conflicts       []string // "slug/id" present locally with different content; local kept
+	Repos           []RepoResult
+}
+
+// Restore writes an archive's data into homeDir, merging it with the current
+// data or replacing it, then restores repo markers and hooks.
+func Restore(homeDir string, a *Archive, opts Options) (*Result, error) {
+	archived, err := a.Config()
+	if err != nil {
+		return nil, err
+	}
+	bySlug, err := a.entries()
+	if err != nil {
+		return nil, err
+	}
+	for slug := range bySlug {
+		if !validSlug(slug) {
+			return nil, fmt.Errorf("backup is corrupt: invalid project directory %q", slug)
+		}
+	}
+	for i := range archived.Projects {
+		p := &archived.Projects[i]
+		if !validSlug(p.Slug) {
+			return nil, fmt.Errorf("backup is corrupt: invalid project directory %q", p.Slug)
+		}
+		for j, repo := range p.Repos {
+			p.Repos[j] = remapPath(repo, opts.RepoMap)
+		}
+	}
+
+	result := &Result{}
+	if opts.Replace {
+		err = restoreReplace(homeDir, a, archived, bySlug, opts, result)
+	} else {
+		err = restoreMerge(homeDir, a, archived, bySlug, opts, result)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	restoreRepos(a, archived, opts, result)
+	return result, nil
+}
+
+// validSlug reports whether a project directory name from an archive is safe
+// to join to the data directory.
+func validSlug(slug string) bool {
+	return slug != "" && !strings.HasPrefix(slug, ".") && !strings.ContainsAny(slug, `/\`) && slug != ".."
+}
+
+// restoreReplace backs up and clears the data directory, then writes the
+// archive's config, entries, watcher state and journal as they are.
+func restoreReplace(homeDir string, a *Archive, archived *project.Config, bySlug map[string][]entryLine, opts Options, result *Result) error {
+	if _, err := os.Stat(project.ConfigPath(homeDir)); err == nil {
+		current, err := project.ReadConfig(homeDir)
+		if err != nil {
+			return err
+		}
+		result.Backup, err = schema.Backup(homeDir, current.SchemaVersion)
+		if err != nil {
+			return fmt.Errorf("backing up current data: %w", err)
+		}
+	}
+
+	root := project.DataDir(homeDir)
+	items, err := os.ReadDir(root)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, item := range items {
+		if keepOnReplace(homeDir, item.Name()) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(root, item.Name())); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+	if err := project.WriteConfig(homeDir, archived); err != nil {
+		return err
+	}
+	result.ProjectsAdded = len(archived.Projects)
+
+	store, err := entry.OpenStore(homeDir)
+	if err != nil {
+		return err
+	}
+	for slug, lines := range bySlug {
+		for _, l := range lines {
+			if err := store.Write(slug, l.ID, l.Data); err != nil {
+				return err
+			}
+			result.EntriesRestored++
+		}
+	}
+
+	if data, ok := a.files[stateName]; ok {
+		if err := restoreWatchState(homeDir, data, opts.RepoMap, false); err != nil {
+			return err
+		}
+	} else if err := os.Remove(watch.StatePath(homeDir)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if data, ok := a.files[journalName]; ok {
+		sealer, err := seal.Load(homeDir)
+		if err != nil {
+			return err
+		}
+		data, err := sealer.SealLines(data, journal.SealContext)
+		if err != nil {
+			return err
+		}
+		if err := fsutil.WriteFileAtomic(journal.Path(homeDir), data, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keepOnReplace reports whether an item of the data directory survives a
+// replacing restore: backups, locks, the encryption header, the repository
+// used by 'hourgit remote', so the restored data can be pushed, and what other
+// directories keep there when they are the data directory, as under
+// HOURGIT_HOME: the keyfile and the watcher's PID file.
+func keepOnReplace(homeDir, name string) bool {
+	switch name {
+	case filepath.Base(schema.BackupDir(homeDir)), ".git", ".gitignore", seal.HeaderName:
+		return true
+	}
+	path := filepath.Join(project.DataDir(homeDir), name)
+	if path == seal.KeyfilePath(homeDir) || path == watch.PIDPath(homeDir) {
+		return true
+	}
+	if name == seal.KeyfileName && project.ConfigDir(homeDir) == project.DataDir(homeDir) {
+		return true
+	}
+	return strings.HasSuffix(name, ".lock")
+}
+
+// restoreMerge adds the archive's projects, repos and entries to the current
+// data. Local data always wins: entries that differ are reported as conflicts.
+func restoreMerge(homeDir string, a *Archive, archived *project.Config, bySlug map[string][]entryLine, opts Options, result *Result) error {
+	current, err := project.ReadConfig(homeDir)
+	if err != nil {
+		return err
+	}
+	if a.Manifest.SchemaVersion < current.SchemaVersion {
+		return fmt.Errorf("backup uses schema version %d and can only be restored with --replace (it is migrated afterwards)", a.Manifest.SchemaVersion)
+	}
+
+	// Entries of a project go to its local directory, which may have been renamed
+	targetSlug := make(map[string]string)
+	err = project.UpdateConfig(homeDir, func(cfg *project.Config) error {
+		for _, p := range archived.Projects {
+			if local := project.FindProjectByID(cfg, p.ID); local != nil {
+				for _, repo := range p.Repos {
+					if !containsString(local.Repos, repo) {
+						local.Repos = append(local.Repos, repo)
+					}
+				}
+				targetSlug[p.Slug] = local.Slug
+				result.ProjectsMerged++
+				continue
+			}
+			if other := project.FindProject(cfg, p.Name); other != nil {
+				return fmt.Errorf("project '%s' exists here with a different ID (%s, backup has %s) — rename one of them first", p.Name, other.ID, p.ID)
+			}
+			for _, other := range cfg.Projects {
+				if other.Slug == p.Slug {
+					return fmt.Errorf("project directory '%s' is used by project '%s' here — rename one of them first", p.Slug, other.Name)
+				}
+			}
+			cfg.Projects = append(cfg.Projects, p)
+			targetSlug[p.Slug] = p.Slug
+			result.ProjectsAdded++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for slug, lines := range bySlug {
+		target, ok := targetSlug[slug]
+		if !ok {
+			target = slug
+		}
+		for _, l := range lines {
+			local, err := entry.ReadRecord(homeDir, target, l.ID)
+			switch {
+			case errors.Is(err, entry.ErrNotFound):
+				if err := entry.RestoreEntry(homeDir, target, l.ID, l.Data); err != nil {
+					return err
+				}
+				result.EntriesRestored++
+			case err != nil:
+				return err
+			case journal.Equal(local.Data, l.Data):
+				result.EntriesSkipped++
+			default:
+				result.Conflicts = append(result.Conflicts, target+"/"+l.ID)
+			}
+		}
+	}
+
+	if data, ok := a.files[stateName]; ok {
+		return restoreWatchState(homeDir, data, opts.RepoMap, true)
+	}
+	return nil
+}
+
+// restoreWatchState writes the archived watcher state with remapped repo
+// paths. When merging, repos already tracked locally are left alone.
+func restoreWatchState(homeDir string, data []byte, mappings []PathMapping, merge bool) error {
+	archived := watch.NewWatchState()
+	if err := json.Unmarshal(data, archived); err != nil {
+		return fmt.Errorf("invalid watcher state in backup: %w", err)
+	}
+
+	state := watch.NewWatchState()
+	if merge {
+		local, err := watch.LoadWatchState(homeDir)
+		if err != nil {
+			return err
+		}
+		state = local
+	}
+	for repo, rs := range archived.Repos {
+		repo = remapPath(repo, mappings)
+		if _, ok := state.GetLastActivity(repo); ok && merge {
+			continue
+		}
+		state.SetLastActivity(repo, rs.LastActivity)
+	}
+	return state.Flush(homeDir)
+}
+
+// restoreRepos writes the marker of every archived repo that exists on this
+// machine and installs the hook in it.
+func restoreRepos(a *Archive, archived *project.Config, opts Options, result *Result) {
+	markers := make(map[string][]byte)
+	for _, r := range a.Manifest.Repos {
+		if r.Marker != "" {
+			markers[remapPath(r.Path, opts.RepoMap)] = a.files[r.Marker]
+		}
+	}
+
+	for _, p := range archived.Projects {
+		for _, repo := range p.Repos {
+			res := RepoResult{Path: repo, Status: RepoRestored}
+			if _, err := gitdir.Resolve(repo); err != nil {
+				res.Status = RepoMissing
+				result.Repos = append(result.Repos, res)
+				continue
+			}
+
+			rc := project.RepoConfig{}
+			if data, ok := markers[repo]; ok {
+				_ = json.Unmarshal(data, &rc)
+			}
+			rc.Project = p.Name
+			rc.ProjectID = p.ID
+			err := project.WriteRepoConfig(repo, &rc)
+			if err == nil && opts.InstallHook != nil {
+				err = opts.InstallHook(repo)
+			}
+			if err != nil {
+				res.Status = RepoFailed
+				res.Err = err
+			}
+			result.Repos = append(result.Repos, res)
+		}
+	}
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package backup
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/paths"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/Flyrell/hourgit/internal/watch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openArchive(t *testing.T, home string) *Archive {
+	t.Helper()
+	a, err := Open(home, bytes.NewReader(createArchive(t, home)))
+	require.NoError(t, err)
+	return a
+}
+
+func TestParsePathMappings(t *testing.T) {
+	mappings, err := ParsePathMappings("/old/=/new, /a=/b")
+	require.NoError(t, err)
+	assert.Equal(t, []PathMapping{{From: "/old", To: "/new"}, {From: "/a", To: "/b"}}, mappings)
+
+	_, err = ParsePathMappings("/old")
+	assert.EqualError(t, err, `invalid repo mapping "/old": expected OLD=NEW`)
+}
+
+func TestRemapPath(t *testing.T) {
+	mappings := []PathMapping{{From: "/home/old", To: "/Users/new"}}
+
+	assert.Equal(t, "/Users/new", remapPath("/home/old", mappings))
+	assert.Equal(t, "/Users/new/code/app", remapPath("/home/old/code/app", mappings))
+	assert.Equal(t, "/home/older/app", remapPath("/home/older/app", mappings))
+}
+
+func TestRestoreIntoEmptyHome(t *testing.T) {
+	src, repo, proj := setupData(t)
+	a := openArchive(t, src)
+
+	// The repo lost its marker and hook, e.g. on a fresh clone
+	require.NoError(t, os.Remove(filepath.Join(repo, ".git", ".hourgit")))
+
+	var installed []string
+	home := t.TempDir()
+	result, err := Restore(home, a, Options{InstallHook: func(dir string) error {
+		installed = append(installed, dir)
+		return nil
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.ProjectsAdded)
+	assert.Equal(t, 2, result.EntriesRestored)
+	assert.Equal(t, []RepoResult{{Path: repo, Status: RepoRestored}}, result.Repos)
+	assert.Equal(t, []string{repo}, installed)
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	require.Len(t, cfg.Projects, 1)
+	assert.Equal(t, proj.ID, cfg.Projects[0].ID)
+
+	e, err := entry.ReadEntry(home, proj.Slug, "aaaa11112222")
+	require.NoError(t, err)
+	assert.Equal(t, "first", e.Message)
+
+	rc, err := project.ReadRepoConfig(repo)
+	require.NoError(t, err)
+	assert.Equal(t, proj.ID, rc.ProjectID)
+
+	state, err := watch.LoadWatchState(home)
+	require.NoError(t, err)
+	_, ok := state.GetLastActivity(repo)
+	assert.True(t, ok)
+}
+
+func TestRestoreMergeKeepsLocalData(t *testing.T) {
+	home, _, proj := setupData(t)
+	a := openArchive(t, home)
+
+	// Local changes after the backup: one edited, one removed, one added
+	e, err := entry.ReadEntry(home, proj.Slug, "aaaa11112222")
+	require.NoError(t, err)
+	e.Message = "edited"
+	require.NoError(t, entry.WriteEntry(home, proj.Slug, e))
+	require.NoError(t, entry.DeleteEntry(home, proj.Slug, "bbbb11112222"))
+	other, err := project.CreateProject(home, "Other")
+	require.NoError(t, err)
+
+	result, err := Restore(home, a, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, result.ProjectsAdded)
+	assert.Equal(t, 1, result.ProjectsMerged)
+	assert.Equal(t, 1, result.EntriesRestored)
+	assert.Equal(t, []string{proj.Slug + "/aaaa11112222"}, result.Conflicts)
+
+	e, err = entry.ReadEntry(home, proj.Slug, "aaaa11112222")
+	require.NoError(t, err)
+	assert.Equal(t, "edited", e.Message)
+	_, err = entry.ReadEntry(home, proj.Slug, "bbbb11112222")
+	assert.NoError(t, err)
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	assert.NotNil(t, project.FindProjectByID(cfg, other.ID))
+	assert.Len(t, cfg.Projects, 2)
+
+	// Restoring again changes nothing
+	result, err = Restore(home, a, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.EntriesRestored)
+	assert.Equal(t, 1, result.EntriesSkipped)
+}
+
+func TestRestoreMergeFollowsRenamedProject(t *testing.T) {
+	home, _, proj := setupData(t)
+	a := openArchive(t, home)
+
+	renamed, err := project.RenameProject(home, proj.ID, "Acme Corp")
+	require.NoError(t, err)
+	require.NoError(t, entry.DeleteEntry(home, renamed.Slug, "bbbb11112222"))
+
+	result, err := Restore(home, a, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.EntriesRestored)
+
+	_, err = entry.ReadEntry(home, renamed.Slug, "bbbb11112222")
+	assert.NoError(t, err)
+}
+
+func TestRestoreMergeRejectsNameConflict(t *testing.T) {
+	src, _, _ := setupData(t)
+	a := openArchive(t, src)
+
+	home := t.TempDir()
+	_, err := project.CreateProject(home, "Acme")
+	require.NoError(t, err)
+
+	_, err = Restore(home, a, Options{})
+	assert.ErrorContains(t, err, "project 'Acme' exists here with a different ID")
+}
+
+func TestRestoreReplaceBacksUpCurrentData(t *testing.T) {
+	src, _, proj := setupData(t)
+	a := openArchive(t, src)
+
+	home := t.TempDir()
+	local, err := project.CreateProject(home, "Local")
+	require.NoError(t, err)
+	require.NoError(t, entry.WriteEntry(home, local.Slug, entry.Entry{ID: "cccc11112222", Start: time.Now(), Minutes: 5, Message: "local"}))
+
+	result, err := Restore(home, a, Options{Replace: true})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Backup)
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	require.Len(t, cfg.Projects, 1)
+	assert.Equal(t, proj.ID, cfg.Projects[0].ID)
+	_, err = os.Stat(project.LogDir(home, local.Slug))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = os.Stat(filepath.Join(result.Backup, local.Slug, "cccc11112222"))
+	assert.NoError(t, err, "replaced data should be in the backup")
+}
+
+func TestRestoreReplaceKeepsKeyfileUnderSingleRoot(t *testing.T) {
+	src, _, proj := setupData(t)
+	a := openArchive(t, src)
+
+	home := t.TempDir()
+	root := filepath.Join(home, "hourgit")
+	defer paths.Use(home, paths.Layout{Config: root, Data: root, State: root, Runtime: root})()
+	_, err := project.CreateProject(home, "Local")
+	require.NoError(t, err)
+	_, err = seal.Setup(home, seal.KeyKeyfile, "")
+	require.NoError(t, err)
+	keyfile, err := os.ReadFile(seal.KeyfilePath(home))
+	require.NoError(t, err)
+	require.Equal(t, root, filepath.Dir(seal.KeyfilePath(home)))
+
+	_, err = Restore(home, a, Options{Replace: true})
+	require.NoError(t, err)
+
+	after, err := os.ReadFile(seal.KeyfilePath(home))
+	require.NoError(t, err, "the keyfile survives")
+	assert.Equal(t, keyfile, after)
+	require.NoError(t, seal.Unlock(home))
+	e, err := entry.ReadEntry(home, proj.Slug, "aaaa11112222")
+	require.NoError(t, err)
+	assert.Equal(t, "first", e.Message)
+}
+
+func TestRestoreRemapsRepos(t *testing.T) {
+	src, repo, _ := setupData(t)
+	a := openArchive(t, src)
+
+	moved := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(moved, ".git"), 0755))
+
+	home := t.TempDir()
+	result, err := Restore(home, a, Options{RepoMap: []PathMapping{{From: repo, To: moved}}})
+	require.NoError(t, err)
+	assert.Equal(t, []RepoResult{{Path: moved, Status: RepoRestored}}, result.Repos)
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, []string{moved}, cfg.Projects[0].Repos)
+
+	state, err := watch.LoadWatchState(home)
+	require.NoError(t, err)
+	_, ok := state.GetLastActivity(moved)
+	assert.True(t, ok)
+}
+
+func TestRestoreReportsMissingRepos(t *testing.T) {
+	src, repo, _ := setupData(t)
+	a := openArchive(t, src)
+	require.NoError(t, os.RemoveAll(filepath.Join(repo, ".git")))
+
+	result, err := Restore(t.TempDir(), a, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []RepoResult{{Path: repo, Status: RepoMissing}}, result.Repos)
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/backup"
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = LeafCommand{
+	Use:   "backup",
+	Short: "Write all hourgit data to a single archive",
+	BoolFlags: []BoolFlag{
+		{Name: "plaintext", Usage: "write an unencrypted archive even though hourgit data is encrypted"},
+	},
+	StrFlags: []StringFlag{
+		{Name: "output", Shorthand: "o", Usage: "archive path (default: hourgit-backup-<date>-<time>.tar.gz, .tar.gz.sealed when encrypted)"},
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		output, _ := cmd.Flags().GetString("output")
+		plaintext, _ := cmd.Flags().GetBool("plaintext")
+		return runBackup(cmd, homeDir, output, plaintext, time.Now())
+	},
+}.Build()
+
+func runBackup(cmd *cobra.Command, homeDir, output string, plaintext bool, now time.Time) error {
+	encrypted := seal.Enabled(homeDir)
+	if output == "" {
+		output = fmt.Sprintf("hourgit-backup-%s.tar.gz", now.Format("20060102-150405"))
+		if encrypted && !plaintext {
+			output += ".sealed"
+		}
+	}
+
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return fmt.Errorf("'%s' already exists", output)
+	}
+	if err != nil {
+		return err
+	}
+
+	m, err := backup.Create(homeDir, f, appVersion, plaintext)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(output)
+		return err
+	}
+
+	entries := 0
+	for _, p := range m.Projects {
+		entries += p.Entries
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("backed up %d project(s), %d entries and %d repo(s) to %s",
+		len(m.Projects), entries, len(m.Repos), Primary(output))))
+	switch {
+	case encrypted && plaintext:
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Warning("the archive is not encrypted — store it somewhere safe"))
+	case encrypted:
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text("the archive is encrypted — restoring it needs the same passphrase or keyfile"))
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execBackup(homeDir, output string, plaintext bool) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := backupCmd
+	cmd.SetOut(stdout)
+
+	err := runBackup(cmd, homeDir, output, plaintext, time.Date(2025, 6, 16, 9, 30, 0, 0, time.UTC))
+	return stdout.String(), err
+}
+
+// setupBackupData creates a project with one entry.
+func setupBackupData(t *testing.T) (homeDir string, proj *project.ProjectEntry) {
+	t.Helper()
+	homeDir = t.TempDir()
+	proj, err := project.CreateProject(homeDir, "Acme")
+	require.NoError(t, err)
+	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, entry.Entry{
+		ID: "aaaa11112222", Start: time.Date(2025, 6, 16, 9, 0, 0, 0, time.UTC), Minutes: 60, Message: "work",
+	}))
+	return homeDir, proj
+}
+
+func TestBackupWritesArchive(t *testing.T) {
+	homeDir, _ := setupBackupData(t)
+	output := filepath.Join(t.TempDir(), "out.tar.gz")
+
+	stdout, err := execBackup(homeDir, output, false)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "backed up 1 project(s), 1 entries and 0 repo(s)")
+	_, err = os.Stat(output)
+	assert.NoError(t, err)
+}
+
+func TestBackupDefaultName(t *testing.T) {
+	homeDir, _ := setupBackupData(t)
+	t.Chdir(t.TempDir())
+
+	stdout, err := execBackup(homeDir, "", false)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "hourgit-backup-20250616-093000.tar.gz")
+	_, err = os.Stat("hourgit-backup-20250616-093000.tar.gz")
+	assert.NoError(t, err)
+}
+
+func TestBackupRefusesToOverwrite(t *testing.T) {
+	homeDir, _ := setupBackupData(t)
+	output := filepath.Join(t.TempDir(), "out.tar.gz")
+	require.NoError(t, os.WriteFile(output, []byte("keep"), 0644))
+
+	_, err := execBackup(homeDir, output, false)
+
+	assert.ErrorContains(t, err, "already exists")
+	data, _ := os.ReadFile(output)
+	assert.Equal(t, "keep", string(data))
+}
+
+func TestBackupWithoutData(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.tar.gz")
+
+	_, err := execBackup(t.TempDir(), output, false)
+
+	assert.EqualError(t, err, "no hourgit data to back up")
+	_, statErr := os.Stat(output)
+	assert.True(t, os.IsNotExist(statErr), "failed backup should not leave a file")
+}
+
+func TestBackupRegistered(t *testing.T) {
+	found := false
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "backup" {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestBackupEncryptedArchive(t *testing.T) {
+	homeDir, _ := setupBackupData(t)
+	t.Setenv(seal.EnvPassphrase, "secret")
+	_, err := execEncrypt(homeDir, false, nil)
+	require.NoError(t, err)
+	t.Chdir(t.TempDir())
+
+	stdout, err := execBackup(homeDir, "", false)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "the archive is encrypted")
+	data, err := os.ReadFile("hourgit-backup-20250616-093000.tar.gz.sealed")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Acme")
+
+	stdout, err = execBackup(homeDir, "", true)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "the archive is not encrypted")
+	_, err = os.Stat("hourgit-backup-20250616-093000.tar.gz")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/hashutil"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/schedule"
+	"github.com/Flyrell/hourgit/internal/timetrack"
+	"github.com/spf13/cobra"
+)
+
+var balanceCmd = LeafCommand{
+	Use:   "balance",
+	Short: "Show the flextime balance of hours over or under schedule",
+	StrFlags: []StringFlag{
+		{Name: "project", Shorthand: "p", Usage: "project name or ID (auto-detected from repo if omitted)"},
+		{Name: "start", Usage: "date the balance is counted from (YYYY-MM-DD, or off)"},
+		{Name: "correct", Usage: "correct the balance by a signed duration (e.g. +2h, -1h30m)"},
+		{Name: "date", Shorthand: "D", Usage: "date of the correction (YYYY-MM-DD, default: today)"},
+		{Name: "message", Shorthand: "m", Usage: "reason for the correction"},
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, repoDir, err := getContextPaths()
+		if err != nil {
+			return err
+		}
+
+		projectFlag, _ := cmd.Flags().GetString("project")
+		startFlag, _ := cmd.Flags().GetString("start")
+		correctFlag, _ := cmd.Flags().GetString("correct")
+		dateFlag, _ := cmd.Flags().GetString("date")
+		messageFlag, _ := cmd.Flags().GetString("message")
+
+		return runBalance(cmd, homeDir, repoDir, projectFlag, startFlag, correctFlag, dateFlag, messageFlag, time.Now)
+	},
+}.Build()
+
+func runBalance(
+	cmd *cobra.Command,
+	homeDir, repoDir, projectFlag, startFlag, correctFlag, dateFlag, messageFlag string,
+	nowFn func() time.Time,
+) error {
+	proj, err := ResolveProjectContext(homeDir, repoDir, projectFlag)
+	if err != nil {
+		return err
+	}
+	now := inProjectZone(proj, nowFn())
+	w := cmd.OutOrStdout()
+
+	if correctFlag == "" && (dateFlag != "" || messageFlag != "") {
+		return fmt.Errorf("--date and --message require --correct")
+	}
+
+	if startFlag != "" {
+		start := startFlag
+		if start == "off" {
+			start = ""
+		}
+		if err := project.SetBalanceStart(homeDir, proj.ID, start); err != nil {
+			return err
+		}
+		proj.BalanceStart = start
+		if start == "" {
+			_, _ = fmt.Fprintf(w, "turned off the balance of project '%s'\n", Primary(proj.Name))
+			return nil
+		}
+		_, _ = fmt.Fprintf(w, "counting the balance of project '%s' from %s\n", Primary(proj.Name), Primary(start))
+	}
+
+	if proj.BalanceStart == "" {
+		return fmt.Errorf("project '%s' has no balance start date (set one with 'hourgit balance --start YYYY-MM-DD')", proj.Name)
+	}
+
+	if correctFlag != "" {
+		minutes, err := entry.ParseSignedDuration(correctFlag)
+		if err != nil {
+			return err
+		}
+		date, err := resolveBaseDate(dateFlag, now)
+		if err != nil {
+			return err
+		}
+		id, err := entry.NewID(homeDir, proj.Slug, hashutil.TimeSeed("correction"))
+		if err != nil {
+			return err
+		}
+		c := entry.CorrectionEntry{
+			ID:        id,
+			Date:      timetrack.DateOf(date),
+			Minutes:   minutes,
+			Message:   messageFlag,
+			CreatedAt: now.UTC(),
+		}
+		if err := entry.WriteCorrectionEntry(homeDir, proj.Slug, c); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(w, "corrected balance by %s on %s (%s)\n",
+			Primary(entry.FormatSignedMinutes(minutes)),
+			Primary(c.Date.Format("2006-01-02")),
+			Silent(c.ID),
+		)
+	}
+
+	cfg, err := project.ReadConfig(homeDir)
+	if err != nil {
+		return err
+	}
+	balance, err := projectBalance(homeDir, cfg, proj, now)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%s  %s\n", Silent("Project:"), Primary(proj.Name))
+	_, _ = fmt.Fprintf(w, "%s    %s\n\n", Silent("Since:"), Text(proj.BalanceStart))
+	_, _ = fmt.Fprintf(w, "%s\n", Silent(fmt.Sprintf("%-9s %11s %11s %12s %11s", "Month", "Scheduled", "Worked", "Corrections", "Balance")))
+	for _, p := range balance.Periods {
+		corrections := "."
+		if p.CorrectionMinutes != 0 {
+			corrections = entry.FormatSignedMinutes(p.CorrectionMinutes)
+		}
+		_, _ = fmt.Fprintf(w, "%-9s %11s %11s %12s %11s\n",
+			p.From.Format("2006-01"),
+			entry.FormatMinutes(p.ScheduledMinutes),
+			entry.FormatMinutes(p.WorkedMinutes),
+			corrections,
+			entry.FormatSignedMinutes(p.BalanceMinutes),
+		)
+	}
+	_, _ = fmt.Fprintf(w, "\n%s  %s\n", Silent("Balance:"), balanceLabel(balance.Minutes()))
+	return nil
+}
+
+// projectBalance computes the flextime balance of a project from its balance
+// start date up to now, or returns nil when the project has none. Only the
+// entries of that period are read.
+func projectBalance(homeDir string, cfg *project.Config, proj *project.ProjectEntry, now time.Time) (*timetrack.Balance, error) {
+	if proj.BalanceStart == "" {
+		return nil, nil
+	}
+	from, err := time.Parse("2006-01-02", proj.BalanceStart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid balance start date %q (expected YYYY-MM-DD)", proj.BalanceStart)
+	}
+
+	// Expand from the day before for the end of an overnight window
+	todayEnd := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, time.UTC)
+	daySchedules, err := schedule.ExpandSchedules(project.GetSchedules(cfg, proj.ID), from.AddDate(0, 0, -1), todayEnd)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := projectOptions(homeDir, cfg, proj, from, timetrack.DateOf(now), now)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := LoadProjectEntriesRange(homeDir, proj, from, timetrack.DateOf(now))
+	if err != nil {
+		return nil, err
+	}
+
+	balance := timetrack.ComputeBalance(
+		entries.Checkouts, entries.Logs, entries.Commits, entries.Events, daySchedules, entries.Corrections,
+		from, now, opts,
+		timetrack.ActivityEntries{Stops: entries.ActivityStops, Starts: entries.ActivityStarts},
+	)
+	return &balance, nil
+}
+
+// balanceLabel formats a balance with its sign, highlighting time under target.
+func balanceLabel(minutes int) string {
+	label := entry.FormatSignedMinutes(minutes)
+	if minutes < 0 {
+		return Warning(label)
+	}
+	return Primary(label)
+}
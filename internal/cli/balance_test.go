@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execBalance(homeDir, projectFlag, startFlag, correctFlag, dateFlag, messageFlag string, now time.Time) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := balanceCmd
+	cmd.SetOut(stdout)
+
+	err := runBalance(cmd, homeDir, "", projectFlag, startFlag, correctFlag, dateFlag, messageFlag, mockNow(now))
+	return stdout.String(), err
+}
+
+func setupBalanceTest(t *testing.T) (homeDir string, proj *project.ProjectEntry) {
+	t.Helper()
+	homeDir, proj = setupStatusTest(t)
+	require.NoError(t, project.SetSchedules(homeDir, proj.ID, weekdaySchedule(9, 0, 17, 0)))
+
+	// 9h on Monday, May 26 and 8h on Monday, June 2
+	for id, start := range map[string]time.Time{
+		"aaa1111": time.Date(2025, 5, 26, 9, 0, 0, 0, time.UTC),
+		"bbb2222": time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC),
+	} {
+		minutes := 480
+		if start.Month() == time.May {
+			minutes = 540
+		}
+		require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, entry.Entry{
+			ID: id, Start: start, Minutes: minutes, Message: "work",
+		}))
+	}
+	return homeDir, proj
+}
+
+func TestBalanceRequiresStart(t *testing.T) {
+	homeDir, proj := setupBalanceTest(t)
+
+	_, err := execBalance(homeDir, proj.Name, "", "", "", "", time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC))
+
+	assert.ErrorContains(t, err, "hourgit balance --start YYYY-MM-DD")
+}
+
+func TestBalanceCarriesOverMonths(t *testing.T) {
+	homeDir, proj := setupBalanceTest(t)
+	now := time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)
+
+	stdout, err := execBalance(homeDir, proj.Name, "2025-05-26", "", "", "", now)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "from 2025-05-26")
+	assert.Regexp(t, `2025-05\s+40h\s+9h\s+\.\s+-31h`, stdout)
+	assert.Regexp(t, `2025-06\s+8h\s+8h\s+\.\s+-31h`, stdout)
+	assert.Contains(t, stdout, "Balance:  -31h")
+
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-05-26", project.FindProjectByID(cfg, proj.ID).BalanceStart)
+}
+
+func TestBalanceCorrection(t *testing.T) {
+	homeDir, proj := setupBalanceTest(t)
+	now := time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)
+	require.NoError(t, project.SetBalanceStart(homeDir, proj.ID, "2025-06-02"))
+
+	stdout, err := execBalance(homeDir, proj.Name, "", "+1h30m", "", "carried over", now)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "corrected balance by +1h 30m on 2025-06-02")
+	assert.Regexp(t, `2025-06\s+8h\s+8h\s+\+1h 30m\s+\+1h 30m`, stdout)
+
+	corrections, err := entry.ReadAllCorrectionEntries(homeDir, proj.Slug)
+	require.NoError(t, err)
+	require.Len(t, corrections, 1)
+	assert.Equal(t, 90, corrections[0].Minutes)
+	assert.Equal(t, "carried over", corrections[0].Message)
+
+	stdout, err = execBalance(homeDir, proj.Name, "", "-2h", "2025-06-02", "", now)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Balance:  -30m")
+}
+
+func TestBalanceErrors(t *testing.T) {
+	homeDir, proj := setupBalanceTest(t)
+	now := time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)
+	require.NoError(t, project.SetBalanceStart(homeDir, proj.ID, "2025-06-02"))
+
+	_, err := execBalance(homeDir, proj.Name, "", "", "", "why", now)
+	assert.ErrorContains(t, err, "require --correct")
+
+	_, err = execBalance(homeDir, proj.Name, "", "2 hours", "", "", now)
+	assert.Error(t, err)
+
+	_, err = execBalance(homeDir, proj.Name, "June", "", "", "", now)
+	assert.ErrorContains(t, err, "invalid balance start date")
+}
+
+func TestBalanceStartOff(t *testing.T) {
+	homeDir, proj := setupBalanceTest(t)
+	require.NoError(t, project.SetBalanceStart(homeDir, proj.ID, "2025-06-02"))
+
+	stdout, err := execBalance(homeDir, proj.Name, "off", "", "", "", time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "turned off the balance")
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	assert.Empty(t, project.FindProjectByID(cfg, proj.ID).BalanceStart)
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/spf13/cobra"
+)
+
+var decryptCmd = LeafCommand{
+	Use:   "decrypt",
+	Short: "Turn encryption off and store entries and config in plain text",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		return runDecrypt(cmd, homeDir)
+	},
+}.Build()
+
+func runDecrypt(cmd *cobra.Command, homeDir string) error {
+	h, err := seal.ReadHeader(homeDir)
+	if err != nil {
+		return err
+	}
+	if h == nil {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Silent("data is not encrypted"))
+		return nil
+	}
+	if err := seal.Unlock(homeDir); err != nil {
+		return err
+	}
+
+	// New writes are plain from here on; the header stays until nothing
+	// sealed is left, so an interrupted run can be finished
+	h.Decrypting = true
+	if err := seal.WriteHeader(homeDir, h); err != nil {
+		return err
+	}
+	count, err := resealData(homeDir)
+	if err != nil {
+		return fmt.Errorf("decryption incomplete, run 'hourgit decrypt' again to finish: %w", err)
+	}
+	if err := seal.RemoveHeader(homeDir); err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("decrypted %d file(s)", count)))
+	if h.Key == seal.KeyKeyfile {
+		_, _ = fmt.Fprintf(w, "%s\n", Info(fmt.Sprintf("the keyfile %s is no longer needed", seal.KeyfilePath(homeDir))))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execDecrypt(homeDir string) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := decryptCmd
+	cmd.SetOut(stdout)
+
+	err := runDecrypt(cmd, homeDir)
+	return stdout.String(), err
+}
+
+func TestDecryptRestoresPlainData(t *testing.T) {
+	homeDir, proj := setupBackupData(t)
+	t.Setenv(seal.EnvPassphrase, "secret")
+	_, err := execEncrypt(homeDir, false, nil)
+	require.NoError(t, err)
+
+	stdout, err := execDecrypt(homeDir)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "decrypted 3 file(s)")
+	assert.False(t, seal.Enabled(homeDir))
+	assert.NoFileExists(t, seal.HeaderPath(homeDir))
+	path, err := entry.EntryPath(homeDir, proj.Slug, "aaaa11112222")
+	require.NoError(t, err)
+	assert.False(t, readSealed(t, path))
+	assert.False(t, readSealed(t, project.ConfigPath(homeDir)))
+
+	e, err := entry.ReadEntry(homeDir, proj.Slug, "aaaa11112222")
+	require.NoError(t, err)
+	assert.Equal(t, "work", e.Message)
+}
+
+func TestDecryptNotEncrypted(t *testing.T) {
+	homeDir, _ := setupBackupData(t)
+
+	stdout, err := execDecrypt(homeDir)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "data is not encrypted")
+}
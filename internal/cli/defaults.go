@@ -4,8 +4,9 @@ import "github.com/spf13/cobra"
 
 var defaultsCmd = GroupCommand{
 	Use:   "defaults",
-	Short: "Manage defaults for new projects",
+	Short: "Manage defaults for new projects and settings shared by all projects",
 	Subcommands: []*cobra.Command{
 		defaultsScheduleCmd,
+		defaultsOverlapCmd,
 	},
 }.Build()
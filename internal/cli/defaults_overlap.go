@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var defaultsOverlapCmd = LeafCommand{
+	Use:   "overlap [POLICY]",
+	Short: "Show or set who gets time several projects claim at once: recent, priority, split or off",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		policy := ""
+		if len(args) > 0 {
+			policy = args[0]
+		}
+		return runDefaultsOverlap(cmd, homeDir, policy)
+	},
+}.Build()
+
+func runDefaultsOverlap(cmd *cobra.Command, homeDir, policy string) error {
+	cfg, err := project.ReadConfig(homeDir)
+	if err != nil {
+		return err
+	}
+	current := project.GetOverlap(cfg)
+
+	if policy == "" {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", Text("overlap:"), Primary(current))
+		return nil
+	}
+
+	if err := project.SetOverlap(homeDir, policy); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("overlap: %s → %s", Silent(current), Primary(policy))))
+	if policy == project.OverlapPriority {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text("set project priorities with hourgit project edit --priority"))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execDefaultsOverlap(homeDir, policy string) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := defaultsOverlapCmd
+	cmd.SetOut(stdout)
+	err := runDefaultsOverlap(cmd, homeDir, policy)
+	return stdout.String(), err
+}
+
+func TestDefaultsOverlapShow(t *testing.T) {
+	stdout, err := execDefaultsOverlap(t.TempDir(), "")
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "overlap: recent")
+}
+
+func TestDefaultsOverlapSet(t *testing.T) {
+	homeDir := t.TempDir()
+
+	stdout, err := execDefaultsOverlap(homeDir, "priority")
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "recent → priority")
+	assert.Contains(t, stdout, "--priority")
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	assert.Equal(t, project.OverlapPriority, project.GetOverlap(cfg))
+}
+
+func TestDefaultsOverlapInvalid(t *testing.T) {
+	_, err := execDefaultsOverlap(t.TempDir(), "first")
+
+	assert.ErrorContains(t, err, "invalid overlap policy")
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/fsck"
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/remote"
+	"github.com/Flyrell/hourgit/internal/schema"
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/Flyrell/hourgit/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var encryptCmd = LeafCommand{
+	Use:   "encrypt",
+	Short: "Encrypt stored entries and config with a passphrase or keyfile",
+	BoolFlags: []BoolFlag{
+		{Name: "keyfile", Usage: "derive the key from a keyfile instead of a passphrase (generated if missing)"},
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		keyfile, _ := cmd.Flags().GetBool("keyfile")
+		return runEncrypt(cmd, homeDir, keyfile, NewPasswordFunc())
+	},
+}.Build()
+
+func runEncrypt(cmd *cobra.Command, homeDir string, keyfile bool, password PromptFunc) error {
+	if _, err := os.Stat(project.ConfigPath(homeDir)); err != nil {
+		return fmt.Errorf("no hourgit data to encrypt")
+	}
+
+	kind := seal.KeyPassphrase
+	if keyfile {
+		kind = seal.KeyKeyfile
+	}
+
+	h, err := seal.ReadHeader(homeDir)
+	if err != nil {
+		return err
+	}
+	if h == nil {
+		passphrase := ""
+		if kind == seal.KeyPassphrase {
+			if passphrase, err = newPassphrase(password); err != nil {
+				return err
+			}
+		}
+		if h, err = seal.Setup(homeDir, kind, passphrase); err != nil {
+			return err
+		}
+	} else {
+		// Already set up, e.g. copied from another machine, or an earlier
+		// run was interrupted: finish with the existing key
+		if keyfile && h.Key != seal.KeyKeyfile {
+			return fmt.Errorf("data is already encrypted with a %s — run 'hourgit decrypt' first to change the key", h.Key)
+		}
+		if err := seal.Unlock(homeDir); err != nil {
+			return err
+		}
+		if h.Decrypting {
+			h.Decrypting = false
+			if err := seal.WriteHeader(homeDir, h); err != nil {
+				return err
+			}
+		}
+	}
+
+	count, err := resealData(homeDir)
+	if err != nil {
+		return fmt.Errorf("encryption incomplete, run 'hourgit encrypt' again to finish: %w", err)
+	}
+
+	w := cmd.OutOrStdout()
+	if count == 0 {
+		_, _ = fmt.Fprintf(w, "%s\n", Silent("data is already encrypted"))
+		return nil
+	}
+	_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("encrypted %d file(s)", count)))
+	if h.Key == seal.KeyKeyfile {
+		_, _ = fmt.Fprintf(w, "%s\n", Info(fmt.Sprintf("keyfile: %s — keep a copy somewhere safe, the data cannot be read without it", seal.KeyfilePath(homeDir))))
+	} else {
+		_, _ = fmt.Fprintf(w, "%s\n", Info(fmt.Sprintf("git hooks and the file watcher can only record activity when %s is set in their environment (or use --keyfile)", seal.EnvPassphrase)))
+	}
+	if items, err := os.ReadDir(schema.BackupDir(homeDir)); err == nil && len(items) > 0 {
+		_, _ = fmt.Fprintf(w, "%s\n", Warning(fmt.Sprintf("%s holds copies made before encryption — delete them once you no longer need them", schema.BackupDir(homeDir))))
+	}
+	if _, err := remote.URL(homeDir); err == nil {
+		_, _ = fmt.Fprintf(w, "%s\n", Warning("the history of the sync repository still holds unencrypted data"))
+	}
+	return nil
+}
+
+// newPassphrase takes the passphrase from the environment, or asks for it
+// twice.
+func newPassphrase(password PromptFunc) (string, error) {
+	if p := os.Getenv(seal.EnvPassphrase); p != "" {
+		return p, nil
+	}
+	p, err := password("Passphrase")
+	if err != nil {
+		return "", err
+	}
+	if p == "" {
+		return "", errors.New("passphrase must not be empty")
+	}
+	again, err := password("Repeat passphrase")
+	if err != nil {
+		return "", err
+	}
+	if again != p {
+		return "", errors.New("passphrases do not match")
+	}
+	return p, nil
+}
+
+// resealData rewrites the config, every entry including quarantined ones, the
+// journal and the watcher state sealed or plain, whichever homeDir currently uses. Returns the
+// number of files rewritten.
+func resealData(homeDir string) (int, error) {
+	count := 0
+	for _, reseal := range []func(string) (int, error){entry.Reseal, fsck.ResealQuarantine} {
+		n, err := reseal(homeDir)
+		count += n
+		if err != nil {
+			return count, err
+		}
+	}
+	for _, reseal := range []func() (bool, error){
+		func() (bool, error) { return project.ResealConfig(homeDir) },
+		func() (bool, error) { return journal.Reseal(homeDir) },
+		func() (bool, error) { return watch.ResealState(homeDir) },
+	} {
+		changed, err := reseal()
+		if err != nil {
+			return count, err
+		}
+		if changed {
+			count++
+		}
+	}
+	return count, remote.RefreshConfig(homeDir)
+}
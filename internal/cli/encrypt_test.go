@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/schema"
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execEncrypt(homeDir string, keyfile bool, password PromptFunc) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := encryptCmd
+	cmd.SetOut(stdout)
+
+	err := runEncrypt(cmd, homeDir, keyfile, password)
+	return stdout.String(), err
+}
+
+// passwords answers prompts with the given values in order.
+func passwords(values ...string) PromptFunc {
+	return func(string) (string, error) {
+		v := values[0]
+		values = values[1:]
+		return v, nil
+	}
+}
+
+// readSealed reports whether the file at path is sealed.
+func readSealed(t *testing.T, path string) bool {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return seal.IsSealed(data)
+}
+
+func TestEncryptSealsData(t *testing.T) {
+	homeDir, proj := setupBackupData(t)
+
+	stdout, err := execEncrypt(homeDir, false, passwords("secret", "secret"))
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "encrypted 3 file(s)")
+	assert.Contains(t, stdout, seal.EnvPassphrase)
+
+	path, err := entry.EntryPath(homeDir, proj.Slug, "aaaa11112222")
+	require.NoError(t, err)
+	assert.True(t, readSealed(t, path))
+	assert.True(t, readSealed(t, project.ConfigPath(homeDir)))
+	data, err := os.ReadFile(journal.Path(homeDir))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "work")
+
+	e, err := entry.ReadEntry(homeDir, proj.Slug, "aaaa11112222")
+	require.NoError(t, err)
+	assert.Equal(t, "work", e.Message)
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	assert.Len(t, cfg.Projects, 1)
+
+	// New writes are sealed as well
+	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, entry.Entry{
+		ID: "bbbb11112222", Start: time.Date(2025, 6, 17, 9, 0, 0, 0, time.UTC), Minutes: 30, Message: "more",
+	}))
+	path, err = entry.EntryPath(homeDir, proj.Slug, "bbbb11112222")
+	require.NoError(t, err)
+	assert.True(t, readSealed(t, path))
+
+	stdout, err = execEncrypt(homeDir, false, nil)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "data is already encrypted")
+}
+
+func TestEncryptPassphraseMismatch(t *testing.T) {
+	homeDir, _ := setupBackupData(t)
+
+	_, err := execEncrypt(homeDir, false, passwords("secret", "other"))
+
+	assert.EqualError(t, err, "passphrases do not match")
+	assert.False(t, seal.Enabled(homeDir))
+}
+
+func TestEncryptWithKeyfile(t *testing.T) {
+	homeDir, proj := setupBackupData(t)
+	keyfile := filepath.Join(t.TempDir(), "hourgit.key")
+	t.Setenv(seal.EnvKeyfile, keyfile)
+
+	stdout, err := execEncrypt(homeDir, true, nil)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "keyfile: "+keyfile)
+	assert.FileExists(t, keyfile)
+	path, err := entry.EntryPath(homeDir, proj.Slug, "aaaa11112222")
+	require.NoError(t, err)
+	assert.True(t, readSealed(t, path))
+}
+
+func TestEncryptWarnsAboutBackups(t *testing.T) {
+	homeDir, _ := setupBackupData(t)
+	t.Setenv(seal.EnvPassphrase, "secret")
+	backups := filepath.Join(schema.BackupDir(homeDir), "v0")
+	require.NoError(t, os.MkdirAll(backups, 0755))
+
+	stdout, err := execEncrypt(homeDir, false, nil)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "holds copies made before encryption")
+}
+
+func TestEncryptWithoutData(t *testing.T) {
+	_, err := execEncrypt(t.TempDir(), false, nil)
+
+	assert.EqualError(t, err, "no hourgit data to encrypt")
+}
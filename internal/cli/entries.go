@@ -1,7 +1,11 @@
 package cli
 
 import (
+	"sort"
+	"time"
+
 	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
 )
 
 // ProjectEntries holds all entry types for a project.
@@ -9,42 +13,70 @@ type ProjectEntries struct {
 	Checkouts      []entry.CheckoutEntry
 	Logs           []entry.Entry
 	Commits        []entry.CommitEntry
+	Events         []entry.GitEventEntry
 	ActivityStops  []entry.ActivityStopEntry
 	ActivityStarts []entry.ActivityStartEntry
+	Corrections    []entry.CorrectionEntry
 }
 
-// LoadProjectEntries reads all 5 entry types for a project in one call.
+// LoadProjectEntries reads all 7 entry types for a project in one call.
+// The project's store is queried once and each entry is read at most once.
 func LoadProjectEntries(homeDir, slug string) (ProjectEntries, error) {
-	checkouts, err := entry.ReadAllCheckoutEntries(homeDir, slug)
+	records, err := entry.QueryRecords(homeDir, slug, entry.Query{})
 	if err != nil {
 		return ProjectEntries{}, err
 	}
+	return decodeProjectEntries(records), nil
+}
 
-	logs, err := entry.ReadAllEntries(homeDir, slug)
+// LoadProjectEntriesRange reads the entries of a project on the dates
+// from..to, with a day's margin on either side for overnight windows, time
+// zones and sessions spanning midnight. The last checkout before that of each
+// of the project's repositories comes along, as it says what was checked out
+// when the range begins. Nothing older is read.
+func LoadProjectEntriesRange(homeDir string, proj *project.ProjectEntry, from, to time.Time) (ProjectEntries, error) {
+	start, end := from.AddDate(0, 0, -1), to.AddDate(0, 0, 2)
+	records, err := entry.QueryRecords(homeDir, proj.Slug, entry.Query{From: start, To: end})
 	if err != nil {
 		return ProjectEntries{}, err
 	}
 
-	commits, err := entry.ReadAllCommitEntries(homeDir, slug)
+	earlier, err := entry.ListRecords(homeDir, proj.Slug, entry.Query{Type: entry.TypeCheckout, To: start})
 	if err != nil {
 		return ProjectEntries{}, err
 	}
-
-	activityStops, err := entry.ReadAllActivityStopEntries(homeDir, slug)
-	if err != nil {
-		return ProjectEntries{}, err
-	}
-
-	activityStarts, err := entry.ReadAllActivityStartEntries(homeDir, slug)
-	if err != nil {
-		return ProjectEntries{}, err
+	sort.Slice(earlier, func(i, j int) bool { return earlier[i].Start.After(earlier[j].Start) })
+	seen := make(map[string]bool)
+	for _, meta := range earlier {
+		if len(seen) >= max(1, len(proj.Repos)) {
+			break
+		}
+		if !meta.Start.Before(start) {
+			continue
+		}
+		rec, err := entry.ReadRecord(homeDir, proj.Slug, meta.ID)
+		if err != nil {
+			return ProjectEntries{}, err
+		}
+		checkouts := entry.Decode[entry.CheckoutEntry]([]entry.Record{rec}, entry.TypeCheckout)
+		if len(checkouts) == 0 || seen[checkouts[0].Repo] {
+			continue
+		}
+		seen[checkouts[0].Repo] = true
+		records = append(records, rec)
 	}
+	return decodeProjectEntries(records), nil
+}
 
+// decodeProjectEntries sorts records into their entry types.
+func decodeProjectEntries(records []entry.Record) ProjectEntries {
 	return ProjectEntries{
-		Checkouts:      checkouts,
-		Logs:           logs,
-		Commits:        commits,
-		ActivityStops:  activityStops,
-		ActivityStarts: activityStarts,
-	}, nil
+		Checkouts:      entry.Decode[entry.CheckoutEntry](records, entry.TypeCheckout),
+		Logs:           entry.Decode[entry.Entry](records, entry.TypeLog),
+		Commits:        entry.Decode[entry.CommitEntry](records, entry.TypeCommit),
+		Events:         entry.Decode[entry.GitEventEntry](records, entry.TypeGitEvent),
+		ActivityStops:  entry.Decode[entry.ActivityStopEntry](records, entry.TypeActivityStop),
+		ActivityStarts: entry.Decode[entry.ActivityStartEntry](records, entry.TypeActivityStart),
+		Corrections:    entry.Decode[entry.CorrectionEntry](records, entry.TypeCorrection),
+	}
 }
@@ -40,6 +40,7 @@ func TestLoadProjectEntriesEmpty(t *testing.T) {
 	assert.Empty(t, entries.Commits)
 	assert.Empty(t, entries.ActivityStops)
 	assert.Empty(t, entries.ActivityStarts)
+	assert.Empty(t, entries.Corrections)
 }
 
 func TestLoadProjectEntriesWithData(t *testing.T) {
@@ -67,6 +68,10 @@ func TestLoadProjectEntriesWithData(t *testing.T) {
 		ID: "eee5555", Timestamp: now.Add(30 * time.Minute),
 	}))
 
+	require.NoError(t, entry.WriteCorrectionEntry(homeDir, proj.Slug, entry.CorrectionEntry{
+		ID: "fff6666", Date: now, Minutes: -60,
+	}))
+
 	entries, err := LoadProjectEntries(homeDir, proj.Slug)
 
 	require.NoError(t, err)
@@ -75,6 +80,7 @@ func TestLoadProjectEntriesWithData(t *testing.T) {
 	assert.Len(t, entries.Commits, 1)
 	assert.Len(t, entries.ActivityStops, 1)
 	assert.Len(t, entries.ActivityStarts, 1)
+	assert.Len(t, entries.Corrections, 1)
 }
 
 func TestLoadProjectEntriesInvalidSlug(t *testing.T) {
@@ -89,4 +95,60 @@ func TestLoadProjectEntriesInvalidSlug(t *testing.T) {
 	assert.Empty(t, entries.Commits)
 	assert.Empty(t, entries.ActivityStops)
 	assert.Empty(t, entries.ActivityStarts)
+	assert.Empty(t, entries.Corrections)
+}
+
+// recordingStore records the IDs of the entries whose payloads it returns.
+type recordingStore struct {
+	*entry.MemoryStore
+	read map[string]bool
+}
+
+func (s *recordingStore) Read(slug, id string) ([]byte, error) {
+	s.read[id] = true
+	return s.MemoryStore.Read(slug, id)
+}
+
+func (s *recordingStore) Query(slug string, q entry.Query) ([]entry.Record, error) {
+	records, err := s.MemoryStore.Query(slug, q)
+	for _, r := range records {
+		s.read[r.ID] = true
+	}
+	return records, err
+}
+
+func TestLoadProjectEntriesRangeReadsOnlyTheRange(t *testing.T) {
+	homeDir, proj := setupEntriesTest(t)
+	store := &recordingStore{MemoryStore: entry.NewMemoryStore(), read: map[string]bool{}}
+	t.Cleanup(entry.UseStore(homeDir, store))
+
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 9, 0, 0, 0, time.UTC) }
+	for id, ts := range map[string]time.Time{"aaa0001": day(1, 6), "aaa0002": day(5, 20), "aaa0003": day(6, 11)} {
+		require.NoError(t, entry.WriteCheckoutEntry(homeDir, proj.Slug, entry.CheckoutEntry{
+			ID: id, Timestamp: ts, Previous: "main", Next: "feature-" + id,
+		}))
+	}
+	for id, ts := range map[string]time.Time{"bbb0001": day(3, 3), "bbb0002": day(6, 10), "bbb0003": day(7, 1)} {
+		require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, entry.Entry{
+			ID: id, Start: ts, Minutes: 60, Message: "work",
+		}))
+	}
+
+	clear(store.read)
+
+	entries, err := LoadProjectEntriesRange(homeDir, proj, day(6, 10), day(6, 11))
+	require.NoError(t, err)
+
+	var checkouts, logs []string
+	for _, c := range entries.Checkouts {
+		checkouts = append(checkouts, c.ID)
+	}
+	for _, l := range entries.Logs {
+		logs = append(logs, l.ID)
+	}
+	assert.ElementsMatch(t, []string{"aaa0002", "aaa0003"}, checkouts, "the last checkout before the range comes along")
+	assert.Equal(t, []string{"bbb0002"}, logs)
+	for _, id := range []string{"aaa0001", "bbb0001", "bbb0003"} {
+		assert.False(t, store.read[id], "%s is outside the range and must not be read", id)
+	}
 }
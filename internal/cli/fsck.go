@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Flyrell/hourgit/internal/fsck"
+	"github.com/spf13/cobra"
+)
+
+var fsckCmd = LeafCommand{
+	Use:   "fsck",
+	Short: "Check stored data for problems and optionally repair them",
+	BoolFlags: []BoolFlag{
+		{Name: "repair", Usage: "fix problems that can be repaired safely"},
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		repair, _ := cmd.Flags().GetBool("repair")
+		return runFsck(cmd, homeDir, repair)
+	},
+}.Build()
+
+func runFsck(cmd *cobra.Command, homeDir string, repair bool) error {
+	report, err := fsck.Check(homeDir)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if len(report.Problems) == 0 {
+		_, _ = fmt.Fprintln(w, Silent("no problems found"))
+		return nil
+	}
+
+	var repairErr error
+	if repair {
+		_, repairErr = report.Repair()
+	}
+
+	for _, p := range report.Problems {
+		location := p.Slug
+		if p.ID != "" {
+			location += "/" + p.ID
+		}
+		status := ""
+		switch {
+		case p.Repaired:
+			status = " " + Info("(repaired)")
+		case p.Repairable():
+			status = " " + Silent("(repairable)")
+		}
+		_, _ = fmt.Fprintf(w, "%s %s %s%s\n", Warning(p.Kind), Primary(location), Text(p.Detail), status)
+	}
+	if repairErr != nil {
+		return repairErr
+	}
+
+	_, _ = fmt.Fprintln(w)
+	remaining := 0
+	for _, p := range report.Problems {
+		if !p.Repaired {
+			remaining++
+		}
+	}
+	fixed := len(report.Problems) - remaining
+	switch {
+	case repair:
+		_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("%d problem(s) found, %d repaired, %d need manual attention", len(report.Problems), fixed, remaining)))
+	case report.Repairable() > 0:
+		_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("%d problem(s) found, %d repairable (run 'hourgit fsck --repair')", len(report.Problems), report.Repairable())))
+	default:
+		_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("%d problem(s) found", len(report.Problems))))
+	}
+	return nil
+}
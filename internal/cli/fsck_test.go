@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execFsck(homeDir string, repair bool) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := fsckCmd
+	cmd.SetOut(stdout)
+	err := runFsck(cmd, homeDir, repair)
+	return stdout.String(), err
+}
+
+func TestFsckNoProblems(t *testing.T) {
+	home := t.TempDir()
+
+	stdout, err := execFsck(home, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, "no problems found\n", stdout)
+}
+
+func TestFsckReportsAndRepairs(t *testing.T) {
+	home := t.TempDir()
+	p, err := project.CreateProject(home, "Fsck")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(project.LogDir(home, p.Slug), "bad1111"), []byte("{"), 0644))
+
+	stdout, err := execFsck(home, false)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "corrupt fsck/bad1111")
+	assert.Contains(t, stdout, "(repairable)")
+	assert.Contains(t, stdout, "1 problem(s) found, 1 repairable (run 'hourgit fsck --repair')")
+
+	stdout, err = execFsck(home, true)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "(repaired)")
+	assert.Contains(t, stdout, "1 problem(s) found, 1 repaired, 0 need manual attention")
+
+	stdout, err = execFsck(home, false)
+	require.NoError(t, err)
+	assert.Equal(t, "no problems found\n", stdout)
+}
+
+func TestFsckRegisteredAsSubcommand(t *testing.T) {
+	commands := rootCmd.Commands()
+	names := make([]string, len(commands))
+	for i, cmd := range commands {
+		names[i] = cmd.Name()
+	}
+	assert.Contains(t, names, "fsck")
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"sort"
@@ -64,64 +65,42 @@ func runHistory(cmd *cobra.Command, homeDir, projectFlag string, limit int) erro
 		projects = []project.ProjectEntry{*entry}
 	}
 
-	var items []historyItem
-
+	// Pick the newest records from entry metadata first so only the entries
+	// that will actually be shown are read from disk.
+	type historyRecord struct {
+		rec     entry.IndexRecord
+		slug    string
+		project string
+	}
+	var records []historyRecord
 	for _, proj := range projects {
-		logs, err := entry.ReadAllEntries(homeDir, proj.Slug)
+		metas, err := entry.ListRecords(homeDir, proj.Slug, entry.Query{})
 		if err != nil {
 			return err
 		}
-		for _, e := range logs {
-			detail := entry.FormatMinutes(e.Minutes)
-			if e.Task != "" {
-				detail += "  [" + e.Task + "]"
+		for _, rec := range metas {
+			switch rec.Type {
+			case entry.TypeLog, entry.TypeCheckout, entry.TypeCommit, entry.TypeGitEvent:
+				records = append(records, historyRecord{rec: rec, slug: proj.Slug, project: proj.Name})
 			}
-			if e.Message != "" {
-				if e.Task != "" {
-					detail += " " + e.Message
-				} else {
-					detail += "  " + e.Message
-				}
-			}
-			items = append(items, historyItem{
-				ID:        e.ID,
-				Timestamp: e.CreatedAt,
-				Type:      "log",
-				Project:   proj.Name,
-				Detail:    detail,
-			})
 		}
+	}
 
-		checkouts, err := entry.ReadAllCheckoutEntries(homeDir, proj.Slug)
-		if err != nil {
-			return err
-		}
-		for _, e := range checkouts {
-			items = append(items, historyItem{
-				ID:        e.ID,
-				Timestamp: e.Timestamp,
-				Type:      "checkout",
-				Project:   proj.Name,
-				Detail:    e.Previous + " → " + e.Next,
-			})
-		}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].rec.SortTime().After(records[j].rec.SortTime())
+	})
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
 
-		commits, err := entry.ReadAllCommitEntries(homeDir, proj.Slug)
+	var items []historyItem
+	for _, r := range records {
+		item, ok, err := readHistoryItem(homeDir, r.slug, r.rec.File, r.project)
 		if err != nil {
 			return err
 		}
-		for _, e := range commits {
-			detail := e.Message
-			if e.Branch != "" {
-				detail = "[" + e.Branch + "] " + detail
-			}
-			items = append(items, historyItem{
-				ID:        e.ID,
-				Timestamp: e.Timestamp,
-				Type:      "commit",
-				Project:   proj.Name,
-				Detail:    detail,
-			})
+		if ok {
+			items = append(items, item)
 		}
 	}
 
@@ -134,10 +113,6 @@ func runHistory(cmd *cobra.Command, homeDir, projectFlag string, limit int) erro
 		return items[i].Timestamp.After(items[j].Timestamp)
 	})
 
-	if limit > 0 && len(items) > limit {
-		items = items[:limit]
-	}
-
 	w := cmd.OutOrStdout()
 	for _, item := range items {
 		_, _ = fmt.Fprintf(w, "%s  %s  %s  %s  %s\n",
@@ -151,3 +126,90 @@ func runHistory(cmd *cobra.Command, homeDir, projectFlag string, limit int) erro
 
 	return nil
 }
+
+// readHistoryItem reads a single entry and formats it for display.
+// Returns false if the entry vanished or could not be parsed.
+func readHistoryItem(homeDir, slug, id, projectName string) (historyItem, bool, error) {
+	rec, err := entry.ReadRecord(homeDir, slug, id)
+	if errors.Is(err, entry.ErrNotFound) {
+		return historyItem{}, false, nil
+	}
+	if err != nil {
+		return historyItem{}, false, err
+	}
+	recs := []entry.Record{rec}
+	switch rec.Type {
+	case entry.TypeLog:
+		logs := entry.Decode[entry.Entry](recs, entry.TypeLog)
+		if len(logs) == 0 {
+			return historyItem{}, false, nil
+		}
+		e := logs[0]
+		detail := entry.FormatMinutes(e.Minutes)
+		if e.Task != "" {
+			detail += "  [" + e.Task + "]"
+		}
+		if e.Message != "" {
+			if e.Task != "" {
+				detail += " " + e.Message
+			} else {
+				detail += "  " + e.Message
+			}
+		}
+		return historyItem{
+			ID:        e.ID,
+			Timestamp: e.CreatedAt,
+			Type:      "log",
+			Project:   projectName,
+			Detail:    detail,
+		}, true, nil
+	case entry.TypeCheckout:
+		checkouts := entry.Decode[entry.CheckoutEntry](recs, entry.TypeCheckout)
+		if len(checkouts) == 0 {
+			return historyItem{}, false, nil
+		}
+		e := checkouts[0]
+		return historyItem{
+			ID:        e.ID,
+			Timestamp: e.Timestamp,
+			Type:      "checkout",
+			Project:   projectName,
+			Detail:    e.Previous + " → " + e.Next,
+		}, true, nil
+	case entry.TypeCommit:
+		commits := entry.Decode[entry.CommitEntry](recs, entry.TypeCommit)
+		if len(commits) == 0 {
+			return historyItem{}, false, nil
+		}
+		e := commits[0]
+		detail := e.Message
+		if e.Branch != "" {
+			detail = "[" + e.Branch + "] " + detail
+		}
+		return historyItem{
+			ID:        e.ID,
+			Timestamp: e.Timestamp,
+			Type:      "commit",
+			Project:   projectName,
+			Detail:    detail,
+		}, true, nil
+	case entry.TypeGitEvent:
+		events := entry.Decode[entry.GitEventEntry](recs, entry.TypeGitEvent)
+		if len(events) == 0 {
+			return historyItem{}, false, nil
+		}
+		e := events[0]
+		detail := e.Message
+		if e.Branch != "" {
+			detail = "[" + e.Branch + "] " + detail
+		}
+		return historyItem{
+			ID:        e.ID,
+			Timestamp: e.Timestamp,
+			Type:      e.Kind,
+			Project:   projectName,
+			Detail:    detail,
+		}, true, nil
+	}
+	return historyItem{}, false, nil
+}
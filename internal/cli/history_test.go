@@ -89,6 +89,25 @@ func TestHistoryCheckoutEntries(t *testing.T) {
 	assert.Contains(t, stdout, "History Test")
 }
 
+func TestHistoryGitEventEntries(t *testing.T) {
+	homeDir, proj := setupHistoryTest(t)
+
+	require.NoError(t, entry.WriteGitEventEntry(homeDir, proj.Slug, entry.GitEventEntry{
+		ID:        "e0a1b2c",
+		Timestamp: time.Date(2025, 6, 15, 11, 0, 0, 0, time.UTC),
+		Kind:      entry.EventMerge,
+		Message:   "merge main: Fast-forward",
+		Branch:    "feature-auth",
+	}))
+
+	stdout, err := execHistory(homeDir, "", 50)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "e0a1b2c")
+	assert.Contains(t, stdout, "merge")
+	assert.Contains(t, stdout, "[feature-auth] merge main: Fast-forward")
+}
+
 func TestHistoryMixedChronologicalOrder(t *testing.T) {
 	homeDir, proj := setupHistoryTest(t)
 
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/spf13/cobra"
+)
+
+// printHookManagerSnippet tells the user how to add hourgit's hooks to the
+// hook manager of the repository at repoDir, which would otherwise overwrite
+// or bypass hooks written into the hooks directory.
+func printHookManagerSnippet(cmd *cobra.Command, repoDir string, manager *project.HookManager, binPath string) {
+	config, err := filepath.Rel(repoDir, manager.Config)
+	if err != nil {
+		config = manager.Config
+	}
+	if manager.Name == project.HookManagerHusky {
+		config = ".husky/"
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n\n%s\n",
+		Text(fmt.Sprintf("hooks are managed by %s — add hourgit to %s:", Primary(manager.Name), Primary(config))),
+		hookManagerSnippet(manager.Name, binPath, appVersion))
+}
+
+// hookManagerSnippet returns the config that makes the named hook manager run
+// hourgit's hooks, followed by the command that activates it.
+func hookManagerSnippet(name, binPath, version string) string {
+	var b strings.Builder
+	sync := fmt.Sprintf("%s sync --skip-updates --skip-watcher", binPath)
+
+	switch name {
+	case project.HookManagerHusky:
+		for _, hook := range project.HookNames {
+			_, _ = fmt.Fprintf(&b, "# .husky/%s\n%s\n", hook, hookBody(hook, binPath, version))
+		}
+	case project.HookManagerLefthook:
+		_, _ = fmt.Fprintf(&b, "%s (version: %s)\n", project.HookMarker, version)
+		for _, hook := range project.HookNames {
+			_, _ = fmt.Fprintf(&b, "%s:\n  commands:\n    hourgit:\n      run: %s || true\n", hook, sync)
+		}
+		b.WriteString("\n# then run: lefthook install\n")
+	case project.HookManagerPreCommit:
+		_, _ = fmt.Fprintf(&b, "%s (version: %s)\n", project.HookMarker, version)
+		_, _ = fmt.Fprintf(&b, "- repo: local\n  hooks:\n    - id: hourgit\n      name: hourgit\n      entry: %s\n", sync)
+		_, _ = fmt.Fprintf(&b, "      language: system\n      always_run: true\n      pass_filenames: false\n")
+		_, _ = fmt.Fprintf(&b, "      stages: [%s]\n", strings.Join(project.HookNames, ", "))
+		b.WriteString("\n# then run: pre-commit install")
+		for _, hook := range project.HookNames {
+			b.WriteString(" --hook-type " + hook)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -6,36 +6,128 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/gitdir"
 	"github.com/Flyrell/hourgit/internal/project"
 	"github.com/Flyrell/hourgit/internal/watch"
 	"github.com/spf13/cobra"
 )
 
-func hookScript(binPath, version string) string {
-	return fmt.Sprintf(`#!/bin/sh
-%s (version: %s)
+// hookScript returns the named hook hourgit installs.
+func hookScript(name, binPath, version string) string {
+	return "#!/bin/sh\n" + hookBody(name, binPath, version)
+}
 
-# Only act on branch checkouts (flag=1), skip file checkouts (flag=0)
+// hookBody returns the named hook without its shebang, as hook managers
+// such as husky expect it.
+func hookBody(name, binPath, version string) string {
+	body := fmt.Sprintf("%s (version: %s)\n\n", project.HookMarker, version)
+	if name == "post-checkout" {
+		body += `# Only act on branch checkouts (flag=1), skip file checkouts (flag=0)
 [ "$3" = "0" ] && exit 0
 
 # Skip if old and new HEAD are the same SHA (e.g. pull, fetch)
 [ "$1" = "$2" ] && exit 0
 
-%s sync --skip-updates --skip-watcher 2>/dev/null || true
-`, project.HookMarker, version, binPath)
+`
+	}
+	return body + fmt.Sprintf("%s sync --skip-updates --skip-watcher 2>/dev/null || true\n", binPath)
+}
+
+// installHooks writes hourgit's hooks into hooksDir. Hooks that already
+// carry the marker are kept; any other existing hook is appended to when
+// appendHook is set and overwritten otherwise.
+func installHooks(hooksDir, binPath string, appendHook bool) error {
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return err
+	}
+	for _, name := range project.HookNames {
+		hookPath := filepath.Join(hooksDir, name)
+		hook := hookScript(name, binPath, appVersion)
+
+		existing, err := os.ReadFile(hookPath)
+		if err == nil {
+			if strings.Contains(string(existing), project.HookMarker) {
+				continue
+			}
+			if appendHook {
+				hook = string(existing) + "\n" + hook
+			}
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		if err := fsutil.WriteFileAtomic(hookPath, []byte(hook), 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// missingHooks returns the hooks hourgit installs that hooksDir lacks.
+func missingHooks(hooksDir string) []string {
+	var missing []string
+	for _, name := range project.HookNames {
+		data, err := os.ReadFile(filepath.Join(hooksDir, name))
+		if err != nil || !strings.Contains(string(data), project.HookMarker) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// foreignHooks returns the hooks in hooksDir that exist but are not hourgit's.
+func foreignHooks(hooksDir string) []string {
+	var foreign []string
+	for _, name := range missingHooks(hooksDir) {
+		if _, err := os.Stat(filepath.Join(hooksDir, name)); err == nil {
+			foreign = append(foreign, name)
+		}
+	}
+	return foreign
+}
+
+// ensureHook installs hourgit's hooks in repoDir without prompting: existing
+// hourgit hooks are kept and any other hook is appended to. A repository
+// whose hooks belong to a hook manager is left alone.
+func ensureHook(repoDir, binPath string) error {
+	if project.DetectHookManager(repoDir) != nil {
+		return nil
+	}
+	return installHooks(filepath.Dir(project.HookPath(repoDir, "post-checkout")), binPath, true)
+}
+
+// ensureSubmoduleHooks installs hourgit's hooks in every checked-out
+// submodule of repoDir, so their checkouts are recorded as well, and returns
+// how many submodules there are.
+func ensureSubmoduleHooks(repoDir, binPath string) (int, error) {
+	submodules, err := gitdir.Submodules(repoDir)
+	if err != nil {
+		return 0, err
+	}
+	for _, dir := range submodules {
+		if err := ensureHook(dir, binPath); err != nil {
+			return 0, err
+		}
+	}
+	return len(submodules), nil
 }
 
 var initCmd = LeafCommand{
-	Use:   "init",
+	Use:   "init [DIR]",
 	Short: "Initialize hourgit in a git repository",
+	Args:  cobra.MaximumNArgs(1),
 	StrFlags: []StringFlag{
 		{Name: "project", Shorthand: "p", Usage: "assign repository to a project by name or ID (creates if needed)"},
 		{Name: "mode", Shorthand: "m", Usage: "tracking mode: standard or precise (default: standard)"},
+		{Name: "match-path", Usage: "with --recursive, assign repos below a path to a project, as PREFIX=PROJECT (comma-separated)"},
+		{Name: "match-remote", Usage: "with --recursive, assign repos whose remote URL matches a regex to a project, as PATTERN=PROJECT (comma-separated)"},
 	},
 	BoolFlags: []BoolFlag{
-		{Name: "force", Shorthand: "f", Usage: "overwrite existing post-checkout hook"},
-		{Name: "append", Shorthand: "a", Usage: "append to existing post-checkout hook"},
+		{Name: "force", Shorthand: "f", Usage: "overwrite existing git hooks"},
+		{Name: "append", Shorthand: "a", Usage: "append to existing git hooks"},
 		{Name: "yes", Shorthand: "y", Usage: "skip confirmation prompt"},
+		{Name: "recursive", Shorthand: "r", Usage: "initialize every git repository under DIR (default: current directory)"},
+		{Name: "dry-run", Usage: "with --recursive, show the plan without installing anything"},
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		dir, err := os.Getwd()
@@ -48,6 +140,23 @@ var initCmd = LeafCommand{
 		force, _ := cmd.Flags().GetBool("force")
 		appendHook, _ := cmd.Flags().GetBool("append")
 		yes, _ := cmd.Flags().GetBool("yes")
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		matchPath, _ := cmd.Flags().GetString("match-path")
+		matchRemote, _ := cmd.Flags().GetString("match-remote")
+
+		if !recursive {
+			switch {
+			case len(args) > 0:
+				return fmt.Errorf("a directory argument requires --recursive")
+			case dryRun:
+				return fmt.Errorf("--dry-run requires --recursive")
+			case matchPath != "" || matchRemote != "":
+				return fmt.Errorf("--match-path and --match-remote require --recursive")
+			}
+		} else if modeFlag != "" {
+			return fmt.Errorf("--mode cannot be combined with --recursive (use 'project edit --mode' afterwards)")
+		}
 
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
@@ -66,6 +175,13 @@ var initCmd = LeafCommand{
 		confirm := ResolveConfirmFunc(yes)
 		selectFn := ResolveSelectFunc(yes)
 
+		if recursive {
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			return runInitRecursive(cmd, dir, homeDir, projectName, matchPath, matchRemote, force, appendHook, dryRun, binPath, confirm)
+		}
+
 		return runInit(cmd, dir, homeDir, projectName, modeFlag, force, appendHook, binPath, confirm, selectFn)
 	},
 }.Build()
@@ -79,41 +195,40 @@ func runInit(cmd *cobra.Command, dir, homeDir, projectName, mode string, force,
 		return fmt.Errorf("--mode precise requires --project")
 	}
 
-	gitDir := filepath.Join(dir, ".git")
-	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
-		return fmt.Errorf("not a git repository")
+	// Linked worktrees and submodules share the hooks of their common git
+	// dir, unless core.hooksPath points elsewhere
+	gitDirs, err := gitdir.Resolve(dir)
+	if err != nil {
+		return err
 	}
 
-	hooksDir := filepath.Join(gitDir, "hooks")
-	hookPath := filepath.Join(hooksDir, "post-checkout")
-	hook := hookScript(binPath, appVersion)
-
-	if existing, err := os.ReadFile(hookPath); err == nil {
-		content := string(existing)
+	// A repository whose hooks belong to a hook manager gets hourgit's hooks
+	// through the manager's config instead
+	manager := project.DetectHookManager(dir)
+	installed := project.HookInstalled(dir)
+	if manager == nil {
+		installed = len(missingHooks(gitDirs.Hooks)) == 0
+	}
 
-		if strings.Contains(content, project.HookMarker) {
-			return fmt.Errorf("hourgit is already initialized")
-		}
+	// A linked worktree shares the hooks of the main one, but may still be
+	// assigned a project of its own
+	if installed && (!gitDirs.Worktree() || projectName == "") {
+		return fmt.Errorf("hourgit is already initialized")
+	}
 
-		if !force && !appendHook {
-			return fmt.Errorf("post-checkout hook already exists (use --force to overwrite or --append to append)")
+	switch {
+	case manager != nil:
+		if !installed {
+			printHookManagerSnippet(cmd, dir, manager, binPath)
 		}
-
-		if appendHook {
-			merged := content + "\n" + hook
-			if err := os.WriteFile(hookPath, []byte(merged), 0755); err != nil {
-				return err
-			}
-		} else {
-			if err := os.WriteFile(hookPath, []byte(hook), 0755); err != nil {
-				return err
+	case !installed:
+		if conflicts := foreignHooks(gitDirs.Hooks); len(conflicts) > 0 && !force && !appendHook {
+			if len(conflicts) == 1 {
+				return fmt.Errorf("%s hook already exists (use --force to overwrite or --append to append)", conflicts[0])
 			}
+			return fmt.Errorf("%s hooks already exist (use --force to overwrite or --append to append)", strings.Join(conflicts, ", "))
 		}
-	} else {
-		if err := os.MkdirAll(hooksDir, 0755); err != nil {
-			return err
-		}
-		if err := os.WriteFile(hookPath, []byte(hook), 0755); err != nil {
+		if err := installHooks(gitDirs.Hooks, binPath, appendHook); err != nil {
 			return err
 		}
 	}
@@ -163,6 +278,15 @@ func runInit(cmd *cobra.Command, dir, homeDir, projectName, mode string, force,
 			return err
 		}
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("repository assigned to project '%s'", Primary(result.Entry.Name))))
+
+		if result.Entry.Submodules == project.SubmodulesParent {
+			if n, err := ensureSubmoduleHooks(dir, binPath); err != nil {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s\n",
+					Warning(fmt.Sprintf("warning: could not install hooks in submodules: %s", err)))
+			} else if n > 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("hooks installed in %d submodule(s)", n)))
+			}
+		}
 	}
 
 	_, _ = fmt.Fprintln(cmd.OutOrStdout(), Text("hourgit initialized successfully"))
@@ -0,0 +1,366 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Flyrell/hourgit/internal/gitdir"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/spf13/cobra"
+)
+
+// skipDirs are directories never searched for repositories: git's own and
+// those holding dependencies, which may be checkouts themselves.
+var skipDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true}
+
+// repoMatch assigns repositories to a project by the URL of one of their
+// remotes or by where their work tree is.
+type repoMatch struct {
+	remote  *regexp.Regexp // matches a remote URL
+	path    string         // the work tree is this directory or below it
+	project string         // project name or ID
+}
+
+// matches reports whether the match applies to the work tree repo with the
+// remote URLs urls.
+func (m repoMatch) matches(repo string, urls []string) bool {
+	if m.remote != nil {
+		for _, url := range urls {
+			if m.remote.MatchString(url) {
+				return true
+			}
+		}
+		return false
+	}
+	rel, err := filepath.Rel(m.path, repo)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// parseRepoMatches parses the comma-separated PREFIX=PROJECT pairs of
+// --match-path, relative prefixes being relative to root, followed by the
+// PATTERN=PROJECT pairs of --match-remote.
+func parseRepoMatches(root, paths, remotes string) ([]repoMatch, error) {
+	var matches []repoMatch
+	for _, flag := range []struct{ name, value string }{{"match-path", paths}, {"match-remote", remotes}} {
+		for _, pair := range strings.Split(flag.value, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			pattern, name, ok := strings.Cut(pair, "=")
+			if !ok || pattern == "" || name == "" {
+				return nil, fmt.Errorf("invalid --%s value %q: expected PATTERN=PROJECT", flag.name, pair)
+			}
+			m := repoMatch{project: name}
+			if flag.name == "match-remote" {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("invalid --match-remote pattern %q: %w", pattern, err)
+				}
+				m.remote = re
+			} else if filepath.IsAbs(pattern) {
+				m.path = filepath.Clean(pattern)
+			} else {
+				m.path = filepath.Join(root, pattern)
+			}
+			matches = append(matches, m)
+		}
+	}
+	return matches, nil
+}
+
+// discoverRepos returns the git work trees under root, root included.
+// Submodules are left out: they follow their superproject's submodules
+// policy.
+func discoverRepos(root string) ([]string, error) {
+	var repos []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			// Unreadable directories are skipped
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && skipDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		if _, err := os.Lstat(filepath.Join(path, ".git")); err != nil {
+			return nil
+		}
+		if dirs, err := gitdir.Resolve(path); err == nil && !dirs.Submodule() {
+			repos = append(repos, path)
+		}
+		return nil
+	})
+	return repos, err
+}
+
+// remoteURLs returns the URLs of the remotes of the work tree at dir.
+func remoteURLs(dir string) []string {
+	out, err := exec.Command("git", "-C", dir, "config", "--get-regexp", `^remote\..*\.url$`).Output()
+	if err != nil {
+		return nil
+	}
+	var urls []string
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		if _, url, ok := strings.Cut(line, " "); ok {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// initPlanItem is what a recursive init does to one repository.
+type initPlanItem struct {
+	repo      string                // the work tree
+	project   string                // project name or ID to assign, "" for none
+	entry     *project.ProjectEntry // the project, nil when it is to be created
+	assigned  string                // project the repository is assigned to already
+	hooks     string                // hooks directory
+	manager   *project.HookManager  // hook manager owning the hooks, if any
+	installed bool                  // hourgit's hooks are in place
+	conflicts []string              // existing hooks that are not hourgit's
+}
+
+// skipped reports whether existing hooks keep the repository from being
+// initialized.
+func (it initPlanItem) skipped(force, appendHook bool) bool {
+	return len(it.conflicts) > 0 && !force && !appendHook
+}
+
+// manualHooks reports whether hourgit's hooks have to be added to the
+// config of the repository's hook manager by hand.
+func (it initPlanItem) manualHooks() bool {
+	return it.manager != nil && !it.installed
+}
+
+// reassigns reports whether the repository is assigned to another project
+// than the one matched, which the plan leaves alone.
+func (it initPlanItem) reassigns() bool {
+	if it.assigned == "" || it.project == "" {
+		return false
+	}
+	return it.entry == nil || it.entry.Name != it.assigned
+}
+
+// planInit decides, for each repository under root, the project it is
+// assigned to — per the first match, else fallback — and which hooks to
+// install.
+func planInit(homeDir, root string, matches []repoMatch, fallback string) ([]initPlanItem, error) {
+	repos, err := discoverRepos(root)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := project.ReadConfig(homeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := make([]initPlanItem, 0, len(repos))
+	for _, repo := range repos {
+		it := initPlanItem{repo: repo, project: fallback}
+		urls := remoteURLs(repo)
+		for _, m := range matches {
+			if m.matches(repo, urls) {
+				it.project = m.project
+				break
+			}
+		}
+		if it.project != "" {
+			it.entry = project.ResolveProject(cfg, it.project)
+		}
+		if rc, err := project.ReadRepoConfig(repo); err == nil && rc != nil {
+			it.assigned = rc.Project
+		}
+
+		dirs, err := gitdir.Resolve(repo)
+		if err != nil {
+			return nil, err
+		}
+		it.hooks = dirs.Hooks
+		it.manager = project.DetectHookManager(repo)
+		if it.manager != nil {
+			it.installed = project.HookInstalled(repo)
+		} else {
+			it.installed = len(missingHooks(dirs.Hooks)) == 0
+			it.conflicts = foreignHooks(dirs.Hooks)
+		}
+		plan = append(plan, it)
+	}
+	return plan, nil
+}
+
+// runInitRecursive initializes every repository under root per planInit,
+// after showing the plan. With dryRun it only shows the plan.
+func runInitRecursive(cmd *cobra.Command, root, homeDir, projectName, matchPath, matchRemote string, force, appendHook, dryRun bool, binPath string, confirm ConfirmFunc) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	matches, err := parseRepoMatches(root, matchPath, matchRemote)
+	if err != nil {
+		return err
+	}
+	plan, err := planInit(homeDir, root, matches, projectName)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if len(plan) == 0 {
+		_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("no git repositories found in %s", root)))
+		return nil
+	}
+
+	printInitPlan(cmd, root, plan, force, appendHook)
+
+	if dryRun {
+		_, _ = fmt.Fprintf(w, "\n%s\n", Text("dry run — nothing was installed"))
+		return nil
+	}
+
+	ok, err := confirm(fmt.Sprintf("Initialize %d repo(s)?", len(plan)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		_, _ = fmt.Fprintln(w, Text("initialization cancelled"))
+		return nil
+	}
+
+	initialized, manual, assigned := 0, 0, 0
+	for _, it := range plan {
+		if it.skipped(force, appendHook) {
+			continue
+		}
+		switch {
+		case it.manualHooks():
+			// The hook manager's config has to be edited by hand
+			manual++
+		case it.manager == nil && !it.installed:
+			if err := installHooks(it.hooks, binPath, appendHook); err != nil {
+				return fmt.Errorf("%s: %w", it.repo, err)
+			}
+			initialized++
+		default:
+			initialized++
+		}
+
+		// Assigned repositories keep their project
+		if it.project == "" || it.assigned != "" {
+			continue
+		}
+		// The plan listed the projects to create, so they are not asked for
+		result, err := project.ResolveOrCreate(homeDir, it.project, func(string) (bool, error) { return true, nil })
+		if err != nil {
+			return err
+		}
+		if err := project.AssignProject(homeDir, it.repo, result.Entry); err != nil {
+			return fmt.Errorf("%s: %w", it.repo, err)
+		}
+		assigned++
+
+		if result.Entry.Submodules == project.SubmodulesParent {
+			if _, err := ensureSubmoduleHooks(it.repo, binPath); err != nil {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s\n",
+					Warning(fmt.Sprintf("warning: could not install hooks in submodules of %s: %s", it.repo, err)))
+			}
+		}
+	}
+
+	summary := fmt.Sprintf("initialized %d repo(s), assigned %d to a project", initialized, assigned)
+	if manual > 0 {
+		summary += fmt.Sprintf(", %d need manual hook setup", manual)
+	}
+	_, _ = fmt.Fprintf(w, "\n%s\n", Text(summary))
+	return nil
+}
+
+// printInitPlan prints a table of what a recursive init does to each
+// repository, followed by the repositories it skips or cannot fully set up.
+func printInitPlan(cmd *cobra.Command, root string, plan []initPlanItem, force, appendHook bool) {
+	w := cmd.OutOrStdout()
+
+	type row struct{ repo, project, hooks string }
+	rows := make([]row, len(plan))
+	var skipped, managed, reassigned []initPlanItem
+	repoWidth, projectWidth := len("Repository"), len("Project")
+	for i, it := range plan {
+		r := row{repo: relRepo(root, it.repo), project: "—"}
+		switch {
+		case it.reassigns():
+			r.project = it.assigned + " (kept)"
+			reassigned = append(reassigned, it)
+		case it.assigned != "":
+			r.project = it.assigned
+		case it.entry != nil:
+			r.project = it.entry.Name
+		case it.project != "":
+			r.project = it.project + " (new)"
+		}
+
+		switch {
+		case it.skipped(force, appendHook):
+			r.hooks = "skip: " + strings.Join(it.conflicts, ", ") + " exists"
+			r.project = "—"
+			skipped = append(skipped, it)
+		case it.installed:
+			r.hooks = "installed"
+		case it.manualHooks():
+			r.hooks = "manual: via " + it.manager.Name
+			managed = append(managed, it)
+		case len(it.conflicts) > 0 && appendHook:
+			r.hooks = "install (append)"
+		case len(it.conflicts) > 0:
+			r.hooks = "install (overwrite)"
+		default:
+			r.hooks = "install"
+		}
+
+		rows[i] = r
+		repoWidth = max(repoWidth, len(r.repo))
+		projectWidth = max(projectWidth, len([]rune(r.project)))
+	}
+
+	_, _ = fmt.Fprintf(w, "%s\n", Silent(fmt.Sprintf("%-*s  %-*s  %s", repoWidth, "Repository", projectWidth, "Project", "Hooks")))
+	for _, r := range rows {
+		pad := projectWidth - len([]rune(r.project))
+		_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("%-*s  %s%s  %s", repoWidth, r.repo, r.project, strings.Repeat(" ", pad), r.hooks)))
+	}
+
+	if len(skipped) > 0 {
+		_, _ = fmt.Fprintf(w, "\n%s\n", Warning(fmt.Sprintf("skipping %d repo(s) with hooks of their own (use --force to overwrite or --append to append):", len(skipped))))
+		for _, it := range skipped {
+			_, _ = fmt.Fprintf(w, "  %s %s\n", Text(relRepo(root, it.repo)), Silent(strings.Join(it.conflicts, ", ")))
+		}
+	}
+	if len(managed) > 0 {
+		_, _ = fmt.Fprintf(w, "\n%s\n", Warning(fmt.Sprintf("%d repo(s) need manual hook setup — they use a hook manager (run 'hourgit init' there to get the config to add):", len(managed))))
+		for _, it := range managed {
+			_, _ = fmt.Fprintf(w, "  %s %s\n", Text(relRepo(root, it.repo)), Silent(it.manager.Name))
+		}
+	}
+	if len(reassigned) > 0 {
+		_, _ = fmt.Fprintf(w, "\n%s\n", Warning(fmt.Sprintf("%d repo(s) keep their project (use 'project assign --force' to reassign):", len(reassigned))))
+		for _, it := range reassigned {
+			_, _ = fmt.Fprintf(w, "  %s %s\n", Text(relRepo(root, it.repo)), Silent(fmt.Sprintf("%s, matched %s", it.assigned, it.project)))
+		}
+	}
+}
+
+// relRepo returns repo relative to root, "." for root itself.
+func relRepo(root, repo string) string {
+	if rel, err := filepath.Rel(root, repo); err == nil {
+		return rel
+	}
+	return repo
+}
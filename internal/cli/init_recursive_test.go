@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupRecursiveInit creates a directory of repositories:
+//
+//	acme/api        remote git@github.com:acme/api.git
+//	acme/legacy     remote git@github.com:acme/legacy.git, own post-checkout hook
+//	personal/blog   no remote
+//	personal/blog/node_modules/dep and vendor/dep, which are skipped
+func setupRecursiveInit(t *testing.T) string {
+	t.Helper()
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	git := func(dir string, args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	for _, repo := range []string{"acme/api", "acme/legacy", "personal/blog", "personal/blog/node_modules/dep", "vendor/dep"} {
+		dir := filepath.Join(root, repo)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		git(dir, "init", "-q")
+	}
+	git(filepath.Join(root, "acme", "api"), "remote", "add", "origin", "git@github.com:acme/api.git")
+	git(filepath.Join(root, "acme", "legacy"), "remote", "add", "origin", "git@github.com:acme/legacy.git")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "acme", "legacy", ".git", "hooks", "post-checkout"), []byte("#!/bin/sh\necho legacy\n"), 0755))
+	return root
+}
+
+func execInitRecursive(root, homeDir, projectName, matchPath, matchRemote string, force, dryRun bool) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := initCmd
+	cmd.SetOut(stdout)
+	defer cmd.SetOut(nil)
+	err := runInitRecursive(cmd, root, homeDir, projectName, matchPath, matchRemote, force, false, dryRun, "/usr/local/bin/hourgit", AlwaysYes())
+	return stdout.String(), err
+}
+
+func TestDiscoverRepos(t *testing.T) {
+	root := setupRecursiveInit(t)
+
+	repos, err := discoverRepos(root)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "acme", "api"),
+		filepath.Join(root, "acme", "legacy"),
+		filepath.Join(root, "personal", "blog"),
+	}, repos)
+}
+
+func TestParseRepoMatches(t *testing.T) {
+	matches, err := parseRepoMatches("/src", "personal=Personal, /opt/work=Work", `github\.com:acme/=Acme`)
+	require.NoError(t, err)
+	require.Len(t, matches, 3)
+
+	assert.True(t, matches[0].matches("/src/personal/blog", nil))
+	assert.False(t, matches[0].matches("/src/personally", nil))
+	assert.True(t, matches[1].matches("/opt/work", nil))
+	assert.True(t, matches[2].matches("/anywhere", []string{"https://example.com/x.git", "git@github.com:acme/api.git"}))
+	assert.False(t, matches[2].matches("/anywhere", nil))
+
+	_, err = parseRepoMatches("/src", "personal", "")
+	assert.ErrorContains(t, err, "invalid --match-path value")
+	_, err = parseRepoMatches("/src", "", "(=Acme")
+	assert.ErrorContains(t, err, "invalid --match-remote pattern")
+}
+
+func TestInitRecursiveDryRun(t *testing.T) {
+	homeDir := t.TempDir()
+	root := setupRecursiveInit(t)
+
+	stdout, err := execInitRecursive(root, homeDir, "Misc", "personal=Personal", "acme/=Acme", false, true)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Repository")
+	assert.Regexp(t, `acme/api\s+Acme \(new\)\s+install`, stdout)
+	assert.Regexp(t, `acme/legacy\s+—\s+skip: post-checkout exists`, stdout)
+	assert.Regexp(t, `personal/blog\s+Personal \(new\)\s+install`, stdout)
+	assert.NotContains(t, stdout, "node_modules")
+	assert.Contains(t, stdout, "skipping 1 repo(s) with hooks of their own")
+	assert.Contains(t, stdout, "dry run — nothing was installed")
+
+	_, err = os.Stat(filepath.Join(root, "acme", "api", ".git", "hooks", "post-checkout"))
+	assert.True(t, os.IsNotExist(err))
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Projects)
+}
+
+func TestInitRecursive(t *testing.T) {
+	homeDir := t.TempDir()
+	root := setupRecursiveInit(t)
+
+	stdout, err := execInitRecursive(root, homeDir, "", "personal=Personal", "acme/=Acme", false, false)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "initialized 2 repo(s), assigned 2 to a project")
+
+	for repo, name := range map[string]string{"acme/api": "Acme", "personal/blog": "Personal"} {
+		dir := filepath.Join(root, repo)
+		for _, hook := range project.HookNames {
+			content, err := os.ReadFile(filepath.Join(dir, ".git", "hooks", hook))
+			require.NoError(t, err, repo+" "+hook)
+			assert.Contains(t, string(content), project.HookMarker)
+		}
+		rc, err := project.ReadRepoConfig(dir)
+		require.NoError(t, err)
+		require.NotNil(t, rc, repo)
+		assert.Equal(t, name, rc.Project)
+	}
+
+	// The repository with a hook of its own is left alone
+	content, err := os.ReadFile(filepath.Join(root, "acme", "legacy", ".git", "hooks", "post-checkout"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), project.HookMarker)
+	rc, err := project.ReadRepoConfig(filepath.Join(root, "acme", "legacy"))
+	require.NoError(t, err)
+	assert.Nil(t, rc)
+
+	// A second run finds everything in place and keeps the assignments
+	stdout, err = execInitRecursive(root, homeDir, "Misc", "", "", true, true)
+	require.NoError(t, err)
+	assert.Regexp(t, `acme/api\s+Acme \(kept\)\s+installed`, stdout)
+	assert.Regexp(t, `acme/legacy\s+Misc \(new\)\s+install \(overwrite\)`, stdout)
+	assert.Contains(t, stdout, "2 repo(s) keep their project")
+}
+
+func TestInitRecursiveHookManagerNeedsManualSetup(t *testing.T) {
+	homeDir := t.TempDir()
+	root := setupRecursiveInit(t)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "personal", "blog", "lefthook.yml"), []byte("pre-commit:\n"), 0644))
+
+	stdout, err := execInitRecursive(root, homeDir, "", "personal=Personal", "acme/=Acme", false, false)
+
+	require.NoError(t, err)
+	assert.Regexp(t, `personal/blog\s+Personal \(new\)\s+manual: via lefthook`, stdout)
+	assert.Contains(t, stdout, "1 repo(s) need manual hook setup")
+	assert.Contains(t, stdout, "initialized 1 repo(s), assigned 2 to a project, 1 need manual hook setup")
+
+	// The hook manager's hooks are left alone, the project is assigned
+	_, err = os.Stat(filepath.Join(root, "personal", "blog", ".git", "hooks", "post-checkout"))
+	assert.True(t, os.IsNotExist(err))
+	rc, err := project.ReadRepoConfig(filepath.Join(root, "personal", "blog"))
+	require.NoError(t, err)
+	require.NotNil(t, rc)
+	assert.Equal(t, "Personal", rc.Project)
+}
+
+func TestInitRecursiveFlagsRequireRecursive(t *testing.T) {
+	_, cleanup := setupInitTest(t)
+	defer cleanup()
+	// initCmd is shared, so its flags keep their values between tests
+	t.Cleanup(func() {
+		for _, name := range []string{"recursive", "dry-run", "mode"} {
+			require.NoError(t, initCmd.Flags().Lookup(name).Value.Set(initCmd.Flags().Lookup(name).DefValue))
+		}
+	})
+
+	_, stderr, err := execInit("--dry-run")
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "--dry-run requires --recursive")
+
+	_, stderr, err = execInit("--recursive", "--mode", "precise")
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "--mode cannot be combined with --recursive")
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
@@ -44,6 +45,7 @@ func execInitDirect(dir, homeDir, projectName, mode string, force, appendHook bo
 	stdout := new(bytes.Buffer)
 	cmd := initCmd
 	cmd.SetOut(stdout)
+	defer cmd.SetOut(nil)
 	err := runInit(cmd, dir, homeDir, projectName, mode, force, appendHook, binPath, confirm, selectFn)
 	return stdout.String(), err
 }
@@ -71,6 +73,12 @@ func TestInitInGitRepo(t *testing.T) {
 	info, err := os.Stat(hookPath)
 	require.NoError(t, err)
 	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	for _, name := range project.HookNames {
+		content, err := os.ReadFile(filepath.Join(dir, ".git", "hooks", name))
+		require.NoError(t, err, name)
+		assert.Contains(t, string(content), project.HookMarker)
+	}
 }
 
 func TestInitNotGitRepo(t *testing.T) {
@@ -89,7 +97,9 @@ func TestInitAlreadyInitialized(t *testing.T) {
 
 	hooksDir := filepath.Join(dir, ".git", "hooks")
 	require.NoError(t, os.MkdirAll(hooksDir, 0755))
-	require.NoError(t, os.WriteFile(filepath.Join(hooksDir, "post-checkout"), []byte("#!/bin/sh\n"+project.HookMarker+"\n"), 0755))
+	for _, name := range project.HookNames {
+		require.NoError(t, os.WriteFile(filepath.Join(hooksDir, name), []byte("#!/bin/sh\n"+project.HookMarker+"\n"), 0755))
+	}
 
 	_, stderr, err := execInit()
 
@@ -97,6 +107,32 @@ func TestInitAlreadyInitialized(t *testing.T) {
 	assert.Contains(t, stderr, "hourgit is already initialized")
 }
 
+func TestInitAddsMissingHooks(t *testing.T) {
+	dir, cleanup := setupInitTest(t)
+	defer cleanup()
+	t.Setenv("SHELL", "")
+
+	// An installation from before post-commit, post-merge and post-rewrite
+	hooksDir := filepath.Join(dir, ".git", "hooks")
+	require.NoError(t, os.MkdirAll(hooksDir, 0755))
+	old := "#!/bin/sh\n" + project.HookMarker + " (version: 1.0.0)\n"
+	require.NoError(t, os.WriteFile(filepath.Join(hooksDir, "post-checkout"), []byte(old), 0755))
+
+	stdout, _, err := execInit()
+
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "hourgit initialized successfully")
+
+	content, err := os.ReadFile(filepath.Join(hooksDir, "post-checkout"))
+	require.NoError(t, err)
+	assert.Equal(t, old, string(content))
+	for _, name := range []string{"post-commit", "post-merge", "post-rewrite"} {
+		content, err := os.ReadFile(filepath.Join(hooksDir, name))
+		require.NoError(t, err, name)
+		assert.Contains(t, string(content), project.HookMarker)
+	}
+}
+
 func TestInitHookExistsNoFlag(t *testing.T) {
 	dir, cleanup := setupInitTest(t)
 	defer cleanup()
@@ -111,6 +147,24 @@ func TestInitHookExistsNoFlag(t *testing.T) {
 	assert.Contains(t, stderr, "post-checkout hook already exists (use --force to overwrite or --append to append)")
 }
 
+func TestInitHooksExistNoFlag(t *testing.T) {
+	dir, cleanup := setupInitTest(t)
+	defer cleanup()
+
+	hooksDir := filepath.Join(dir, ".git", "hooks")
+	require.NoError(t, os.MkdirAll(hooksDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(hooksDir, "post-commit"), []byte("#!/bin/sh\necho existing"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(hooksDir, "post-merge"), []byte("#!/bin/sh\necho existing"), 0755))
+
+	_, stderr, err := execInit()
+
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "post-commit, post-merge hooks already exist (use --force to overwrite or --append to append)")
+
+	_, err = os.Stat(filepath.Join(hooksDir, "post-checkout"))
+	assert.True(t, os.IsNotExist(err))
+}
+
 func TestInitHookExistsForce(t *testing.T) {
 	dir, cleanup := setupInitTest(t)
 	defer cleanup()
@@ -151,6 +205,55 @@ func TestInitHookExistsAppend(t *testing.T) {
 	assert.Contains(t, string(content), project.HookMarker)
 }
 
+func TestInitHooksPath(t *testing.T) {
+	dir, cleanup := setupInitTest(t)
+	defer cleanup()
+	t.Setenv("SHELL", "")
+
+	for _, args := range [][]string{{"init", "-q"}, {"config", "core.hooksPath", ".githooks"}} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	stdout, _, err := execInit()
+
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "hourgit initialized successfully")
+	for _, name := range project.HookNames {
+		content, err := os.ReadFile(filepath.Join(dir, ".githooks", name))
+		require.NoError(t, err, name)
+		assert.Contains(t, string(content), project.HookMarker)
+	}
+	_, err = os.Stat(filepath.Join(dir, ".git", "hooks", "post-checkout"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestInitHookManager(t *testing.T) {
+	dir, cleanup := setupInitTest(t)
+	defer cleanup()
+	t.Setenv("SHELL", "")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git", "hooks"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lefthook.yml"), []byte("pre-commit:\n"), 0644))
+
+	stdout, _, err := execInit()
+
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "hooks are managed by lefthook")
+	assert.Contains(t, stdout, "lefthook install")
+	assert.Contains(t, stdout, "hourgit initialized successfully")
+	_, err = os.Stat(filepath.Join(dir, ".git", "hooks", "post-checkout"))
+	assert.True(t, os.IsNotExist(err))
+
+	// Once the snippet is in the config, hourgit is initialized
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lefthook.yml"), []byte(hookManagerSnippet(project.HookManagerLefthook, "hourgit", "1.0.0")), 0644))
+
+	_, stderr, err := execInit()
+
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "hourgit is already initialized")
+}
+
 func TestInitWithProjectFlag(t *testing.T) {
 	dir, cleanup := setupInitTest(t)
 	defer cleanup()
@@ -185,6 +288,34 @@ func TestInitWithProjectFlag(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestInitInLinkedWorktree(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("SHELL", "")
+	main := t.TempDir()
+	gitDir := filepath.Join(main, ".git", "worktrees", "wt")
+	require.NoError(t, os.MkdirAll(gitDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "commondir"), []byte("../..\n"), 0644))
+	wt := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+gitDir+"\n"), 0644))
+
+	_, err := execInitDirect(wt, home, "", "", false, false, "/usr/local/bin/hourgit", AlwaysYes(), nil)
+	require.NoError(t, err)
+
+	// The hook goes to the hooks shared by all worktrees
+	content, err := os.ReadFile(filepath.Join(main, ".git", "hooks", "post-checkout"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), project.HookMarker)
+
+	// The hook exists now, but the worktree can still be assigned a project
+	stdout, err := execInitDirect(wt, home, "My Project", "", false, false, "/usr/local/bin/hourgit", AlwaysYes(), nil)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "repository assigned to project 'My Project'")
+	assert.FileExists(t, filepath.Join(gitDir, ".hourgit"))
+
+	_, err = execInitDirect(main, home, "", "", false, false, "/usr/local/bin/hourgit", AlwaysYes(), nil)
+	assert.ErrorContains(t, err, "hourgit is already initialized")
+}
+
 func TestInitWithProjectFlagByID(t *testing.T) {
 	dir, cleanup := setupInitTest(t)
 	defer cleanup()
@@ -447,7 +578,7 @@ func TestInitCreateHooksDir(t *testing.T) {
 }
 
 func TestHookScript(t *testing.T) {
-	script := hookScript("/usr/local/bin/hourgit", "1.2.3")
+	script := hookScript("post-checkout", "/usr/local/bin/hourgit", "1.2.3")
 
 	assert.Contains(t, script, "#!/bin/sh")
 	assert.Contains(t, script, project.HookMarker)
@@ -460,6 +591,30 @@ func TestHookScript(t *testing.T) {
 	assert.NotContains(t, script, `git symbolic-ref`)
 	assert.NotContains(t, script, `git rev-parse --git-dir`)
 	assert.NotContains(t, script, `rebase-merge`)
+
+	for _, name := range []string{"post-commit", "post-merge", "post-rewrite"} {
+		script := hookScript(name, "/usr/local/bin/hourgit", "1.2.3")
+		assert.Contains(t, script, project.HookMarker)
+		assert.Contains(t, script, `/usr/local/bin/hourgit sync --skip-updates --skip-watcher`)
+		assert.NotContains(t, script, `[ "$3" = "0" ]`, name)
+	}
+}
+
+func TestHookManagerSnippet(t *testing.T) {
+	husky := hookManagerSnippet(project.HookManagerHusky, "/usr/local/bin/hourgit", "1.2.3")
+	for _, name := range project.HookNames {
+		assert.Contains(t, husky, "# .husky/"+name)
+	}
+	assert.NotContains(t, husky, "#!/bin/sh")
+
+	lefthook := hookManagerSnippet(project.HookManagerLefthook, "/usr/local/bin/hourgit", "1.2.3")
+	assert.Contains(t, lefthook, project.HookMarker)
+	assert.Contains(t, lefthook, "post-rewrite:\n  commands:\n    hourgit:\n      run: /usr/local/bin/hourgit sync --skip-updates --skip-watcher || true")
+
+	preCommit := hookManagerSnippet(project.HookManagerPreCommit, "/usr/local/bin/hourgit", "1.2.3")
+	assert.Contains(t, preCommit, project.HookMarker)
+	assert.Contains(t, preCommit, "stages: [post-checkout, post-commit, post-merge, post-rewrite]")
+	assert.Contains(t, preCommit, "pre-commit install --hook-type post-checkout --hook-type post-commit")
 }
 
 func TestInitRegistered(t *testing.T) {
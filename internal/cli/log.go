@@ -9,5 +9,6 @@ var logCmd = GroupCommand{
 		logAddCmd,
 		logEditCmd,
 		logRemoveCmd,
+		logShowCmd,
 	},
 }.Build()
@@ -75,7 +75,7 @@ func runLogAdd(
 		return fmt.Errorf("--duration and --from/--to are mutually exclusive")
 	}
 
-	now := nowFn()
+	now := inProjectZone(proj, nowFn())
 
 	// 2. Resolve date: use flag if provided, otherwise prompt
 	if dateFlag == "" && !hasDuration && !hasFrom && !hasTo && message == "" {
@@ -169,13 +169,13 @@ func runLogAdd(
 	return writeAndPrintEntry(cmd, homeDir, proj, start, minutes, message, taskFlag, now)
 }
 
-// resolveBaseDate parses the --date flag value into a date.
+// resolveBaseDate parses the --date flag value into a date in now's time zone.
 // If dateFlag is empty, returns now (today).
 func resolveBaseDate(dateFlag string, now time.Time) (time.Time, error) {
 	if dateFlag == "" {
 		return now, nil
 	}
-	d, err := time.Parse("2006-01-02", dateFlag)
+	d, err := time.ParseInLocation("2006-01-02", dateFlag, now.Location())
 	if err != nil {
 		return time.Time{}, fmt.Errorf("invalid --date format, expected YYYY-MM-DD: %w", err)
 	}
@@ -283,9 +283,7 @@ func getDayScheduleWindows(homeDir string, proj *project.ProjectEntry, entryStar
 
 	scheduledMinutes := 0
 	for _, w := range dayWindows {
-		fromMins := w.From.Hour*60 + w.From.Minute
-		toMins := w.To.Hour*60 + w.To.Minute
-		scheduledMinutes += toMins - fromMins
+		scheduledMinutes += w.Minutes()
 	}
 
 	return dayWindows, scheduledMinutes, daySchedules, nil
@@ -305,8 +303,8 @@ func checkBoundsWarning(
 
 	overlapMins := 0
 	for _, w := range windows {
-		wFrom := w.From.Hour*60 + w.From.Minute
-		wTo := w.To.Hour*60 + w.To.Minute
+		wFrom := w.From.Minutes()
+		wTo := w.End()
 		overlap := min(entryToMins, wTo) - max(entryFromMins, wFrom)
 		if overlap > 0 {
 			overlapMins += overlap
@@ -420,9 +418,14 @@ func writeAndPrintEntry(
 	message, task string,
 	now time.Time,
 ) error {
+	id, err := entry.NewID(homeDir, proj.Slug, hashutil.TimeSeed("log"))
+	if err != nil {
+		return err
+	}
+
 	e := entry.Entry{
-		ID:        hashutil.GenerateID("log"),
-		Start:     start.UTC(),
+		ID:        id,
+		Start:     start,
 		Minutes:   minutes,
 		Message:   message,
 		Task:      task,
@@ -824,3 +824,21 @@ func TestLogAddWithTask(t *testing.T) {
 	assert.Equal(t, "research", entries[0].Task)
 	assert.Equal(t, "read documentation", entries[0].Message)
 }
+
+func TestLogAddUsesProjectTimezone(t *testing.T) {
+	homeDir, repoDir, proj := setupLogAddTest(t)
+	require.NoError(t, project.SetTimezone(homeDir, proj.ID, "Asia/Tokyo"))
+
+	// fixedNow is 14:00 UTC, which is 23:00 in Tokyo
+	_, err := execLogAdd(homeDir, repoDir, "", "", "9am", "10am", "2025-06-16", "", "standup")
+	require.NoError(t, err)
+
+	entries, err := entry.ReadAllEntries(homeDir, proj.Slug)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 9, entries[0].Start.Hour())
+	assert.Equal(t, 16, entries[0].Start.Day())
+	_, offset := entries[0].Start.Zone()
+	assert.Equal(t, 9*60*60, offset, "stored with the project's offset")
+	assert.True(t, entries[0].Start.Equal(time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)))
+}
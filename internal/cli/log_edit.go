@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -139,7 +140,8 @@ func runLogEdit(
 	return nil
 }
 
-// locateEntry finds the entry by hash, trying project flag, repo context, then scanning all projects.
+// locateEntry finds the entry by hash or unique hash prefix, trying project flag, repo context,
+// then scanning all projects.
 // Returns the slug, project entry (may be nil for cross-project scan), entry, and error.
 func locateEntry(homeDir, repoDir, projectFlag, hash string) (string, *project.ProjectEntry, entry.Entry, error) {
 	// Try project flag first
@@ -152,6 +154,10 @@ func locateEntry(homeDir, repoDir, projectFlag, hash string) (string, *project.P
 		if proj == nil {
 			return "", nil, entry.Entry{}, fmt.Errorf("project '%s' not found", projectFlag)
 		}
+		hash, err := resolveEntryID(homeDir, proj.Slug, hash)
+		if err != nil {
+			return "", nil, entry.Entry{}, err
+		}
 		e, err := entry.ReadEntry(homeDir, proj.Slug, hash)
 		if err != nil {
 			if entry.IsCheckoutEntry(homeDir, proj.Slug, hash) {
@@ -166,12 +172,16 @@ func locateEntry(homeDir, repoDir, projectFlag, hash string) (string, *project.P
 	if repoDir != "" {
 		proj, err := ResolveProjectContext(homeDir, repoDir, "")
 		if err == nil {
-			e, err := entry.ReadEntry(homeDir, proj.Slug, hash)
+			id, err := resolveEntryID(homeDir, proj.Slug, hash)
+			if err != nil {
+				return "", nil, entry.Entry{}, err
+			}
+			e, err := entry.ReadEntry(homeDir, proj.Slug, id)
 			if err == nil {
 				return proj.Slug, proj, e, nil
 			}
-			if entry.IsCheckoutEntry(homeDir, proj.Slug, hash) {
-				return "", nil, entry.Entry{}, fmt.Errorf("entry '%s' is a checkout entry and cannot be edited", hash)
+			if entry.IsCheckoutEntry(homeDir, proj.Slug, id) {
+				return "", nil, entry.Entry{}, fmt.Errorf("entry '%s' is a checkout entry and cannot be edited", id)
 			}
 		}
 	}
@@ -191,6 +201,17 @@ func locateEntry(homeDir, repoDir, projectFlag, hash string) (string, *project.P
 	return found.Slug, proj, found.Entry, nil
 }
 
+// resolveEntryID expands a unique ID prefix to the full entry ID within a project.
+// Unknown IDs are returned unchanged so callers report them as not found; ambiguous
+// prefixes are an error.
+func resolveEntryID(homeDir, slug, hash string) (string, error) {
+	id, err := entry.ResolveID(homeDir, slug, hash)
+	if errors.Is(err, entry.ErrNotFound) {
+		return hash, nil
+	}
+	return id, err
+}
+
 // findProjectBySlug looks up a project by its slug.
 func findProjectBySlug(cfg *project.Config, slug string) *project.ProjectEntry {
 	for i := range cfg.Projects {
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/Flyrell/hourgit/internal/entry"
@@ -39,7 +40,7 @@ var logRemoveCmd = LeafCommand{
 }.Build()
 
 func runLogRemove(cmd *cobra.Command, homeDir, repoDir, projectFlag, hash string, confirm ConfirmFunc) error {
-	slug, entryType, detail, err := locateAnyEntry(homeDir, repoDir, projectFlag, hash)
+	slug, id, entryType, detail, err := locateAnyEntry(homeDir, repoDir, projectFlag, hash)
 	if err != nil {
 		return err
 	}
@@ -59,26 +60,26 @@ func runLogRemove(cmd *cobra.Command, homeDir, repoDir, projectFlag, hash string
 		}
 	}
 
-	if err := entry.DeleteEntry(homeDir, slug, hash); err != nil {
+	if err := entry.DeleteEntry(homeDir, slug, id); err != nil {
 		return err
 	}
 
-	_, _ = fmt.Fprintf(w, "removed entry %s\n", Silent(hash))
+	_, _ = fmt.Fprintf(w, "removed entry %s\n", Silent(id))
 	return nil
 }
 
-// locateAnyEntry finds any entry (log or checkout) by hash, trying project flag,
-// repo context, then scanning all projects.
-func locateAnyEntry(homeDir, repoDir, projectFlag, hash string) (slug, entryType, detail string, err error) {
+// locateAnyEntry finds any entry (log or checkout) by hash or unique hash prefix,
+// trying project flag, repo context, then scanning all projects.
+func locateAnyEntry(homeDir, repoDir, projectFlag, hash string) (slug, id, entryType, detail string, err error) {
 	// Try project flag first
 	if projectFlag != "" {
 		cfg, err := project.ReadConfig(homeDir)
 		if err != nil {
-			return "", "", "", err
+			return "", "", "", "", err
 		}
 		proj := project.ResolveProject(cfg, projectFlag)
 		if proj == nil {
-			return "", "", "", fmt.Errorf("project '%s' not found", projectFlag)
+			return "", "", "", "", fmt.Errorf("project '%s' not found", projectFlag)
 		}
 		return locateAnyEntryInProject(homeDir, proj.Slug, hash)
 	}
@@ -87,9 +88,13 @@ func locateAnyEntry(homeDir, repoDir, projectFlag, hash string) (slug, entryType
 	if repoDir != "" {
 		proj, err := ResolveProjectContext(homeDir, repoDir, "")
 		if err == nil {
-			s, t, d, err := locateAnyEntryInProject(homeDir, proj.Slug, hash)
+			s, i, t, d, err := locateAnyEntryInProject(homeDir, proj.Slug, hash)
 			if err == nil {
-				return s, t, d, nil
+				return s, i, t, d, nil
+			}
+			var ambiguous *entry.AmbiguousIDError
+			if errors.As(err, &ambiguous) {
+				return "", "", "", "", err
 			}
 		}
 	}
@@ -97,30 +102,29 @@ func locateAnyEntry(homeDir, repoDir, projectFlag, hash string) (slug, entryType
 	// Scan all projects
 	found, err := entry.FindAnyEntryAcrossProjects(homeDir, hash)
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", "", err
 	}
-	return found.Slug, found.Type, found.Detail, nil
+	return found.Slug, found.ID, found.Type, found.Detail, nil
 }
 
 // locateAnyEntryInProject tries to find a log or checkout entry in a specific project.
-func locateAnyEntryInProject(homeDir, slug, hash string) (string, string, string, error) {
+func locateAnyEntryInProject(homeDir, slug, hash string) (string, string, string, string, error) {
+	id, err := resolveEntryID(homeDir, slug, hash)
+	if err != nil {
+		return "", "", "", "", err
+	}
+
 	// Try as log entry
-	e, err := entry.ReadEntry(homeDir, slug, hash)
+	e, err := entry.ReadEntry(homeDir, slug, id)
 	if err == nil {
-		detail := fmt.Sprintf("%s — %s", entry.FormatMinutes(e.Minutes), e.Message)
-		if e.Task != "" {
-			detail = fmt.Sprintf("[%s] %s", e.Task, detail)
-		}
-		return slug, entry.TypeLog, detail, nil
+		return slug, id, entry.TypeLog, entry.DescribeEntry(e), nil
 	}
 
 	// Try as checkout entry
-	ce, err := entry.ReadCheckoutEntry(homeDir, slug, hash)
+	ce, err := entry.ReadCheckoutEntry(homeDir, slug, id)
 	if err == nil {
-		detail := fmt.Sprintf("%s → %s at %s",
-			ce.Previous, ce.Next, ce.Timestamp.Format("2006-01-02 15:04"))
-		return slug, entry.TypeCheckout, detail, nil
+		return slug, id, entry.TypeCheckout, entry.DescribeCheckoutEntry(ce), nil
 	}
 
-	return "", "", "", fmt.Errorf("entry '%s' not found", hash)
+	return "", "", "", "", fmt.Errorf("entry '%s' not found", hash)
 }
@@ -173,3 +173,38 @@ func TestLogRemoveRegisteredAsSubcommand(t *testing.T) {
 	}
 	assert.Contains(t, names, "remove")
 }
+
+func TestLogRemoveByPrefix(t *testing.T) {
+	homeDir, repoDir, proj, _, _ := setupLogRemoveTest(t)
+
+	stdout, err := execLogRemove(homeDir, repoDir, "", "0010", AlwaysYes())
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "removed entry 0010012")
+
+	_, err = entry.ReadEntry(homeDir, proj.Slug, "0010012")
+	assert.Error(t, err)
+}
+
+func TestLogRemoveAmbiguousPrefix(t *testing.T) {
+	homeDir, repoDir, proj, _, _ := setupLogRemoveTest(t)
+
+	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, entry.Entry{
+		ID:      "00c0012aaaaa",
+		Start:   time.Date(2025, 6, 17, 9, 0, 0, 0, time.UTC),
+		Minutes: 30,
+		Message: "other",
+	}))
+
+	stdout, err := execLogRemove(homeDir, repoDir, "", "00c0", AlwaysYes())
+	assert.EqualError(t, err, "entry ID '00c0' is ambiguous, it matches: 00c0012, 00c0012aaaaa")
+	assert.Empty(t, stdout)
+
+	_, err = execLogRemove(homeDir, "", proj.Name, "00c0", AlwaysYes())
+	assert.Error(t, err)
+
+	// The exact legacy ID still resolves
+	stdout, err = execLogRemove(homeDir, repoDir, "", "00c0012", AlwaysYes())
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "removed entry 00c0012")
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var logShowCmd = LeafCommand{
+	Use:   "show <hash>",
+	Short: "Show an entry and, optionally, every revision of it",
+	Args:  cobra.ExactArgs(1),
+	BoolFlags: []BoolFlag{
+		{Name: "history", Usage: "list every recorded revision of the entry"},
+	},
+	StrFlags: []StringFlag{
+		{Name: "project", Shorthand: "p", Usage: "project name or ID (searches all projects if omitted)"},
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, _, err := getContextPaths()
+		if err != nil {
+			return err
+		}
+
+		projectFlag, _ := cmd.Flags().GetString("project")
+		historyFlag, _ := cmd.Flags().GetBool("history")
+
+		return runLogShow(cmd, homeDir, projectFlag, args[0], historyFlag)
+	},
+}.Build()
+
+func runLogShow(cmd *cobra.Command, homeDir, projectFlag, hash string, history bool) error {
+	slug, id, err := locateEntryRevision(homeDir, projectFlag, hash)
+	if err != nil {
+		return err
+	}
+	changes, err := journal.EntryHistory(homeDir, slug, id)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(w, "  id:      %s\n", Primary(id))
+	_, _ = fmt.Fprintf(w, "  project: %s\n", Primary(slug))
+	rec, err := entry.ReadRecord(homeDir, slug, id)
+	switch {
+	case err == nil:
+		_, _ = fmt.Fprintf(w, "  type:    %s\n", Primary(rec.Type))
+		_, _ = fmt.Fprintf(w, "  detail:  %s\n", Primary(entry.DescribeRecord(rec.Data)))
+	case errors.Is(err, entry.ErrNotFound) && len(changes) > 0:
+		last := changes[len(changes)-1]
+		snapshot := last.After
+		if snapshot == nil {
+			snapshot = last.Before
+		}
+		_, _ = fmt.Fprintf(w, "  status:  %s\n", Warning("deleted"))
+		_, _ = fmt.Fprintf(w, "  detail:  %s\n", Primary(entry.DescribeRecord(snapshot)))
+	default:
+		return err
+	}
+
+	if !history {
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(w)
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintln(w, Silent("no recorded revisions"))
+		return nil
+	}
+	for _, c := range changes {
+		action, snapshot := "updated", c.After
+		switch {
+		case c.Before == nil:
+			action = "created"
+		case c.After == nil:
+			action, snapshot = "deleted", c.Before
+		}
+		if c.Reverts != "" {
+			action += " (undo)"
+		}
+		_, _ = fmt.Fprintf(w, "%s %s %s %s\n",
+			Silent(c.Time.Local().Format("2006-01-02 15:04:05")),
+			Info(action),
+			Text(entry.DescribeRecord(snapshot)),
+			Silent(fmt.Sprintf("by %s via '%s'", c.User, c.Command)))
+	}
+	return nil
+}
+
+// locateEntryRevision finds an entry by hash or unique hash prefix among
+// stored entries or, for deleted ones, in the journal.
+func locateEntryRevision(homeDir, projectFlag, hash string) (string, string, error) {
+	slug := ""
+	if projectFlag != "" {
+		cfg, err := project.ReadConfig(homeDir)
+		if err != nil {
+			return "", "", err
+		}
+		proj := project.ResolveProject(cfg, projectFlag)
+		if proj == nil {
+			return "", "", fmt.Errorf("project '%s' not found", projectFlag)
+		}
+		slug = proj.Slug
+
+		id, err := resolveEntryID(homeDir, slug, hash)
+		if err != nil {
+			return "", "", err
+		}
+		if _, err := entry.ReadRecord(homeDir, slug, id); err == nil {
+			return slug, id, nil
+		}
+	} else {
+		found, err := entry.FindRecordAcrossProjects(homeDir, hash)
+		if err != nil {
+			return "", "", err
+		}
+		if found != nil {
+			return found.Slug, found.File, nil
+		}
+	}
+
+	refs, err := journal.FindEntries(homeDir, hash)
+	if err != nil {
+		return "", "", err
+	}
+	var matches []journal.EntryRef
+	for _, ref := range refs {
+		if slug == "" || ref.Slug == slug {
+			matches = append(matches, ref)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", "", fmt.Errorf("entry '%s' not found", hash)
+	case 1:
+		return matches[0].Slug, matches[0].ID, nil
+	}
+	ambiguous := &entry.AmbiguousIDError{Prefix: hash}
+	for _, ref := range matches {
+		ambiguous.Matches = append(ambiguous.Matches, fmt.Sprintf("%s (%s)", ref.ID, ref.Slug))
+	}
+	return "", "", ambiguous
+}
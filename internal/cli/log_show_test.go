@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execLogShow(homeDir, projectFlag, hash string, history bool) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := logShowCmd
+	cmd.SetOut(stdout)
+
+	err := runLogShow(cmd, homeDir, projectFlag, hash, history)
+	return stdout.String(), err
+}
+
+func TestLogShowEntry(t *testing.T) {
+	homeDir, _, proj, _, _ := setupLogRemoveTest(t)
+
+	stdout, err := execLogShow(homeDir, "", "0010012", false)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "0010012")
+	assert.Contains(t, stdout, proj.Slug)
+	assert.Contains(t, stdout, "log")
+	assert.Contains(t, stdout, "test work")
+}
+
+func TestLogShowHistory(t *testing.T) {
+	homeDir, _, proj, le, _ := setupLogRemoveTest(t)
+
+	journal.Begin("log edit")
+	edited := le
+	edited.Message = "edited work"
+	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, edited))
+
+	stdout, err := execLogShow(homeDir, proj.Name, "0010", true)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "created")
+	assert.Contains(t, stdout, "updated")
+	assert.Contains(t, stdout, "edited work")
+	assert.Contains(t, stdout, "via 'log edit'")
+}
+
+func TestLogShowDeletedEntry(t *testing.T) {
+	homeDir, _, proj, le, _ := setupLogRemoveTest(t)
+
+	journal.Begin("log remove")
+	require.NoError(t, entry.DeleteEntry(homeDir, proj.Slug, le.ID))
+
+	stdout, err := execLogShow(homeDir, "", "0010012", true)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "deleted")
+	assert.Contains(t, stdout, "test work")
+	assert.Contains(t, stdout, "via 'log remove'")
+}
+
+func TestLogShowNotFound(t *testing.T) {
+	homeDir, _, _, _, _ := setupLogRemoveTest(t)
+
+	_, err := execLogShow(homeDir, "", "ffff999", false)
+
+	assert.EqualError(t, err, "entry 'ffff999' not found")
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Flyrell/hourgit/internal/paths"
+	"github.com/Flyrell/hourgit/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// skipAutoMigrate lists commands that run without migrating stored data
+// first: migrate reports on its own, version/update must keep working when
+// the data comes from a newer hourgit, and sync runs from the git hooks on
+// every checkout and commit, so it skips even detecting the schema. sync
+// reads older layouts, and the next other command migrates them.
+var skipAutoMigrate = map[string]bool{
+	"migrate": true,
+	"version": true,
+	"update":  true,
+	"sync":    true,
+}
+
+var migrateCmd = LeafCommand{
+	Use:   "migrate",
+	Short: "Upgrade stored data to the current schema version",
+	BoolFlags: []BoolFlag{
+		{Name: "dry-run", Usage: "show pending migrations without changing anything"},
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		return runMigrate(cmd, homeDir, dryRun)
+	},
+}.Build()
+
+func runMigrate(cmd *cobra.Command, homeDir string, dryRun bool) error {
+	result, err := schema.Migrate(homeDir, dryRun)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if len(result.Steps) == 0 {
+		_, _ = fmt.Fprintln(w, Silent(fmt.Sprintf("data is up to date (schema v%d)", result.To)))
+		return nil
+	}
+
+	for _, step := range result.Steps {
+		_, _ = fmt.Fprintf(w, "%s %s %s\n",
+			Primary(fmt.Sprintf("v%d", step.Migration.Version)),
+			Info(step.Migration.Name),
+			Text(step.Migration.Description))
+		if len(step.Changes) == 0 {
+			_, _ = fmt.Fprintf(w, "  %s\n", Silent("nothing to change"))
+		}
+		for _, c := range step.Changes {
+			_, _ = fmt.Fprintf(w, "  %s\n", Silent(c))
+		}
+	}
+
+	_, _ = fmt.Fprintln(w)
+	if dryRun {
+		_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("dry run: would migrate from schema v%d to v%d", result.From, result.To)))
+		return nil
+	}
+	_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("migrated from schema v%d to v%d (backup: %s)", result.From, result.To, Primary(result.Backup))))
+	return nil
+}
+
+// autoMigrate upgrades data written by an older hourgit before a command runs.
+func autoMigrate(cmd *cobra.Command, homeDir string) error {
+	result, err := schema.Migrate(homeDir, false)
+	if err != nil {
+		return err
+	}
+	if len(result.Steps) > 0 {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s\n", Info(fmt.Sprintf("migrated hourgit data from schema v%d to v%d (backup: %s)", result.From, result.To, result.Backup)))
+	}
+	return nil
+}
+
+// moveLegacyData moves data kept in ~/.hourgit by older versions into the
+// directories of l, reporting on stderr when it did.
+func moveLegacyData(cmd *cobra.Command, homeDir string, l paths.Layout) error {
+	moved, err := paths.MigrateLegacy(homeDir, l)
+	if err != nil {
+		return err
+	}
+	if moved {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s\n", Info(fmt.Sprintf("moved hourgit data from %s to %s (config: %s)", paths.LegacyDir(homeDir), l.Data, l.Config)))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/paths"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execMigrate(homeDir string, dryRun bool) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := migrateCmd
+	cmd.SetOut(stdout)
+
+	err := runMigrate(cmd, homeDir, dryRun)
+	return stdout.String(), err
+}
+
+// writeUnversionedConfig writes a config.json from before schema versioning
+// with an entry that has no type.
+func writeUnversionedConfig(t *testing.T, homeDir string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(project.LogDir(homeDir, "old"), 0755))
+	require.NoError(t, os.MkdirAll(project.ConfigDir(homeDir), 0755))
+	require.NoError(t, os.WriteFile(project.ConfigPath(homeDir),
+		[]byte(`{"version":"0.1.0","projects":[{"id":"abc1234","name":"Old","slug":"old","repos":[]}]}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(project.LogDir(homeDir, "old"), "aaa1111"),
+		[]byte(`{"id":"aaa1111","start":"2025-06-15T09:00:00Z","minutes":30,"message":"old"}`), 0644))
+}
+
+func TestMigrateUpToDate(t *testing.T) {
+	homeDir := t.TempDir()
+	_, err := project.CreateProject(homeDir, "Fresh")
+	require.NoError(t, err)
+
+	stdout, err := execMigrate(homeDir, false)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "data is up to date")
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	homeDir := t.TempDir()
+	writeUnversionedConfig(t, homeDir)
+
+	stdout, err := execMigrate(homeDir, true)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "entry-types")
+	assert.Contains(t, stdout, "old/aaa1111: set type to log")
+	assert.Contains(t, stdout, "nothing to change")
+	assert.Contains(t, stdout, "dry run: would migrate from schema v0")
+
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.SchemaVersion)
+}
+
+func TestMigrateApplies(t *testing.T) {
+	homeDir := t.TempDir()
+	writeUnversionedConfig(t, homeDir)
+
+	stdout, err := execMigrate(homeDir, false)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "migrated from schema v0")
+	assert.Contains(t, stdout, "backup:")
+
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	assert.Equal(t, project.SchemaVersion, cfg.SchemaVersion)
+}
+
+func TestAutoMigrateReportsOnStderr(t *testing.T) {
+	homeDir := t.TempDir()
+	writeUnversionedConfig(t, homeDir)
+
+	stderr := new(bytes.Buffer)
+	cmd := migrateCmd
+	cmd.SetErr(stderr)
+	require.NoError(t, autoMigrate(cmd, homeDir))
+	assert.Contains(t, stderr.String(), "migrated hourgit data from schema v0")
+
+	stderr.Reset()
+	require.NoError(t, autoMigrate(cmd, homeDir))
+	assert.Empty(t, stderr.String())
+}
+
+func TestAutoMigrateRefusesNewerSchema(t *testing.T) {
+	homeDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(project.ConfigDir(homeDir), 0755))
+	require.NoError(t, os.WriteFile(project.ConfigPath(homeDir), []byte(`{"schema_version":99}`), 0644))
+
+	err := autoMigrate(migrateCmd, homeDir)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "please update hourgit")
+}
+
+func TestMoveLegacyDataReportsOnStderr(t *testing.T) {
+	homeDir := t.TempDir()
+	legacy := paths.LegacyDir(homeDir)
+	require.NoError(t, os.MkdirAll(filepath.Join(legacy, "acme"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "config.json"), []byte(`{}`), 0644))
+
+	stderr := new(bytes.Buffer)
+	cmd := migrateCmd
+	cmd.SetErr(stderr)
+	layout := paths.Default(homeDir)
+	require.NoError(t, moveLegacyData(cmd, homeDir, layout))
+	assert.Contains(t, stderr.String(), "moved hourgit data from "+legacy+" to "+layout.Data)
+	assert.FileExists(t, project.ConfigPath(homeDir))
+	assert.DirExists(t, project.LogDir(homeDir, "acme"))
+
+	stderr.Reset()
+	require.NoError(t, moveLegacyData(cmd, homeDir, layout))
+	assert.Empty(t, stderr.String())
+}
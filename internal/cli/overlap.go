@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/schedule"
+	"github.com/Flyrell/hourgit/internal/timetrack"
+)
+
+// projectOptions returns the options proj's time on the dates from..to is
+// computed with: its own policies and the time the other projects claim.
+func projectOptions(homeDir string, cfg *project.Config, proj *project.ProjectEntry, from, to, now time.Time) (timetrack.Options, error) {
+	opts := timetrack.ProjectOptions(proj)
+	overlap, err := projectOverlap(homeDir, cfg, proj, from, to, now)
+	opts.Overlap = overlap
+	return opts, err
+}
+
+// projectOverlap loads the time every other project claims on the dates
+// from..to, so that time several projects claim at once is counted once per
+// the configured overlap policy. now is in proj's time zone.
+func projectOverlap(homeDir string, cfg *project.Config, proj *project.ProjectEntry, from, to, now time.Time) (timetrack.Overlap, error) {
+	overlap := timetrack.Overlap{
+		Policy:   project.GetOverlap(cfg),
+		Project:  proj.ID,
+		Priority: proj.Priority,
+	}
+	if overlap.Policy == project.OverlapOff {
+		return overlap, nil
+	}
+	// Nothing is claimed after today
+	if today := timetrack.DateOf(now); to.After(today) {
+		to = today
+	}
+	if from.After(to) {
+		return overlap, nil
+	}
+
+	// Another project's days may start up to a day apart in its own zone
+	from, to = from.AddDate(0, 0, -1), to.AddDate(0, 0, 1)
+	rangeEnd := time.Date(to.Year(), to.Month(), to.Day(), 23, 59, 59, 0, time.UTC)
+
+	for i := range cfg.Projects {
+		other := &cfg.Projects[i]
+		if other.ID == proj.ID {
+			continue
+		}
+		entries, err := LoadProjectEntriesRange(homeDir, other, from, to)
+		if err != nil {
+			return overlap, err
+		}
+		if len(entries.Checkouts) == 0 && len(entries.Logs) == 0 {
+			continue
+		}
+		daySchedules, err := schedule.ExpandSchedules(project.GetSchedules(cfg, other.ID), from.AddDate(0, 0, -1), rangeEnd)
+		if err != nil {
+			return overlap, err
+		}
+		// now is in proj's zone; a project without a zone of its own uses the local one
+		otherNow := inProjectZone(other, now)
+		if other.Timezone == "" && proj.Timezone != "" {
+			otherNow = now.Local()
+		}
+		overlap.Claims = append(overlap.Claims, timetrack.ProjectClaims(
+			other.ID, other.Priority,
+			entries.Checkouts, entries.Logs, entries.Commits, entries.Events, daySchedules,
+			from, to, otherNow, timetrack.ProjectOptions(other),
+			timetrack.ActivityEntries{Stops: entries.ActivityStops, Starts: entries.ActivityStarts},
+		)...)
+	}
+	return overlap, nil
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectOverlapStopsAtToday(t *testing.T) {
+	homeDir := t.TempDir()
+	proj, err := project.CreateProject(homeDir, "Mine")
+	require.NoError(t, err)
+	other, err := project.CreateProject(homeDir, "Other")
+	require.NoError(t, err)
+
+	for _, e := range []entry.Entry{
+		{ID: "0e10001", Start: time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC), Minutes: 60, Message: "past", Task: "past"},
+		{ID: "0e10002", Start: time.Date(2025, 6, 20, 10, 0, 0, 0, time.UTC), Minutes: 60, Message: "planned", Task: "planned"},
+	} {
+		require.NoError(t, entry.WriteEntry(homeDir, other.Slug, e))
+	}
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	overlap, err := projectOverlap(homeDir, cfg, project.FindProjectByID(cfg, proj.ID),
+		time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), now)
+	require.NoError(t, err)
+	require.Len(t, overlap.Claims, 1)
+	assert.Equal(t, other.ID, overlap.Claims[0].Project)
+	assert.Equal(t, time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC), overlap.Claims[0].From.UTC())
+
+	// A range after today claims nothing
+	overlap, err = projectOverlap(homeDir, cfg, project.FindProjectByID(cfg, proj.ID),
+		time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), now)
+	require.NoError(t, err)
+	assert.Empty(t, overlap.Claims)
+}
@@ -11,6 +11,7 @@ var projectCmd = GroupCommand{
 		projectEditCmd,
 		projectListCmd,
 		projectRemoveCmd,
+		rulesCmd,
 		scheduleCmd,
 	},
 }.Build()
@@ -3,8 +3,6 @@ package cli
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/Flyrell/hourgit/internal/project"
 	"github.com/spf13/cobra"
@@ -58,9 +56,7 @@ var projectAssignCmd = LeafCommand{
 
 func runProjectAssign(cmd *cobra.Command, repoDir, homeDir, projectName string, force bool, confirm ConfirmFunc) error {
 	// Check hourgit is initialized
-	hookPath := filepath.Join(repoDir, ".git", "hooks", "post-checkout")
-	hookData, err := os.ReadFile(hookPath)
-	if err != nil || !strings.Contains(string(hookData), project.HookMarker) {
+	if !project.HookInstalled(repoDir) {
 		return fmt.Errorf("hourgit is not initialized (run 'hourgit init' first)")
 	}
 
@@ -94,17 +90,15 @@ func runProjectAssign(cmd *cobra.Command, repoDir, homeDir, projectName string,
 		}
 
 		// Remove repo from old project
-		appCfg, err := project.ReadConfig(homeDir)
+		err := project.UpdateConfig(homeDir, func(appCfg *project.Config) error {
+			if oldEntry := project.FindProject(appCfg, cfg.Project); oldEntry != nil {
+				project.RemoveRepoFromProject(oldEntry, repoDir)
+			}
+			return nil
+		})
 		if err != nil {
 			return err
 		}
-		oldEntry := project.FindProject(appCfg, cfg.Project)
-		if oldEntry != nil {
-			project.RemoveRepoFromProject(oldEntry, repoDir)
-			if err := project.WriteConfig(homeDir, appCfg); err != nil {
-				return err
-			}
-		}
 	}
 
 	if result.Created {
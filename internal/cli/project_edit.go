@@ -7,13 +7,15 @@ import (
 	"strconv"
 
 	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/rounding"
+	"github.com/Flyrell/hourgit/internal/schedule"
 	"github.com/Flyrell/hourgit/internal/watch"
 	"github.com/spf13/cobra"
 )
 
 var projectEditCmd = LeafCommand{
 	Use:   "edit [PROJECT]",
-	Short: "Edit project name or tracking mode",
+	Short: "Edit project name, tracking mode, time zone, rounding, overtime, repo merge or submodule policy, priority or attribution",
 	Args:  cobra.MaximumNArgs(1),
 	BoolFlags: []BoolFlag{
 		{Name: "yes", Shorthand: "y", Usage: "skip confirmation prompts"},
@@ -23,6 +25,13 @@ var projectEditCmd = LeafCommand{
 		{Name: "name", Shorthand: "n", Usage: "new project name"},
 		{Name: "mode", Shorthand: "m", Usage: "tracking mode: standard or precise"},
 		{Name: "idle-threshold", Shorthand: "t", Usage: "idle threshold in minutes (precise mode only)"},
+		{Name: "timezone", Usage: "IANA time zone days are counted in, e.g. Europe/Prague (\"local\" to unset)"},
+		{Name: "rounding", Usage: "rounding policy INCREMENT[:MODE[:SCOPE]], e.g. 15:up:entry (\"off\" to disable)"},
+		{Name: "overtime", Usage: "time outside the schedule: ignore, separate or activity"},
+		{Name: "repo-merge", Usage: "time claimed by several repos at once: latest, split or active"},
+		{Name: "priority", Usage: "priority against other projects under the priority overlap policy (higher wins)"},
+		{Name: "attribution", Usage: "attribution strategy: checkout or commits[:MAXGAP[:LEADIN]], e.g. commits:120:30"},
+		{Name: "submodules", Usage: "project of submodule checkouts: own or parent"},
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		homeDir, err := os.UserHomeDir()
@@ -44,6 +53,13 @@ var projectEditCmd = LeafCommand{
 		nameFlag, _ := cmd.Flags().GetString("name")
 		modeFlag, _ := cmd.Flags().GetString("mode")
 		idleThresholdFlag, _ := cmd.Flags().GetString("idle-threshold")
+		timezoneFlag, _ := cmd.Flags().GetString("timezone")
+		roundingFlag, _ := cmd.Flags().GetString("rounding")
+		overtimeFlag, _ := cmd.Flags().GetString("overtime")
+		repoMergeFlag, _ := cmd.Flags().GetString("repo-merge")
+		priorityFlag, _ := cmd.Flags().GetString("priority")
+		attributionFlag, _ := cmd.Flags().GetString("attribution")
+		submodulesFlag, _ := cmd.Flags().GetString("submodules")
 		yes, _ := cmd.Flags().GetBool("yes")
 
 		var idleThreshold int
@@ -72,14 +88,45 @@ var projectEditCmd = LeafCommand{
 			Confirm:           ResolveConfirmFunc(yes),
 		}
 
-		return runProjectEdit(cmd, homeDir, repoDir, identifier, nameFlag, modeFlag, idleThreshold, binPath, pk)
+		return runProjectEdit(cmd, homeDir, repoDir, identifier, nameFlag, modeFlag, timezoneFlag, roundingFlag, overtimeFlag, repoMergeFlag, priorityFlag, attributionFlag, submodulesFlag, idleThreshold, binPath, pk)
 	},
 }.Build()
 
-func runProjectEdit(cmd *cobra.Command, homeDir, repoDir, identifier, nameFlag, modeFlag string, idleThreshold int, binPath string, pk PromptKit) error {
+func runProjectEdit(cmd *cobra.Command, homeDir, repoDir, identifier, nameFlag, modeFlag, timezoneFlag, roundingFlag, overtimeFlag, repoMergeFlag, priorityFlag, attributionFlag, submodulesFlag string, idleThreshold int, binPath string, pk PromptKit) error {
 	if err := validateMode(modeFlag); err != nil {
 		return err
 	}
+	newTimezone := timezoneFlag
+	if newTimezone == "local" {
+		newTimezone = ""
+	} else if newTimezone != "" {
+		if _, err := schedule.LoadLocation(newTimezone); err != nil {
+			return err
+		}
+	}
+	newRounding, err := rounding.Parse(roundingFlag)
+	if err != nil {
+		return err
+	}
+	if err := project.ValidateOvertime(overtimeFlag); err != nil {
+		return err
+	}
+	if err := project.ValidateRepoMerge(repoMergeFlag); err != nil {
+		return err
+	}
+	var newPriority int
+	if priorityFlag != "" {
+		if newPriority, err = strconv.Atoi(priorityFlag); err != nil {
+			return fmt.Errorf("invalid --priority value %q: must be a number", priorityFlag)
+		}
+	}
+	newAttribution, err := project.ParseAttribution(attributionFlag)
+	if err != nil {
+		return err
+	}
+	if err := project.ValidateSubmodules(submodulesFlag); err != nil {
+		return err
+	}
 
 	// Resolve project
 	entry, err := resolveEditProject(homeDir, repoDir, identifier)
@@ -92,7 +139,7 @@ func runProjectEdit(cmd *cobra.Command, homeDir, repoDir, identifier, nameFlag,
 	newIdleThreshold := idleThreshold
 
 	// Interactive mode: prompt for values if no flags provided
-	if nameFlag == "" && modeFlag == "" && idleThreshold == 0 {
+	if nameFlag == "" && modeFlag == "" && timezoneFlag == "" && roundingFlag == "" && overtimeFlag == "" && repoMergeFlag == "" && priorityFlag == "" && attributionFlag == "" && submodulesFlag == "" && idleThreshold == 0 {
 		newName, newMode, newIdleThreshold, err = promptProjectEdit(entry, pk)
 		if err != nil {
 			return err
@@ -125,7 +172,15 @@ func runProjectEdit(cmd *cobra.Command, homeDir, repoDir, identifier, nameFlag,
 	currentThreshold := project.GetIdleThreshold(cfg, entry.ID)
 	thresholdChanged := newIdleThreshold > 0 && newIdleThreshold != currentThreshold
 
-	if !nameChanged && !modeChanged && !thresholdChanged {
+	timezoneChanged := timezoneFlag != "" && newTimezone != entry.Timezone
+	roundingChanged := roundingFlag != "" && newRounding != entry.Rounding
+	overtimeChanged := overtimeFlag != "" && overtimeLabel(overtimeFlag) != overtimeLabel(entry.Overtime)
+	repoMergeChanged := repoMergeFlag != "" && repoMergeLabel(repoMergeFlag) != repoMergeLabel(entry.RepoMerge)
+	priorityChanged := priorityFlag != "" && newPriority != entry.Priority
+	attributionChanged := attributionFlag != "" && newAttribution != entry.Attribution
+	submodulesChanged := submodulesFlag != "" && submodulesLabel(submodulesFlag) != submodulesLabel(entry.Submodules)
+
+	if !nameChanged && !modeChanged && !thresholdChanged && !timezoneChanged && !roundingChanged && !overtimeChanged && !repoMergeChanged && !priorityChanged && !attributionChanged && !submodulesChanged {
 		_, _ = fmt.Fprintln(cmd.OutOrStdout(), Text("no changes"))
 		return nil
 	}
@@ -174,9 +229,122 @@ func runProjectEdit(cmd *cobra.Command, homeDir, repoDir, identifier, nameFlag,
 			Silent(fmt.Sprintf("%dm", currentThreshold)), Primary(fmt.Sprintf("%dm", newIdleThreshold)))))
 	}
 
+	// Apply time zone change
+	if timezoneChanged {
+		if err := project.SetTimezone(homeDir, entry.ID, newTimezone); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("time zone: %s → %s",
+			Silent(timezoneLabel(entry.Timezone)), Primary(timezoneLabel(newTimezone)))))
+	}
+
+	// Apply rounding change
+	if roundingChanged {
+		if err := project.SetRounding(homeDir, entry.ID, newRounding); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("rounding: %s → %s",
+			Silent(entry.Rounding.String()), Primary(newRounding.String()))))
+	}
+
+	// Apply overtime policy change
+	if overtimeChanged {
+		if err := project.SetOvertime(homeDir, entry.ID, overtimeFlag); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("overtime: %s → %s",
+			Silent(overtimeLabel(entry.Overtime)), Primary(overtimeLabel(overtimeFlag)))))
+		if overtimeFlag == project.OvertimeActivity && effectiveMode != "precise" {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Warning("overtime is only counted from recorded activity — enable precise mode with --mode precise"))
+		}
+	}
+
+	// Apply repo merge policy change
+	if repoMergeChanged {
+		if err := project.SetRepoMerge(homeDir, entry.ID, repoMergeFlag); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("repo merge: %s → %s",
+			Silent(repoMergeLabel(entry.RepoMerge)), Primary(repoMergeLabel(repoMergeFlag)))))
+		if repoMergeFlag == project.RepoMergeActive && effectiveMode != "precise" {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Warning("activity is only recorded in precise mode — enable it with --mode precise"))
+		}
+	}
+
+	// Apply priority change
+	if priorityChanged {
+		if err := project.SetPriority(homeDir, entry.ID, newPriority); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("priority: %s → %s",
+			Silent(strconv.Itoa(entry.Priority)), Primary(strconv.Itoa(newPriority)))))
+		if project.GetOverlap(cfg) != project.OverlapPriority {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Warning("priorities only apply under the priority overlap policy — set it with hourgit defaults overlap priority"))
+		}
+	}
+
+	// Apply attribution strategy change
+	if attributionChanged {
+		if err := project.SetAttribution(homeDir, entry.ID, newAttribution); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("attribution: %s → %s",
+			Silent(entry.Attribution.String()), Primary(newAttribution.String()))))
+	}
+
+	// Apply submodule policy change
+	if submodulesChanged {
+		if err := project.SetSubmodules(homeDir, entry.ID, submodulesFlag); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("submodules: %s → %s",
+			Silent(submodulesLabel(entry.Submodules)), Primary(submodulesLabel(submodulesFlag)))))
+		if submodulesFlag == project.SubmodulesParent {
+			// Submodule checkouts are only recorded with a hook of their own
+			for _, repo := range entry.Repos {
+				if _, err := ensureSubmoduleHooks(repo, binPath); err != nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s\n",
+						Warning(fmt.Sprintf("warning: could not install hooks in submodules of %s: %s", repo, err)))
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
+// submodulesLabel names a project submodule policy for display.
+func submodulesLabel(policy string) string {
+	if policy == "" {
+		return project.SubmodulesOwn
+	}
+	return policy
+}
+
+// repoMergeLabel names a project repo merge policy for display.
+func repoMergeLabel(policy string) string {
+	if policy == "" {
+		return project.RepoMergeLatest
+	}
+	return policy
+}
+
+// overtimeLabel names a project overtime policy for display.
+func overtimeLabel(policy string) string {
+	if policy == "" {
+		return project.OvertimeIgnore
+	}
+	return policy
+}
+
+// timezoneLabel names a project time zone for display.
+func timezoneLabel(name string) string {
+	if name == "" {
+		return "local"
+	}
+	return name
+}
+
 func resolveEditProject(homeDir, repoDir, identifier string) (*project.ProjectEntry, error) {
 	cfg, err := project.ReadConfig(homeDir)
 	if err != nil {
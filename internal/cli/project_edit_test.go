@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/rounding"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -20,7 +21,7 @@ func execProjectEdit(homeDir, repoDir, identifier, nameFlag, modeFlag string, id
 		Confirm: AlwaysYes(),
 	}
 
-	err := runProjectEdit(cmd, homeDir, repoDir, identifier, nameFlag, modeFlag, idleThreshold, "/usr/local/bin/hourgit", pk)
+	err := runProjectEdit(cmd, homeDir, repoDir, identifier, nameFlag, modeFlag, "", "", "", "", "", "", "", idleThreshold, "/usr/local/bin/hourgit", pk)
 	return stdout.String(), err
 }
 
@@ -273,7 +274,7 @@ func TestProjectEditInteractiveMode(t *testing.T) {
 		},
 	}
 
-	err = runProjectEdit(cmd, home, "", "My Project", "", "", 0, "/usr/local/bin/hourgit", pk)
+	err = runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "", "", "", "", 0, "/usr/local/bin/hourgit", pk)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, promptCalls, "should prompt for name and idle threshold")
@@ -394,7 +395,7 @@ func TestProjectEditIdleThresholdInvalidInteractive(t *testing.T) {
 		},
 	}
 
-	err = runProjectEdit(cmd, home, "", "My Project", "", "", 0, "/usr/local/bin/hourgit", pk)
+	err = runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "", "", "", "", 0, "/usr/local/bin/hourgit", pk)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid idle threshold")
@@ -430,7 +431,7 @@ func TestProjectEditInteractivePreciseIdleThreshold(t *testing.T) {
 		},
 	}
 
-	err = runProjectEdit(cmd, home, "", "My Project", "", "", 0, "/usr/local/bin/hourgit", pk)
+	err = runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "", "", "", "", 0, "/usr/local/bin/hourgit", pk)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, promptCalls, "should prompt for name and idle threshold")
@@ -451,3 +452,184 @@ func TestProjectEditRegisteredAsSubcommand(t *testing.T) {
 	}
 	assert.Contains(t, names, "edit")
 }
+
+func TestProjectEditTimezone(t *testing.T) {
+	home := t.TempDir()
+	entry, err := project.CreateProject(home, "My Project")
+	require.NoError(t, err)
+
+	stdout := new(bytes.Buffer)
+	cmd := projectEditCmd
+	cmd.SetOut(stdout)
+	pk := PromptKit{Confirm: AlwaysYes()}
+
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "Europe/Prague", "", "", "", "", "", "", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "time zone: local → Europe/Prague")
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Prague", project.FindProjectByID(cfg, entry.ID).Timezone)
+
+	stdout.Reset()
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "local", "", "", "", "", "", "", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "time zone: Europe/Prague → local")
+
+	err = runProjectEdit(cmd, home, "", "My Project", "", "", "Mars/Olympus", "", "", "", "", "", "", 0, "/usr/local/bin/hourgit", pk)
+	assert.ErrorContains(t, err, "invalid time zone")
+}
+
+func TestProjectEditRounding(t *testing.T) {
+	home := t.TempDir()
+	entry, err := project.CreateProject(home, "My Project")
+	require.NoError(t, err)
+
+	stdout := new(bytes.Buffer)
+	cmd := projectEditCmd
+	cmd.SetOut(stdout)
+	pk := PromptKit{Confirm: AlwaysYes()}
+
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "", "15:up", "", "", "", "", "", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "rounding: off → 15 min, up, per entry")
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, rounding.Policy{Increment: 15, Mode: rounding.ModeUp, Scope: rounding.ScopeEntry}, project.FindProjectByID(cfg, entry.ID).Rounding)
+
+	stdout.Reset()
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "", "off", "", "", "", "", "", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "rounding: 15 min, up, per entry → off")
+
+	err = runProjectEdit(cmd, home, "", "My Project", "", "", "", "15:sideways", "", "", "", "", "", 0, "/usr/local/bin/hourgit", pk)
+	assert.ErrorContains(t, err, "invalid rounding mode")
+}
+
+func TestProjectEditOvertime(t *testing.T) {
+	home := t.TempDir()
+	entry, err := project.CreateProject(home, "My Project")
+	require.NoError(t, err)
+
+	stdout := new(bytes.Buffer)
+	cmd := projectEditCmd
+	cmd.SetOut(stdout)
+	pk := PromptKit{Confirm: AlwaysYes()}
+
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "separate", "", "", "", "", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "overtime: ignore → separate")
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, project.OvertimeSeparate, project.FindProjectByID(cfg, entry.ID).Overtime)
+
+	stdout.Reset()
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "activity", "", "", "", "", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "overtime: separate → activity")
+	assert.Contains(t, stdout.String(), "enable precise mode")
+
+	stdout.Reset()
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "activity", "", "", "", "", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "no changes")
+
+	err = runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "always", "", "", "", "", 0, "/usr/local/bin/hourgit", pk)
+	assert.ErrorContains(t, err, "invalid overtime policy")
+}
+
+func TestProjectEditRepoMerge(t *testing.T) {
+	home := t.TempDir()
+	entry, err := project.CreateProject(home, "My Project")
+	require.NoError(t, err)
+
+	stdout := new(bytes.Buffer)
+	cmd := projectEditCmd
+	cmd.SetOut(stdout)
+	pk := PromptKit{Confirm: AlwaysYes()}
+
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "split", "", "", "", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "repo merge: latest → split")
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, project.RepoMergeSplit, project.FindProjectByID(cfg, entry.ID).RepoMerge)
+
+	stdout.Reset()
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "active", "", "", "", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "repo merge: split → active")
+	assert.Contains(t, stdout.String(), "enable it with --mode precise")
+
+	err = runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "first", "", "", "", 0, "/usr/local/bin/hourgit", pk)
+	assert.ErrorContains(t, err, "invalid repo merge policy")
+}
+
+func TestProjectEditPriority(t *testing.T) {
+	home := t.TempDir()
+	entry, err := project.CreateProject(home, "My Project")
+	require.NoError(t, err)
+
+	stdout := new(bytes.Buffer)
+	cmd := projectEditCmd
+	cmd.SetOut(stdout)
+	pk := PromptKit{Confirm: AlwaysYes()}
+
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "", "2", "", "", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "priority: 0 → 2")
+	assert.Contains(t, stdout.String(), "hourgit defaults overlap priority")
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, 2, project.FindProjectByID(cfg, entry.ID).Priority)
+
+	err = runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "", "high", "", "", 0, "/usr/local/bin/hourgit", pk)
+	assert.ErrorContains(t, err, "invalid --priority value")
+}
+
+func TestProjectEditAttribution(t *testing.T) {
+	home := t.TempDir()
+	entry, err := project.CreateProject(home, "My Project")
+	require.NoError(t, err)
+
+	stdout := new(bytes.Buffer)
+	cmd := projectEditCmd
+	cmd.SetOut(stdout)
+	pk := PromptKit{Confirm: AlwaysYes()}
+
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "", "", "commits:90", "", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "attribution: checkout → commits, 90 min gap, 30 min lead-in")
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, project.Attribution{Strategy: project.AttributionCommits, MaxGap: 90, LeadIn: 30}, project.FindProjectByID(cfg, entry.ID).Attribution)
+
+	stdout.Reset()
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "", "", "checkout", "", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "attribution: commits, 90 min gap, 30 min lead-in → checkout")
+
+	err = runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "", "", "guess", "", 0, "/usr/local/bin/hourgit", pk)
+	assert.ErrorContains(t, err, "invalid attribution")
+}
+
+func TestProjectEditSubmodules(t *testing.T) {
+	home := t.TempDir()
+	repoDir, subDir := gitWithSubmodule(t)
+	entry, err := project.CreateProject(home, "My Project")
+	require.NoError(t, err)
+	require.NoError(t, project.AssignProject(home, repoDir, entry))
+
+	stdout := new(bytes.Buffer)
+	cmd := projectEditCmd
+	cmd.SetOut(stdout)
+	pk := PromptKit{Confirm: AlwaysYes()}
+
+	require.NoError(t, runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "", "", "", "parent", 0, "/usr/local/bin/hourgit", pk))
+	assert.Contains(t, stdout.String(), "submodules: own → parent")
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, project.SubmodulesParent, project.FindProjectByID(cfg, entry.ID).Submodules)
+
+	// Submodule checkouts need a hook of their own
+	hook, err := os.ReadFile(project.HookPath(subDir, "post-checkout"))
+	require.NoError(t, err)
+	assert.Contains(t, string(hook), project.HookMarker)
+
+	err = runProjectEdit(cmd, home, "", "My Project", "", "", "", "", "", "", "", "", "child", 0, "/usr/local/bin/hourgit", pk)
+	assert.ErrorContains(t, err, "invalid submodule policy")
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/Flyrell/hourgit/internal/entry"
 	"github.com/Flyrell/hourgit/internal/project"
 	"github.com/spf13/cobra"
 )
@@ -57,16 +58,16 @@ func runProjectRemove(cmd *cobra.Command, homeDir, identifier string, confirm Co
 		return err
 	}
 
-	entry := project.ResolveProject(cfg, identifier)
-	if entry == nil {
+	proj := project.ResolveProject(cfg, identifier)
+	if proj == nil {
 		return fmt.Errorf("project '%s' not found", identifier)
 	}
 
 	// If project has repos, prompt for confirmation
-	if len(entry.Repos) > 0 {
-		repoList := strings.Join(entry.Repos, "\n  ")
+	if len(proj.Repos) > 0 {
+		repoList := strings.Join(proj.Repos, "\n  ")
 		prompt := fmt.Sprintf("Project '%s' is assigned to %d repo(s):\n  %s\nRemove project and clean up assignments?",
-			entry.Name, len(entry.Repos), repoList)
+			proj.Name, len(proj.Repos), repoList)
 
 		confirmed, err := confirm(prompt)
 		if err != nil {
@@ -81,13 +82,19 @@ func runProjectRemove(cmd *cobra.Command, homeDir, identifier string, confirm Co
 	// Best-effort cleanup: remove repo configs and hooks. Errors are intentionally
 	// ignored because the repos may have been moved/deleted since assignment, and
 	// failing to clean up a single repo should not block project removal.
-	for _, repoDir := range entry.Repos {
+	for _, repoDir := range proj.Repos {
 		_ = project.RemoveRepoConfig(repoDir)
 		_ = project.RemoveHookFromRepo(repoDir)
 	}
 
-	// Best-effort cleanup: delete the project's time entry directory
-	_ = os.RemoveAll(project.LogDir(homeDir, entry.Slug))
+	// Best-effort cleanup: delete the project's entries one by one so the
+	// journal can restore them, then whatever is left of its directory
+	if records, err := entry.ListRecords(homeDir, proj.Slug, entry.Query{}); err == nil {
+		for _, r := range records {
+			_ = entry.DeleteEntry(homeDir, proj.Slug, r.File)
+		}
+	}
+	_ = os.RemoveAll(project.LogDir(homeDir, proj.Slug))
 
 	// Remove project from registry
 	_, err = project.RemoveProject(homeDir, identifier)
@@ -95,6 +102,6 @@ func runProjectRemove(cmd *cobra.Command, homeDir, identifier string, confirm Co
 		return err
 	}
 
-	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("project '%s' removed", Primary(entry.Name))))
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("project '%s' removed", Primary(proj.Name))))
 	return nil
 }
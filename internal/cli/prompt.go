@@ -41,6 +41,20 @@ func NewPromptFunc() PromptFunc {
 	}
 }
 
+// NewPasswordFunc creates a PromptFunc using huh's input component with the
+// typed text hidden.
+func NewPasswordFunc() PromptFunc {
+	return func(prompt string) (string, error) {
+		var result string
+		err := huh.NewInput().
+			Title(prompt).
+			EchoMode(huh.EchoModePassword).
+			Value(&result).
+			Run()
+		return result, err
+	}
+}
+
 // SelectFunc prompts the user to select one option from a list. Returns 0-based index.
 type SelectFunc func(title string, options []string) (int, error)
 
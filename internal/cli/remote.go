@@ -0,0 +1,13 @@
+package cli
+
+import "github.com/spf13/cobra"
+
+var remoteCmd = GroupCommand{
+	Use:   "remote",
+	Short: "Sync hourgit data between machines through a git remote",
+	Subcommands: []*cobra.Command{
+		remoteAddCmd,
+		remotePushCmd,
+		remotePullCmd,
+	},
+}.Build()
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/Flyrell/hourgit/internal/remote"
+	"github.com/spf13/cobra"
+)
+
+var remoteAddCmd = LeafCommand{
+	Use:   "add <url>",
+	Short: "Keep the data directory in git and sync it with a remote",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		return runRemoteAdd(cmd, homeDir, args[0])
+	},
+}.Build()
+
+func runRemoteAdd(cmd *cobra.Command, homeDir, url string) error {
+	result, err := remote.Add(homeDir, url)
+	if err != nil {
+		return err
+	}
+	printRemoteResult(cmd, result)
+	return nil
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execRemoteAdd(homeDir, url string) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := remoteAddCmd
+	cmd.SetOut(stdout)
+	err := runRemoteAdd(cmd, homeDir, url)
+	return stdout.String(), err
+}
+
+// newSyncRemote creates an empty bare repository to sync through.
+func newSyncRemote(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	out, err := exec.Command("git", "init", "-q", "--bare", dir).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return dir
+}
+
+func TestRemoteAdd(t *testing.T) {
+	homeDir := t.TempDir()
+	_, err := project.CreateProject(homeDir, "Acme")
+	require.NoError(t, err)
+	url := newSyncRemote(t)
+
+	stdout, err := execRemoteAdd(homeDir, url)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "no changes from the remote")
+	assert.Contains(t, stdout, "pushed to "+url)
+}
+
+func TestRemoteAddTwice(t *testing.T) {
+	homeDir := t.TempDir()
+	_, err := project.CreateProject(homeDir, "Acme")
+	require.NoError(t, err)
+	url := newSyncRemote(t)
+	_, err = execRemoteAdd(homeDir, url)
+	require.NoError(t, err)
+
+	_, err = execRemoteAdd(homeDir, url)
+
+	assert.ErrorContains(t, err, "remote already configured")
+}
+
+func TestRemoteRegistered(t *testing.T) {
+	names := map[string]bool{}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "remote" {
+			for _, sub := range c.Commands() {
+				names[sub.Name()] = true
+			}
+		}
+	}
+	assert.Equal(t, map[string]bool{"add": true, "push": true, "pull": true}, names)
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Flyrell/hourgit/internal/remote"
+	"github.com/spf13/cobra"
+)
+
+var remotePullCmd = LeafCommand{
+	Use:   "pull",
+	Short: "Merge data pushed from other machines",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		return runRemotePull(cmd, homeDir)
+	},
+}.Build()
+
+func runRemotePull(cmd *cobra.Command, homeDir string) error {
+	result, err := remote.Pull(homeDir)
+	if err != nil {
+		return err
+	}
+	printRemoteResult(cmd, result)
+	return nil
+}
+
+// printRemoteResult reports what a pull or push merged and sent.
+func printRemoteResult(cmd *cobra.Command, result *remote.Result) {
+	w := cmd.OutOrStdout()
+	if result.UpToDate {
+		_, _ = fmt.Fprintln(w, Silent("no changes from the remote"))
+	} else {
+		var parts []string
+		for _, c := range []struct {
+			n    int
+			noun string
+		}{
+			{result.EntriesAdded, "entries added"},
+			{result.EntriesUpdated, "entries updated"},
+			{result.EntriesDeleted, "entries deleted"},
+			{result.Duplicates, "duplicate entries dropped"},
+			{result.ProjectsAdded, "projects added"},
+			{result.ProjectsUpdated, "projects updated"},
+			{result.ProjectsRemoved, "projects removed"},
+		} {
+			if c.n > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", c.n, c.noun))
+			}
+		}
+		summary := "nothing to change"
+		if len(parts) > 0 {
+			summary = strings.Join(parts, ", ")
+		}
+		_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("merged from %s: %s", Primary(result.URL), summary)))
+	}
+
+	for _, c := range result.Conflicts {
+		_, _ = fmt.Fprintf(w, "%s\n", Warning("conflict: "+c))
+	}
+	if result.Pushed {
+		_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("pushed to %s", Primary(result.URL))))
+	}
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execRemotePull(homeDir string) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := remotePullCmd
+	cmd.SetOut(stdout)
+	err := runRemotePull(cmd, homeDir)
+	return stdout.String(), err
+}
+
+func TestRemotePullFromOtherMachine(t *testing.T) {
+	url := newSyncRemote(t)
+
+	desktop := t.TempDir()
+	proj, err := project.CreateProject(desktop, "Acme")
+	require.NoError(t, err)
+	_, err = execRemoteAdd(desktop, url)
+	require.NoError(t, err)
+
+	laptop := t.TempDir()
+	require.NoError(t, os.MkdirAll(project.ConfigDir(laptop), 0755))
+	require.NoError(t, project.WriteConfig(laptop, &project.Config{}))
+	stdout, err := execRemoteAdd(laptop, url)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "1 projects added")
+
+	require.NoError(t, entry.WriteEntry(laptop, proj.Slug, entry.Entry{
+		ID: "aaaa11112222", Start: time.Date(2025, 6, 16, 9, 0, 0, 0, time.UTC), Minutes: 45, Message: "on the laptop",
+	}))
+	_, err = execRemotePush(laptop)
+	require.NoError(t, err)
+
+	stdout, err = execRemotePull(desktop)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "merged from "+url+": 1 entries added")
+	e, err := entry.ReadEntry(desktop, proj.Slug, "aaaa11112222")
+	require.NoError(t, err)
+	assert.Equal(t, "on the laptop", e.Message)
+
+	stdout, err = execRemotePull(desktop)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "no changes from the remote")
+}
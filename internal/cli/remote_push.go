@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/Flyrell/hourgit/internal/remote"
+	"github.com/spf13/cobra"
+)
+
+var remotePushCmd = LeafCommand{
+	Use:   "push",
+	Short: "Merge remote changes, then push local data to the remote",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		return runRemotePush(cmd, homeDir)
+	},
+}.Build()
+
+func runRemotePush(cmd *cobra.Command, homeDir string) error {
+	result, err := remote.Push(homeDir)
+	if err != nil {
+		return err
+	}
+	printRemoteResult(cmd, result)
+	return nil
+}
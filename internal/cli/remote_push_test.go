@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execRemotePush(homeDir string) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := remotePushCmd
+	cmd.SetOut(stdout)
+	err := runRemotePush(cmd, homeDir)
+	return stdout.String(), err
+}
+
+func TestRemotePushWithoutRemote(t *testing.T) {
+	homeDir := t.TempDir()
+	_, err := project.CreateProject(homeDir, "Acme")
+	require.NoError(t, err)
+
+	_, err = execRemotePush(homeDir)
+
+	assert.ErrorContains(t, err, "no remote configured")
+}
+
+func TestRemotePushSendsLocalChanges(t *testing.T) {
+	homeDir := t.TempDir()
+	_, err := project.CreateProject(homeDir, "Acme")
+	require.NoError(t, err)
+	url := newSyncRemote(t)
+	_, err = execRemoteAdd(homeDir, url)
+	require.NoError(t, err)
+	_, err = project.CreateProject(homeDir, "Second")
+	require.NoError(t, err)
+
+	stdout, err := execRemotePush(homeDir)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "pushed to "+url)
+}
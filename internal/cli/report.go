@@ -15,28 +15,43 @@ import (
 // reportInputs holds the raw data loaded from storage, shared between
 // the interactive report and the PDF export paths.
 type reportInputs struct {
+	cfg            *project.Config
 	proj           *project.ProjectEntry
 	checkouts      []entry.CheckoutEntry
 	logs           []entry.Entry
 	commits        []entry.CommitEntry
+	events         []entry.GitEventEntry
 	schedules      []schedule.DaySchedule
 	submits        []entry.SubmitEntry
 	activityStops  []entry.ActivityStopEntry
 	activityStarts []entry.ActivityStartEntry
+	opts           timetrack.Options // the project's policies and the time other projects claim
 	from           time.Time
 	to             time.Time
-	year           int
-	month          time.Month
-	weekNum        int // >0 when using --week view
+	weekNum        int       // >0 when using --week view
+	now            time.Time // the current time in the project's time zone
+}
+
+// reportFlags are the flags of the report command.
+type reportFlags struct {
+	project, month, week, year, from, to   string
+	export, detail                         string
+	monthChanged, weekChanged, yearChanged bool
+	byRepo                                 bool
 }
 
 var reportCmd = LeafCommand{
 	Use:   "report",
-	Short: "Generate a monthly time report",
+	Short: "Generate a time report for a month, week or date range",
+	BoolFlags: []BoolFlag{
+		{Name: "by-repo", Usage: "break time down by repository"},
+	},
 	StrFlags: []StringFlag{
 		{Name: "month", Shorthand: "m", Usage: "month number 1-12 (default: current month)"},
 		{Name: "week", Shorthand: "w", Usage: "ISO week number 1-53 (default: current week)"},
 		{Name: "year", Shorthand: "y", Usage: "year (complementary to --month or --week)"},
+		{Name: "from", Usage: "first day of a custom range (YYYY-MM-DD, requires --to)"},
+		{Name: "to", Usage: "last day of a custom range (YYYY-MM-DD, requires --from)"},
 		{Name: "project", Shorthand: "p", Usage: "project name or ID (auto-detected from repo if omitted)"},
 		{Name: "export", Shorthand: "e", Usage: "export format (pdf)"},
 		{Name: "detail", Shorthand: "d", Usage: "export detail level: summary or full (default: summary)"},
@@ -47,62 +62,67 @@ var reportCmd = LeafCommand{
 			return err
 		}
 
-		projectFlag, _ := cmd.Flags().GetString("project")
-		monthFlag, _ := cmd.Flags().GetString("month")
-		weekFlag, _ := cmd.Flags().GetString("week")
-		yearFlag, _ := cmd.Flags().GetString("year")
-		exportFlag, _ := cmd.Flags().GetString("export")
-		detailFlag, _ := cmd.Flags().GetString("detail")
-
-		monthChanged := cmd.Flags().Changed("month")
-		weekChanged := cmd.Flags().Changed("week")
-		yearChanged := cmd.Flags().Changed("year")
-
-		return runReport(cmd, homeDir, repoDir, projectFlag, monthFlag, weekFlag, yearFlag, exportFlag, detailFlag, monthChanged, weekChanged, yearChanged, time.Now)
+		flags := reportFlags{
+			monthChanged: cmd.Flags().Changed("month"),
+			weekChanged:  cmd.Flags().Changed("week"),
+			yearChanged:  cmd.Flags().Changed("year"),
+		}
+		flags.project, _ = cmd.Flags().GetString("project")
+		flags.month, _ = cmd.Flags().GetString("month")
+		flags.week, _ = cmd.Flags().GetString("week")
+		flags.year, _ = cmd.Flags().GetString("year")
+		flags.from, _ = cmd.Flags().GetString("from")
+		flags.to, _ = cmd.Flags().GetString("to")
+		flags.export, _ = cmd.Flags().GetString("export")
+		flags.detail, _ = cmd.Flags().GetString("detail")
+		flags.byRepo, _ = cmd.Flags().GetBool("by-repo")
+
+		return runReport(cmd, homeDir, repoDir, flags, time.Now)
 	},
 }.Build()
 
-func runReport(
-	cmd *cobra.Command,
-	homeDir, repoDir, projectFlag, monthFlag, weekFlag, yearFlag, exportFlag, detailFlag string,
-	monthChanged, weekChanged, yearChanged bool,
-	nowFn func() time.Time,
-) error {
+func runReport(cmd *cobra.Command, homeDir, repoDir string, flags reportFlags, nowFn func() time.Time) error {
 	now := nowFn()
 
 	// Validate detail flag
-	if detailFlag != "" && detailFlag != "summary" && detailFlag != "full" {
-		return fmt.Errorf("invalid --detail value %q (supported: summary, full)", detailFlag)
+	if flags.detail != "" && flags.detail != "summary" && flags.detail != "full" {
+		return fmt.Errorf("invalid --detail value %q (supported: summary, full)", flags.detail)
 	}
 
-	inputs, err := loadReportInputs(homeDir, repoDir, projectFlag, monthFlag, weekFlag, yearFlag, monthChanged, weekChanged, yearChanged, now)
+	inputs, err := loadReportInputs(homeDir, repoDir, flags, now)
 	if err != nil {
 		return err
 	}
+	now = inputs.now
 
 	// PDF export path
-	if exportFlag != "" {
-		if exportFlag != "pdf" {
-			return fmt.Errorf("unsupported export format %q (supported: pdf)", exportFlag)
+	if flags.export != "" {
+		if flags.export != "pdf" {
+			return fmt.Errorf("unsupported export format %q (supported: pdf)", flags.export)
 		}
 
 		exportData := timetrack.BuildExportData(
-			inputs.checkouts, inputs.logs, inputs.commits, inputs.schedules,
-			inputs.year, inputs.month, now, nil,
-			inputs.proj.Name, detailFlag,
+			inputs.checkouts, inputs.logs, inputs.commits, inputs.events, inputs.schedules,
+			inputs.from, inputs.to, now, nil, inputs.proj.Name, flags.detail, inputs.opts,
 			timetrack.ActivityEntries{Stops: inputs.activityStops, Starts: inputs.activityStarts},
 		)
 
+		exportData.ByRepo = flags.byRepo
+
 		if len(exportData.Days) == 0 {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No time entries for %s %d.\n", inputs.month, inputs.year)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No time entries for %s.\n", periodLabel(inputs.from, inputs.to))
 			return nil
 		}
 
 		var outputPath string
-		if inputs.weekNum > 0 {
-			outputPath = fmt.Sprintf("%s-%d-week-%02d.pdf", inputs.proj.Slug, inputs.year, inputs.weekNum)
-		} else {
-			outputPath = fmt.Sprintf("%s-%d-month-%02d.pdf", inputs.proj.Slug, inputs.year, inputs.month)
+		switch {
+		case inputs.weekNum > 0:
+			year, _ := inputs.from.ISOWeek()
+			outputPath = fmt.Sprintf("%s-%d-week-%02d.pdf", inputs.proj.Slug, year, inputs.weekNum)
+		case isFullMonth(inputs.from, inputs.to):
+			outputPath = fmt.Sprintf("%s-%d-month-%02d.pdf", inputs.proj.Slug, inputs.from.Year(), inputs.from.Month())
+		default:
+			outputPath = fmt.Sprintf("%s-%s-to-%s.pdf", inputs.proj.Slug, inputs.from.Format("2006-01-02"), inputs.to.Format("2006-01-02"))
 		}
 
 		if err := renderExportPDF(exportData, outputPath); err != nil {
@@ -115,8 +135,8 @@ func runReport(
 
 	// Interactive table path — use detailed report
 	data := timetrack.BuildDetailedReport(
-		inputs.checkouts, inputs.logs, inputs.commits, inputs.schedules,
-		inputs.from, inputs.to, now,
+		inputs.checkouts, inputs.logs, inputs.commits, inputs.events, inputs.schedules,
+		inputs.from, inputs.to, now, inputs.opts,
 		timetrack.ActivityEntries{Stops: inputs.activityStops, Starts: inputs.activityStarts},
 	)
 
@@ -125,6 +145,14 @@ func runReport(
 		return nil
 	}
 
+	// Only the table shows the balance, so only it pays for computing it
+	balance, err := projectBalance(homeDir, inputs.cfg, inputs.proj, now)
+	if err != nil {
+		return err
+	}
+	data.Balance = balance
+	data.ByRepo = flags.byRepo
+
 	// Check if period was previously submitted
 	submitted := isSubmitted(inputs.submits, inputs.from, inputs.to)
 
@@ -166,20 +194,31 @@ func parseMonthYearFlags(monthFlag, yearFlag string, now time.Time) (int, time.M
 	return year, month, nil
 }
 
-// parseReportDateRange resolves the from/to date range from --month, --week, --year flags.
+// parseReportDateRange resolves the from/to date range from --month, --week, --year,
+// --from and --to flags.
 // Rules:
 //   - --month + --week = error
 //   - --year alone = error
+//   - --from/--to with --month, --week or --year = error
+//   - --from without --to (or the reverse) = error
 //   - neither = default to current month
 //   - --month (with optional --year) = full month
 //   - --week (with optional --year) = ISO week Mon-Sun
-func parseReportDateRange(monthFlag, weekFlag, yearFlag string, monthChanged, weekChanged, yearChanged bool, now time.Time) (from, to time.Time, year int, month time.Month, err error) {
+//   - --from + --to = the dates from..to (inclusive)
+func parseReportDateRange(monthFlag, weekFlag, yearFlag, fromFlag, toFlag string, monthChanged, weekChanged, yearChanged bool, now time.Time) (from, to time.Time, err error) {
+	if fromFlag != "" || toFlag != "" {
+		if monthChanged || weekChanged || yearChanged {
+			return time.Time{}, time.Time{}, fmt.Errorf("--from and --to cannot be used with --month, --week or --year")
+		}
+		return parseCustomRange(fromFlag, toFlag)
+	}
+
 	if monthChanged && weekChanged {
-		return time.Time{}, time.Time{}, 0, 0, fmt.Errorf("--month and --week cannot be used together")
+		return time.Time{}, time.Time{}, fmt.Errorf("--month and --week cannot be used together")
 	}
 
 	if yearChanged && !monthChanged && !weekChanged {
-		return time.Time{}, time.Time{}, 0, 0, fmt.Errorf("--year must be used with --month or --week")
+		return time.Time{}, time.Time{}, fmt.Errorf("--year must be used with --month or --week")
 	}
 
 	if weekChanged {
@@ -189,22 +228,39 @@ func parseReportDateRange(monthFlag, weekFlag, yearFlag string, monthChanged, we
 	// Default to month view (handles both --month and no flags)
 	y, m, parseErr := parseMonthYearFlags(monthFlag, yearFlag, now)
 	if parseErr != nil {
-		return time.Time{}, time.Time{}, 0, 0, parseErr
+		return time.Time{}, time.Time{}, parseErr
 	}
 
-	daysInMonth := time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
-	from = time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
-	to = time.Date(y, m, daysInMonth, 0, 0, 0, 0, time.UTC)
-	return from, to, y, m, nil
+	from, to = timetrack.MonthRange(y, m)
+	return from, to, nil
+}
+
+// parseCustomRange parses --from and --to into an inclusive date range.
+func parseCustomRange(fromFlag, toFlag string) (from, to time.Time, err error) {
+	if fromFlag == "" || toFlag == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from and --to must be used together")
+	}
+	from, err = time.Parse("2006-01-02", fromFlag)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --from value %q (expected YYYY-MM-DD)", fromFlag)
+	}
+	to, err = time.Parse("2006-01-02", toFlag)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --to value %q (expected YYYY-MM-DD)", toFlag)
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--to %s is before --from %s", toFlag, fromFlag)
+	}
+	return from, to, nil
 }
 
 // parseWeekRange parses --week and --year into a Mon-Sun date range.
-func parseWeekRange(weekFlag, yearFlag string, now time.Time) (from, to time.Time, year int, month time.Month, err error) {
+func parseWeekRange(weekFlag, yearFlag string, now time.Time) (from, to time.Time, err error) {
 	y := now.Year()
 	if yearFlag != "" {
 		yy, parseErr := strconv.Atoi(yearFlag)
 		if parseErr != nil || yy <= 0 {
-			return time.Time{}, time.Time{}, 0, 0, fmt.Errorf("invalid --year value %q (expected a positive number)", yearFlag)
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --year value %q (expected a positive number)", yearFlag)
 		}
 		y = yy
 	}
@@ -219,16 +275,33 @@ func parseWeekRange(weekFlag, yearFlag string, now time.Time) (from, to time.Tim
 	} else {
 		w, parseErr := strconv.Atoi(weekFlag)
 		if parseErr != nil || w < 1 || w > 53 {
-			return time.Time{}, time.Time{}, 0, 0, fmt.Errorf("invalid --week value %q (expected 1-53)", weekFlag)
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --week value %q (expected 1-53)", weekFlag)
 		}
 		week = w
 	}
 
 	monday := isoWeekStart(y, week)
 	sunday := monday.AddDate(0, 0, 6)
+	return monday, sunday, nil
+}
 
-	// Use the month of the Monday as the primary month for display
-	return monday, sunday, monday.Year(), monday.Month(), nil
+// isFullMonth reports whether from..to covers exactly one calendar month.
+func isFullMonth(from, to time.Time) bool {
+	first, last := timetrack.MonthRange(from.Year(), from.Month())
+	return from.Equal(first) && to.Equal(last)
+}
+
+// periodLabel describes the dates from..to for titles and messages, e.g.
+// "June 2025" or "Sep 29 – Oct 5, 2025".
+func periodLabel(from, to time.Time) string {
+	switch {
+	case isFullMonth(from, to):
+		return fmt.Sprintf("%s %d", from.Month(), from.Year())
+	case from.Year() != to.Year():
+		return fmt.Sprintf("%s – %s", from.Format("Jan 2, 2006"), to.Format("Jan 2, 2006"))
+	default:
+		return fmt.Sprintf("%s – %s", from.Format("Jan 2"), to.Format("Jan 2, 2006"))
+	}
 }
 
 // isoWeekStart returns the Monday of the given ISO year and week.
@@ -249,13 +322,14 @@ func isoWeekStart(year, week int) time.Time {
 
 // loadReportInputs resolves the project and loads all entries, schedules, and
 // generated-day markers needed by both the interactive report and PDF export.
-func loadReportInputs(homeDir, repoDir, projectFlag, monthFlag, weekFlag, yearFlag string, monthChanged, weekChanged, yearChanged bool, now time.Time) (*reportInputs, error) {
-	proj, err := ResolveProjectContext(homeDir, repoDir, projectFlag)
+func loadReportInputs(homeDir, repoDir string, flags reportFlags, now time.Time) (*reportInputs, error) {
+	proj, err := ResolveProjectContext(homeDir, repoDir, flags.project)
 	if err != nil {
 		return nil, err
 	}
+	now = inProjectZone(proj, now)
 
-	from, to, year, month, err := parseReportDateRange(monthFlag, weekFlag, yearFlag, monthChanged, weekChanged, yearChanged, now)
+	from, to, err := parseReportDateRange(flags.month, flags.week, flags.year, flags.from, flags.to, flags.monthChanged, flags.weekChanged, flags.yearChanged, now)
 	if err != nil {
 		return nil, err
 	}
@@ -267,17 +341,16 @@ func loadReportInputs(homeDir, repoDir, projectFlag, monthFlag, weekFlag, yearFl
 
 	schedules := project.GetSchedules(cfg, proj.ID)
 
-	// Expand schedules to cover the full date range (may span multiple months for week view)
-	rangeStart := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
-	lastDay := time.Date(to.Year(), to.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
-	rangeEnd := time.Date(to.Year(), to.Month(), lastDay, 23, 59, 59, 0, time.UTC)
+	// Expand schedules to cover the full date range, plus the day before for
+	// the end of an overnight window when counting overtime
+	rangeEnd := time.Date(to.Year(), to.Month(), to.Day(), 23, 59, 59, 0, time.UTC)
 
-	daySchedules, err := schedule.ExpandSchedules(schedules, rangeStart, rangeEnd)
+	daySchedules, err := schedule.ExpandSchedules(schedules, from.AddDate(0, 0, -1), rangeEnd)
 	if err != nil {
 		return nil, err
 	}
 
-	entries, err := LoadProjectEntries(homeDir, proj.Slug)
+	entries, err := LoadProjectEntriesRange(homeDir, proj, from, to)
 	if err != nil {
 		return nil, err
 	}
@@ -287,25 +360,32 @@ func loadReportInputs(homeDir, repoDir, projectFlag, monthFlag, weekFlag, yearFl
 		return nil, err
 	}
 
+	opts, err := projectOptions(homeDir, cfg, proj, from, to, now)
+	if err != nil {
+		return nil, err
+	}
+
 	var weekNum int
-	if weekChanged {
+	if flags.weekChanged {
 		// Derive week number from the resolved Monday date
 		_, weekNum = from.ISOWeek()
 	}
 
 	return &reportInputs{
+		cfg:            cfg,
 		proj:           proj,
 		checkouts:      entries.Checkouts,
 		logs:           entries.Logs,
 		commits:        entries.Commits,
+		events:         entries.Events,
 		schedules:      daySchedules,
 		submits:        submits,
 		activityStops:  entries.ActivityStops,
 		activityStarts: entries.ActivityStarts,
+		opts:           opts,
 		from:           from,
 		to:             to,
-		year:           year,
-		month:          month,
 		weekNum:        weekNum,
+		now:            now,
 	}, nil
 }
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/Flyrell/hourgit/internal/entry"
 	"github.com/Flyrell/hourgit/internal/timetrack"
@@ -42,7 +43,7 @@ func renderExportPDF(data timetrack.ExportData, outputPath string) error {
 		}),
 	)
 	m.AddRow(8,
-		text.NewCol(12, fmt.Sprintf("%s %d", data.Month, data.Year), props.Text{
+		text.NewCol(12, periodLabel(data.From, data.To), props.Text{
 			Size:  12,
 			Color: &pdfMutedColor,
 		}),
@@ -56,6 +57,9 @@ func renderExportPDF(data timetrack.ExportData, outputPath string) error {
 		dayLabel := fmt.Sprintf("%s %d, %s",
 			day.Date.Month(), day.Date.Day(), weekday)
 		dayTotal := entry.FormatMinutes(day.TotalMinutes)
+		if data.Rounding.Enabled() {
+			dayTotal = roundedLabel(day.TotalMinutes, day.RawMinutes)
+		}
 
 		// Day header row
 		m.AddRow(8,
@@ -77,7 +81,7 @@ func renderExportPDF(data timetrack.ExportData, outputPath string) error {
 			if len(group.Entries) == 1 && group.Entries[0].Message == group.Task {
 				// Single-entry group where task == message: show as standalone row
 				m.AddRow(6,
-					text.NewCol(9, "  "+group.Task, props.Text{Size: 9}),
+					text.NewCol(9, "  "+taskLabel(group.Task, group.Category), props.Text{Size: 9}),
 					text.NewCol(3, entry.FormatMinutes(group.TotalMinutes), props.Text{
 						Size:  9,
 						Align: align.Right,
@@ -86,7 +90,7 @@ func renderExportPDF(data timetrack.ExportData, outputPath string) error {
 			} else {
 				// Task group header
 				m.AddRow(6,
-					text.NewCol(9, "  "+group.Task, props.Text{
+					text.NewCol(9, "  "+taskLabel(group.Task, group.Category), props.Text{
 						Style: fontstyle.Bold,
 						Size:  9,
 					}),
@@ -100,7 +104,7 @@ func renderExportPDF(data timetrack.ExportData, outputPath string) error {
 				// Individual entries
 				for _, e := range group.Entries {
 					m.AddRow(5,
-						text.NewCol(9, "    "+e.Message, props.Text{
+						text.NewCol(9, "    "+exportEntryLabel(e), props.Text{
 							Size:  8,
 							Color: &pdfMutedColor,
 						}),
@@ -114,6 +118,22 @@ func renderExportPDF(data timetrack.ExportData, outputPath string) error {
 			}
 		}
 
+		if day.OvertimeMinutes > 0 {
+			m.AddRow(6,
+				text.NewCol(9, "  Overtime", props.Text{
+					Style: fontstyle.Italic,
+					Size:  9,
+					Color: &pdfMutedColor,
+				}),
+				text.NewCol(3, entry.FormatMinutes(day.OvertimeMinutes), props.Text{
+					Style: fontstyle.Italic,
+					Size:  9,
+					Align: align.Right,
+					Color: &pdfMutedColor,
+				}),
+			)
+		}
+
 		// Spacer between days
 		m.AddRow(4)
 	}
@@ -133,6 +153,53 @@ func renderExportPDF(data timetrack.ExportData, outputPath string) error {
 			Color: &pdfHeaderColor,
 		}),
 	)
+	if data.OvertimeMinutes > 0 {
+		m.AddRow(6,
+			text.NewCol(9, "Overtime (not included above)", props.Text{
+				Size:  9,
+				Color: &pdfMutedColor,
+			}),
+			text.NewCol(3, entry.FormatMinutes(data.OvertimeMinutes), props.Text{
+				Size:  9,
+				Align: align.Right,
+				Color: &pdfMutedColor,
+			}),
+		)
+	}
+	if data.ByRepo {
+		for _, repo := range sortedRepos(data.RepoMinutes) {
+			m.AddRow(6,
+				text.NewCol(9, repoLabel(repo), props.Text{
+					Size:  9,
+					Color: &pdfMutedColor,
+				}),
+				text.NewCol(3, entry.FormatMinutes(data.RepoMinutes[repo]), props.Text{
+					Size:  9,
+					Align: align.Right,
+					Color: &pdfMutedColor,
+				}),
+			)
+		}
+	}
+	if data.Rounding.Enabled() {
+		m.AddRow(6,
+			text.NewCol(9, "Unrounded", props.Text{
+				Size:  9,
+				Color: &pdfMutedColor,
+			}),
+			text.NewCol(3, entry.FormatMinutes(data.RawMinutes), props.Text{
+				Size:  9,
+				Align: align.Right,
+				Color: &pdfMutedColor,
+			}),
+		)
+		m.AddRow(6,
+			text.NewCol(12, "Rounded "+data.Rounding.String(), props.Text{
+				Size:  8,
+				Color: &pdfMutedColor,
+			}),
+		)
+	}
 
 	doc, err := m.Generate()
 	if err != nil {
@@ -141,3 +208,38 @@ func renderExportPDF(data timetrack.ExportData, outputPath string) error {
 
 	return doc.Save(outputPath)
 }
+
+// sortedRepos returns the repositories of a breakdown, most time first.
+func sortedRepos(minutes map[string]int) []string {
+	repos := make([]string, 0, len(minutes))
+	for repo, mins := range minutes {
+		if mins > 0 {
+			repos = append(repos, repo)
+		}
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		if minutes[repos[i]] != minutes[repos[j]] {
+			return minutes[repos[i]] > minutes[repos[j]]
+		}
+		return repos[i] < repos[j]
+	})
+	return repos
+}
+
+// exportEntryLabel formats an entry's message, prefixed with the git event
+// that ended it, e.g. "[merge] Merge branch 'main'".
+func exportEntryLabel(e timetrack.ExportEntry) string {
+	if e.Event == "" {
+		return e.Message
+	}
+	return "[" + e.Event + "] " + e.Message
+}
+
+// roundedLabel formats rounded minutes, followed by the raw minutes when
+// rounding changed them, e.g. "7h 30m (raw 7h 22m)".
+func roundedLabel(minutes, raw int) string {
+	if minutes == raw {
+		return entry.FormatMinutes(minutes)
+	}
+	return fmt.Sprintf("%s (raw %s)", entry.FormatMinutes(minutes), entry.FormatMinutes(raw))
+}
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/rounding"
 	"github.com/Flyrell/hourgit/internal/timetrack"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,8 +19,8 @@ func TestRenderExportPDF_CreatesFile(t *testing.T) {
 
 	data := timetrack.ExportData{
 		ProjectName:  "Test Project",
-		Year:         2025,
-		Month:        time.January,
+		From:         reportDay(2025, time.January, 1),
+		To:           reportDay(2025, time.January, 28),
 		TotalMinutes: 330,
 		Days: []timetrack.ExportDay{
 			{
@@ -59,8 +61,8 @@ func TestRenderExportPDF_MultipleDays(t *testing.T) {
 
 	data := timetrack.ExportData{
 		ProjectName:  "Multi Day Project",
-		Year:         2025,
-		Month:        time.February,
+		From:         reportDay(2025, time.February, 1),
+		To:           reportDay(2025, time.February, 28),
 		TotalMinutes: 600,
 		Days: []timetrack.ExportDay{
 			{
@@ -106,8 +108,8 @@ func TestRenderExportPDF_EmptyData(t *testing.T) {
 
 	data := timetrack.ExportData{
 		ProjectName:  "Empty Project",
-		Year:         2025,
-		Month:        time.January,
+		From:         reportDay(2025, time.January, 1),
+		To:           reportDay(2025, time.January, 28),
 		TotalMinutes: 0,
 		Days:         nil,
 	}
@@ -119,3 +121,101 @@ func TestRenderExportPDF_EmptyData(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, info.Size() > 0)
 }
+
+func TestRenderExportPDF_Rounded(t *testing.T) {
+	dir := t.TempDir()
+	outPath := filepath.Join(dir, "rounded.pdf")
+
+	data := timetrack.ExportData{
+		ProjectName:  "Rounded Project",
+		From:         reportDay(2025, time.January, 1),
+		To:           reportDay(2025, time.January, 28),
+		TotalMinutes: 15,
+		RawMinutes:   10,
+		Rounding:     rounding.Policy{Increment: 15, Mode: rounding.ModeUp, Scope: rounding.ScopeEntry},
+		Days: []timetrack.ExportDay{
+			{
+				Date:         time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+				TotalMinutes: 15,
+				RawMinutes:   10,
+				Groups: []timetrack.ExportTaskGroup{
+					{
+						Task:         "review",
+						TotalMinutes: 15,
+						RawMinutes:   10,
+						Entries: []timetrack.ExportEntry{
+							{Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 15, RawMinutes: 10, Message: "review"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := renderExportPDF(data, outPath)
+	require.NoError(t, err)
+
+	info, err := os.Stat(outPath)
+	require.NoError(t, err)
+	assert.True(t, info.Size() > 0)
+}
+
+func TestRoundedLabel(t *testing.T) {
+	assert.Equal(t, "2h", roundedLabel(120, 120))
+	assert.Equal(t, "7h 30m (raw 7h 22m)", roundedLabel(450, 442))
+}
+
+func TestExportEntryLabel(t *testing.T) {
+	assert.Equal(t, "Add login form", exportEntryLabel(timetrack.ExportEntry{Message: "Add login form"}))
+	assert.Equal(t, "[rebase] rebase (finish): returning to refs/heads/feature-x",
+		exportEntryLabel(timetrack.ExportEntry{Message: "rebase (finish): returning to refs/heads/feature-x", Event: entry.EventRebase}))
+}
+
+func TestRenderExportPDF_Overtime(t *testing.T) {
+	dir := t.TempDir()
+	outPath := filepath.Join(dir, "overtime.pdf")
+
+	data := timetrack.ExportData{
+		ProjectName:     "Overtime Project",
+		From:            reportDay(2025, time.January, 1),
+		To:              reportDay(2025, time.January, 28),
+		OvertimeMinutes: 90,
+		Days: []timetrack.ExportDay{
+			{Date: time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC), OvertimeMinutes: 90},
+		},
+	}
+
+	err := renderExportPDF(data, outPath)
+	require.NoError(t, err)
+
+	info, err := os.Stat(outPath)
+	require.NoError(t, err)
+	assert.True(t, info.Size() > 0)
+}
+
+func TestRenderExportPDF_ByRepo(t *testing.T) {
+	dir := t.TempDir()
+	outPath := filepath.Join(dir, "repos.pdf")
+
+	data := timetrack.ExportData{
+		ProjectName:  "Repo Project",
+		From:         reportDay(2025, time.January, 1),
+		To:           reportDay(2025, time.January, 28),
+		TotalMinutes: 90,
+		ByRepo:       true,
+		RepoMinutes:  map[string]int{"/src/api": 60, "": 30},
+	}
+
+	err := renderExportPDF(data, outPath)
+	require.NoError(t, err)
+
+	info, err := os.Stat(outPath)
+	require.NoError(t, err)
+	assert.True(t, info.Size() > 0)
+}
+
+func TestSortedRepos(t *testing.T) {
+	repos := sortedRepos(map[string]int{"/b": 30, "/a": 30, "/c": 90, "/d": 0})
+
+	assert.Equal(t, []string{"/c", "/a", "/b"}, repos)
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/Flyrell/hourgit/internal/entry"
-	"github.com/Flyrell/hourgit/internal/hashutil"
 	"github.com/Flyrell/hourgit/internal/schedule"
 	"github.com/Flyrell/hourgit/internal/timetrack"
 	tea "github.com/charmbracelet/bubbletea"
@@ -412,9 +411,7 @@ const (
 )
 
 type addOverlay struct {
-	day     int
-	month   time.Month
-	year    int
+	day     time.Time
 	task    string // pre-filled from selected row
 	times   timeFields
 	message string
@@ -422,12 +419,10 @@ type addOverlay struct {
 	err     string
 }
 
-func newAddOverlay(day int, month time.Month, year int, task string) *addOverlay {
+func newAddOverlay(day time.Time, task string) *addOverlay {
 	return &addOverlay{
-		day:   day,
-		month: month,
-		year:  year,
-		task:  task,
+		day:  day,
+		task: task,
 		times: timeFields{
 			from: fmt.Sprintf("%d:00", defaultStartHour),
 		},
@@ -528,7 +523,7 @@ func (o *addOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (o *addOverlay) View() string {
 	var b strings.Builder
-	b.WriteString(overlayTitleStyle.Render(fmt.Sprintf("Add Entry — %s %d", o.month, o.day)))
+	b.WriteString(overlayTitleStyle.Render(fmt.Sprintf("Add Entry — %s %d", o.day.Month(), o.day.Day())))
 	b.WriteString("\n\n")
 
 	fields := []struct {
@@ -576,6 +571,8 @@ func (o *addOverlay) View() string {
 	return overlayBoxStyle.Render(b.String())
 }
 
+// buildEntry builds the new entry on the overlay's day, reading the entered
+// times in now's time zone.
 func (o *addOverlay) buildEntry(now time.Time) (entry.Entry, error) {
 	fromTOD, err := schedule.ParseTimeOfDay(o.times.from)
 	if err != nil {
@@ -601,8 +598,7 @@ func (o *addOverlay) buildEntry(now time.Time) (entry.Entry, error) {
 	}
 
 	return entry.Entry{
-		ID:        hashutil.GenerateID("add"),
-		Start:     time.Date(o.year, o.month, o.day, fromTOD.Hour, fromTOD.Minute, 0, 0, time.UTC),
+		Start:     time.Date(o.day.Year(), o.day.Month(), o.day.Day(), fromTOD.Hour, fromTOD.Minute, 0, 0, now.Location()),
 		Minutes:   mins,
 		Message:   msg,
 		Task:      task,
@@ -662,14 +658,17 @@ func (o *removeOverlay) View() string {
 type submitOverlay struct {
 	inMemoryCount int
 	from, to      time.Time
+	total, raw    int // rounded and raw minutes of the period
 	confirm       confirmOverlay
 }
 
-func newSubmitOverlay(inMemoryCount int, from, to time.Time) *submitOverlay {
+func newSubmitOverlay(inMemoryCount int, from, to time.Time, total, raw int) *submitOverlay {
 	return &submitOverlay{
 		inMemoryCount: inMemoryCount,
 		from:          from,
 		to:            to,
+		total:         total,
+		raw:           raw,
 		confirm:       confirmOverlay{action: "submit", cursor: 1},
 	}
 }
@@ -696,6 +695,12 @@ func (o *submitOverlay) View() string {
 	b.WriteString(period)
 	b.WriteString("\n\n")
 
+	fmt.Fprintf(&b, "  Total: %s", entry.FormatMinutes(o.total))
+	if o.raw != o.total {
+		fmt.Fprintf(&b, " (%s before rounding)", entry.FormatMinutes(o.raw))
+	}
+	b.WriteString("\n\n")
+
 	if o.inMemoryCount > 0 {
 		fmt.Fprintf(&b, "  %d generated entries will be persisted.\n\n", o.inMemoryCount)
 	} else {
@@ -242,7 +242,7 @@ func TestEditOverlay_View(t *testing.T) {
 }
 
 func TestAddOverlay_ValidSubmit(t *testing.T) {
-	o := newAddOverlay(5, time.January, 2025, "my-task")
+	o := newAddOverlay(reportDay(2025, time.January, 5), "my-task")
 	o.times.from = "9:00"
 	o.times.to = "10:00"
 	o.times.duration = "1h"
@@ -259,7 +259,7 @@ func TestAddOverlay_ValidSubmit(t *testing.T) {
 }
 
 func TestAddOverlay_EmptyTo(t *testing.T) {
-	o := newAddOverlay(5, time.January, 2025, "task")
+	o := newAddOverlay(reportDay(2025, time.January, 5), "task")
 	o.times.to = ""
 	o.field = addFieldConfirm
 
@@ -270,7 +270,7 @@ func TestAddOverlay_EmptyTo(t *testing.T) {
 }
 
 func TestAddOverlay_ToBeforeFrom(t *testing.T) {
-	o := newAddOverlay(5, time.January, 2025, "task")
+	o := newAddOverlay(reportDay(2025, time.January, 5), "task")
 	o.times.from = "5pm"
 	o.times.to = "9am"
 	o.field = addFieldConfirm
@@ -282,7 +282,7 @@ func TestAddOverlay_ToBeforeFrom(t *testing.T) {
 }
 
 func TestAddOverlay_BuildEntry(t *testing.T) {
-	o := newAddOverlay(5, time.January, 2025, "my-task")
+	o := newAddOverlay(reportDay(2025, time.January, 5), "my-task")
 	o.times.from = "9:00"
 	o.times.to = "11:00"
 	o.times.duration = "2h"
@@ -299,7 +299,7 @@ func TestAddOverlay_BuildEntry(t *testing.T) {
 }
 
 func TestAddOverlay_BuildEntryMessageFallback(t *testing.T) {
-	o := newAddOverlay(5, time.January, 2025, "my-task")
+	o := newAddOverlay(reportDay(2025, time.January, 5), "my-task")
 	o.times.from = "9:00"
 	o.times.to = "10:00"
 	o.times.duration = "1h"
@@ -356,7 +356,7 @@ func TestRemoveOverlay_View(t *testing.T) {
 func TestSubmitOverlay_Confirm(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
-	o := newSubmitOverlay(5, from, to)
+	o := newSubmitOverlay(5, from, to, 600, 600)
 
 	// Toggle to Yes
 	updated, _ := o.Update(tea.KeyMsg{Type: tea.KeyTab})
@@ -376,13 +376,24 @@ func TestSubmitOverlay_Confirm(t *testing.T) {
 func TestSubmitOverlay_View(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
-	o := newSubmitOverlay(3, from, to)
+	o := newSubmitOverlay(3, from, to, 600, 600)
 
 	view := o.View()
 	assert.Contains(t, view, "Submit Period")
 	assert.Contains(t, view, "3 generated entries")
 	assert.Contains(t, view, "Jan 1, 2025")
 	assert.Contains(t, view, "Jan 31, 2025")
+	assert.Contains(t, view, "Total: 10h")
+	assert.NotContains(t, view, "before rounding")
+}
+
+func TestSubmitOverlay_ViewRounded(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	o := newSubmitOverlay(0, from, to, 630, 607)
+
+	view := o.View()
+	assert.Contains(t, view, "Total: 10h 30m (10h 7m before rounding)")
 }
 
 func TestReportModel_CountInMemoryEntries(t *testing.T) {
@@ -391,12 +402,12 @@ func TestReportModel_CountInMemoryEntries(t *testing.T) {
 			Rows: []timetrack.DetailedTaskRow{
 				{
 					Name: "task",
-					Days: map[int]*timetrack.CellData{
-						1: {Entries: []timetrack.CellEntry{
+					Days: map[time.Time]*timetrack.CellData{
+						reportDay(2025, time.January, 1): {Entries: []timetrack.CellEntry{
 							{Persisted: true},
 							{Persisted: false},
 						}},
-						2: {Entries: []timetrack.CellEntry{
+						reportDay(2025, time.January, 2): {Entries: []timetrack.CellEntry{
 							{Persisted: false},
 						}},
 					},
@@ -415,8 +426,8 @@ func TestReportModel_AddCellEntry(t *testing.T) {
 				{
 					Name:         "existing",
 					TotalMinutes: 60,
-					Days: map[int]*timetrack.CellData{
-						1: {TotalMinutes: 60, Entries: []timetrack.CellEntry{{ID: "e100001", Minutes: 60}}},
+					Days: map[time.Time]*timetrack.CellData{
+						reportDay(2025, time.January, 1): {TotalMinutes: 60, Entries: []timetrack.CellEntry{{ID: "e100001", Minutes: 60}}},
 					},
 				},
 			},
@@ -425,13 +436,13 @@ func TestReportModel_AddCellEntry(t *testing.T) {
 
 	// Add to existing row
 	ce := timetrack.CellEntry{ID: "e200002", Minutes: 30}
-	m.addCellEntry("existing", 2, ce)
+	m.addCellEntry("existing", reportDay(2025, time.January, 2), ce)
 	assert.Equal(t, 90, m.data.Rows[0].TotalMinutes)
-	assert.NotNil(t, m.data.Rows[0].Days[2])
+	assert.NotNil(t, m.data.Rows[0].Days[reportDay(2025, time.January, 2)])
 
 	// Add to new task
 	ce2 := timetrack.CellEntry{ID: "e300003", Minutes: 45}
-	m.addCellEntry("new-task", 1, ce2)
+	m.addCellEntry("new-task", reportDay(2025, time.January, 1), ce2)
 	assert.Equal(t, 2, len(m.data.Rows))
 }
 
@@ -442,8 +453,8 @@ func TestReportModel_RemoveCellEntry(t *testing.T) {
 				{
 					Name:         "task",
 					TotalMinutes: 120,
-					Days: map[int]*timetrack.CellData{
-						1: {
+					Days: map[time.Time]*timetrack.CellData{
+						reportDay(2025, time.January, 1): {
 							TotalMinutes: 120,
 							Entries: []timetrack.CellEntry{
 								{ID: "e100001", Minutes: 60},
@@ -456,14 +467,14 @@ func TestReportModel_RemoveCellEntry(t *testing.T) {
 		},
 	}
 
-	m.removeCellEntry(0, 1, timetrack.CellEntry{ID: "e100001", Minutes: 60})
+	m.removeCellEntry(0, reportDay(2025, time.January, 1), timetrack.CellEntry{ID: "e100001", Minutes: 60})
 	assert.Equal(t, 60, m.data.Rows[0].TotalMinutes)
-	assert.Equal(t, 1, len(m.data.Rows[0].Days[1].Entries))
+	assert.Equal(t, 1, len(m.data.Rows[0].Days[reportDay(2025, time.January, 1)].Entries))
 
 	// Remove last entry — cell data should be cleaned up
-	m.removeCellEntry(0, 1, timetrack.CellEntry{ID: "e200002", Minutes: 60})
+	m.removeCellEntry(0, reportDay(2025, time.January, 1), timetrack.CellEntry{ID: "e200002", Minutes: 60})
 	assert.Equal(t, 0, m.data.Rows[0].TotalMinutes)
-	assert.Nil(t, m.data.Rows[0].Days[1])
+	assert.Nil(t, m.data.Rows[0].Days[reportDay(2025, time.January, 1)])
 }
 
 func TestReportModel_HandleEdit_PersistsInMemoryEntry(t *testing.T) {
@@ -502,13 +513,13 @@ func TestReportModel_HandleEdit_PersistsInMemoryEntry(t *testing.T) {
 		overlay: editOv,
 		mode:    modeEditing,
 		data: timetrack.DetailedReportData{
-			DaysInMonth: 31,
+			Dates: monthDates(2025, time.January),
 			Rows: []timetrack.DetailedTaskRow{
 				{
 					Name:         "feature-x",
 					TotalMinutes: 60,
-					Days: map[int]*timetrack.CellData{
-						2: {TotalMinutes: 60, Entries: []timetrack.CellEntry{ce}},
+					Days: map[time.Time]*timetrack.CellData{
+						reportDay(2025, time.January, 2): {TotalMinutes: 60, Entries: []timetrack.CellEntry{ce}},
 					},
 				},
 			},
@@ -579,7 +590,7 @@ func TestEditOverlay_Interdependency_DurationRecomputesTo(t *testing.T) {
 }
 
 func TestAddOverlay_FieldNavigation(t *testing.T) {
-	o := newAddOverlay(5, time.January, 2025, "my-task")
+	o := newAddOverlay(reportDay(2025, time.January, 5), "my-task")
 
 	assert.Equal(t, addFieldFrom, o.field)
 
@@ -601,7 +612,7 @@ func TestAddOverlay_FieldNavigation(t *testing.T) {
 }
 
 func TestAddOverlay_Interdependency(t *testing.T) {
-	o := newAddOverlay(5, time.January, 2025, "task")
+	o := newAddOverlay(reportDay(2025, time.January, 5), "task")
 	o.times.from = "9:00"
 	o.times.duration = "3h"
 
@@ -618,7 +629,7 @@ func TestAddOverlay_Interdependency(t *testing.T) {
 }
 
 func TestAddOverlay_BuildEntryUsesFromTo(t *testing.T) {
-	o := newAddOverlay(5, time.January, 2025, "my-task")
+	o := newAddOverlay(reportDay(2025, time.January, 5), "my-task")
 	o.times.from = "10:30"
 	o.times.to = "12:30"
 	o.times.duration = "2h"
@@ -633,7 +644,7 @@ func TestAddOverlay_BuildEntryUsesFromTo(t *testing.T) {
 }
 
 func TestAddOverlay_BuildEntryToBeforeFrom(t *testing.T) {
-	o := newAddOverlay(5, time.January, 2025, "task")
+	o := newAddOverlay(reportDay(2025, time.January, 5), "task")
 	o.times.from = "5pm"
 	o.times.to = "9am"
 
@@ -51,8 +51,17 @@ type reportModel struct {
 	overlay          tea.Model // active overlay (nil in normal mode)
 	homeDir          string
 	slug             string
-	submitted        bool   // whether period was previously submitted
-	footerMsg        string // temporary message shown in footer
+	submitted        bool           // whether period was previously submitted
+	footerMsg        string         // temporary message shown in footer
+	loc              *time.Location // project time zone for new entries (nil = local)
+}
+
+// now returns the current time in the project's time zone.
+func (m reportModel) now() time.Time {
+	if m.loc == nil {
+		return time.Now()
+	}
+	return time.Now().In(m.loc)
 }
 
 // currentCellEntries returns the entries for the currently selected cell, or nil.
@@ -60,11 +69,10 @@ func (m reportModel) currentCellEntries() []timetrack.CellEntry {
 	if m.cursorRow < 0 || m.cursorRow >= len(m.data.Rows) {
 		return nil
 	}
-	if m.cursorCol < 0 || m.cursorCol >= m.data.DaysInMonth {
+	if m.cursorCol < 0 || m.cursorCol >= len(m.data.Dates) {
 		return nil
 	}
-	day := m.cursorCol + 1
-	cd := m.data.Rows[m.cursorRow].Days[day]
+	cd := m.data.Rows[m.cursorRow].Days[m.data.Dates[m.cursorCol]]
 	if cd == nil {
 		return nil
 	}
@@ -82,8 +90,8 @@ func (m reportModel) visibleDays() int {
 	if cols < 1 {
 		cols = 1
 	}
-	if cols > m.data.DaysInMonth {
-		cols = m.data.DaysInMonth
+	if cols > len(m.data.Dates) {
+		cols = len(m.data.Dates)
 	}
 	return cols
 }
@@ -103,6 +111,12 @@ func (m reportModel) visibleRows() int {
 	if m.submitted {
 		reserved++
 	}
+	if m.data.OvertimeTotal() > 0 {
+		reserved++
+	}
+	if m.data.ByRepo {
+		reserved += len(m.data.RepoBreakdown())
+	}
 	reserved += m.detailPanelHeight()
 	available := m.termHeight - reserved
 	if available < 1 {
@@ -115,7 +129,7 @@ func (m reportModel) visibleRows() int {
 }
 
 func (m reportModel) maxScrollX() int {
-	max := m.data.DaysInMonth - m.visibleDays()
+	max := len(m.data.Dates) - m.visibleDays()
 	if max < 0 {
 		return 0
 	}
@@ -150,7 +164,7 @@ func (m reportModel) countInMemoryEntries() int {
 }
 
 // updateCellEntry updates an existing entry in the report data.
-func (m *reportModel) updateCellEntry(rowIdx, day int, updated timetrack.CellEntry) {
+func (m *reportModel) updateCellEntry(rowIdx int, day time.Time, updated timetrack.CellEntry) {
 	if rowIdx >= len(m.data.Rows) {
 		return
 	}
@@ -162,17 +176,15 @@ func (m *reportModel) updateCellEntry(rowIdx, day int, updated timetrack.CellEnt
 
 	for i, ce := range cd.Entries {
 		if (ce.ID != "" && ce.ID == updated.ID) || (ce.ID == "" && !ce.Persisted && ce.Message == updated.Message) {
-			oldMins := ce.Minutes
 			cd.Entries[i] = updated
-			cd.TotalMinutes += updated.Minutes - oldMins
-			row.TotalMinutes += updated.Minutes - oldMins
+			m.data.Recount()
 			break
 		}
 	}
 }
 
 // addCellEntry adds a new entry to the report data, finding or creating the appropriate row.
-func (m *reportModel) addCellEntry(task string, day int, ce timetrack.CellEntry) {
+func (m *reportModel) addCellEntry(task string, day time.Time, ce timetrack.CellEntry) {
 	// Find existing row for this task
 	rowIdx := -1
 	for i, row := range m.data.Rows {
@@ -185,15 +197,12 @@ func (m *reportModel) addCellEntry(task string, day int, ce timetrack.CellEntry)
 	if rowIdx == -1 {
 		// Create new row
 		m.data.Rows = append(m.data.Rows, timetrack.DetailedTaskRow{
-			Name:         task,
-			TotalMinutes: ce.Minutes,
-			Days: map[int]*timetrack.CellData{
-				day: {
-					Entries:      []timetrack.CellEntry{ce},
-					TotalMinutes: ce.Minutes,
-				},
+			Name: task,
+			Days: map[time.Time]*timetrack.CellData{
+				day: {Entries: []timetrack.CellEntry{ce}},
 			},
 		})
+		m.data.Recount()
 		return
 	}
 
@@ -204,12 +213,11 @@ func (m *reportModel) addCellEntry(task string, day int, ce timetrack.CellEntry)
 		row.Days[day] = cd
 	}
 	cd.Entries = append(cd.Entries, ce)
-	cd.TotalMinutes += ce.Minutes
-	row.TotalMinutes += ce.Minutes
+	m.data.Recount()
 }
 
 // removeCellEntry removes an entry from the report data.
-func (m *reportModel) removeCellEntry(rowIdx, day int, ce timetrack.CellEntry) {
+func (m *reportModel) removeCellEntry(rowIdx int, day time.Time, ce timetrack.CellEntry) {
 	if rowIdx >= len(m.data.Rows) {
 		return
 	}
@@ -228,8 +236,6 @@ func (m *reportModel) removeCellEntry(rowIdx, day int, ce timetrack.CellEntry) {
 		}
 		if match {
 			cd.Entries = append(cd.Entries[:i], cd.Entries[i+1:]...)
-			cd.TotalMinutes -= ce.Minutes
-			row.TotalMinutes -= ce.Minutes
 			break
 		}
 	}
@@ -238,6 +244,7 @@ func (m *reportModel) removeCellEntry(rowIdx, day int, ce timetrack.CellEntry) {
 	if len(cd.Entries) == 0 {
 		delete(row.Days, day)
 	}
+	m.data.Recount()
 }
 
 // ensureCursorVisible adjusts scroll so the cursor is within the visible viewport.
@@ -284,20 +291,15 @@ func runReportTable(cmd *cobra.Command, data timetrack.DetailedReportData, homeD
 		return printStaticDetailedTable(out, data)
 	}
 
-	// Start cursor on today's column if the report period contains today
-	initialCol := 0
-	if !now.Before(data.From) && !now.After(data.To) {
-		initialCol = now.Day() - 1
-	}
-
 	m := reportModel{
 		data:       data,
-		cursorCol:  initialCol,
+		cursorCol:  initialColumn(data, now),
 		termWidth:  120,
 		termHeight: 40,
 		homeDir:    homeDir,
 		slug:       slug,
 		submitted:  submitted,
+		loc:        now.Location(),
 	}
 
 	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithOutput(out))
@@ -305,7 +307,19 @@ func runReportTable(cmd *cobra.Command, data timetrack.DetailedReportData, homeD
 	return err
 }
 
+// initialColumn returns the column of today's date if the report period
+// contains it, and the first column otherwise.
+func initialColumn(data timetrack.DetailedReportData, now time.Time) int {
+	today := timetrack.DateOf(now)
+	for i, d := range data.Dates {
+		if d.Equal(today) {
+			return i
+		}
+	}
+	return 0
+}
+
 func printStaticDetailedTable(w io.Writer, data timetrack.DetailedReportData) error {
-	_, err := fmt.Fprint(w, renderDetailedTable(data, 0, 0, data.DaysInMonth, len(data.Rows), -1, -1, false, ""))
+	_, err := fmt.Fprint(w, renderDetailedTable(data, 0, 0, len(data.Dates), len(data.Rows), -1, -1, false, ""))
 	return err
 }
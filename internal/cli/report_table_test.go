@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Flyrell/hourgit/internal/rounding"
 	"github.com/Flyrell/hourgit/internal/timetrack"
 	"github.com/stretchr/testify/assert"
 )
@@ -55,21 +56,29 @@ func TestPadCenter(t *testing.T) {
 	})
 }
 
+// reportDay returns the report day key for the given date.
+func reportDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// monthDates returns every date of the given month.
+func monthDates(year int, month time.Month) []time.Time {
+	return timetrack.Dates(timetrack.MonthRange(year, month))
+}
+
 // makeScheduledDays returns a ScheduledDays map for Feb 2026 weekdays (Mon-Fri).
 // Feb 2026: 1=Sun, so weekdays are 2-6, 9-13, 16-20, 23-27.
-func makeScheduledDays() map[int]bool {
-	days := map[int]bool{}
+func makeScheduledDays() map[time.Time]bool {
+	days := map[time.Time]bool{}
 	for _, d := range []int{2, 3, 4, 5, 6, 9, 10, 11, 12, 13, 16, 17, 18, 19, 20, 23, 24, 25, 26, 27} {
-		days[d] = true
+		days[reportDay(2026, time.February, d)] = true
 	}
 	return days
 }
 
 func makeDetailedData() timetrack.DetailedReportData {
 	return timetrack.DetailedReportData{
-		Year:          2026,
-		Month:         time.February,
-		DaysInMonth:   28,
+		Dates:         monthDates(2026, time.February),
 		From:          time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
 		To:            time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
 		ScheduledDays: makeScheduledDays(),
@@ -77,14 +86,14 @@ func makeDetailedData() timetrack.DetailedReportData {
 			{
 				Name:         "feature-x",
 				TotalMinutes: 120,
-				Days: map[int]*timetrack.CellData{
-					2: {
+				Days: map[time.Time]*timetrack.CellData{
+					reportDay(2026, time.February, 2): {
 						TotalMinutes: 60,
 						Entries: []timetrack.CellEntry{
 							{ID: "e100001", Minutes: 60, Message: "work", Task: "feature-x", Source: "manual", Persisted: true},
 						},
 					},
-					3: {
+					reportDay(2026, time.February, 3): {
 						TotalMinutes: 60,
 						Entries: []timetrack.CellEntry{
 							{ID: "", Minutes: 60, Message: "feature-x", Task: "feature-x", Source: "checkout", Persisted: false},
@@ -95,8 +104,8 @@ func makeDetailedData() timetrack.DetailedReportData {
 			{
 				Name:         "bugfix",
 				TotalMinutes: 30,
-				Days: map[int]*timetrack.CellData{
-					2: {
+				Days: map[time.Time]*timetrack.CellData{
+					reportDay(2026, time.February, 2): {
 						TotalMinutes: 30,
 						Entries: []timetrack.CellEntry{
 							{ID: "e200002", Minutes: 30, Message: "fix", Task: "bugfix", Source: "manual", Persisted: true},
@@ -137,14 +146,72 @@ func TestRenderDetailedTableOutput(t *testing.T) {
 	assert.Contains(t, result, "x")
 }
 
+func TestRenderDetailedTableRounded(t *testing.T) {
+	data := makeDetailedData()
+	data.Rounding = rounding.Policy{Increment: 60, Mode: rounding.ModeUp, Scope: rounding.ScopeDay}
+	data.Recount()
+
+	day, raw := data.DayTotal(reportDay(2026, time.February, 2))
+	assert.Equal(t, 120, day)
+	assert.Equal(t, 90, raw)
+
+	var buf bytes.Buffer
+	assert.NoError(t, printStaticDetailedTable(&buf, data))
+	result := buf.String()
+	assert.Contains(t, result, "3h")
+	assert.Contains(t, result, "rounded 60 min, up, per day")
+}
+
+func TestRenderDetailedTableOvertime(t *testing.T) {
+	data := makeDetailedData()
+
+	result := renderDetailedTable(data, 0, 0, 5, len(data.Rows), -1, -1, false, "")
+	assert.NotContains(t, result, "Overtime")
+
+	data.Overtime = map[time.Time]int{reportDay(2026, time.February, 3): 95}
+	result = renderDetailedTable(data, 0, 0, 5, len(data.Rows), -1, -1, false, "")
+	assert.Contains(t, result, "Overtime")
+	assert.Contains(t, result, "1h 35m")
+}
+
+func TestRenderDetailedTableBalance(t *testing.T) {
+	data := makeDetailedData()
+
+	result := renderDetailedTable(data, 0, 0, 5, len(data.Rows), -1, -1, false, "")
+	assert.NotContains(t, result, "balance")
+
+	data.Balance = &timetrack.Balance{WorkedMinutes: 400, ScheduledMinutes: 480, CorrectionMinutes: 20}
+	result = renderDetailedTable(data, 0, 0, 5, len(data.Rows), -1, -1, false, "")
+	assert.Contains(t, result, "balance -1h")
+}
+
+func TestRenderDetailedTableByRepo(t *testing.T) {
+	data := makeDetailedData()
+	data.Rows[0].Days[reportDay(2026, time.February, 3)].Entries[0].Repo = "/home/dev/api"
+
+	result := renderDetailedTable(data, 0, 0, 5, len(data.Rows), -1, -1, false, "")
+	assert.NotContains(t, result, "repo:")
+
+	data.ByRepo = true
+	result = renderDetailedTable(data, 0, 0, 5, len(data.Rows), -1, -1, false, "")
+	assert.Contains(t, result, "repo: api")
+	assert.Contains(t, result, "repo: (logged)")
+}
+
+func TestRenderDetailedTableCategory(t *testing.T) {
+	data := makeDetailedData()
+	data.Rows[0].Category = "development"
+
+	result := renderDetailedTable(data, 0, 0, 5, len(data.Rows), -1, -1, false, "")
+	assert.Contains(t, result, "feature-x (development)")
+}
+
 func TestRenderDetailedTableWithFooter(t *testing.T) {
 	data := timetrack.DetailedReportData{
-		Year:        2026,
-		Month:       time.February,
-		DaysInMonth: 28,
-		From:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
-		To:          time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
-		Rows:        nil,
+		Dates: monthDates(2026, time.February),
+		From:  time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:    time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		Rows:  nil,
 	}
 
 	result := renderDetailedTable(data, 0, 0, 5, 0, -1, -1, false, "")
@@ -155,12 +222,10 @@ func TestRenderDetailedTableWithFooter(t *testing.T) {
 
 func TestRenderDetailedTableSubmittedWarning(t *testing.T) {
 	data := timetrack.DetailedReportData{
-		Year:        2026,
-		Month:       time.February,
-		DaysInMonth: 28,
-		From:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
-		To:          time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
-		Rows:        nil,
+		Dates: monthDates(2026, time.February),
+		From:  time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:    time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		Rows:  nil,
 	}
 
 	result := renderDetailedTable(data, 0, 0, 5, 0, -1, -1, true, "")
@@ -169,17 +234,15 @@ func TestRenderDetailedTableSubmittedWarning(t *testing.T) {
 
 func TestPrintStaticDetailedTable(t *testing.T) {
 	data := timetrack.DetailedReportData{
-		Year:        2026,
-		Month:       time.February,
-		DaysInMonth: 28,
-		From:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
-		To:          time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		Dates: monthDates(2026, time.February),
+		From:  time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:    time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
 		Rows: []timetrack.DetailedTaskRow{
 			{
 				Name:         "work",
 				TotalMinutes: 480,
-				Days: map[int]*timetrack.CellData{
-					1: {TotalMinutes: 480, Entries: []timetrack.CellEntry{
+				Days: map[time.Time]*timetrack.CellData{
+					reportDay(2026, time.February, 1): {TotalMinutes: 480, Entries: []timetrack.CellEntry{
 						{ID: "e100001", Minutes: 480, Message: "work", Persisted: true},
 					}},
 				},
@@ -202,7 +265,7 @@ func TestPrintStaticDetailedTable(t *testing.T) {
 func TestVisibleDays(t *testing.T) {
 	t.Run("wide terminal shows all days", func(t *testing.T) {
 		m := reportModel{
-			data:      timetrack.DetailedReportData{DaysInMonth: 28},
+			data:      timetrack.DetailedReportData{Dates: monthDates(2026, time.February)},
 			termWidth: 500,
 		}
 		assert.Equal(t, 28, m.visibleDays())
@@ -210,7 +273,7 @@ func TestVisibleDays(t *testing.T) {
 
 	t.Run("narrow terminal limits days", func(t *testing.T) {
 		m := reportModel{
-			data:      timetrack.DetailedReportData{DaysInMonth: 31},
+			data:      timetrack.DetailedReportData{Dates: monthDates(2026, time.March)},
 			termWidth: 80,
 		}
 		days := m.visibleDays()
@@ -220,7 +283,7 @@ func TestVisibleDays(t *testing.T) {
 
 	t.Run("very narrow terminal shows at least 1 day", func(t *testing.T) {
 		m := reportModel{
-			data:      timetrack.DetailedReportData{DaysInMonth: 31},
+			data:      timetrack.DetailedReportData{Dates: monthDates(2026, time.March)},
 			termWidth: 10,
 		}
 		assert.Equal(t, 1, m.visibleDays())
@@ -230,7 +293,7 @@ func TestVisibleDays(t *testing.T) {
 func TestMaxScrollX(t *testing.T) {
 	t.Run("wide terminal no scroll needed", func(t *testing.T) {
 		m := reportModel{
-			data:      timetrack.DetailedReportData{DaysInMonth: 28},
+			data:      timetrack.DetailedReportData{Dates: monthDates(2026, time.February)},
 			termWidth: 500,
 		}
 		assert.Equal(t, 0, m.maxScrollX())
@@ -238,7 +301,7 @@ func TestMaxScrollX(t *testing.T) {
 
 	t.Run("narrow terminal allows scroll", func(t *testing.T) {
 		m := reportModel{
-			data:      timetrack.DetailedReportData{DaysInMonth: 31},
+			data:      timetrack.DetailedReportData{Dates: monthDates(2026, time.March)},
 			termWidth: 80,
 		}
 		maxScroll := m.maxScrollX()
@@ -249,17 +312,15 @@ func TestMaxScrollX(t *testing.T) {
 
 func TestRenderDetailedTableScroll(t *testing.T) {
 	data := timetrack.DetailedReportData{
-		Year:        2026,
-		Month:       time.February,
-		DaysInMonth: 28,
-		From:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
-		To:          time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		Dates: monthDates(2026, time.February),
+		From:  time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:    time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
 		Rows: []timetrack.DetailedTaskRow{
 			{
 				Name:         "task",
 				TotalMinutes: 60,
-				Days: map[int]*timetrack.CellData{
-					15: {TotalMinutes: 60, Entries: []timetrack.CellEntry{
+				Days: map[time.Time]*timetrack.CellData{
+					reportDay(2026, time.February, 15): {TotalMinutes: 60, Entries: []timetrack.CellEntry{
 						{ID: "e100001", Minutes: 60, Persisted: true},
 					}},
 				},
@@ -281,7 +342,7 @@ func TestRenderDetailedTableScroll(t *testing.T) {
 func TestEnsureCursorVisible(t *testing.T) {
 	m := reportModel{
 		data: timetrack.DetailedReportData{
-			DaysInMonth: 28,
+			Dates: monthDates(2026, time.February),
 			Rows: []timetrack.DetailedTaskRow{
 				{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"},
 			},
@@ -314,11 +375,9 @@ func TestRenderDetailedTable_CursorHighlight(t *testing.T) {
 
 func TestRenderDetailedTable_FooterMsg(t *testing.T) {
 	data := timetrack.DetailedReportData{
-		Year:        2026,
-		Month:       time.February,
-		DaysInMonth: 28,
-		From:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
-		To:          time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		Dates: monthDates(2026, time.February),
+		From:  time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:    time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
 	}
 
 	result := renderDetailedTable(data, 0, 0, 5, 0, -1, -1, false, "Entry saved!")
@@ -328,18 +387,16 @@ func TestRenderDetailedTable_FooterMsg(t *testing.T) {
 func TestRenderDetailedTable_NonScheduledDaysShowX(t *testing.T) {
 	// Only day 2 (Mon) is scheduled; day 1 (Sun) is not
 	data := timetrack.DetailedReportData{
-		Year:          2026,
-		Month:         time.February,
-		DaysInMonth:   28,
+		Dates:         monthDates(2026, time.February),
 		From:          time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
 		To:            time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
-		ScheduledDays: map[int]bool{2: true},
+		ScheduledDays: map[time.Time]bool{reportDay(2026, time.February, 2): true},
 		Rows: []timetrack.DetailedTaskRow{
 			{
 				Name:         "task",
 				TotalMinutes: 60,
-				Days: map[int]*timetrack.CellData{
-					2: {TotalMinutes: 60, Entries: []timetrack.CellEntry{
+				Days: map[time.Time]*timetrack.CellData{
+					reportDay(2026, time.February, 2): {TotalMinutes: 60, Entries: []timetrack.CellEntry{
 						{ID: "e100001", Minutes: 60, Persisted: true},
 					}},
 				},
@@ -357,94 +414,71 @@ func TestRenderDetailedTable_NonScheduledDaysShowX(t *testing.T) {
 	assert.Contains(t, dataLine, "1h")
 }
 
-func TestInitialCursorCol(t *testing.T) {
-	t.Run("current month sets cursor to today", func(t *testing.T) {
-		now := time.Date(2026, 2, 15, 10, 0, 0, 0, time.UTC)
-		data := timetrack.DetailedReportData{
-			DaysInMonth: 28,
-			From:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
-			To:          time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
-		}
-
-		// Simulate the logic from runReportTable
-		initialCol := 0
-		if !now.Before(data.From) && !now.After(data.To) {
-			initialCol = now.Day() - 1
-		}
-
-		assert.Equal(t, 14, initialCol) // day 15 → 0-indexed = 14
-	})
-
-	t.Run("past month keeps cursor at 0", func(t *testing.T) {
-		now := time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
-		data := timetrack.DetailedReportData{
-			DaysInMonth: 28,
-			From:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
-			To:          time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
-		}
-
-		initialCol := 0
-		if !now.Before(data.From) && !now.After(data.To) {
-			initialCol = now.Day() - 1
-		}
-
-		assert.Equal(t, 0, initialCol)
-	})
-
-	t.Run("future month keeps cursor at 0", func(t *testing.T) {
-		now := time.Date(2026, 1, 20, 10, 0, 0, 0, time.UTC)
-		data := timetrack.DetailedReportData{
-			DaysInMonth: 28,
-			From:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
-			To:          time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
-		}
-
-		initialCol := 0
-		if !now.Before(data.From) && !now.After(data.To) {
-			initialCol = now.Day() - 1
-		}
-
-		assert.Equal(t, 0, initialCol)
-	})
-
-	t.Run("first day of month sets cursor to 0", func(t *testing.T) {
-		now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
-		data := timetrack.DetailedReportData{
-			DaysInMonth: 28,
-			From:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
-			To:          time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
-		}
-
-		initialCol := 0
-		if !now.Before(data.From) && !now.After(data.To) {
-			initialCol = now.Day() - 1
-		}
+func TestInitialColumn(t *testing.T) {
+	feb := timetrack.DetailedReportData{
+		From:  reportDay(2026, time.February, 1),
+		To:    reportDay(2026, time.February, 28),
+		Dates: monthDates(2026, time.February),
+	}
 
-		assert.Equal(t, 0, initialCol)
-	})
+	tests := []struct {
+		name string
+		data timetrack.DetailedReportData
+		now  time.Time
+		want int
+	}{
+		{"current month sets cursor to today", feb, time.Date(2026, 2, 15, 10, 0, 0, 0, time.UTC), 14},
+		{"past month keeps cursor at 0", feb, time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC), 0},
+		{"future month keeps cursor at 0", feb, time.Date(2026, 1, 20, 10, 0, 0, 0, time.UTC), 0},
+		{"first day of month sets cursor to 0", feb, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), 0},
+		{"last day of month sets cursor to last col", feb, time.Date(2026, 2, 28, 23, 0, 0, 0, time.UTC), 27},
+		{"cross-month week", timetrack.DetailedReportData{
+			From:  reportDay(2025, time.September, 29),
+			To:    reportDay(2025, time.October, 5),
+			Dates: timetrack.Dates(reportDay(2025, time.September, 29), reportDay(2025, time.October, 5)),
+		}, time.Date(2025, 10, 2, 9, 0, 0, 0, time.UTC), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, initialColumn(tt.data, tt.now))
+		})
+	}
+}
 
-	t.Run("last day of month sets cursor to last col", func(t *testing.T) {
-		now := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
-		data := timetrack.DetailedReportData{
-			DaysInMonth: 28,
-			From:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
-			To:          time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
-		}
+func TestRenderDetailedTable_CrossMonthWeek(t *testing.T) {
+	from, to := reportDay(2025, time.September, 29), reportDay(2025, time.October, 5)
+	data := timetrack.DetailedReportData{
+		From:  from,
+		To:    to,
+		Dates: timetrack.Dates(from, to),
+		Rows: []timetrack.DetailedTaskRow{
+			{
+				Name:         "task",
+				TotalMinutes: 90,
+				Days: map[time.Time]*timetrack.CellData{
+					reportDay(2025, time.September, 30): {TotalMinutes: 30, Entries: []timetrack.CellEntry{{ID: "e100001", Minutes: 30, Persisted: true}}},
+					reportDay(2025, time.October, 2):    {TotalMinutes: 60, Entries: []timetrack.CellEntry{{ID: "e200002", Minutes: 60, Persisted: true}}},
+				},
+			},
+		},
+	}
 
-		initialCol := 0
-		if !now.Before(data.From) && !now.After(data.To) {
-			initialCol = now.Day() - 1
-		}
+	result := renderDetailedTable(data, 0, 0, len(data.Dates), 1, -1, -1, false, "")
+	lines := strings.Split(result, "\n")
 
-		assert.Equal(t, 27, initialCol)
-	})
+	assert.Contains(t, lines[0], "Sep 29 – Oct 5, 2025")
+	assert.Contains(t, lines[1], "Mon 29")
+	assert.Contains(t, lines[1], "Thu 2")
+	assert.Contains(t, lines[1], "Sun 5")
+	assert.Contains(t, lines[3], "30m")
+	assert.Contains(t, lines[3], "1h")
 }
 
 func TestEnsureCursorVisibleScrollsToColumn(t *testing.T) {
 	m := reportModel{
 		data: timetrack.DetailedReportData{
-			DaysInMonth: 31,
-			Rows:        []timetrack.DetailedTaskRow{{Name: "task"}},
+			Dates: monthDates(2026, time.March),
+			Rows:  []timetrack.DetailedTaskRow{{Name: "task"}},
 		},
 		termWidth:  80,
 		termHeight: 20,
@@ -463,15 +497,15 @@ func TestEnsureCursorVisibleScrollsToColumn(t *testing.T) {
 
 func TestIsWeekend(t *testing.T) {
 	// Feb 1, 2026 = Sunday
-	assert.True(t, isWeekend(2026, time.February, 1))
+	assert.True(t, isWeekend(reportDay(2026, time.February, 1)))
 	// Feb 7, 2026 = Saturday
-	assert.True(t, isWeekend(2026, time.February, 7))
+	assert.True(t, isWeekend(reportDay(2026, time.February, 7)))
 	// Feb 2, 2026 = Monday
-	assert.False(t, isWeekend(2026, time.February, 2))
+	assert.False(t, isWeekend(reportDay(2026, time.February, 2)))
 }
 
 func TestDayAbbrev(t *testing.T) {
-	assert.Equal(t, "Sun", dayAbbrev(2026, time.February, 1))
-	assert.Equal(t, "Mon", dayAbbrev(2026, time.February, 2))
-	assert.Equal(t, "Sat", dayAbbrev(2026, time.February, 7))
+	assert.Equal(t, "Sun", dayAbbrev(reportDay(2026, time.February, 1)))
+	assert.Equal(t, "Mon", dayAbbrev(reportDay(2026, time.February, 2)))
+	assert.Equal(t, "Sat", dayAbbrev(reportDay(2026, time.February, 7)))
 }
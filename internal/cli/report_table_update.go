@@ -5,6 +5,7 @@ import (
 
 	"github.com/Flyrell/hourgit/internal/entry"
 	"github.com/Flyrell/hourgit/internal/hashutil"
+	"github.com/Flyrell/hourgit/internal/journal"
 	"github.com/Flyrell/hourgit/internal/timetrack"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -25,7 +26,7 @@ func (m reportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "q", "esc", "ctrl+c":
 			return m, tea.Quit
 		case "right", "l":
-			if m.cursorCol < m.data.DaysInMonth-1 {
+			if m.cursorCol < len(m.data.Dates)-1 {
 				m.cursorCol++
 				m.selectedEntryIdx = 0
 				m = m.ensureCursorVisible()
@@ -145,8 +146,9 @@ func (m reportModel) handleEdit() (tea.Model, tea.Cmd) {
 	}
 
 	ce := editor.entry
-	day := m.cursorCol + 1
+	day := m.data.Dates[m.cursorCol]
 
+	journal.Begin("report edit")
 	if ce.Persisted && ce.Entry != nil {
 		// Update existing persisted entry
 		ce.Entry.Start = ce.Start
@@ -161,12 +163,20 @@ func (m reportModel) handleEdit() (tea.Model, tea.Cmd) {
 		}
 	} else {
 		// Persist in-memory entry (checkout-generated)
+		id, err := entry.NewID(m.homeDir, m.slug, hashutil.TimeSeed("edit"))
+		if err != nil {
+			m.footerMsg = "Error saving: " + err.Error()
+			m.overlay = nil
+			m.mode = modeNormal
+			return m, nil
+		}
 		e := entry.Entry{
-			ID:        hashutil.GenerateID("edit"),
+			ID:        id,
 			Start:     ce.Start,
 			Minutes:   ce.Minutes,
 			Message:   ce.Message,
 			Task:      ce.Task,
+			Category:  ce.Category,
 			Source:    "checkout-generated",
 			CreatedAt: time.Now().UTC(),
 		}
@@ -197,13 +207,21 @@ func (m reportModel) handleAdd() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	e, err := adder.buildEntry(time.Now())
+	e, err := adder.buildEntry(m.now())
 	if err != nil {
 		m.footerMsg = "Error: " + err.Error()
 		m.overlay = nil
 		m.mode = modeNormal
 		return m, nil
 	}
+	journal.Begin("report add")
+	e.ID, err = entry.NewID(m.homeDir, m.slug, hashutil.TimeSeed("add"))
+	if err != nil {
+		m.footerMsg = "Error saving: " + err.Error()
+		m.overlay = nil
+		m.mode = modeNormal
+		return m, nil
+	}
 
 	if err := entry.WriteEntry(m.homeDir, m.slug, e); err != nil {
 		m.footerMsg = "Error saving: " + err.Error()
@@ -213,7 +231,7 @@ func (m reportModel) handleAdd() (tea.Model, tea.Cmd) {
 	}
 
 	// Add to report data
-	day := m.cursorCol + 1
+	day := m.data.Dates[m.cursorCol]
 	task := e.Task
 	if task == "" {
 		task = e.Message
@@ -225,6 +243,7 @@ func (m reportModel) handleAdd() (tea.Model, tea.Cmd) {
 		Minutes:   e.Minutes,
 		Message:   e.Message,
 		Task:      e.Task,
+		Category:  e.Category,
 		Source:    e.Source,
 		Persisted: true,
 		Entry:    &e,
@@ -246,9 +265,10 @@ func (m reportModel) handleRemove() (tea.Model, tea.Cmd) {
 	}
 
 	ce := remover.entry
-	day := m.cursorCol + 1
+	day := m.data.Dates[m.cursorCol]
 
 	if ce.Persisted {
+		journal.Begin("report remove")
 		if err := entry.DeleteEntry(m.homeDir, m.slug, ce.ID); err != nil {
 			m.footerMsg = "Error removing: " + err.Error()
 			m.overlay = nil
@@ -266,6 +286,15 @@ func (m reportModel) handleRemove() (tea.Model, tea.Cmd) {
 }
 
 func (m reportModel) handleSubmit() (tea.Model, tea.Cmd) {
+	journal.Begin("report submit")
+	ids, err := entry.LoadIDs(m.homeDir, m.slug)
+	if err != nil {
+		m.footerMsg = "Error submitting: " + err.Error()
+		m.overlay = nil
+		m.mode = modeNormal
+		return m, nil
+	}
+
 	// Persist all in-memory entries
 	for rowIdx := range m.data.Rows {
 		row := &m.data.Rows[rowIdx]
@@ -275,11 +304,12 @@ func (m reportModel) handleSubmit() (tea.Model, tea.Cmd) {
 					continue
 				}
 				e := entry.Entry{
-					ID:        hashutil.GenerateID("submit"),
+					ID:        ids.Allocate(hashutil.TimeSeed("submit")),
 					Start:     ce.Start,
 					Minutes:   ce.Minutes,
 					Message:   ce.Message,
 					Task:      ce.Task,
+					Category:  ce.Category,
 					Source:    "checkout-generated",
 					CreatedAt: time.Now().UTC(),
 				}
@@ -298,11 +328,15 @@ func (m reportModel) handleSubmit() (tea.Model, tea.Cmd) {
 	}
 
 	// Create submit marker
+	total, raw := m.data.Total()
 	submitEntry := entry.SubmitEntry{
-		ID:        hashutil.GenerateID("submit-marker"),
-		From:      m.data.From,
-		To:        m.data.To,
-		CreatedAt: time.Now().UTC(),
+		ID:         ids.Allocate(hashutil.TimeSeed("submit-marker")),
+		From:       m.data.From,
+		To:         m.data.To,
+		Minutes:    total,
+		RawMinutes: raw,
+		Rounding:   m.data.Rounding,
+		CreatedAt:  time.Now().UTC(),
 	}
 	if err := entry.WriteSubmitEntry(m.homeDir, m.slug, submitEntry); err != nil {
 		m.footerMsg = "Error creating submit marker: " + err.Error()
@@ -319,10 +353,10 @@ func (m reportModel) handleSubmit() (tea.Model, tea.Cmd) {
 }
 
 func (m reportModel) startEdit() (tea.Model, tea.Cmd) {
-	if m.cursorRow >= len(m.data.Rows) || m.cursorCol < 0 || m.cursorCol >= m.data.DaysInMonth {
+	if m.cursorRow >= len(m.data.Rows) || m.cursorCol < 0 || m.cursorCol >= len(m.data.Dates) {
 		return m, nil
 	}
-	day := m.cursorCol + 1
+	day := m.data.Dates[m.cursorCol]
 	cd := m.data.Rows[m.cursorRow].Days[day]
 	if cd == nil || len(cd.Entries) == 0 {
 		m.footerMsg = "No entries to edit in this cell"
@@ -338,24 +372,24 @@ func (m reportModel) startEdit() (tea.Model, tea.Cmd) {
 }
 
 func (m reportModel) startAdd() (tea.Model, tea.Cmd) {
-	if m.cursorCol < 0 || m.cursorCol >= m.data.DaysInMonth {
+	if m.cursorCol < 0 || m.cursorCol >= len(m.data.Dates) {
 		return m, nil
 	}
-	day := m.cursorCol + 1
+	day := m.data.Dates[m.cursorCol]
 	task := ""
 	if m.cursorRow >= 0 && m.cursorRow < len(m.data.Rows) {
 		task = m.data.Rows[m.cursorRow].Name
 	}
 	m.mode = modeAdding
-	m.overlay = newAddOverlay(day, m.data.Month, m.data.Year, task)
+	m.overlay = newAddOverlay(day, task)
 	return m, nil
 }
 
 func (m reportModel) startRemove() (tea.Model, tea.Cmd) {
-	if m.cursorRow >= len(m.data.Rows) || m.cursorCol < 0 || m.cursorCol >= m.data.DaysInMonth {
+	if m.cursorRow >= len(m.data.Rows) || m.cursorCol < 0 || m.cursorCol >= len(m.data.Dates) {
 		return m, nil
 	}
-	day := m.cursorCol + 1
+	day := m.data.Dates[m.cursorCol]
 	cd := m.data.Rows[m.cursorRow].Days[day]
 	if cd == nil || len(cd.Entries) == 0 {
 		m.footerMsg = "No entries to remove in this cell"
@@ -373,6 +407,7 @@ func (m reportModel) startRemove() (tea.Model, tea.Cmd) {
 func (m reportModel) startSubmit() (tea.Model, tea.Cmd) {
 	inMemoryCount := m.countInMemoryEntries()
 	m.mode = modeSubmitting
-	m.overlay = newSubmitOverlay(inMemoryCount, m.data.From, m.data.To)
+	total, raw := m.data.Total()
+	m.overlay = newSubmitOverlay(inMemoryCount, m.data.From, m.data.To, total, raw)
 	return m, nil
 }
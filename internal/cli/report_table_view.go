@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -74,14 +75,14 @@ func (m reportModel) renderDetailPanel() string {
 }
 
 // isWeekend returns true if the given date falls on Saturday or Sunday.
-func isWeekend(year int, month time.Month, day int) bool {
-	wd := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday()
+func isWeekend(day time.Time) bool {
+	wd := day.Weekday()
 	return wd == time.Saturday || wd == time.Sunday
 }
 
 // dayAbbrev returns a 3-letter weekday abbreviation for the given date.
-func dayAbbrev(year int, month time.Month, day int) string {
-	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday().String()[:3]
+func dayAbbrev(day time.Time) string {
+	return day.Weekday().String()[:3]
 }
 
 // renderDetailedTable produces the table string from DetailedReportData with cursor highlighting.
@@ -95,7 +96,7 @@ func renderDetailedTable(data timetrack.DetailedReportData, scrollX, scrollY, vi
 	}
 
 	// Title line
-	title := fmt.Sprintf("--- %s %d ---", data.Month, data.Year)
+	title := fmt.Sprintf("--- %s ---", periodLabel(data.From, data.To))
 	b.WriteString(headerStyle.Render(title))
 	b.WriteString("\n")
 
@@ -104,10 +105,10 @@ func renderDetailedTable(data timetrack.DetailedReportData, scrollX, scrollY, vi
 	b.WriteString(" | ")
 	b.WriteString(headerStyle.Render(padCenter("Sum", dayColWidth)))
 	for i := 0; i < visibleDays; i++ {
-		day := scrollX + i + 1
+		day := data.Dates[scrollX+i]
 		b.WriteString(" | ")
-		label := fmt.Sprintf("%s %d", dayAbbrev(data.Year, data.Month, day), day)
-		if isWeekend(data.Year, data.Month, day) {
+		label := fmt.Sprintf("%s %d", dayAbbrev(day), day.Day())
+		if isWeekend(day) {
 			b.WriteString(weekendStyle.Bold(true).Render(padCenter(label, dayColWidth)))
 		} else {
 			b.WriteString(headerStyle.Render(padCenter(label, dayColWidth)))
@@ -132,7 +133,7 @@ func renderDetailedTable(data timetrack.DetailedReportData, scrollX, scrollY, vi
 	}
 	for rowIdx := scrollY; rowIdx < endRow; rowIdx++ {
 		row := data.Rows[rowIdx]
-		label := row.Name
+		label := taskLabel(row.Name, row.Category)
 		if len(label) > taskColWidth {
 			label = label[:taskColWidth-3] + "..."
 		}
@@ -143,11 +144,11 @@ func renderDetailedTable(data timetrack.DetailedReportData, scrollX, scrollY, vi
 		b.WriteString(padCenter(entry.FormatMinutes(row.TotalMinutes), dayColWidth))
 
 		for i := 0; i < visibleDays; i++ {
-			day := scrollX + i + 1
+			day := data.Dates[scrollX+i]
 			colIdx := scrollX + i // 0-indexed day column
 			b.WriteString(" | ")
 
-			weekend := isWeekend(data.Year, data.Month, day)
+			weekend := isWeekend(day)
 			scheduled := data.ScheduledDays[day]
 
 			cd := row.Days[day]
@@ -199,10 +200,7 @@ func renderDetailedTable(data timetrack.DetailedReportData, scrollX, scrollY, vi
 	b.WriteString("\n")
 
 	// Totals row
-	totalMinutes := 0
-	for _, row := range data.Rows {
-		totalMinutes += row.TotalMinutes
-	}
+	totalMinutes, _ := data.Total()
 	b.WriteString(headerStyle.Render(padRight("Total", taskColWidth)))
 
 	// Grand total in Sum column
@@ -210,19 +208,13 @@ func renderDetailedTable(data timetrack.DetailedReportData, scrollX, scrollY, vi
 	b.WriteString(headerStyle.Render(padCenter(entry.FormatMinutes(totalMinutes), dayColWidth)))
 
 	for i := 0; i < visibleDays; i++ {
-		day := scrollX + i + 1
+		day := data.Dates[scrollX+i]
 		b.WriteString(" | ")
 
-		weekend := isWeekend(data.Year, data.Month, day)
+		weekend := isWeekend(day)
 		scheduled := data.ScheduledDays[day]
 
-		dayTotal := 0
-		for _, row := range data.Rows {
-			cd := row.Days[day]
-			if cd != nil {
-				dayTotal += cd.TotalMinutes
-			}
-		}
+		dayTotal, _ := data.DayTotal(day)
 		if dayTotal > 0 {
 			if weekend {
 				b.WriteString(weekendStyle.Bold(true).Render(padCenter(entry.FormatMinutes(dayTotal), dayColWidth)))
@@ -237,11 +229,58 @@ func renderDetailedTable(data timetrack.DetailedReportData, scrollX, scrollY, vi
 	}
 	b.WriteString("\n")
 
+	// Overtime row (separate overtime policy)
+	if overtime := data.OvertimeTotal(); overtime > 0 {
+		b.WriteString(footerStyle.Render(padRight("Overtime", taskColWidth)))
+		b.WriteString(" | ")
+		b.WriteString(footerStyle.Render(padCenter(entry.FormatMinutes(overtime), dayColWidth)))
+		for i := 0; i < visibleDays; i++ {
+			day := data.Dates[scrollX+i]
+			b.WriteString(" | ")
+			if mins := data.Overtime[day]; mins > 0 {
+				b.WriteString(footerStyle.Render(padCenter(entry.FormatMinutes(mins), dayColWidth)))
+			} else {
+				b.WriteString(dotStyle.Render(padCenter(".", dayColWidth)))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	// Per-repository rows (--by-repo)
+	if data.ByRepo {
+		for _, repo := range data.RepoBreakdown() {
+			label := repoLabel(repo.Repo)
+			if len(label) > taskColWidth {
+				label = label[:taskColWidth-3] + "..."
+			}
+			b.WriteString(footerStyle.Render(padRight(label, taskColWidth)))
+			b.WriteString(" | ")
+			b.WriteString(footerStyle.Render(padCenter(entry.FormatMinutes(repo.TotalMinutes), dayColWidth)))
+			for i := 0; i < visibleDays; i++ {
+				day := data.Dates[scrollX+i]
+				b.WriteString(" | ")
+				if mins := repo.Days[day]; mins > 0 {
+					b.WriteString(footerStyle.Render(padCenter(entry.FormatMinutes(mins), dayColWidth)))
+				} else {
+					b.WriteString(dotStyle.Render(padCenter(".", dayColWidth)))
+				}
+			}
+			b.WriteString("\n")
+		}
+	}
+
 	// Footer
 	b.WriteString("\n")
+	period := periodLabel(data.From, data.To)
+	if data.Rounding.Enabled() {
+		period += " (rounded " + data.Rounding.String() + ")"
+	}
+	if data.Balance != nil {
+		period += "  |  balance " + entry.FormatSignedMinutes(data.Balance.Minutes())
+	}
 	footer := fmt.Sprintf(
-		"%s %d  |  ←/→/↑/↓ navigate  |  tab cycle entries  |  e edit  |  a add  |  r remove  |  s submit  |  q quit",
-		data.Month, data.Year,
+		"%s  |  ←/→/↑/↓ navigate  |  tab cycle entries  |  e edit  |  a add  |  r remove  |  s submit  |  q quit",
+		period,
 	)
 	if footerMsg != "" {
 		footer = footerMsg + "  |  " + footer
@@ -252,6 +291,24 @@ func renderDetailedTable(data timetrack.DetailedReportData, scrollX, scrollY, vi
 	return b.String()
 }
 
+// repoLabel names a repository in the per-repository breakdown by its
+// directory name.
+func repoLabel(repo string) string {
+	if repo == "" {
+		return "repo: (logged)"
+	}
+	return "repo: " + filepath.Base(repo)
+}
+
+// taskLabel names a task in the report with its category, if it has one,
+// e.g. "PROJ-12 (development)".
+func taskLabel(task, category string) string {
+	if category == "" {
+		return task
+	}
+	return task + " (" + category + ")"
+}
+
 func padRight(s string, width int) string {
 	if len(s) >= width {
 		return s[:width]
@@ -16,16 +16,14 @@ func buildDetailPanelModel(entries []timetrack.CellEntry, selectedIdx int) repor
 	}
 	row := timetrack.DetailedTaskRow{
 		Name: "dev",
-		Days: map[int]*timetrack.CellData{
-			1: {Entries: entries, TotalMinutes: totalMin},
+		Days: map[time.Time]*timetrack.CellData{
+			reportDay(2026, time.March, 1): {Entries: entries, TotalMinutes: totalMin},
 		},
 	}
 	return reportModel{
 		data: timetrack.DetailedReportData{
-			Year:        2026,
-			Month:       time.March,
-			DaysInMonth: 31,
-			Rows:        []timetrack.DetailedTaskRow{row},
+			Dates: monthDates(2026, time.March),
+			Rows:  []timetrack.DetailedTaskRow{row},
 		},
 		cursorRow:        0,
 		cursorCol:        0,
@@ -36,8 +34,8 @@ func buildDetailPanelModel(entries []timetrack.CellEntry, selectedIdx int) repor
 func TestRenderDetailPanel_Empty(t *testing.T) {
 	m := reportModel{
 		data: timetrack.DetailedReportData{
-			DaysInMonth: 31,
-			Rows:        []timetrack.DetailedTaskRow{{Days: map[int]*timetrack.CellData{}}},
+			Dates: monthDates(2026, time.March),
+			Rows:  []timetrack.DetailedTaskRow{{Days: map[time.Time]*timetrack.CellData{}}},
 		},
 		cursorRow: 0,
 		cursorCol: 0,
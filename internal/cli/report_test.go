@@ -39,7 +39,7 @@ func execReport(homeDir, repoDir, projectFlag, monthFlag, yearFlag string) (stri
 	cmd := reportCmd
 	cmd.SetOut(stdout)
 
-	err := runReport(cmd, homeDir, repoDir, projectFlag, monthFlag, "", yearFlag, "", "", false, false, false, fixedNow)
+	err := runReport(cmd, homeDir, repoDir, reportFlags{project: projectFlag, month: monthFlag, year: yearFlag}, fixedNow)
 	return stdout.String(), err
 }
 
@@ -103,30 +103,24 @@ func TestParseMonthYearFlags_InvalidYear(t *testing.T) {
 
 func TestParseReportDateRange_DefaultMonth(t *testing.T) {
 	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
-	from, to, year, month, err := parseReportDateRange("", "", "", false, false, false, now)
+	from, to, err := parseReportDateRange("", "", "", "", "", false, false, false, now)
 	require.NoError(t, err)
-	assert.Equal(t, 2025, year)
-	assert.Equal(t, time.March, month)
 	assert.Equal(t, "2025-03-01", from.Format("2006-01-02"))
 	assert.Equal(t, "2025-03-31", to.Format("2006-01-02"))
 }
 
 func TestParseReportDateRange_ExplicitMonth(t *testing.T) {
 	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
-	from, to, year, month, err := parseReportDateRange("6", "", "", true, false, false, now)
+	from, to, err := parseReportDateRange("6", "", "", "", "", true, false, false, now)
 	require.NoError(t, err)
-	assert.Equal(t, 2025, year)
-	assert.Equal(t, time.June, month)
 	assert.Equal(t, "2025-06-01", from.Format("2006-01-02"))
 	assert.Equal(t, "2025-06-30", to.Format("2006-01-02"))
 }
 
 func TestParseReportDateRange_MonthWithYear(t *testing.T) {
 	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
-	from, to, year, month, err := parseReportDateRange("2", "", "2024", true, false, true, now)
+	from, to, err := parseReportDateRange("2", "", "2024", "", "", true, false, true, now)
 	require.NoError(t, err)
-	assert.Equal(t, 2024, year)
-	assert.Equal(t, time.February, month)
 	assert.Equal(t, "2024-02-01", from.Format("2006-01-02"))
 	assert.Equal(t, "2024-02-29", to.Format("2006-01-02")) // 2024 is leap year
 }
@@ -134,7 +128,7 @@ func TestParseReportDateRange_MonthWithYear(t *testing.T) {
 func TestParseReportDateRange_WeekCurrent(t *testing.T) {
 	// June 15, 2025 is a Sunday, ISO week 24
 	now := time.Date(2025, 6, 15, 14, 0, 0, 0, time.UTC)
-	from, to, _, _, err := parseReportDateRange("", "", "", false, true, false, now)
+	from, to, err := parseReportDateRange("", "", "", "", "", false, true, false, now)
 	require.NoError(t, err)
 
 	// Week 24 of 2025: Mon Jun 9 - Sun Jun 15
@@ -146,7 +140,7 @@ func TestParseReportDateRange_WeekCurrent(t *testing.T) {
 
 func TestParseReportDateRange_WeekExplicit(t *testing.T) {
 	now := time.Date(2025, 6, 15, 14, 0, 0, 0, time.UTC)
-	from, to, _, _, err := parseReportDateRange("", "1", "", false, true, false, now)
+	from, to, err := parseReportDateRange("", "1", "", "", "", false, true, false, now)
 	require.NoError(t, err)
 
 	// Week 1 of 2025
@@ -156,7 +150,7 @@ func TestParseReportDateRange_WeekExplicit(t *testing.T) {
 
 func TestParseReportDateRange_WeekWithYear(t *testing.T) {
 	now := time.Date(2025, 6, 15, 14, 0, 0, 0, time.UTC)
-	from, to, _, _, err := parseReportDateRange("", "22", "2025", false, true, true, now)
+	from, to, err := parseReportDateRange("", "22", "2025", "", "", false, true, true, now)
 	require.NoError(t, err)
 	assert.Equal(t, time.Monday, from.Weekday())
 	assert.Equal(t, time.Sunday, to.Weekday())
@@ -164,30 +158,76 @@ func TestParseReportDateRange_WeekWithYear(t *testing.T) {
 
 func TestParseReportDateRange_MonthAndWeekError(t *testing.T) {
 	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
-	_, _, _, _, err := parseReportDateRange("3", "10", "", true, true, false, now)
+	_, _, err := parseReportDateRange("3", "10", "", "", "", true, true, false, now)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "cannot be used together")
 }
 
 func TestParseReportDateRange_YearAloneError(t *testing.T) {
 	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
-	_, _, _, _, err := parseReportDateRange("", "", "2024", false, false, true, now)
+	_, _, err := parseReportDateRange("", "", "2024", "", "", false, false, true, now)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "must be used with")
 }
 
 func TestParseReportDateRange_InvalidWeek(t *testing.T) {
 	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
-	_, _, _, _, err := parseReportDateRange("", "0", "", false, true, false, now)
+	_, _, err := parseReportDateRange("", "0", "", "", "", false, true, false, now)
 	assert.Error(t, err)
 
-	_, _, _, _, err = parseReportDateRange("", "54", "", false, true, false, now)
+	_, _, err = parseReportDateRange("", "54", "", "", "", false, true, false, now)
 	assert.Error(t, err)
 
-	_, _, _, _, err = parseReportDateRange("", "abc", "", false, true, false, now)
+	_, _, err = parseReportDateRange("", "abc", "", "", "", false, true, false, now)
 	assert.Error(t, err)
 }
 
+func TestParseReportDateRange_FromTo(t *testing.T) {
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	from, to, err := parseReportDateRange("", "", "", "2025-09-29", "2025-10-05", false, false, false, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-09-29", from.Format("2006-01-02"))
+	assert.Equal(t, "2025-10-05", to.Format("2006-01-02"))
+
+	// A quarter
+	from, to, err = parseReportDateRange("", "", "", "2025-01-01", "2025-03-31", false, false, false, now)
+	require.NoError(t, err)
+	assert.Len(t, timetrack.Dates(from, to), 90)
+}
+
+func TestParseReportDateRange_FromToErrors(t *testing.T) {
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	_, _, err := parseReportDateRange("6", "", "", "2025-06-01", "2025-06-30", true, false, false, now)
+	assert.ErrorContains(t, err, "cannot be used with")
+
+	_, _, err = parseReportDateRange("", "", "", "2025-06-01", "", false, false, false, now)
+	assert.ErrorContains(t, err, "must be used together")
+
+	_, _, err = parseReportDateRange("", "", "", "06/01/2025", "2025-06-30", false, false, false, now)
+	assert.ErrorContains(t, err, "invalid --from")
+
+	_, _, err = parseReportDateRange("", "", "", "2025-06-30", "2025-06-01", false, false, false, now)
+	assert.ErrorContains(t, err, "is before --from")
+}
+
+func TestPeriodLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     string
+	}{
+		{"full month", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), "June 2025"},
+		{"cross-month week", time.Date(2025, 9, 29, 0, 0, 0, 0, time.UTC), time.Date(2025, 10, 5, 0, 0, 0, 0, time.UTC), "Sep 29 – Oct 5, 2025"},
+		{"cross-year week", time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC), "Dec 29, 2025 – Jan 4, 2026"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, periodLabel(tt.from, tt.to))
+		})
+	}
+}
+
 func TestIsoWeekStart(t *testing.T) {
 	t.Run("week 1 of 2025", func(t *testing.T) {
 		monday := isoWeekStart(2025, 1)
@@ -241,15 +281,29 @@ func TestReportWithLogEntries(t *testing.T) {
 	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, e))
 
 	now := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
-	inputs, err := loadReportInputs(homeDir, repoDir, "", "6", "", "2025", true, false, true, now)
+	inputs, err := loadReportInputs(homeDir, repoDir, reportFlags{month: "6", year: "2025", monthChanged: true, yearChanged: true}, now)
 	require.NoError(t, err)
 
-	data := timetrack.BuildDetailedReport(inputs.checkouts, inputs.logs, inputs.commits, inputs.schedules, inputs.from, inputs.to, now)
+	data := timetrack.BuildDetailedReport(inputs.checkouts, inputs.logs, inputs.commits, inputs.events, inputs.schedules, inputs.from, inputs.to, now, timetrack.Options{})
 	assert.Equal(t, 1, len(data.Rows))
 	assert.Equal(t, "research", data.Rows[0].Name)
 	assert.Equal(t, 120, data.Rows[0].TotalMinutes)
 }
 
+func TestLoadReportInputs_ProjectTimezone(t *testing.T) {
+	homeDir, repoDir, proj := setupReportTest(t)
+	require.NoError(t, project.SetTimezone(homeDir, proj.ID, "Asia/Tokyo"))
+
+	// Still June in UTC, already July in Tokyo
+	now := time.Date(2025, 6, 30, 20, 0, 0, 0, time.UTC)
+	inputs, err := loadReportInputs(homeDir, repoDir, reportFlags{}, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2025-07-01", inputs.from.Format("2006-01-02"))
+	assert.Equal(t, "Asia/Tokyo", inputs.now.Location().String())
+	assert.True(t, inputs.now.Equal(now))
+}
+
 func TestIsSubmitted(t *testing.T) {
 	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
@@ -295,7 +349,7 @@ func execReportWithExport(t *testing.T, homeDir, repoDir, monthFlag, weekFlag, y
 			mc := c.Flags().Changed("month")
 			wc := c.Flags().Changed("week")
 			yc := c.Flags().Changed("year")
-			return runReport(c, homeDir, repoDir, "", mf, wf, yf, ef, "", mc, wc, yc, fixedNow)
+			return runReport(c, homeDir, repoDir, reportFlags{month: mf, week: wf, year: yf, export: ef, monthChanged: mc, weekChanged: wc, yearChanged: yc}, fixedNow)
 		},
 	}.Build()
 
@@ -347,6 +401,33 @@ func TestReportExportFlag_GeneratesPDF(t *testing.T) {
 	assert.True(t, info.Size() > 0)
 }
 
+func TestReportExport_CustomRange(t *testing.T) {
+	homeDir, repoDir, proj := setupReportTest(t)
+
+	for _, e := range []entry.Entry{
+		{ID: "0df0011", Start: time.Date(2025, 9, 30, 10, 0, 0, 0, time.UTC), Minutes: 60, Message: "september", Task: "research", CreatedAt: time.Date(2025, 9, 30, 12, 0, 0, 0, time.UTC)},
+		{ID: "0df0012", Start: time.Date(2025, 10, 2, 10, 0, 0, 0, time.UTC), Minutes: 90, Message: "october", Task: "research", CreatedAt: time.Date(2025, 10, 2, 12, 0, 0, 0, time.UTC)},
+	} {
+		require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, e))
+	}
+
+	origDir, _ := os.Getwd()
+	tmpDir := t.TempDir()
+	require.NoError(t, os.Chdir(tmpDir))
+	t.Cleanup(func() { _ = os.Chdir(origDir) })
+
+	stdout := new(bytes.Buffer)
+	cmd := reportCmd
+	cmd.SetOut(stdout)
+	now := func() time.Time { return time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC) }
+	err := runReport(cmd, homeDir, repoDir, reportFlags{from: "2025-09-29", to: "2025-10-05", export: "pdf"}, now)
+	require.NoError(t, err)
+
+	expectedName := fmt.Sprintf("%s-2025-09-29-to-2025-10-05.pdf", proj.Slug)
+	assert.Contains(t, stdout.String(), "Exported report to "+expectedName)
+	assert.FileExists(t, filepath.Join(tmpDir, expectedName))
+}
+
 func TestReportExportFlag_EmptyMonth(t *testing.T) {
 	homeDir, repoDir, _ := setupReportTest(t)
 
@@ -405,7 +486,7 @@ func TestReportDetailFlag_InvalidValue(t *testing.T) {
 	cmd := reportCmd
 	cmd.SetOut(stdout)
 
-	err := runReport(cmd, homeDir, repoDir, "", "6", "", "2025", "pdf", "invalid", false, false, false, fixedNow)
+	err := runReport(cmd, homeDir, repoDir, reportFlags{month: "6", year: "2025", export: "pdf", detail: "invalid"}, fixedNow)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid --detail value")
 }
@@ -420,7 +501,7 @@ func TestReportDetailFlag_ValidValues(t *testing.T) {
 	// "summary" and "full" should not return a validation error
 	// (they'll print "No time entries" since no data exists)
 	for _, val := range []string{"", "summary", "full"} {
-		err := runReport(cmd, homeDir, repoDir, "", "1", "", "2025", "", val, false, false, false, fixedNow)
+		err := runReport(cmd, homeDir, repoDir, reportFlags{month: "1", year: "2025", detail: val}, fixedNow)
 		assert.NoError(t, err, "detail=%q should be valid", val)
 	}
 }
@@ -446,7 +527,7 @@ func TestReportShortFlags(t *testing.T) {
 			mc := c.Flags().Changed("month")
 			wc := c.Flags().Changed("week")
 			yc := c.Flags().Changed("year")
-			return runReport(c, homeDir, repoDir, "", mf, wf, yf, "", "", mc, wc, yc, fixedNow)
+			return runReport(c, homeDir, repoDir, reportFlags{month: mf, week: wf, year: yf, monthChanged: mc, weekChanged: wc, yearChanged: yc}, fixedNow)
 		},
 	}.Build()
 
@@ -466,3 +547,36 @@ func TestReportRegisteredAsSubcommand(t *testing.T) {
 	}
 	assert.Contains(t, names, "report")
 }
+
+func TestReportBalanceOnlyComputedForTable(t *testing.T) {
+	homeDir, repoDir, proj := setupReportTest(t)
+	e := entry.Entry{
+		ID:        "0df0013",
+		Start:     time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC),
+		Minutes:   60,
+		Message:   "research",
+		Task:      "research",
+		CreatedAt: time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, e))
+
+	// A balance that cannot be computed fails only the paths that show it
+	require.NoError(t, project.UpdateConfig(homeDir, func(cfg *project.Config) error {
+		project.FindProjectByID(cfg, proj.ID).BalanceStart = "not-a-date"
+		return nil
+	}))
+
+	origDir, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(origDir) })
+
+	stdout, err := execReport(homeDir, repoDir, "", "1", "2025")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "No time entries")
+
+	_, err = execReportWithExport(t, homeDir, repoDir, "6", "", "2025", "pdf")
+	require.NoError(t, err)
+
+	_, err = execReport(homeDir, repoDir, "", "6", "2025")
+	assert.ErrorContains(t, err, "invalid balance start date")
+}
@@ -3,7 +3,9 @@ package cli
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/Flyrell/hourgit/internal/gitdir"
 	"github.com/Flyrell/hourgit/internal/project"
 )
 
@@ -21,7 +23,9 @@ func getContextPaths() (homeDir, repoDir string, err error) {
 }
 
 // ResolveProjectContext finds the active project using the --project flag or
-// the current repo's .git/.hourgit config.
+// the current repo's .git/.hourgit config. A submodule without a config of its
+// own belongs to its superproject's project when that project attributes
+// submodules to itself.
 func ResolveProjectContext(homeDir, repoDir, projectFlag string) (*project.ProjectEntry, error) {
 	cfg, err := project.ReadConfig(homeDir)
 	if err != nil {
@@ -52,7 +56,22 @@ func ResolveProjectContext(homeDir, repoDir, projectFlag string) (*project.Proje
 			}
 			return nil, fmt.Errorf("project '%s' from repo config not found in registry", repoCfg.Project)
 		}
+		if dirs, err := gitdir.Resolve(repoDir); err == nil && dirs.Submodule() {
+			parent, err := ResolveProjectContext(homeDir, dirs.Super, "")
+			if err == nil && parent.Submodules == project.SubmodulesParent {
+				return parent, nil
+			}
+		}
 	}
 
 	return nil, fmt.Errorf("no project found (use --project or run from inside an assigned repo)")
 }
+
+// inProjectZone returns t in the project's time zone. Projects without one
+// keep the zone t already carries — the local one for time.Now.
+func inProjectZone(proj *project.ProjectEntry, t time.Time) time.Time {
+	if proj == nil || proj.Timezone == "" {
+		return t
+	}
+	return t.In(project.Location(proj))
+}
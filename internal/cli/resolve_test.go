@@ -2,6 +2,7 @@ package cli
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
@@ -86,3 +87,47 @@ func TestResolveProjectContextRepoNotAssigned(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "no project found")
 }
+
+// gitWithSubmodule creates a repository with one commit and a submodule at
+// lib, and returns both work trees.
+func gitWithSubmodule(t *testing.T) (repoDir, subDir string) {
+	t.Helper()
+	git := func(dir string, args ...string) {
+		args = append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com",
+			"-c", "protocol.file.allow=always"}, args...)
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	lib, repo := t.TempDir(), t.TempDir()
+	for _, dir := range []string{lib, repo} {
+		git(dir, "init", "-q")
+		git(dir, "commit", "-q", "--allow-empty", "-m", "init")
+	}
+	git(repo, "submodule", "add", "-q", lib, "lib")
+	return repo, filepath.Join(repo, "lib")
+}
+
+func TestResolveProjectContextSubmodule(t *testing.T) {
+	homeDir := t.TempDir()
+	repoDir, subDir := gitWithSubmodule(t)
+	entry, err := project.CreateProject(homeDir, "Parent")
+	require.NoError(t, err)
+	require.NoError(t, project.AssignProject(homeDir, repoDir, entry))
+
+	// A submodule is a repository of its own by default
+	_, err = ResolveProjectContext(homeDir, subDir, "")
+	assert.ErrorContains(t, err, "no project found")
+
+	require.NoError(t, project.SetSubmodules(homeDir, entry.ID, project.SubmodulesParent))
+	got, err := ResolveProjectContext(homeDir, subDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, entry.ID, got.ID)
+
+	// A project assigned to the submodule itself wins
+	other, err := project.CreateProject(homeDir, "Library")
+	require.NoError(t, err)
+	require.NoError(t, project.AssignProject(homeDir, subDir, other))
+	got, err = ResolveProjectContext(homeDir, subDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, got.ID)
+}
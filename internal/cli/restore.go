@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Flyrell/hourgit/internal/backup"
+	"github.com/Flyrell/hourgit/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var restoreCmd = LeafCommand{
+	Use:   "restore <archive>",
+	Short: "Restore hourgit data from a backup archive",
+	Args:  cobra.ExactArgs(1),
+	StrFlags: []StringFlag{
+		{Name: "map-repo", Usage: "rewrite repo paths, as OLD=NEW (comma-separated)"},
+	},
+	BoolFlags: []BoolFlag{
+		{Name: "replace", Usage: "replace all current data instead of merging (current data is backed up first)"},
+		{Name: "yes", Shorthand: "y", Usage: "skip confirmation prompt"},
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		mapRepo, _ := cmd.Flags().GetString("map-repo")
+		replace, _ := cmd.Flags().GetBool("replace")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		binPath, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("could not resolve binary path: %w", err)
+		}
+		binPath, err = filepath.EvalSymlinks(binPath)
+		if err != nil {
+			return fmt.Errorf("could not resolve binary path: %w", err)
+		}
+
+		return runRestore(cmd, homeDir, args[0], mapRepo, replace, binPath, ResolveConfirmFunc(yes))
+	},
+}.Build()
+
+func runRestore(cmd *cobra.Command, homeDir, archivePath, mapRepo string, replace bool, binPath string, confirm ConfirmFunc) error {
+	mappings, err := backup.ParsePathMappings(mapRepo)
+	if err != nil {
+		return err
+	}
+
+	a, err := backup.OpenFile(homeDir, archivePath)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	m := a.Manifest
+	_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("backup from %s (hourgit %s, schema v%d)",
+		Primary(m.CreatedAt.Local().Format("2006-01-02 15:04")), m.HourgitVersion, m.SchemaVersion)))
+	for _, p := range m.Projects {
+		_, _ = fmt.Fprintf(w, "  %s %s %s\n", Primary(p.Name), Silent("("+p.ID+")"), Text(fmt.Sprintf("%d entries", p.Entries)))
+	}
+
+	prompt := "Merge this backup into your hourgit data?"
+	if replace {
+		prompt = "Replace all hourgit data with this backup? (current data is backed up first)"
+	}
+	confirmed, err := confirm(prompt)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		_, _ = fmt.Fprintln(w, "cancelled")
+		return nil
+	}
+
+	result, err := backup.Restore(homeDir, a, backup.Options{
+		Replace: replace,
+		RepoMap: mappings,
+		InstallHook: func(repoDir string) error {
+			return ensureHook(repoDir, binPath)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.Backup != "" {
+		_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("previous data backed up to %s", Primary(result.Backup))))
+	}
+	_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("restored %d entries (%d project(s) added, %d merged, %d entries already present)",
+		result.EntriesRestored, result.ProjectsAdded, result.ProjectsMerged, result.EntriesSkipped)))
+	for _, c := range result.Conflicts {
+		_, _ = fmt.Fprintf(w, "%s\n", Warning(fmt.Sprintf("conflict: %s differs from the backup, kept the local version", c)))
+	}
+	for _, r := range result.Repos {
+		switch r.Status {
+		case backup.RepoRestored:
+			_, _ = fmt.Fprintf(w, "  %s %s\n", Info("restored"), Text(r.Path))
+		case backup.RepoMissing:
+			_, _ = fmt.Fprintf(w, "  %s %s %s\n", Warning("missing"), Text(r.Path), Silent("(use --map-repo if it moved)"))
+		default:
+			_, _ = fmt.Fprintf(w, "  %s %s %s\n", Error("failed"), Text(r.Path), Silent(r.Err.Error()))
+		}
+	}
+
+	if err := watch.EnsureWatcherService(homeDir, binPath); err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s\n",
+			Warning(fmt.Sprintf("warning: could not configure watcher service: %s", err)))
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execRestore(homeDir, archivePath, mapRepo string, replace bool, confirm ConfirmFunc) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := restoreCmd
+	cmd.SetOut(stdout)
+	cmd.SetErr(new(bytes.Buffer))
+
+	err := runRestore(cmd, homeDir, archivePath, mapRepo, replace, "/usr/local/bin/hourgit", confirm)
+	return stdout.String(), err
+}
+
+// backupArchive writes a backup of homeDir and returns its path.
+func backupArchive(t *testing.T, homeDir string) string {
+	t.Helper()
+	output := filepath.Join(t.TempDir(), "backup.tar.gz")
+	_, err := execBackup(homeDir, output, false)
+	require.NoError(t, err)
+	return output
+}
+
+func TestRestoreMerge(t *testing.T) {
+	src, proj := setupBackupData(t)
+	archive := backupArchive(t, src)
+	homeDir := t.TempDir()
+
+	stdout, err := execRestore(homeDir, archive, "", false, AlwaysYes())
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Acme")
+	assert.Contains(t, stdout, "restored 1 entries (1 project(s) added, 0 merged, 0 entries already present)")
+	e, err := entry.ReadEntry(homeDir, proj.Slug, "aaaa11112222")
+	require.NoError(t, err)
+	assert.Equal(t, "work", e.Message)
+}
+
+func TestRestoreEncryptedArchive(t *testing.T) {
+	src, proj := setupBackupData(t)
+	t.Setenv(seal.EnvPassphrase, "secret")
+	_, err := execEncrypt(src, false, nil)
+	require.NoError(t, err)
+	archive := backupArchive(t, src)
+	homeDir := t.TempDir()
+
+	_, err = execRestore(homeDir, archive, "", false, AlwaysYes())
+
+	require.NoError(t, err)
+	e, err := entry.ReadEntry(homeDir, proj.Slug, "aaaa11112222")
+	require.NoError(t, err)
+	assert.Equal(t, "work", e.Message)
+}
+
+func TestRestoreDeclined(t *testing.T) {
+	src, _ := setupBackupData(t)
+	archive := backupArchive(t, src)
+	homeDir := t.TempDir()
+
+	stdout, err := execRestore(homeDir, archive, "", false, func(string) (bool, error) { return false, nil })
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "cancelled")
+	_, err = os.Stat(project.ConfigPath(homeDir))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRestoreReplaceReportsBackup(t *testing.T) {
+	src, _ := setupBackupData(t)
+	archive := backupArchive(t, src)
+	homeDir := t.TempDir()
+	_, err := project.CreateProject(homeDir, "Local")
+	require.NoError(t, err)
+
+	stdout, err := execRestore(homeDir, archive, "", true, AlwaysYes())
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "previous data backed up to")
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	require.Len(t, cfg.Projects, 1)
+	assert.Equal(t, "Acme", cfg.Projects[0].Name)
+}
+
+func TestRestoreInstallsHookInMappedRepo(t *testing.T) {
+	src, proj := setupBackupData(t)
+	oldRepo := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, os.MkdirAll(filepath.Join(oldRepo, ".git"), 0755))
+	require.NoError(t, project.AssignProject(src, oldRepo, proj))
+	archive := backupArchive(t, src)
+
+	newRepo := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, os.MkdirAll(filepath.Join(newRepo, ".git", "hooks"), 0755))
+	hookPath := filepath.Join(newRepo, ".git", "hooks", "post-checkout")
+	require.NoError(t, os.WriteFile(hookPath, []byte("#!/bin/sh\necho existing\n"), 0755))
+
+	homeDir := t.TempDir()
+	stdout, err := execRestore(homeDir, archive, filepath.Dir(oldRepo)+"="+filepath.Dir(newRepo), false, AlwaysYes())
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "restored")
+	assert.Contains(t, stdout, newRepo)
+
+	hook, err := os.ReadFile(hookPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(hook), "#!/bin/sh\necho existing\n"), "existing hook should be kept")
+	assert.Contains(t, string(hook), project.HookMarker)
+
+	rc, err := project.ReadRepoConfig(newRepo)
+	require.NoError(t, err)
+	assert.Equal(t, proj.ID, rc.ProjectID)
+}
+
+func TestRestoreReportsMissingRepo(t *testing.T) {
+	src, proj := setupBackupData(t)
+	repo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".git"), 0755))
+	require.NoError(t, project.AssignProject(src, repo, proj))
+	archive := backupArchive(t, src)
+	require.NoError(t, os.RemoveAll(repo))
+
+	stdout, err := execRestore(t.TempDir(), archive, "", false, AlwaysYes())
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "missing")
+	assert.Contains(t, stdout, "--map-repo")
+}
+
+func TestRestoreInvalidArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.tar.gz")
+	require.NoError(t, os.WriteFile(path, []byte("garbage"), 0644))
+
+	_, err := execRestore(t.TempDir(), path, "", false, AlwaysYes())
+
+	assert.ErrorContains(t, err, "not a hourgit backup")
+}
@@ -2,7 +2,14 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/Flyrell/hourgit/internal/paths"
+	"github.com/Flyrell/hourgit/internal/seal"
+
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
@@ -19,12 +26,22 @@ func newRootCmd() *cobra.Command {
 			reportCmd,
 			historyCmd,
 			statusCmd,
+			balanceCmd,
 			versionCmd,
 			projectCmd,
 			defaultsCmd,
 			completionCmd,
 			updateCmd,
 			watchCmd,
+			storageCmd,
+			fsckCmd,
+			undoCmd,
+			migrateCmd,
+			backupCmd,
+			restoreCmd,
+			remoteCmd,
+			encryptCmd,
+			decryptCmd,
 		},
 	}.Build()
 	cmd.SilenceUsage = true
@@ -34,6 +51,28 @@ func newRootCmd() *cobra.Command {
 	cmd.PersistentFlags().Bool("skip-updates", false, "skip the automatic update check")
 	cmd.PersistentFlags().Bool("skip-watcher", false, "skip the file watcher health check")
 	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		command := strings.TrimPrefix(cmd.CommandPath(), "hourgit ")
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		layout := paths.Resolve(homeDir, os.Getenv)
+		paths.Use(homeDir, layout)
+		if isatty.IsTerminal(os.Stdin.Fd()) {
+			password := NewPasswordFunc()
+			seal.SetPassphraseFunc(func() (string, error) { return password("Passphrase for hourgit data") })
+		}
+		// Migrations are journaled apart from the command, and never undone
+		journal.Begin(journal.CommandAutoMigrate)
+		if err := moveLegacyData(cmd, homeDir, layout); err != nil {
+			return err
+		}
+		if !skipAutoMigrate[command] {
+			if err := autoMigrate(cmd, homeDir); err != nil {
+				return err
+			}
+		}
+		journal.Begin(command)
 		checkForUpdate(cmd, defaultUpdateDeps())
 		checkWatcherHealth(cmd, defaultWatcherCheckDeps())
 		return nil
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/rules"
+	"github.com/spf13/cobra"
+)
+
+var rulesAddCmd = LeafCommand{
+	Use:   "add",
+	Short: "Add a rule assigning a task or category to matching time",
+	StrFlags: []StringFlag{
+		{Name: "project", Shorthand: "p", Usage: "project name or ID (auto-detected from repo if omitted)"},
+		{Name: "branch", Usage: "regular expression matching the branch name, e.g. ^feature/(PROJ-\\d+)"},
+		{Name: "commit", Usage: "regular expression matching the commit message, e.g. ^fix(\\(.+\\))?:"},
+		{Name: "repo", Usage: "regular expression matching the repository path"},
+		{Name: "task", Usage: "task key to assign; $1 refers to the first group of the first matcher"},
+		{Name: "category", Usage: "category to assign, e.g. development, review or maintenance"},
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, repoDir, err := getContextPaths()
+		if err != nil {
+			return err
+		}
+
+		projectFlag, _ := cmd.Flags().GetString("project")
+		branchFlag, _ := cmd.Flags().GetString("branch")
+		commitFlag, _ := cmd.Flags().GetString("commit")
+		repoFlag, _ := cmd.Flags().GetString("repo")
+		taskFlag, _ := cmd.Flags().GetString("task")
+		categoryFlag, _ := cmd.Flags().GetString("category")
+
+		rule := rules.Rule{
+			Branch:   branchFlag,
+			Commit:   commitFlag,
+			Repo:     repoFlag,
+			Task:     taskFlag,
+			Category: categoryFlag,
+		}
+		return runRulesAdd(cmd, homeDir, repoDir, projectFlag, rule)
+	},
+}.Build()
+
+func runRulesAdd(cmd *cobra.Command, homeDir, repoDir, projectFlag string, rule rules.Rule) error {
+	entry, err := ResolveProjectContext(homeDir, repoDir, projectFlag)
+	if err != nil {
+		return err
+	}
+
+	if err := project.AddRule(homeDir, entry.ID, rule); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("added rule %d to '%s': %s", len(entry.Rules)+1, Primary(entry.Name), rule.String())))
+	return nil
+}
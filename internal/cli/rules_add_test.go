@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execRulesAdd(homeDir, repoDir, projectFlag string, rule rules.Rule) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := rulesAddCmd
+	cmd.SetOut(stdout)
+	err := runRulesAdd(cmd, homeDir, repoDir, projectFlag, rule)
+	return stdout.String(), err
+}
+
+func TestRulesAdd(t *testing.T) {
+	homeDir, repoDir, entry := setupScheduleTest(t)
+	rule := rules.Rule{Branch: `^feature/(PROJ-\d+)`, Task: "$1", Category: rules.CategoryDevelopment}
+
+	stdout, err := execRulesAdd(homeDir, repoDir, "", rule)
+
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "added rule 1")
+
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	assert.Equal(t, []rules.Rule{rule}, project.FindProjectByID(cfg, entry.ID).Rules)
+}
+
+func TestRulesAddInvalid(t *testing.T) {
+	homeDir, repoDir, _ := setupScheduleTest(t)
+
+	_, err := execRulesAdd(homeDir, repoDir, "", rules.Rule{Branch: "(", Task: "x"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid branch expression")
+
+	_, err = execRulesAdd(homeDir, repoDir, "", rules.Rule{Task: "x"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "matches nothing")
+}
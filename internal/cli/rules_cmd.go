@@ -0,0 +1,13 @@
+package cli
+
+import "github.com/spf13/cobra"
+
+var rulesCmd = GroupCommand{
+	Use:   "rules",
+	Short: "Manage rules mapping branches, commits and repos to tasks",
+	Subcommands: []*cobra.Command{
+		rulesListCmd,
+		rulesAddCmd,
+		rulesRemoveCmd,
+	},
+}.Build()
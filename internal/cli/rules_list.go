@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var rulesListCmd = LeafCommand{
+	Use:   "list",
+	Short: "Show the task rules of a project",
+	StrFlags: []StringFlag{
+		{Name: "project", Shorthand: "p", Usage: "project name or ID (auto-detected from repo if omitted)"},
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, repoDir, err := getContextPaths()
+		if err != nil {
+			return err
+		}
+
+		projectFlag, _ := cmd.Flags().GetString("project")
+
+		return runRulesList(cmd, homeDir, repoDir, projectFlag)
+	},
+}.Build()
+
+func runRulesList(cmd *cobra.Command, homeDir, repoDir, projectFlag string) error {
+	entry, err := ResolveProjectContext(homeDir, repoDir, projectFlag)
+	if err != nil {
+		return err
+	}
+
+	if len(entry.Rules) == 0 {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("no task rules for '%s' (time is grouped by branch)", Primary(entry.Name))))
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("Task rules for '%s' (first match wins):", Primary(entry.Name))))
+	for i, r := range entry.Rules {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s %s\n", Silent(fmt.Sprintf("%d.", i+1)), Text(r.String()))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execRulesList(homeDir, repoDir, projectFlag string) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := rulesListCmd
+	cmd.SetOut(stdout)
+	err := runRulesList(cmd, homeDir, repoDir, projectFlag)
+	return stdout.String(), err
+}
+
+func TestRulesListEmpty(t *testing.T) {
+	homeDir, repoDir, _ := setupScheduleTest(t)
+
+	stdout, err := execRulesList(homeDir, repoDir, "")
+
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "no task rules")
+}
+
+func TestRulesListNumbersRules(t *testing.T) {
+	homeDir, _, entry := setupScheduleTest(t)
+	require.NoError(t, project.AddRule(homeDir, entry.ID, rules.Rule{Branch: `^\w+/(PROJ-\d+)`, Task: "$1"}))
+	require.NoError(t, project.AddRule(homeDir, entry.ID, rules.Rule{Commit: `^chore:`, Category: rules.CategoryMaintenance}))
+
+	stdout, err := execRulesList(homeDir, "", entry.Name)
+
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "Test Project")
+	assert.Contains(t, stdout, `1. branch ^\w+/(PROJ-\d+) → task $1`)
+	assert.Contains(t, stdout, "2. commit ^chore: → category maintenance")
+}
+
+func TestRulesRegisteredUnderProject(t *testing.T) {
+	commands := projectCmd.Commands()
+	names := make([]string, len(commands))
+	for i, cmd := range commands {
+		names[i] = cmd.Name()
+	}
+	assert.Contains(t, names, "rules")
+
+	names = names[:0]
+	for _, cmd := range rulesCmd.Commands() {
+		names = append(names, cmd.Name())
+	}
+	assert.ElementsMatch(t, []string{"list", "add", "remove"}, names)
+}
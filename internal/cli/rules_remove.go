@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var rulesRemoveCmd = LeafCommand{
+	Use:   "remove <number>",
+	Short: "Remove a task rule by its number in rules list",
+	Args:  cobra.ExactArgs(1),
+	StrFlags: []StringFlag{
+		{Name: "project", Shorthand: "p", Usage: "project name or ID (auto-detected from repo if omitted)"},
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, repoDir, err := getContextPaths()
+		if err != nil {
+			return err
+		}
+
+		projectFlag, _ := cmd.Flags().GetString("project")
+
+		return runRulesRemove(cmd, homeDir, repoDir, projectFlag, args[0])
+	},
+}.Build()
+
+func runRulesRemove(cmd *cobra.Command, homeDir, repoDir, projectFlag, number string) error {
+	index, err := strconv.Atoi(number)
+	if err != nil {
+		return fmt.Errorf("invalid rule number %q (see 'hourgit project rules list')", number)
+	}
+
+	entry, err := ResolveProjectContext(homeDir, repoDir, projectFlag)
+	if err != nil {
+		return err
+	}
+
+	removed, err := project.RemoveRule(homeDir, entry.ID, index)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("removed rule %d from '%s': %s", index, Primary(entry.Name), removed.String())))
+	return nil
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execRulesRemove(homeDir, repoDir, projectFlag, number string) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := rulesRemoveCmd
+	cmd.SetOut(stdout)
+	err := runRulesRemove(cmd, homeDir, repoDir, projectFlag, number)
+	return stdout.String(), err
+}
+
+func TestRulesRemove(t *testing.T) {
+	homeDir, repoDir, entry := setupScheduleTest(t)
+	first := rules.Rule{Branch: `^feature/`, Category: rules.CategoryDevelopment}
+	second := rules.Rule{Branch: `^review/`, Category: rules.CategoryReview}
+	require.NoError(t, project.AddRule(homeDir, entry.ID, first))
+	require.NoError(t, project.AddRule(homeDir, entry.ID, second))
+
+	stdout, err := execRulesRemove(homeDir, repoDir, "", "1")
+
+	assert.NoError(t, err)
+	assert.Contains(t, stdout, "removed rule 1")
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	assert.Equal(t, []rules.Rule{second}, project.FindProjectByID(cfg, entry.ID).Rules)
+}
+
+func TestRulesRemoveInvalidNumber(t *testing.T) {
+	homeDir, repoDir, _ := setupScheduleTest(t)
+
+	_, err := execRulesRemove(homeDir, repoDir, "", "x")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid rule number")
+
+	_, err = execRulesRemove(homeDir, repoDir, "", "1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rule 1 not found")
+}
@@ -196,6 +196,14 @@ func buildScheduleEntry(kit PromptKit, w io.Writer) (schedule.ScheduleEntry, err
 		RRule:  rruleStr,
 	}
 
+	if hasOvernightRange(ranges) {
+		split, err := kit.Confirm("Count the hours after midnight towards the next day?")
+		if err != nil {
+			return schedule.ScheduleEntry{}, err
+		}
+		entry.SplitAtMidnight = split
+	}
+
 	_, _ = fmt.Fprintf(w, "\n  %s\n", Text("→ "+schedule.FormatScheduleEntry(entry)))
 
 	return entry, nil
@@ -396,7 +404,8 @@ func promptTimeRanges(kit PromptKit, w io.Writer) ([]schedule.TimeRange, error)
 	return ranges, nil
 }
 
-// promptSingleTimeRange asks the user for a start and end time, validating order.
+// promptSingleTimeRange asks the user for a start and end time. An end time
+// before the start time makes the range overnight.
 func promptSingleTimeRange(prompt PromptFunc, w io.Writer) (string, string, error) {
 	for {
 		fromInput, err := prompt("Start time (e.g. 9am, 9:00, 14:30)")
@@ -419,8 +428,8 @@ func promptSingleTimeRange(prompt PromptFunc, w io.Writer) (string, string, erro
 			continue
 		}
 
-		if !fromTod.Before(toTod) {
-			_, _ = fmt.Fprintf(w, "%s\n", Error("end time must be after start time"))
+		if fromTod == toTod {
+			_, _ = fmt.Fprintf(w, "%s\n", Error("end time must differ from start time"))
 			continue
 		}
 
@@ -428,6 +437,19 @@ func promptSingleTimeRange(prompt PromptFunc, w io.Writer) (string, string, erro
 	}
 }
 
+// hasOvernightRange reports whether any range runs past midnight into the
+// next day. Ranges ending exactly at midnight don't count.
+func hasOvernightRange(ranges []schedule.TimeRange) bool {
+	for _, r := range ranges {
+		from, errFrom := schedule.ParseTimeOfDay(r.From)
+		to, errTo := schedule.ParseTimeOfDay(r.To)
+		if errFrom == nil && errTo == nil && from.Overnight(to) && to != (schedule.TimeOfDay{}) {
+			return true
+		}
+	}
+	return false
+}
+
 // entriesOverlap checks whether candidate shares any days with existing entries
 // by expanding both over a 90-day window from today.
 func entriesOverlap(existing []schedule.ScheduleEntry, candidate schedule.ScheduleEntry) bool {
@@ -415,10 +415,10 @@ func TestBuildScheduleEntryEveryNDays(t *testing.T) {
 
 func TestBuildScheduleEntryTimeOrderError(t *testing.T) {
 	w := new(bytes.Buffer)
-	// First attempt: end before start; second attempt: valid
+	// First attempt: end equals start; second attempt: valid
 	kit := testKit(
 		mockSelectSequence(0, 0), // recurring, every weekday
-		mockPrompt("5pm", "9am", "9am", "5pm"),
+		mockPrompt("9am", "9am", "9am", "5pm"),
 		mockConfirm(false), // no more ranges
 		mockMultiSelect(nil),
 	)
@@ -429,7 +429,42 @@ func TestBuildScheduleEntryTimeOrderError(t *testing.T) {
 	require.Len(t, entry.Ranges, 1)
 	assert.Equal(t, "09:00", entry.Ranges[0].From)
 	assert.Equal(t, "17:00", entry.Ranges[0].To)
-	assert.Contains(t, w.String(), "end time must be after start time")
+	assert.Contains(t, w.String(), "end time must differ from start time")
+}
+
+func TestBuildScheduleEntryOvernight(t *testing.T) {
+	w := new(bytes.Buffer)
+	kit := testKit(
+		mockSelectSequence(0, 0), // recurring, every weekday
+		mockPrompt("10pm", "6am"),
+		mockConfirmSequence(false, true), // no more ranges, split at midnight
+		mockMultiSelect(nil),
+	)
+
+	entry, err := buildScheduleEntry(kit, w)
+
+	require.NoError(t, err)
+	require.Len(t, entry.Ranges, 1)
+	assert.Equal(t, "22:00", entry.Ranges[0].From)
+	assert.Equal(t, "06:00", entry.Ranges[0].To)
+	assert.True(t, entry.SplitAtMidnight)
+	assert.Contains(t, w.String(), "10:00 PM - 6:00 AM (next day)")
+}
+
+func TestBuildScheduleEntryEndsAtMidnight(t *testing.T) {
+	w := new(bytes.Buffer)
+	kit := testKit(
+		mockSelectSequence(0, 0), // recurring, every weekday
+		mockPrompt("6pm", "12am"),
+		mockConfirm(false), // no more ranges, no split question
+		mockMultiSelect(nil),
+	)
+
+	entry, err := buildScheduleEntry(kit, w)
+
+	require.NoError(t, err)
+	assert.Equal(t, "00:00", entry.Ranges[0].To)
+	assert.False(t, entry.SplitAtMidnight)
 }
 
 func TestBuildScheduleEntryMultipleRanges(t *testing.T) {
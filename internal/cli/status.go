@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os/exec"
 	"sort"
 	"strings"
@@ -18,6 +19,9 @@ import (
 var statusCmd = LeafCommand{
 	Use:   "status",
 	Short: "Show current tracking status",
+	BoolFlags: []BoolFlag{
+		{Name: "all", Shorthand: "a", Usage: "show today's time across all projects"},
+	},
 	StrFlags: []StringFlag{
 		{Name: "project", Shorthand: "p", Usage: "project name or ID"},
 	},
@@ -27,6 +31,13 @@ var statusCmd = LeafCommand{
 			return err
 		}
 		projectFlag, _ := cmd.Flags().GetString("project")
+		allFlag, _ := cmd.Flags().GetBool("all")
+		if allFlag {
+			if projectFlag != "" {
+				return fmt.Errorf("--all cannot be used with --project")
+			}
+			return runStatusAll(cmd, homeDir, time.Now)
+		}
 		return runStatus(cmd, homeDir, repoDir, projectFlag, defaultGitBranch, time.Now)
 	},
 
@@ -57,7 +68,7 @@ func runStatus(
 		return err
 	}
 
-	now := nowFunc()
+	now := inProjectZone(proj, nowFunc())
 	w := cmd.OutOrStdout()
 
 	// Project
@@ -65,6 +76,7 @@ func runStatus(
 
 	// Resolve the repo directory for branch lookup:
 	// If CWD is one of the project's repos, use it; otherwise pick the first assigned repo.
+	// A linked worktree of an assigned repo has a HEAD of its own, so use it too.
 	branchRepoDir := ""
 	cfgEntry := project.FindProjectByID(cfg, proj.ID)
 	if cfgEntry != nil && len(cfgEntry.Repos) > 0 {
@@ -75,6 +87,11 @@ func runStatus(
 				break
 			}
 		}
+		if branchRepoDir != repoDir && repoDir != "" {
+			if rc, err := project.ReadRepoConfig(repoDir); err == nil && rc != nil && rc.ProjectID == proj.ID {
+				branchRepoDir = repoDir
+			}
+		}
 	}
 
 	// Branch
@@ -85,8 +102,8 @@ func runStatus(
 		}
 	}
 
-	// Load all entries once
-	entries, err := LoadProjectEntries(homeDir, proj.Slug)
+	// Load today's entries once, with the checkouts still running from before
+	entries, err := LoadProjectEntriesRange(homeDir, proj, timetrack.DateOf(now), timetrack.DateOf(now))
 	if err != nil {
 		return err
 	}
@@ -97,64 +114,64 @@ func runStatus(
 		_, _ = fmt.Fprintf(w, "%s  %s\n", Silent("Checked out:"), Text(ago+" ago"))
 	}
 
-	// Schedule for today
+	// Schedule for today, plus the rest of any overnight window from yesterday
 	schedules := project.GetSchedules(cfg, proj.ID)
+	balance, err := projectBalance(homeDir, cfg, proj, now)
+	if err != nil {
+		return err
+	}
 	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 	todayEnd := todayStart.Add(24*time.Hour - time.Second)
-	daySchedules, err := schedule.ExpandSchedules(schedules, todayStart, todayEnd)
+	daySchedules, err := schedule.ExpandSchedules(schedules, todayStart.AddDate(0, 0, -1), todayEnd)
 	if err != nil {
 		return err
 	}
 
-	// Find today's schedule
-	var todaySchedule *schedule.DaySchedule
-	for i := range daySchedules {
-		if daySchedules[i].Date.Day() == now.Day() &&
-			daySchedules[i].Date.Month() == now.Month() &&
-			daySchedules[i].Date.Year() == now.Year() {
-			todaySchedule = &daySchedules[i]
-			break
+	var windows []schedule.TimeWindow
+	yesterday := now.AddDate(0, 0, -1)
+	for _, ds := range daySchedules {
+		switch {
+		case sameDay(ds.Date, yesterday):
+			windows = append(overnightTails(ds.Windows), windows...)
+		case sameDay(ds.Date, now):
+			windows = append(windows, ds.Windows...)
 		}
 	}
 
-	if todaySchedule == nil || len(todaySchedule.Windows) == 0 {
+	if len(windows) == 0 {
 		_, _ = fmt.Fprintln(w)
 		_, _ = fmt.Fprintf(w, "%s  %s\n", Silent("Today:"), Text("not a working day"))
+		printBalanceLine(w, balance)
 		return nil
 	}
 
-	// Compute today's logged time (with activity-aware idle trimming)
-	// Expand schedules for the whole month (needed by ComputeDayBudget)
-	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
-	monthEnd := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, time.UTC)
-	monthSchedules, err := schedule.ExpandSchedules(schedules, monthStart, monthEnd)
+	budget, err := projectDayBudget(homeDir, cfg, proj, entries, now)
 	if err != nil {
 		return err
 	}
 
-	budget := timetrack.ComputeDayBudget(
-		entries.Checkouts, entries.Logs, entries.Commits,
-		monthSchedules, now, now,
-		timetrack.ActivityEntries{Stops: entries.ActivityStops, Starts: entries.ActivityStarts},
-	)
-
 	_, _ = fmt.Fprintln(w)
-	_, _ = fmt.Fprintf(w, "%s  %s  %s  %s\n",
+	today := fmt.Sprintf("%s  %s  %s  %s",
 		Silent("Today:"),
 		Primary(entry.FormatMinutes(budget.LoggedMinutes)+" logged"),
 		Silent("·"),
 		Text(entry.FormatMinutes(budget.RemainingMinutes)+" remaining"),
 	)
+	if budget.OvertimeMinutes > 0 {
+		today += fmt.Sprintf("  %s  %s", Silent("·"), Warning(entry.FormatMinutes(budget.OvertimeMinutes)+" overtime"))
+	}
+	_, _ = fmt.Fprintln(w, today)
+	printBalanceLine(w, balance)
 
 	// Schedule line
-	windowStrs := make([]string, len(todaySchedule.Windows))
-	for i, win := range todaySchedule.Windows {
+	windowStrs := make([]string, len(windows))
+	for i, win := range windows {
 		windowStrs[i] = schedule.FormatTimeRange(win.From.String(), win.To.String())
 	}
 	_, _ = fmt.Fprintf(w, "%s  %s\n", Silent("Schedule:"), Text(strings.Join(windowStrs, ", ")))
 
 	// Tracking state
-	active, activeUntil := isWithinSchedule(now, todaySchedule.Windows)
+	active, activeUntil := isWithinSchedule(now, windows)
 	if active {
 		// Format the end time using FormatTimeRange and extracting the "to" part
 		untilStr := schedule.FormatTimeRange(activeUntil.String(), activeUntil.String())
@@ -178,6 +195,57 @@ func runStatus(
 	return nil
 }
 
+// projectDayBudget computes today's time of a project (with activity-aware
+// idle trimming), less the time other projects win per overlap policy.
+func projectDayBudget(homeDir string, cfg *project.Config, proj *project.ProjectEntry, entries ProjectEntries, now time.Time) (timetrack.DayBudget, error) {
+	// Expand schedules for the whole month and the day before (needed by ComputeDayBudget)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := time.Date(now.Year(), now.Month()+1, 0, 23, 59, 59, 0, time.UTC)
+	monthSchedules, err := schedule.ExpandSchedules(project.GetSchedules(cfg, proj.ID), monthStart.AddDate(0, 0, -1), monthEnd)
+	if err != nil {
+		return timetrack.DayBudget{}, err
+	}
+
+	today := timetrack.DateOf(now)
+	opts, err := projectOptions(homeDir, cfg, proj, today, today, now)
+	if err != nil {
+		return timetrack.DayBudget{}, err
+	}
+
+	return timetrack.ComputeDayBudget(
+		entries.Checkouts, entries.Logs, entries.Commits, entries.Events,
+		monthSchedules, now, now, opts,
+		timetrack.ActivityEntries{Stops: entries.ActivityStops, Starts: entries.ActivityStarts},
+	), nil
+}
+
+// printBalanceLine prints the project's flextime balance, if it has one.
+func printBalanceLine(w io.Writer, balance *timetrack.Balance) {
+	if balance == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%s  %s\n", Silent("Balance:"), balanceLabel(balance.Minutes()))
+}
+
+// overnightTails returns the part after midnight of each overnight window,
+// e.g. 00:00-06:00 for a 22:00-06:00 window.
+func overnightTails(windows []schedule.TimeWindow) []schedule.TimeWindow {
+	var tails []schedule.TimeWindow
+	for _, w := range windows {
+		if w.Overnight() && w.To != (schedule.TimeOfDay{}) {
+			tails = append(tails, schedule.TimeWindow{To: w.To, Location: w.Location})
+		}
+	}
+	return tails
+}
+
+// sameDay reports whether a and b fall on the same calendar date.
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
 // findLastCheckout returns the most recent checkout entry, or nil if none.
 func findLastCheckout(checkouts []entry.CheckoutEntry) *entry.CheckoutEntry {
 	if len(checkouts) == 0 {
@@ -210,13 +278,17 @@ func formatDurationAgo(d time.Duration) string {
 }
 
 // isWithinSchedule checks if the current time falls within any schedule window.
-// Returns true and the end time of the current window if active.
+// Returns true and the end time of the current window if active. Overnight
+// windows run until midnight; pass their tails from the day before (see
+// overnightTails) to cover the morning.
 func isWithinSchedule(now time.Time, windows []schedule.TimeWindow) (bool, schedule.TimeOfDay) {
-	nowMinutes := now.Hour()*60 + now.Minute()
 	for _, w := range windows {
-		fromMins := w.From.Hour*60 + w.From.Minute
-		toMins := w.To.Hour*60 + w.To.Minute
-		if nowMinutes >= fromMins && nowMinutes < toMins {
+		at := now
+		if w.Location != nil {
+			at = now.In(w.Location)
+		}
+		nowMinutes := at.Hour()*60 + at.Minute()
+		if nowMinutes >= w.From.Minutes() && (w.Overnight() || nowMinutes < w.To.Minutes()) {
 			return true, w.To
 		}
 	}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/schedule"
+	"github.com/Flyrell/hourgit/internal/timetrack"
+	"github.com/spf13/cobra"
+)
+
+// runStatusAll prints today's time of every project with time or a schedule
+// today, and the day's budget across them. Time several projects claim at
+// once counts for one of them only, per overlap policy, and the scheduled
+// time is the union of the projects' schedule windows.
+func runStatusAll(cmd *cobra.Command, homeDir string, nowFunc func() time.Time) error {
+	cfg, err := project.ReadConfig(homeDir)
+	if err != nil {
+		return err
+	}
+	w := cmd.OutOrStdout()
+
+	if len(cfg.Projects) == 0 {
+		_, _ = fmt.Fprintln(w, Text("no projects found"))
+		return nil
+	}
+
+	type projectDay struct {
+		name   string
+		budget timetrack.DayBudget
+	}
+	var days []projectDay
+	var spans [][2]time.Time
+	loggedTotal, overtimeTotal := 0, 0
+	for i := range cfg.Projects {
+		proj := &cfg.Projects[i]
+		now := inProjectZone(proj, nowFunc())
+
+		entries, err := LoadProjectEntriesRange(homeDir, proj, timetrack.DateOf(now), timetrack.DateOf(now))
+		if err != nil {
+			return err
+		}
+		budget, err := projectDayBudget(homeDir, cfg, proj, entries, now)
+		if err != nil {
+			return err
+		}
+		projectSpans, err := scheduledSpans(project.GetSchedules(cfg, proj.ID), now)
+		if err != nil {
+			return err
+		}
+		if budget.LoggedMinutes == 0 && budget.OvertimeMinutes == 0 && budget.ScheduledMinutes == 0 {
+			continue
+		}
+
+		days = append(days, projectDay{name: proj.Name, budget: budget})
+		spans = append(spans, projectSpans...)
+		loggedTotal += budget.LoggedMinutes
+		overtimeTotal += budget.OvertimeMinutes
+	}
+
+	if len(days) == 0 {
+		_, _ = fmt.Fprintf(w, "%s  %s\n", Silent("Today:"), Text("not a working day"))
+		return nil
+	}
+
+	nameWidth := len("Project")
+	for _, d := range days {
+		nameWidth = max(nameWidth, len(d.name))
+	}
+	_, _ = fmt.Fprintf(w, "%s\n", Silent(fmt.Sprintf("%-*s %10s %10s %10s", nameWidth, "Project", "Logged", "Scheduled", "Overtime")))
+	for _, d := range days {
+		overtime := "."
+		if d.budget.OvertimeMinutes > 0 {
+			overtime = entry.FormatMinutes(d.budget.OvertimeMinutes)
+		}
+		_, _ = fmt.Fprintf(w, "%-*s %10s %10s %10s\n",
+			nameWidth, d.name,
+			entry.FormatMinutes(d.budget.LoggedMinutes),
+			entry.FormatMinutes(d.budget.ScheduledMinutes),
+			overtime,
+		)
+	}
+
+	scheduled := unionMinutes(spans)
+	today := fmt.Sprintf("%s  %s  %s  %s",
+		Silent("Today:"),
+		Primary(entry.FormatMinutes(loggedTotal)+" logged"),
+		Silent("·"),
+		Text(entry.FormatMinutes(max(scheduled-loggedTotal, 0))+" remaining"),
+	)
+	if overtimeTotal > 0 {
+		today += fmt.Sprintf("  %s  %s", Silent("·"), Warning(entry.FormatMinutes(overtimeTotal)+" overtime"))
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, today)
+	return nil
+}
+
+// scheduledSpans returns the schedule windows of today (the date of now),
+// including the rest of an overnight window from yesterday, as absolute
+// start and end times cut to today.
+func scheduledSpans(schedules []schedule.ScheduleEntry, now time.Time) ([][2]time.Time, error) {
+	today := timetrack.DateOf(now)
+	daySchedules, err := schedule.ExpandSchedules(schedules, today.AddDate(0, 0, -1), today.Add(24*time.Hour-time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	var spans [][2]time.Time
+	for _, ds := range daySchedules {
+		for _, win := range ds.Windows {
+			start, end := timetrack.WindowBounds(timetrack.DateOf(ds.Date), win, now.Location())
+			if start.Before(dayStart) {
+				start = dayStart
+			}
+			if end.After(dayEnd) {
+				end = dayEnd
+			}
+			if end.After(start) {
+				spans = append(spans, [2]time.Time{start, end})
+			}
+		}
+	}
+	return spans, nil
+}
+
+// unionMinutes returns the minutes covered by at least one of spans.
+func unionMinutes(spans [][2]time.Time) int {
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0].Before(spans[j][0]) })
+	total := 0
+	var cur [2]time.Time
+	for i, span := range spans {
+		if i > 0 && !span[0].After(cur[1]) {
+			if span[1].After(cur[1]) {
+				cur[1] = span[1]
+			}
+			continue
+		}
+		if i > 0 {
+			total += int(cur[1].Sub(cur[0]).Minutes())
+		}
+		cur = span
+	}
+	if len(spans) > 0 {
+		total += int(cur[1].Sub(cur[0]).Minutes())
+	}
+	return total
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execStatusAll(homeDir string, now func() time.Time) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := statusCmd
+	cmd.SetOut(stdout)
+
+	err := runStatusAll(cmd, homeDir, now)
+	return stdout.String(), err
+}
+
+// setupTwoProjects creates projects API and Web on a 9am-5pm schedule, with
+// a branch checked out in API at 9am and in Web at 11am on Wed, Jun 11, 2025.
+func setupTwoProjects(t *testing.T) (homeDir string, api, web *project.ProjectEntry) {
+	t.Helper()
+	homeDir = t.TempDir()
+
+	var err error
+	api, err = project.CreateProject(homeDir, "API")
+	require.NoError(t, err)
+	web, err = project.CreateProject(homeDir, "Web")
+	require.NoError(t, err)
+
+	for _, p := range []*project.ProjectEntry{api, web} {
+		require.NoError(t, project.SetSchedules(homeDir, p.ID, weekdaySchedule(9, 0, 17, 0)))
+	}
+	require.NoError(t, entry.WriteCheckoutEntry(homeDir, api.Slug, entry.CheckoutEntry{
+		ID: "aaa1111", Timestamp: time.Date(2025, 6, 11, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "feature/api",
+	}))
+	require.NoError(t, entry.WriteCheckoutEntry(homeDir, web.Slug, entry.CheckoutEntry{
+		ID: "bbb2222", Timestamp: time.Date(2025, 6, 11, 11, 0, 0, 0, time.UTC), Previous: "main", Next: "feature/web",
+	}))
+	return homeDir, api, web
+}
+
+func TestStatusOverlapResolvedAcrossProjects(t *testing.T) {
+	homeDir, api, _ := setupTwoProjects(t)
+	now := mockNow(time.Date(2025, 6, 11, 18, 0, 0, 0, time.UTC))
+
+	stdout, err := execStatus(homeDir, "", api.Name, mockGitBranch("feature/api"), now)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "2h logged", "Web was checked out later and wins the overlap")
+
+	require.NoError(t, project.SetOverlap(homeDir, project.OverlapOff))
+	stdout, err = execStatus(homeDir, "", api.Name, mockGitBranch("feature/api"), now)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "8h logged")
+}
+
+func TestStatusAll(t *testing.T) {
+	homeDir, _, _ := setupTwoProjects(t)
+	now := mockNow(time.Date(2025, 6, 11, 18, 0, 0, 0, time.UTC))
+
+	stdout, err := execStatusAll(homeDir, now)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "API")
+	assert.Contains(t, stdout, "2h")
+	assert.Contains(t, stdout, "Web")
+	assert.Contains(t, stdout, "6h")
+	assert.Contains(t, stdout, "8h logged")
+	assert.Contains(t, stdout, "0m remaining", "the projects share one schedule")
+}
+
+func TestStatusAllSplit(t *testing.T) {
+	homeDir, _, _ := setupTwoProjects(t)
+	require.NoError(t, project.SetOverlap(homeDir, project.OverlapSplit))
+
+	stdout, err := execStatusAll(homeDir, mockNow(time.Date(2025, 6, 11, 12, 0, 0, 0, time.UTC)))
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "2h 30m")
+	assert.Contains(t, stdout, "30m")
+	assert.Contains(t, stdout, "3h logged")
+	assert.Contains(t, stdout, "5h remaining")
+}
+
+func TestStatusAllDayOff(t *testing.T) {
+	homeDir, _, _ := setupTwoProjects(t)
+
+	stdout, err := execStatusAll(homeDir, mockNow(time.Date(2025, 6, 14, 10, 0, 0, 0, time.UTC)))
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "not a working day")
+}
+
+func TestStatusAllNoProjects(t *testing.T) {
+	stdout, err := execStatusAll(t.TempDir(), mockNow(time.Now()))
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "no projects found")
+}
+
+func TestUnionMinutes(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2025, 6, 11, h, 0, 0, 0, time.UTC) }
+
+	assert.Equal(t, 0, unionMinutes(nil))
+	assert.Equal(t, 600, unionMinutes([][2]time.Time{{at(9), at(17)}, {at(8), at(12)}, {at(16), at(18)}}))
+	assert.Equal(t, 180, unionMinutes([][2]time.Time{{at(9), at(10)}, {at(12), at(14)}}))
+}
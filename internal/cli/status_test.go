@@ -136,6 +136,50 @@ func TestStatusWithCheckoutEntry(t *testing.T) {
 	assert.Contains(t, stdout, "2h 15m ago")
 }
 
+func TestStatusSeparateOvertime(t *testing.T) {
+	homeDir, proj := setupStatusTest(t)
+
+	require.NoError(t, project.SetSchedules(homeDir, proj.ID, weekdaySchedule(9, 0, 17, 0)))
+	require.NoError(t, project.SetOvertime(homeDir, proj.ID, project.OvertimeSeparate))
+
+	require.NoError(t, entry.WriteCheckoutEntry(homeDir, proj.Slug, entry.CheckoutEntry{
+		ID:        "abc1234",
+		Timestamp: time.Date(2025, 6, 11, 9, 0, 0, 0, time.UTC),
+		Previous:  "main",
+		Next:      "hotfix",
+	}))
+
+	stdout, err := execStatus(homeDir, "", proj.Name, mockGitBranch("hotfix"), mockNow(time.Date(2025, 6, 11, 19, 30, 0, 0, time.UTC)))
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "8h logged")
+	assert.Contains(t, stdout, "2h 30m overtime")
+}
+
+func TestStatusBalance(t *testing.T) {
+	homeDir, proj := setupStatusTest(t)
+
+	require.NoError(t, project.SetSchedules(homeDir, proj.ID, weekdaySchedule(9, 0, 17, 0)))
+	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, entry.Entry{
+		ID: "abc1234", Start: time.Date(2025, 6, 9, 9, 0, 0, 0, time.UTC), Minutes: 600, Message: "release",
+	}))
+
+	// Saturday, after a 10h Monday and nothing else that week
+	now := mockNow(time.Date(2025, 6, 14, 10, 0, 0, 0, time.UTC))
+
+	stdout, err := execStatus(homeDir, "", proj.Name, mockGitBranch("main"), now)
+	require.NoError(t, err)
+	assert.NotContains(t, stdout, "Balance:")
+
+	require.NoError(t, project.SetBalanceStart(homeDir, proj.ID, "2025-06-09"))
+	stdout, err = execStatus(homeDir, "", proj.Name, mockGitBranch("main"), now)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "not a working day")
+	assert.Contains(t, stdout, "Balance:")
+	assert.Contains(t, stdout, "-30h")
+}
+
 func TestStatusDayOff(t *testing.T) {
 	homeDir, proj := setupStatusTest(t)
 
@@ -166,6 +210,32 @@ func TestStatusTrackingInactive(t *testing.T) {
 	assert.Contains(t, stdout, "inactive")
 }
 
+func TestStatusOvernightShift(t *testing.T) {
+	homeDir, proj := setupStatusTest(t)
+
+	schedules := []schedule.ScheduleEntry{
+		{Ranges: []schedule.TimeRange{{From: "22:00", To: "06:00"}}, RRule: "FREQ=WEEKLY;BYDAY=MO"},
+	}
+	require.NoError(t, project.SetSchedules(homeDir, proj.ID, schedules))
+
+	// Monday at 11 PM, during the shift
+	stdout, err := execStatus(homeDir, "", proj.Name, mockGitBranch("main"), mockNow(time.Date(2025, 6, 9, 23, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "10:00 PM - 6:00 AM (next day)")
+	assert.Contains(t, stdout, "active (until 6:00 AM)")
+
+	// Tuesday at 2 AM, still in Monday's shift
+	stdout, err = execStatus(homeDir, "", proj.Name, mockGitBranch("main"), mockNow(time.Date(2025, 6, 10, 2, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "12:00 AM - 6:00 AM")
+	assert.Contains(t, stdout, "active (until 6:00 AM)")
+
+	// Tuesday at 7 AM, the shift is over
+	stdout, err = execStatus(homeDir, "", proj.Name, mockGitBranch("main"), mockNow(time.Date(2025, 6, 10, 7, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "inactive")
+}
+
 func TestStatusWithLoggedTime(t *testing.T) {
 	homeDir, proj := setupStatusTest(t)
 
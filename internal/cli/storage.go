@@ -0,0 +1,11 @@
+package cli
+
+import "github.com/spf13/cobra"
+
+var storageCmd = GroupCommand{
+	Use:   "storage",
+	Short: "Manage the entry storage backend",
+	Subcommands: []*cobra.Command{
+		storageMigrateCmd,
+	},
+}.Build()
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var storageMigrateCmd = LeafCommand{
+	Use:   "migrate",
+	Short: "Move all entries to another storage backend",
+	StrFlags: []StringFlag{
+		{Name: "to", Usage: "target backend (files or segments)"},
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		to, _ := cmd.Flags().GetString("to")
+		return runStorageMigrate(cmd, homeDir, to)
+	},
+}.Build()
+
+func runStorageMigrate(cmd *cobra.Command, homeDir, to string) error {
+	if to == "" {
+		return fmt.Errorf("--to is required (%s or %s)", project.StorageFiles, project.StorageSegments)
+	}
+
+	cfg, err := project.ReadConfig(homeDir)
+	if err != nil {
+		return err
+	}
+	from := project.GetStorage(cfg)
+	if from == to {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Silent(fmt.Sprintf("already using '%s' storage", to)))
+		return nil
+	}
+
+	src, err := entry.NewStore(homeDir, from)
+	if err != nil {
+		return err
+	}
+	dst, err := entry.NewStore(homeDir, to)
+	if err != nil {
+		return err
+	}
+
+	slugs, err := entry.ProjectSlugs(homeDir)
+	if err != nil {
+		return err
+	}
+
+	moved, err := entry.MigrateStore(src, dst, slugs)
+	if err != nil {
+		return fmt.Errorf("migration failed, storage left on '%s': %w", from, err)
+	}
+
+	if err := project.SetStorage(homeDir, to); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", Text(fmt.Sprintf("moved %d entries from '%s' to '%s' storage", moved, from, to)))
+	return nil
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execStorageMigrate(homeDir, to string) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := storageMigrateCmd
+	cmd.SetOut(stdout)
+	err := runStorageMigrate(cmd, homeDir, to)
+	return stdout.String(), err
+}
+
+func seedStorageEntries(t *testing.T, home string) {
+	t.Helper()
+	require.NoError(t, entry.WriteEntry(home, "my-project", entry.Entry{
+		ID:      "aaa1111",
+		Start:   time.Date(2025, 6, 15, 9, 0, 0, 0, time.UTC),
+		Minutes: 60,
+		Message: "first",
+	}))
+	require.NoError(t, entry.WriteCheckoutEntry(home, "my-project", entry.CheckoutEntry{
+		ID:        "bbb2222",
+		Timestamp: time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC),
+		Previous:  "main",
+		Next:      "feature",
+	}))
+}
+
+func TestStorageMigrateToSegments(t *testing.T) {
+	home := t.TempDir()
+	seedStorageEntries(t, home)
+
+	stdout, err := execStorageMigrate(home, project.StorageSegments)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "moved 2 entries from 'files' to 'segments' storage")
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, project.StorageSegments, project.GetStorage(cfg))
+
+	_, err = os.Stat(filepath.Join(project.LogDir(home, "my-project"), "aaa1111"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(project.LogDir(home, "my-project"), "segments", "2025-06.jsonl"))
+	assert.NoError(t, err)
+
+	e, err := entry.ReadEntry(home, "my-project", "aaa1111")
+	require.NoError(t, err)
+	assert.Equal(t, "first", e.Message)
+	checkouts, err := entry.ReadAllCheckoutEntries(home, "my-project")
+	require.NoError(t, err)
+	assert.Len(t, checkouts, 1)
+}
+
+func TestStorageMigrateRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	seedStorageEntries(t, home)
+
+	_, err := execStorageMigrate(home, project.StorageSegments)
+	require.NoError(t, err)
+	_, err = execStorageMigrate(home, project.StorageFiles)
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(project.LogDir(home, "my-project"), "segments"))
+	assert.True(t, os.IsNotExist(err))
+
+	e, err := entry.ReadEntry(home, "my-project", "aaa1111")
+	require.NoError(t, err)
+	assert.Equal(t, 60, e.Minutes)
+}
+
+func TestStorageMigrateSameBackend(t *testing.T) {
+	home := t.TempDir()
+
+	stdout, err := execStorageMigrate(home, project.StorageFiles)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "already using 'files' storage")
+}
+
+func TestStorageMigrateValidation(t *testing.T) {
+	home := t.TempDir()
+
+	_, err := execStorageMigrate(home, "")
+	assert.EqualError(t, err, "--to is required (files or segments)")
+
+	_, err = execStorageMigrate(home, "cloud")
+	assert.EqualError(t, err, `unknown storage backend "cloud"`)
+}
+
+func TestStorageRegisteredAsSubcommand(t *testing.T) {
+	commands := rootCmd.Commands()
+	names := make([]string, len(commands))
+	for i, cmd := range commands {
+		names[i] = cmd.Name()
+	}
+	assert.Contains(t, names, "storage")
+}
@@ -34,7 +34,7 @@ func defaultGitReflog(repoDir string, since *time.Time) (string, error) {
 
 var syncCmd = LeafCommand{
 	Use:   "sync",
-	Short: "Sync branch checkouts and commits from git reflog",
+	Short: "Sync branch checkouts, commits and other git events from git reflog",
 	StrFlags: []StringFlag{
 		{Name: "project", Shorthand: "p", Usage: "project name or ID (auto-detected from repo if omitted)"},
 	},
@@ -99,26 +99,16 @@ func runSync(
 		return fmt.Errorf("failed to read git reflog: %w", err)
 	}
 
-	// Parse reflog for checkouts and commits
+	// Parse reflog for checkouts, commits and other git events
 	records := reflog.ParseReflog(output)
 	commitRecords := reflog.ParseCommits(output)
+	eventRecords := reflog.ParseEvents(output)
 
-	// Build known IDs set from existing checkout and commit entries
-	existingCheckouts, err := entry.ReadAllCheckoutEntries(homeDir, proj.Slug)
+	// Build known IDs set from existing entries
+	knownIDs, err := entry.LoadIDs(homeDir, proj.Slug)
 	if err != nil {
 		return err
 	}
-	existingCommits, err := entry.ReadAllCommitEntries(homeDir, proj.Slug)
-	if err != nil {
-		return err
-	}
-	knownIDs := make(map[string]bool, len(existingCheckouts)+len(existingCommits))
-	for _, e := range existingCheckouts {
-		knownIDs[e.ID] = true
-	}
-	for _, e := range existingCommits {
-		knownIDs[e.ID] = true
-	}
 
 	// Build sorted checkout records (oldest first) for branch resolution
 	sortedCheckouts := make([]reflog.CheckoutRecord, len(records))
@@ -148,14 +138,15 @@ func runSync(
 			continue
 		}
 
-		// Generate deterministic ID
-		seed := rec.CommitRef + rec.Timestamp.Format(time.RFC3339) + rec.Previous + rec.Next
-		id := hashutil.GenerateIDFromSeed(seed)
+		// Generate deterministic ID (in UTC, so the ID doesn't depend on the
+		// offset the reflog was written with)
+		seed := rec.CommitRef + rec.Timestamp.UTC().Format(time.RFC3339) + rec.Previous + rec.Next
 
 		// Skip already-synced entries (dedup by ID)
-		if knownIDs[id] {
+		if knownIDs.Known(hashutil.Hash(seed)) {
 			continue
 		}
+		id := knownIDs.Allocate(seed)
 
 		e := entry.CheckoutEntry{
 			ID:        id,
@@ -170,7 +161,6 @@ func runSync(
 			return err
 		}
 
-		knownIDs[id] = true
 		createdCheckouts++
 
 		if rec.Timestamp.After(newestTimestamp) {
@@ -184,13 +174,13 @@ func runSync(
 		rec := commitRecords[i]
 
 		// Generate deterministic ID
-		seed := rec.CommitRef + rec.Timestamp.Format(time.RFC3339) + "commit"
-		id := hashutil.GenerateIDFromSeed(seed)
+		seed := rec.CommitRef + rec.Timestamp.UTC().Format(time.RFC3339) + "commit"
 
 		// Skip already-synced entries (dedup by ID)
-		if knownIDs[id] {
+		if knownIDs.Known(hashutil.Hash(seed)) {
 			continue
 		}
+		id := knownIDs.Allocate(seed)
 
 		branch := resolveCommitBranch(rec.Timestamp, sortedCheckouts)
 
@@ -207,7 +197,6 @@ func runSync(
 			return err
 		}
 
-		knownIDs[id] = true
 		createdCommits++
 
 		if rec.Timestamp.After(newestTimestamp) {
@@ -215,7 +204,48 @@ func runSync(
 		}
 	}
 
-	totalCreated := createdCheckouts + createdCommits
+	// Process git event records oldest-first
+	var createdEvents int
+	for i := len(eventRecords) - 1; i >= 0; i-- {
+		rec := eventRecords[i]
+
+		// Generate deterministic ID
+		seed := rec.CommitRef + rec.Timestamp.UTC().Format(time.RFC3339) + rec.Kind + rec.Message
+
+		// Skip already-synced entries (dedup by ID)
+		if knownIDs.Known(hashutil.Hash(seed)) {
+			continue
+		}
+		id := knownIDs.Allocate(seed)
+
+		branch := resolveCommitBranch(rec.Timestamp, sortedCheckouts)
+		if rec.Kind == entry.EventBranchRename {
+			branch = rec.Next
+		}
+
+		e := entry.GitEventEntry{
+			ID:        id,
+			Timestamp: rec.Timestamp,
+			Kind:      rec.Kind,
+			Message:   rec.Message,
+			CommitRef: rec.CommitRef,
+			Branch:    branch,
+			Previous:  rec.Previous,
+			Repo:      repoDir,
+		}
+
+		if err := entry.WriteGitEventEntry(homeDir, proj.Slug, e); err != nil {
+			return err
+		}
+
+		createdEvents++
+
+		if rec.Timestamp.After(newestTimestamp) {
+			newestTimestamp = rec.Timestamp
+		}
+	}
+
+	totalCreated := createdCheckouts + createdCommits + createdEvents
 
 	// Update LastSync to the newest processed record's timestamp
 	if totalCreated > 0 && repoCfg != nil && !newestTimestamp.IsZero() {
@@ -235,8 +265,15 @@ func runSync(
 		if createdCommits > 0 {
 			parts = append(parts, fmt.Sprintf("%d commit(s)", createdCommits))
 		}
+		if createdEvents > 0 {
+			parts = append(parts, fmt.Sprintf("%d git event(s)", createdEvents))
+		}
+		synced := parts[len(parts)-1]
+		if len(parts) > 1 {
+			synced = strings.Join(parts[:len(parts)-1], ", ") + " and " + synced
+		}
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n",
-			Text(fmt.Sprintf("synced %s for project '%s'", strings.Join(parts, " and "), Primary(proj.Name))))
+			Text(fmt.Sprintf("synced %s for project '%s'", synced, Primary(proj.Name))))
 	}
 
 	return nil
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/hashutil"
 	"github.com/Flyrell/hourgit/internal/project"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -203,6 +204,33 @@ func TestSyncTimestampFromReflog(t *testing.T) {
 	assert.Equal(t, expected, entries[0].Timestamp)
 }
 
+func TestSyncKeepsReflogOffset(t *testing.T) {
+	homeDir, repoDir, proj := setupSyncTest(t)
+
+	reflogOutput := `abc1234 HEAD@{2025-06-15 16:30:00 +0200}: checkout: moving from main to feature-x`
+
+	_, err := execSync(homeDir, repoDir, "", fakeReflog(reflogOutput))
+	require.NoError(t, err)
+
+	entries, err := entry.ReadAllCheckoutEntries(homeDir, proj.Slug)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	assert.True(t, entries[0].Timestamp.Equal(time.Date(2025, 6, 15, 14, 30, 0, 0, time.UTC)))
+	_, offset := entries[0].Timestamp.Zone()
+	assert.Equal(t, 2*60*60, offset)
+
+	// IDs are seeded in UTC, so the same moment gives the same ID whatever
+	// offset the reflog was written with
+	otherHome, otherRepo, otherProj := setupSyncTest(t)
+	_, err = execSync(otherHome, otherRepo, "", fakeReflog(`abc1234 HEAD@{2025-06-15 14:30:00 +0000}: checkout: moving from main to feature-x`))
+	require.NoError(t, err)
+	other, err := entry.ReadAllCheckoutEntries(otherHome, otherProj.Slug)
+	require.NoError(t, err)
+	require.Len(t, other, 1)
+	assert.Equal(t, other[0].ID, entries[0].ID)
+}
+
 func TestSyncSinceOptimization(t *testing.T) {
 	homeDir, repoDir, _ := setupSyncTest(t)
 
@@ -367,6 +395,42 @@ func TestSyncCommitDeduplication(t *testing.T) {
 	assert.Contains(t, stdout, "already up to date")
 }
 
+func TestSyncGitEvents(t *testing.T) {
+	homeDir, repoDir, proj := setupSyncTest(t)
+
+	reflogOutput := fmt.Sprintf(
+		"%s\n%s\n%s\n%s",
+		`aaa1111 HEAD@{2025-06-15 16:00:00 +0000}: Branch: renamed refs/heads/feature-x to refs/heads/feature-y`,
+		`bbb2222 HEAD@{2025-06-15 15:30:00 +0000}: rebase (finish): returning to refs/heads/feature-x`,
+		`ccc3333 HEAD@{2025-06-15 15:00:00 +0000}: merge main: Fast-forward`,
+		`def5678 HEAD@{2025-06-15 14:00:00 +0000}: checkout: moving from main to feature-x`,
+	)
+
+	stdout, err := execSync(homeDir, repoDir, "", fakeReflog(reflogOutput))
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "synced 1 checkout(s) and 3 git event(s)")
+
+	events, err := entry.ReadAllGitEventEntries(homeDir, proj.Slug)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	byKind := make(map[string]entry.GitEventEntry)
+	for _, e := range events {
+		byKind[e.Kind] = e
+	}
+	assert.Equal(t, "feature-x", byKind[entry.EventMerge].Branch)
+	assert.Equal(t, "merge main: Fast-forward", byKind[entry.EventMerge].Message)
+	assert.Equal(t, "feature-x", byKind[entry.EventRebase].Branch)
+	assert.Equal(t, "feature-x", byKind[entry.EventBranchRename].Previous)
+	assert.Equal(t, "feature-y", byKind[entry.EventBranchRename].Branch)
+	assert.Equal(t, repoDir, byKind[entry.EventMerge].Repo)
+
+	// A second sync finds nothing new
+	stdout, err = execSync(homeDir, repoDir, "", fakeReflog(reflogOutput))
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "already up to date")
+}
+
 func TestSyncCommitBranchResolution(t *testing.T) {
 	homeDir, repoDir, proj := setupSyncTest(t)
 
@@ -425,3 +489,22 @@ func TestSyncOutputIncludesCommitCount(t *testing.T) {
 	assert.Contains(t, stdout, "1 checkout(s)")
 	assert.Contains(t, stdout, "3 commit(s)")
 }
+
+func TestSyncSkipsEntriesWithLegacyIDs(t *testing.T) {
+	homeDir, repoDir, proj := setupSyncTest(t)
+
+	reflogOutput := `abc1234 HEAD@{2025-06-15 14:30:00 +0000}: checkout: moving from main to feature-x`
+
+	// An entry written by an older version with a 7-char ID from the same seed
+	seed := "abc1234" + "2025-06-15T14:30:00Z" + "main" + "feature-x"
+	require.NoError(t, entry.WriteCheckoutEntry(homeDir, proj.Slug, entry.CheckoutEntry{
+		ID:        hashutil.GenerateIDFromSeed(seed),
+		Timestamp: time.Date(2025, 6, 15, 14, 30, 0, 0, time.UTC),
+		Previous:  "main",
+		Next:      "feature-x",
+	}))
+
+	stdout, err := execSync(homeDir, repoDir, "", fakeReflog(reflogOutput))
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "already up to date")
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = LeafCommand{
+	Use:   "undo [n]",
+	Short: "Revert the last n operations (default 1)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+
+		n := 1
+		if len(args) == 1 {
+			n, err = strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid count %q: expected a positive number", args[0])
+			}
+		}
+
+		return runUndo(cmd, homeDir, n)
+	},
+}.Build()
+
+func runUndo(cmd *cobra.Command, homeDir string, n int) error {
+	ops, err := journal.Undoable(homeDir)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	if len(ops) == 0 {
+		_, _ = fmt.Fprintln(w, Silent("nothing to undo"))
+		return nil
+	}
+	if n > len(ops) {
+		n = len(ops)
+	}
+
+	// Nothing is reverted unless every operation can be
+	if err := verifyUndo(homeDir, ops[:n]); err != nil {
+		return err
+	}
+	for _, op := range ops[:n] {
+		journal.BeginUndo(op.ID)
+		for i := len(op.Changes) - 1; i >= 0; i-- {
+			if err := revertChange(homeDir, op.Changes[i]); err != nil {
+				return fmt.Errorf("undoing '%s': %w", op.Command, err)
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%s\n", Text(fmt.Sprintf("undid '%s' from %s (%d change(s))",
+			Primary(op.Command), op.Time.Local().Format("2006-01-02 15:04"), len(op.Changes))))
+	}
+	return nil
+}
+
+// verifyUndo checks that everything the operations touched is still in the
+// state they left behind, so an undo never discards later changes. ops are
+// checked newest first, each against the state undoing the ones before it
+// leaves.
+func verifyUndo(homeDir string, ops []journal.Operation) error {
+	type object struct{ kind, slug, id string }
+	undone := make(map[object][]byte)
+	for _, op := range ops {
+		final := make(map[object]journal.Change)
+		for _, c := range op.Changes {
+			final[object{c.Kind, c.Slug, c.ID}] = c
+		}
+
+		for obj, c := range final {
+			current, ok := undone[obj]
+			if !ok {
+				var err error
+				if current, err = currentState(homeDir, c); err != nil {
+					return err
+				}
+			}
+			if (current == nil) != (c.After == nil) || (current != nil && !journal.Equal(current, c.After)) {
+				return fmt.Errorf("cannot undo '%s': %s was changed afterwards", op.Command, describeChange(c))
+			}
+		}
+		for i := len(op.Changes) - 1; i >= 0; i-- {
+			c := op.Changes[i]
+			undone[object{c.Kind, c.Slug, c.ID}] = c.Before
+		}
+	}
+	return nil
+}
+
+// currentState returns the live counterpart of a change's snapshot.
+func currentState(homeDir string, c journal.Change) ([]byte, error) {
+	switch c.Kind {
+	case journal.KindEntry:
+		rec, err := entry.ReadRecord(homeDir, c.Slug, c.ID)
+		if errors.Is(err, entry.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return rec.Data, nil
+	case journal.KindConfig:
+		return project.ConfigSnapshot(homeDir)
+	case journal.KindProjectDir:
+		var slug string
+		if err := json.Unmarshal(c.After, &slug); err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(project.LogDir(homeDir, slug)); err != nil {
+			return nil, nil
+		}
+		return c.After, nil
+	}
+	return nil, fmt.Errorf("unknown journal change kind %q", c.Kind)
+}
+
+// revertChange puts back the state recorded before a change.
+func revertChange(homeDir string, c journal.Change) error {
+	switch c.Kind {
+	case journal.KindEntry:
+		return entry.RestoreEntry(homeDir, c.Slug, c.ID, c.Before)
+	case journal.KindConfig:
+		return project.RestoreConfig(homeDir, c.Before)
+	case journal.KindProjectDir:
+		var from, to string
+		if err := json.Unmarshal(c.After, &from); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(c.Before, &to); err != nil {
+			return err
+		}
+		return project.MoveLogDir(homeDir, from, to)
+	}
+	return fmt.Errorf("unknown journal change kind %q", c.Kind)
+}
+
+// describeChange names the object a change touched.
+func describeChange(c journal.Change) string {
+	switch c.Kind {
+	case journal.KindEntry:
+		return fmt.Sprintf("entry %s in '%s'", c.ID, c.Slug)
+	case journal.KindProjectDir:
+		return "the project directory"
+	}
+	return "the config"
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execUndo(homeDir string, n int) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := undoCmd
+	cmd.SetOut(stdout)
+
+	err := runUndo(cmd, homeDir, n)
+	return stdout.String(), err
+}
+
+// setupUndoTest runs the log remove fixture as its own "setup" operation.
+func setupUndoTest(t *testing.T) (string, string, *project.ProjectEntry, entry.Entry, entry.CheckoutEntry) {
+	t.Helper()
+	journal.Begin("setup")
+	return setupLogRemoveTest(t)
+}
+
+func TestUndoNothing(t *testing.T) {
+	stdout, err := execUndo(t.TempDir(), 1)
+
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "nothing to undo")
+}
+
+func TestUndoRemove(t *testing.T) {
+	homeDir, repoDir, proj, _, _ := setupUndoTest(t)
+
+	journal.Begin("log remove")
+	_, err := execLogRemove(homeDir, repoDir, "", "0010012", AlwaysYes())
+	require.NoError(t, err)
+
+	stdout, err := execUndo(homeDir, 1)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "undid 'log remove'")
+
+	e, err := entry.ReadEntry(homeDir, proj.Slug, "0010012")
+	require.NoError(t, err)
+	assert.Equal(t, "test work", e.Message)
+
+	// The undone operation is not offered again
+	stdout, err = execUndo(homeDir, 1)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "undid 'setup'")
+}
+
+func TestUndoMultipleOperations(t *testing.T) {
+	homeDir, _, proj, le, _ := setupUndoTest(t)
+
+	journal.Begin("log edit")
+	edited := le
+	edited.Message = "edited"
+	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, edited))
+	journal.Begin("log remove")
+	require.NoError(t, entry.DeleteEntry(homeDir, proj.Slug, le.ID))
+
+	stdout, err := execUndo(homeDir, 2)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "undid 'log remove'")
+	assert.Contains(t, stdout, "undid 'log edit'")
+
+	e, err := entry.ReadEntry(homeDir, proj.Slug, le.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "test work", e.Message)
+}
+
+func TestUndoRefusesWhenChangedAfterwards(t *testing.T) {
+	homeDir, _, proj, le, _ := setupUndoTest(t)
+
+	journal.Begin("log edit")
+	edited := le
+	edited.Message = "edited"
+	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, edited))
+
+	// A change the journal did not attribute to an undoable operation
+	journal.Begin("watch")
+	edited.Message = "edited again"
+	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, edited))
+
+	_, err := execUndo(homeDir, 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "was changed afterwards")
+
+	e, err := entry.ReadEntry(homeDir, proj.Slug, le.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "edited again", e.Message)
+}
+
+func TestUndoIsAllOrNothing(t *testing.T) {
+	homeDir, _, proj, le, _ := setupUndoTest(t)
+
+	journal.Begin("log edit")
+	edited := le
+	edited.Message = "edited"
+	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, edited))
+	journal.Begin("watch")
+	edited.Message = "edited again"
+	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, edited))
+	journal.Begin("log add")
+	added := le
+	added.ID = "0020021"
+	require.NoError(t, entry.WriteEntry(homeDir, proj.Slug, added))
+
+	// 'log add' could be undone, but 'log edit' cannot, so neither is
+	_, err := execUndo(homeDir, 2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot undo 'log edit'")
+
+	_, err = entry.ReadEntry(homeDir, proj.Slug, added.ID)
+	assert.NoError(t, err)
+}
+
+func TestUndoProjectRename(t *testing.T) {
+	homeDir, _, proj, le, _ := setupUndoTest(t)
+
+	journal.Begin("project edit")
+	_, err := project.RenameProject(homeDir, proj.ID, "Renamed")
+	require.NoError(t, err)
+
+	_, err = execUndo(homeDir, 1)
+	require.NoError(t, err)
+
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	restored := project.FindProjectByID(cfg, proj.ID)
+	require.NotNil(t, restored)
+	assert.Equal(t, "Remove Test", restored.Name)
+
+	_, err = entry.ReadEntry(homeDir, proj.Slug, le.ID)
+	assert.NoError(t, err)
+}
+
+func TestUndoProjectRemove(t *testing.T) {
+	homeDir, _, proj, le, _ := setupUndoTest(t)
+
+	journal.Begin("project remove")
+	_, err := execProjectRemove(homeDir, proj.Name, AlwaysYes())
+	require.NoError(t, err)
+
+	_, err = execUndo(homeDir, 1)
+	require.NoError(t, err)
+
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	assert.NotNil(t, project.FindProjectByID(cfg, proj.ID))
+	_, err = entry.ReadEntry(homeDir, proj.Slug, le.ID)
+	assert.NoError(t, err)
+}
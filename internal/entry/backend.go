@@ -0,0 +1,79 @@
+package entry
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Flyrell/hourgit/internal/project"
+)
+
+// ErrNotFound is returned by a Store when no entry exists for an ID.
+var ErrNotFound = errors.New("entry not found")
+
+// Record is a stored entry: its index metadata plus the raw JSON payload.
+type Record struct {
+	IndexRecord
+	Data []byte
+}
+
+// Store persists raw entry payloads per project. Implementations decide the
+// on-disk layout; typed reads and writes in this package go through it.
+type Store interface {
+	// Write creates or replaces the entry with the given ID.
+	Write(slug, id string, data []byte) error
+	// Read returns the payload for an ID, or ErrNotFound.
+	Read(slug, id string) ([]byte, error)
+	// Delete removes an entry, or returns ErrNotFound.
+	Delete(slug, id string) error
+	// List returns metadata for all entries matching q, ordered by ID.
+	List(slug string, q Query) ([]IndexRecord, error)
+	// Query returns metadata and payloads for all entries matching q, ordered by ID.
+	Query(slug string, q Query) ([]Record, error)
+}
+
+var (
+	storeOverridesMu sync.Mutex
+	storeOverrides   = make(map[string]Store)
+)
+
+// UseStore routes all entry operations for homeDir to s instead of the
+// configured backend, e.g. an in-memory store in tests. Call the returned
+// function to restore the default.
+func UseStore(homeDir string, s Store) (restore func()) {
+	storeOverridesMu.Lock()
+	defer storeOverridesMu.Unlock()
+	storeOverrides[homeDir] = s
+	return func() {
+		storeOverridesMu.Lock()
+		defer storeOverridesMu.Unlock()
+		delete(storeOverrides, homeDir)
+	}
+}
+
+// OpenStore returns the store configured for homeDir in config.json.
+func OpenStore(homeDir string) (Store, error) {
+	storeOverridesMu.Lock()
+	s, ok := storeOverrides[homeDir]
+	storeOverridesMu.Unlock()
+	if ok {
+		return s, nil
+	}
+
+	cfg, err := project.ReadConfig(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(homeDir, project.GetStorage(cfg))
+}
+
+// NewStore returns the store implementation for a storage backend name.
+func NewStore(homeDir, storage string) (Store, error) {
+	switch storage {
+	case "", project.StorageFiles:
+		return NewDirStore(homeDir), nil
+	case project.StorageSegments:
+		return sharedSegmentStore(homeDir), nil
+	}
+	return nil, fmt.Errorf("unknown storage backend %q", storage)
+}
@@ -0,0 +1,15 @@
+package entry
+
+import "time"
+
+// CorrectionEntry adjusts a project's flextime balance by hand, e.g. to carry
+// over hours from another system or to book paid-out overtime. Minutes is
+// positive to add to the balance and negative to take from it.
+type CorrectionEntry struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Date      time.Time `json:"date"`
+	Minutes   int       `json:"minutes"`
+	Message   string    `json:"message,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
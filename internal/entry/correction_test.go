@@ -0,0 +1,61 @@
+package entry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCorrectionEntry(id string, minutes int) CorrectionEntry {
+	return CorrectionEntry{
+		ID:        id,
+		Date:      time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		Minutes:   minutes,
+		Message:   "carried over from the old system",
+		CreatedAt: time.Date(2025, 6, 15, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWriteAndReadCorrectionEntry(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+	e := testCorrectionEntry("ccc1234", -90)
+
+	require.NoError(t, WriteCorrectionEntry(home, slug, e))
+
+	entries, err := ReadAllCorrectionEntries(home, slug)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, e.ID, entries[0].ID)
+	assert.Equal(t, TypeCorrection, entries[0].Type)
+	assert.Equal(t, e.Date, entries[0].Date)
+	assert.Equal(t, -90, entries[0].Minutes)
+	assert.Equal(t, e.Message, entries[0].Message)
+}
+
+func TestReadAllCorrectionEntriesEmpty(t *testing.T) {
+	home := t.TempDir()
+	entries, err := ReadAllCorrectionEntries(home, "nonexistent")
+	require.NoError(t, err)
+	assert.Nil(t, entries)
+}
+
+func TestReadAllCorrectionEntriesSkipsOtherTypes(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+
+	require.NoError(t, WriteEntry(home, slug, testEntry("a0a1111", "work")))
+	require.NoError(t, WriteSubmitEntry(home, slug, testSubmitEntry("b0b1111")))
+	require.NoError(t, WriteCorrectionEntry(home, slug, testCorrectionEntry("c0c1111", 120)))
+
+	entries, err := ReadAllCorrectionEntries(home, slug)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "c0c1111", entries[0].ID)
+
+	logs, err := ReadAllEntries(home, slug)
+	require.NoError(t, err)
+	assert.Len(t, logs, 1)
+}
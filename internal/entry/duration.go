@@ -54,3 +54,33 @@ func FormatMinutes(m int) string {
 
 	return strings.Join(parts, " ")
 }
+
+// FormatSignedMinutes formats a minute count that may be negative, with its
+// sign. Examples: 90 → "+1h 30m", -30 → "-30m", 0 → "0m".
+func FormatSignedMinutes(m int) string {
+	switch {
+	case m > 0:
+		return "+" + FormatMinutes(m)
+	case m < 0:
+		return "-" + FormatMinutes(-m)
+	}
+	return "0m"
+}
+
+// ParseSignedDuration parses a duration with an optional sign, e.g. "+2h",
+// "-1h30m" or "45m", into minutes.
+func ParseSignedDuration(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	sign := 1
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	minutes, err := ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	return sign * minutes, nil
+}
@@ -65,3 +65,50 @@ func TestFormatMinutes(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSignedDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"2h", 120, false},
+		{"+2h", 120, false},
+		{"-1h30m", -90, false},
+		{" -45m ", -45, false},
+		{"-", 0, true},
+		{"--1h", 0, true},
+		{"+0m", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSignedDuration(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatSignedMinutes(t *testing.T) {
+	tests := []struct {
+		input int
+		want  string
+	}{
+		{0, "0m"},
+		{90, "+1h 30m"},
+		{-30, "-30m"},
+		{-1500, "-25h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatSignedMinutes(tt.input))
+		})
+	}
+}
@@ -9,6 +9,8 @@ const (
 	TypeCommit        = "commit"
 	TypeActivityStop  = "activity_stop"
 	TypeActivityStart = "activity_start"
+	TypeCorrection    = "correction"
+	TypeGitEvent      = "git_event"
 )
 
 // Entry represents a single time log entry (a "time commit").
@@ -19,6 +21,7 @@ type Entry struct {
 	Minutes   int       `json:"minutes"`
 	Message   string    `json:"message"`
 	Task      string    `json:"task,omitempty"`
+	Category  string    `json:"category,omitempty"`
 	Source    string    `json:"source,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -0,0 +1,27 @@
+package entry
+
+import "time"
+
+// Git event kinds, the reflog events besides checkouts and commits.
+const (
+	EventMerge        = "merge"
+	EventRebase       = "rebase"
+	EventCherryPick   = "cherry-pick"
+	EventReset        = "reset"
+	EventPull         = "pull"
+	EventBranchRename = "branch-rename"
+)
+
+// GitEventEntry represents a merge, rebase, cherry-pick, reset, pull or
+// branch rename captured from reflog.
+type GitEventEntry struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Kind      string    `json:"kind"`
+	Message   string    `json:"message"` // the reflog subject, e.g. "rebase (pick): add login form"
+	CommitRef string    `json:"commit_ref"`
+	Branch    string    `json:"branch"`             // branch checked out at the time; the new name for renames
+	Previous  string    `json:"previous,omitempty"` // the old name for renames
+	Repo      string    `json:"repo,omitempty"`
+}
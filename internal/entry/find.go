@@ -1,10 +1,13 @@
 package entry
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
+
+	"github.com/Flyrell/hourgit/internal/project"
 )
 
 // FoundEntry pairs an entry with the project slug it was found in.
@@ -13,10 +16,10 @@ type FoundEntry struct {
 	Slug  string
 }
 
-// iterateProjectSlugs returns all project directory names under ~/.hourgit/.
-func iterateProjectSlugs(homeDir string) ([]string, error) {
-	hourgitDir := filepath.Join(homeDir, ".hourgit")
-	dirs, err := os.ReadDir(hourgitDir)
+// ProjectSlugs returns all project directory names in the data directory.
+// Hidden directories are not projects and are skipped.
+func ProjectSlugs(homeDir string) ([]string, error) {
+	dirs, err := os.ReadDir(project.DataDir(homeDir))
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return nil, nil
@@ -26,43 +29,105 @@ func iterateProjectSlugs(homeDir string) ([]string, error) {
 
 	var slugs []string
 	for _, d := range dirs {
-		if d.IsDir() {
+		if d.IsDir() && !strings.HasPrefix(d.Name(), ".") {
 			slugs = append(slugs, d.Name())
 		}
 	}
 	return slugs, nil
 }
 
-// FindEntryAcrossProjects scans all project directories under ~/.hourgit/
-// looking for a log entry with the given ID. Returns the first match.
-// If the ID exists as a checkout entry, returns an error indicating it cannot be edited.
-func FindEntryAcrossProjects(homeDir, id string) (*FoundEntry, error) {
-	slugs, err := iterateProjectSlugs(homeDir)
+// FoundRecord is an entry of any type located by ID prefix and the project it lives in.
+type FoundRecord struct {
+	Record
+	Slug string
+}
+
+// FindRecordAcrossProjects resolves an ID or unique ID prefix across all
+// project directories. An exact ID match wins over prefix matches. Returns
+// nil if nothing matches, or an *AmbiguousIDError.
+func FindRecordAcrossProjects(homeDir, prefix string) (*FoundRecord, error) {
+	if !validPrefixPattern.MatchString(prefix) {
+		return nil, nil
+	}
+	slugs, err := ProjectSlugs(homeDir)
 	if err != nil {
 		return nil, err
 	}
-	if slugs == nil {
-		return nil, fmt.Errorf("entry '%s' not found", id)
-	}
 
+	type candidate struct {
+		slug string
+		id   string
+	}
+	var exact, partial []candidate
 	for _, slug := range slugs {
-		// Try as log entry
-		e, err := ReadEntry(homeDir, slug, id)
-		if err == nil {
-			return &FoundEntry{Entry: e, Slug: slug}, nil
+		ids, err := LoadIDs(homeDir, slug)
+		if err != nil {
+			return nil, err
 		}
-
-		// Check if it's a checkout entry
-		if IsCheckoutEntry(homeDir, slug, id) {
-			return nil, fmt.Errorf("entry '%s' is a checkout entry and cannot be edited", id)
+		for _, id := range ids.Match(prefix) {
+			if id == prefix {
+				exact = append(exact, candidate{slug, id})
+			} else {
+				partial = append(partial, candidate{slug, id})
+			}
 		}
+	}
 
-		// Check if it's a commit entry
-		if IsCommitEntry(homeDir, slug, id) {
-			return nil, fmt.Errorf("entry '%s' is a commit entry and cannot be edited", id)
+	matches := exact
+	if len(matches) == 0 {
+		matches = partial
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		ambiguous := &AmbiguousIDError{Prefix: prefix}
+		for _, c := range matches {
+			ambiguous.Matches = append(ambiguous.Matches, fmt.Sprintf("%s (%s)", c.id, c.slug))
 		}
+		return nil, ambiguous
+	}
+
+	rec, err := ReadRecord(homeDir, matches[0].slug, matches[0].id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rec.Invalid {
+		return nil, nil
+	}
+	return &FoundRecord{Record: rec, Slug: matches[0].slug}, nil
+}
+
+// FindEntryAcrossProjects scans all project directories
+// looking for a log entry with the given ID or unique ID prefix.
+// If the ID exists as a checkout entry, returns an error indicating it cannot be edited.
+func FindEntryAcrossProjects(homeDir, id string) (*FoundEntry, error) {
+	found, err := FindRecordAcrossProjects(homeDir, id)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("entry '%s' not found", id)
 	}
 
+	switch found.Type {
+	case TypeLog:
+		e, err := ReadEntry(homeDir, found.Slug, found.File)
+		if err != nil {
+			return nil, err
+		}
+		return &FoundEntry{Entry: e, Slug: found.Slug}, nil
+	case TypeCheckout:
+		return nil, fmt.Errorf("entry '%s' is a checkout entry and cannot be edited", found.File)
+	case TypeCommit:
+		return nil, fmt.Errorf("entry '%s' is a commit entry and cannot be edited", found.File)
+	case TypeGitEvent:
+		return nil, fmt.Errorf("entry '%s' is a git event entry and cannot be edited", found.File)
+	}
 	return nil, fmt.Errorf("entry '%s' not found", id)
 }
 
@@ -74,41 +139,89 @@ type FoundAnyEntry struct {
 	Detail string // human-readable summary
 }
 
-// FindAnyEntryAcrossProjects scans all project directories under ~/.hourgit/
-// looking for a log or checkout entry with the given ID. Returns the first match.
+// FindAnyEntryAcrossProjects scans all project directories
+// looking for a log or checkout entry with the given ID or unique ID prefix.
 func FindAnyEntryAcrossProjects(homeDir, id string) (*FoundAnyEntry, error) {
-	slugs, err := iterateProjectSlugs(homeDir)
+	found, err := FindRecordAcrossProjects(homeDir, id)
 	if err != nil {
 		return nil, err
 	}
-	if slugs == nil {
+	if found == nil {
 		return nil, fmt.Errorf("entry '%s' not found", id)
 	}
 
-	for _, slug := range slugs {
-		// Try as log entry
-		e, err := ReadEntry(homeDir, slug, id)
-		if err == nil {
-			detail := fmt.Sprintf("%s — %s", FormatMinutes(e.Minutes), e.Message)
-			if e.Task != "" {
-				detail = fmt.Sprintf("[%s] %s", e.Task, detail)
-			}
-			return &FoundAnyEntry{ID: e.ID, Type: TypeLog, Slug: slug, Detail: detail}, nil
+	switch found.Type {
+	case TypeLog:
+		e, err := ReadEntry(homeDir, found.Slug, found.File)
+		if err != nil {
+			return nil, err
 		}
-
-		// Try as checkout entry
-		ce, err := ReadCheckoutEntry(homeDir, slug, id)
-		if err == nil {
-			detail := fmt.Sprintf("%s → %s at %s",
-				ce.Previous, ce.Next, ce.Timestamp.Format("2006-01-02 15:04"))
-			return &FoundAnyEntry{ID: ce.ID, Type: TypeCheckout, Slug: slug, Detail: detail}, nil
+		return &FoundAnyEntry{ID: found.File, Type: TypeLog, Slug: found.Slug, Detail: DescribeEntry(e)}, nil
+	case TypeCheckout:
+		ce, err := ReadCheckoutEntry(homeDir, found.Slug, found.File)
+		if err != nil {
+			return nil, err
 		}
+		return &FoundAnyEntry{ID: found.File, Type: TypeCheckout, Slug: found.Slug, Detail: DescribeCheckoutEntry(ce)}, nil
+	case TypeCommit:
+		return &FoundAnyEntry{ID: found.File, Type: TypeCommit, Slug: found.Slug, Detail: "commit entry"}, nil
+	case TypeGitEvent:
+		return &FoundAnyEntry{ID: found.File, Type: TypeGitEvent, Slug: found.Slug, Detail: "git event entry"}, nil
+	}
+	return nil, fmt.Errorf("entry '%s' not found", id)
+}
 
-		// Try as commit entry
-		if IsCommitEntry(homeDir, slug, id) {
-			return &FoundAnyEntry{ID: id, Type: TypeCommit, Slug: slug, Detail: "commit entry"}, nil
-		}
+// DescribeEntry returns a one-line summary of a log entry.
+func DescribeEntry(e Entry) string {
+	detail := fmt.Sprintf("%s — %s", FormatMinutes(e.Minutes), e.Message)
+	if e.Task != "" {
+		detail = fmt.Sprintf("[%s] %s", e.Task, detail)
 	}
+	return detail
+}
 
-	return nil, fmt.Errorf("entry '%s' not found", id)
+// DescribeCheckoutEntry returns a one-line summary of a checkout entry.
+func DescribeCheckoutEntry(ce CheckoutEntry) string {
+	return fmt.Sprintf("%s → %s at %s",
+		ce.Previous, ce.Next, ce.Timestamp.Format("2006-01-02 15:04"))
+}
+
+// DescribeRecord returns a one-line summary of a raw entry payload of any type.
+func DescribeRecord(data []byte) string {
+	r := recordFromData("", data)
+	switch r.Type {
+	case TypeLog:
+		var e Entry
+		if err := json.Unmarshal(data, &e); err == nil {
+			return fmt.Sprintf("%s %s", e.Start.Format("2006-01-02 15:04"), DescribeEntry(e))
+		}
+	case TypeCheckout:
+		var ce CheckoutEntry
+		if err := json.Unmarshal(data, &ce); err == nil {
+			return DescribeCheckoutEntry(ce)
+		}
+	case TypeCommit:
+		var ce CommitEntry
+		if err := json.Unmarshal(data, &ce); err == nil {
+			return fmt.Sprintf("%s on %s at %s", ce.Message, ce.Branch, ce.Timestamp.Format("2006-01-02 15:04"))
+		}
+	case TypeGitEvent:
+		var ge GitEventEntry
+		if err := json.Unmarshal(data, &ge); err == nil {
+			return fmt.Sprintf("%s on %s at %s", ge.Message, ge.Branch, ge.Timestamp.Format("2006-01-02 15:04"))
+		}
+	case TypeSubmit:
+		var se SubmitEntry
+		if err := json.Unmarshal(data, &se); err == nil {
+			return fmt.Sprintf("submitted %s – %s", se.From.Format("2006-01-02"), se.To.Format("2006-01-02"))
+		}
+	case TypeCorrection:
+		var ce CorrectionEntry
+		if err := json.Unmarshal(data, &ce); err == nil {
+			return fmt.Sprintf("%s balance correction %s — %s", ce.Date.Format("2006-01-02"), FormatSignedMinutes(ce.Minutes), ce.Message)
+		}
+	case TypeActivityStart, TypeActivityStop:
+		return fmt.Sprintf("%s at %s", strings.ReplaceAll(r.Type, "_", " "), r.Start.Format("2006-01-02 15:04"))
+	}
+	return r.Type + " entry"
 }
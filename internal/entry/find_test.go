@@ -2,10 +2,10 @@ package entry
 
 import (
 	"os"
-	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/Flyrell/hourgit/internal/project"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,7 +15,7 @@ func TestFindEntryAcrossProjects(t *testing.T) {
 	slug := "my-project"
 
 	// Create project directory and write an entry
-	dir := filepath.Join(homeDir, ".hourgit", slug)
+	dir := project.LogDir(homeDir, slug)
 	require.NoError(t, os.MkdirAll(dir, 0755))
 
 	e := Entry{
@@ -36,7 +36,7 @@ func TestFindEntryAcrossProjects(t *testing.T) {
 
 func TestFindEntryAcrossProjectsNotFound(t *testing.T) {
 	homeDir := t.TempDir()
-	require.NoError(t, os.MkdirAll(filepath.Join(homeDir, ".hourgit", "some-project"), 0755))
+	require.NoError(t, os.MkdirAll(project.LogDir(homeDir, "some-project"), 0755))
 
 	_, err := FindEntryAcrossProjects(homeDir, "aaa0000")
 	assert.Error(t, err)
@@ -47,7 +47,7 @@ func TestFindEntryAcrossProjectsSkipsCheckoutEntries(t *testing.T) {
 	homeDir := t.TempDir()
 	slug := "my-project"
 
-	dir := filepath.Join(homeDir, ".hourgit", slug)
+	dir := project.LogDir(homeDir, slug)
 	require.NoError(t, os.MkdirAll(dir, 0755))
 
 	// Write a checkout entry — should be skipped
@@ -69,8 +69,8 @@ func TestFindEntryAcrossProjectsMultipleProjects(t *testing.T) {
 	homeDir := t.TempDir()
 
 	// Create two project directories
-	require.NoError(t, os.MkdirAll(filepath.Join(homeDir, ".hourgit", "project-a"), 0755))
-	require.NoError(t, os.MkdirAll(filepath.Join(homeDir, ".hourgit", "project-b"), 0755))
+	require.NoError(t, os.MkdirAll(project.LogDir(homeDir, "project-a"), 0755))
+	require.NoError(t, os.MkdirAll(project.LogDir(homeDir, "project-b"), 0755))
 
 	// Write entry in project-b
 	e := Entry{
@@ -101,7 +101,7 @@ func TestFindEntryAcrossProjectsSkipsCommitEntries(t *testing.T) {
 	homeDir := t.TempDir()
 	slug := "my-project"
 
-	dir := filepath.Join(homeDir, ".hourgit", slug)
+	dir := project.LogDir(homeDir, slug)
 	require.NoError(t, os.MkdirAll(dir, 0755))
 
 	ce := CommitEntry{
@@ -122,7 +122,7 @@ func TestFindAnyEntryAcrossProjectsFindsCommit(t *testing.T) {
 	homeDir := t.TempDir()
 	slug := "my-project"
 
-	dir := filepath.Join(homeDir, ".hourgit", slug)
+	dir := project.LogDir(homeDir, slug)
 	require.NoError(t, os.MkdirAll(dir, 0755))
 
 	ce := CommitEntry{
@@ -139,3 +139,35 @@ func TestFindAnyEntryAcrossProjectsFindsCommit(t *testing.T) {
 	assert.Equal(t, TypeCommit, found.Type)
 	assert.Equal(t, slug, found.Slug)
 }
+
+func TestFindEntryAcrossProjectsByPrefix(t *testing.T) {
+	homeDir := t.TempDir()
+
+	require.NoError(t, WriteEntry(homeDir, "alpha", testEntry("abc1234def00", "alpha work")))
+	require.NoError(t, WriteEntry(homeDir, "beta", testEntry("abd5678", "beta work")))
+
+	found, err := FindEntryAcrossProjects(homeDir, "abc1")
+	require.NoError(t, err)
+	assert.Equal(t, "alpha", found.Slug)
+	assert.Equal(t, "abc1234def00", found.Entry.ID)
+
+	any, err := FindAnyEntryAcrossProjects(homeDir, "abd5")
+	require.NoError(t, err)
+	assert.Equal(t, "beta", any.Slug)
+	assert.Equal(t, "abd5678", any.ID)
+}
+
+func TestFindEntryAcrossProjectsAmbiguousPrefix(t *testing.T) {
+	homeDir := t.TempDir()
+
+	require.NoError(t, WriteEntry(homeDir, "alpha", testEntry("abc1234", "alpha work")))
+	require.NoError(t, WriteEntry(homeDir, "beta", testEntry("abc1299", "beta work")))
+
+	_, err := FindAnyEntryAcrossProjects(homeDir, "abc1")
+	assert.EqualError(t, err, "entry ID 'abc1' is ambiguous, it matches: abc1234 (alpha), abc1299 (beta)")
+
+	// The full ID is still unique
+	found, err := FindEntryAcrossProjects(homeDir, "abc1299")
+	require.NoError(t, err)
+	assert.Equal(t, "beta", found.Slug)
+}
@@ -0,0 +1,161 @@
+package entry
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Flyrell/hourgit/internal/hashutil"
+)
+
+// MinPrefixLength is the shortest ID prefix accepted when resolving entries.
+const MinPrefixLength = 4
+
+// legacyIDLength is the length of IDs written by older versions. Seeded IDs
+// are matched against stored IDs from this length up when deduplicating.
+const legacyIDLength = 7
+
+var (
+	validIDPattern     = regexp.MustCompile(`^[0-9a-f]{7,64}$`)
+	validPrefixPattern = regexp.MustCompile(`^[0-9a-f]{4,64}$`)
+)
+
+// validateID checks that an entry ID is 7 to 64 lowercase hex characters.
+func validateID(id string) error {
+	if !validIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid entry ID %q", id)
+	}
+	return nil
+}
+
+// AmbiguousIDError is returned when an ID prefix matches more than one entry.
+type AmbiguousIDError struct {
+	Prefix  string
+	Matches []string
+}
+
+func (e *AmbiguousIDError) Error() string {
+	return fmt.Sprintf("entry ID '%s' is ambiguous, it matches: %s", e.Prefix, strings.Join(e.Matches, ", "))
+}
+
+// IDSet holds the IDs stored in a project, for allocating new IDs and
+// resolving prefixes.
+type IDSet struct {
+	ids    map[string]bool
+	sorted []string
+}
+
+// LoadIDs returns the set of IDs stored in a project.
+func LoadIDs(homeDir, slug string) (*IDSet, error) {
+	store, err := OpenStore(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	records, err := store.List(slug, Query{Invalid: true})
+	if err != nil {
+		return nil, err
+	}
+	s := &IDSet{ids: make(map[string]bool, len(records))}
+	for _, r := range records {
+		s.Add(r.File)
+	}
+	return s, nil
+}
+
+// Add records an ID as taken.
+func (s *IDSet) Add(id string) {
+	if s.ids[id] {
+		return
+	}
+	s.ids[id] = true
+	i := sort.SearchStrings(s.sorted, id)
+	s.sorted = append(s.sorted, "")
+	copy(s.sorted[i+1:], s.sorted[i:])
+	s.sorted[i] = id
+}
+
+// Known reports whether an ID derived from hash is already stored, whatever
+// length it was allocated with. Used to skip re-creating seeded entries.
+func (s *IDSet) Known(hash string) bool {
+	for n := legacyIDLength; n <= len(hash); n++ {
+		if s.ids[hash[:n]] {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the stored IDs starting with prefix, in sorted order.
+func (s *IDSet) Match(prefix string) []string {
+	var matches []string
+	for i := sort.SearchStrings(s.sorted, prefix); i < len(s.sorted) && strings.HasPrefix(s.sorted[i], prefix); i++ {
+		matches = append(matches, s.sorted[i])
+	}
+	return matches
+}
+
+// conflicts reports whether id equals or is a prefix of a stored ID, which
+// would make the stored entry unreachable by its full ID.
+func (s *IDSet) conflicts(id string) bool {
+	return len(s.Match(id)) > 0
+}
+
+// Allocate returns a new ID derived from seed and marks it as taken. The ID
+// is hashutil.EntryIDLength characters long, extended one character at a
+// time while it collides with a stored ID.
+func (s *IDSet) Allocate(seed string) string {
+	hash := hashutil.Hash(seed)
+	id := hash
+	for n := hashutil.EntryIDLength; n <= len(hash); n++ {
+		if !s.conflicts(hash[:n]) {
+			id = hash[:n]
+			break
+		}
+	}
+	s.Add(id)
+	return id
+}
+
+// Resolve returns the stored ID matching an ID or unique ID prefix. An exact
+// match wins over longer IDs sharing the prefix, like git does.
+func (s *IDSet) Resolve(prefix string) (string, error) {
+	if !validPrefixPattern.MatchString(prefix) {
+		return "", ErrNotFound
+	}
+	if s.ids[prefix] {
+		return prefix, nil
+	}
+	matches := s.Match(prefix)
+	switch len(matches) {
+	case 0:
+		return "", ErrNotFound
+	case 1:
+		return matches[0], nil
+	}
+	return "", &AmbiguousIDError{Prefix: prefix, Matches: matches}
+}
+
+// NewID allocates an unused ID for a new entry in the project.
+func NewID(homeDir, slug, seed string) (string, error) {
+	ids, err := LoadIDs(homeDir, slug)
+	if err != nil {
+		return "", err
+	}
+	return ids.Allocate(seed), nil
+}
+
+// ResolveID returns the full ID of the entry in the project matching an ID or
+// unique prefix. Returns ErrNotFound or an *AmbiguousIDError.
+func ResolveID(homeDir, slug, prefix string) (string, error) {
+	ids, err := LoadIDs(homeDir, slug)
+	if err != nil {
+		return "", err
+	}
+	return ids.Resolve(prefix)
+}
+
+// IsValidID reports whether id is a well-formed stored entry ID.
+func IsValidID(id string) bool {
+	return validateID(id) == nil
+}
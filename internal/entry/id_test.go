@@ -0,0 +1,100 @@
+package entry
+
+import (
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/hashutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIDSet(ids ...string) *IDSet {
+	s := &IDSet{ids: make(map[string]bool)}
+	for _, id := range ids {
+		s.Add(id)
+	}
+	return s
+}
+
+func TestIDSetAllocateDefaultLength(t *testing.T) {
+	s := newIDSet()
+
+	id := s.Allocate("seed")
+
+	assert.Equal(t, hashutil.Hash("seed")[:hashutil.EntryIDLength], id)
+	assert.NoError(t, validateID(id))
+}
+
+func TestIDSetAllocateExtendsOnCollision(t *testing.T) {
+	hash := hashutil.Hash("seed")
+	s := newIDSet(hash[:hashutil.EntryIDLength])
+
+	id := s.Allocate("seed")
+
+	assert.Equal(t, hash[:hashutil.EntryIDLength+1], id)
+}
+
+func TestIDSetAllocateAvoidsLegacyPrefix(t *testing.T) {
+	hash := hashutil.Hash("seed")
+	// A legacy 7-char ID from the same hash stays reachable by its exact ID
+	s := newIDSet(hash[:7])
+
+	id := s.Allocate("seed")
+
+	assert.NotEqual(t, hash[:7], id)
+	resolved, err := s.Resolve(hash[:7])
+	require.NoError(t, err)
+	assert.Equal(t, hash[:7], resolved)
+}
+
+func TestIDSetKnownMatchesAnyLength(t *testing.T) {
+	hash := hashutil.Hash("seed")
+
+	assert.True(t, newIDSet(hash[:7]).Known(hash))
+	assert.True(t, newIDSet(hash[:13]).Known(hash))
+	assert.False(t, newIDSet(hash[:6]).Known(hash))
+	assert.False(t, newIDSet("0000000").Known(hash))
+}
+
+func TestIDSetResolve(t *testing.T) {
+	s := newIDSet("abc1234", "abc1234def00", "abd5678aaaaa")
+
+	id, err := s.Resolve("abd5")
+	require.NoError(t, err)
+	assert.Equal(t, "abd5678aaaaa", id)
+
+	// Exact match wins over a longer ID sharing the prefix
+	id, err = s.Resolve("abc1234")
+	require.NoError(t, err)
+	assert.Equal(t, "abc1234", id)
+
+	_, err = s.Resolve("abc1")
+	var ambiguous *AmbiguousIDError
+	require.ErrorAs(t, err, &ambiguous)
+	assert.Equal(t, []string{"abc1234", "abc1234def00"}, ambiguous.Matches)
+	assert.EqualError(t, err, "entry ID 'abc1' is ambiguous, it matches: abc1234, abc1234def00")
+
+	_, err = s.Resolve("abc") // too short
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.Resolve("ffff")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestNewIDAndResolveID(t *testing.T) {
+	home := t.TempDir()
+	slug := "proj"
+
+	id, err := NewID(home, slug, "seed")
+	require.NoError(t, err)
+	e := testEntry(id, "work")
+	require.NoError(t, WriteEntry(home, slug, e))
+
+	// The same seed now collides and gets a longer ID
+	id2, err := NewID(home, slug, "seed")
+	require.NoError(t, err)
+	assert.Len(t, id2, hashutil.EntryIDLength+1)
+
+	resolved, err := ResolveID(home, slug, id[:5])
+	require.NoError(t, err)
+	assert.Equal(t, id, resolved)
+}
@@ -0,0 +1,284 @@
+package entry
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
+)
+
+// indexFileName is the per-project index file. Dot-prefixed so directory scans
+// never mistake it for an entry.
+const indexFileName = ".index"
+
+// indexVersion is bumped whenever IndexRecord changes shape; older indexes are
+// discarded and rebuilt from scratch.
+const indexVersion = 1
+
+// IndexRecord describes a single entry file without its payload.
+// Start and End span the entry's time range; point-in-time entries such as
+// checkouts have Start == End.
+type IndexRecord struct {
+	ID      string    `json:"id"`
+	Type    string    `json:"type"`
+	File    string    `json:"file"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Created time.Time `json:"created"`
+	ModTime int64     `json:"mtime"`
+	Size    int64     `json:"size"`
+	Invalid bool      `json:"invalid,omitempty"` // file could not be parsed
+}
+
+// SortTime returns the time used to order the record in activity feeds:
+// creation time for logs, start time for everything else.
+func (r IndexRecord) SortTime() time.Time {
+	if r.Type == TypeLog {
+		return r.Created
+	}
+	return r.Start
+}
+
+// Query selects index records by type and time range. Zero values mean
+// "any type" and "unbounded" respectively. Unparseable entries are only
+// returned when Invalid is set, regardless of the other fields.
+type Query struct {
+	Type    string
+	From    time.Time
+	To      time.Time
+	Invalid bool
+}
+
+// matches reports whether r satisfies the query.
+func (q Query) matches(r IndexRecord) bool {
+	if r.Invalid {
+		return q.Invalid
+	}
+	if q.Type != "" && r.Type != q.Type {
+		return false
+	}
+	if !q.From.IsZero() && r.End.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && r.Start.After(q.To) {
+		return false
+	}
+	return true
+}
+
+// Index maps entry files in a project's log directory to their type and time
+// range so readers can skip files they don't need.
+type Index struct {
+	dir     string
+	records map[string]IndexRecord // file name -> record
+}
+
+// indexFile is the on-disk form of an Index.
+type indexFile struct {
+	Version int           `json:"version"`
+	Records []IndexRecord `json:"records"`
+}
+
+// indexSealContext binds a sealed index to being an index.
+const indexSealContext = "index"
+
+// indexPath returns the path to a project's index file.
+func indexPath(homeDir, slug string) string {
+	return filepath.Join(project.LogDir(homeDir, slug), indexFileName)
+}
+
+// LoadIndex loads a project's index and brings it up to date with the log
+// directory. Only files whose size or mtime changed since the last refresh are
+// re-read. The refreshed index is persisted when anything changed.
+func LoadIndex(homeDir, slug string) (*Index, error) {
+	idx := &Index{
+		dir:     project.LogDir(homeDir, slug),
+		records: make(map[string]IndexRecord),
+	}
+
+	sealer, err := seal.Load(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := os.ReadFile(indexPath(homeDir, slug)); err == nil {
+		var f indexFile
+		if data, err = sealer.Open(data, indexSealContext); err != nil && !errors.Is(err, seal.ErrCorrupt) {
+			return nil, err
+		}
+		if json.Unmarshal(data, &f) == nil && f.Version == indexVersion {
+			for _, r := range f.Records {
+				idx.records[r.File] = r
+			}
+		}
+	}
+
+	changed, err := idx.refresh(sealer)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		// The index is only a cache — failing to persist it must not fail the read.
+		_ = idx.save(sealer, indexPath(homeDir, slug))
+	}
+	return idx, nil
+}
+
+// refresh re-scans the log directory and updates stale records.
+// Returns true if any record was added, updated, or dropped.
+func (idx *Index) refresh(sealer *seal.Sealer) (bool, error) {
+	files, err := os.ReadDir(idx.dir)
+	if os.IsNotExist(err) {
+		changed := len(idx.records) > 0
+		idx.records = make(map[string]IndexRecord)
+		return changed, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	seen := make(map[string]bool, len(files))
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		info, err := f.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		seen[f.Name()] = true
+
+		mtime := info.ModTime().UnixNano()
+		if r, ok := idx.records[f.Name()]; ok && r.ModTime == mtime && r.Size == info.Size() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(idx.dir, f.Name()))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if data, err = openEntry(sealer, f.Name(), data); err != nil {
+			return false, err
+		}
+		r := recordFromData(f.Name(), data)
+		r.ModTime = mtime
+		r.Size = info.Size()
+		idx.records[f.Name()] = r
+		changed = true
+	}
+
+	for name := range idx.records {
+		if !seen[name] {
+			delete(idx.records, name)
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// save writes the index to disk, sealed if the data is encrypted.
+func (idx *Index) save(sealer *seal.Sealer, path string) error {
+	f := indexFile{Version: indexVersion, Records: idx.sorted()}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if data, err = sealer.Seal(data, indexSealContext); err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, data, 0600)
+}
+
+// sorted returns all records ordered by file name.
+func (idx *Index) sorted() []IndexRecord {
+	records := make([]IndexRecord, 0, len(idx.records))
+	for _, r := range idx.records {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].File < records[j].File
+	})
+	return records
+}
+
+// Find returns all valid records matching q, ordered by file name.
+func (idx *Index) Find(q Query) []IndexRecord {
+	var result []IndexRecord
+	for _, r := range idx.sorted() {
+		if q.matches(r) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// Lookup returns the record for the entry with the given ID.
+func (idx *Index) Lookup(id string) (IndexRecord, bool) {
+	r, ok := idx.records[id]
+	if !ok || r.Invalid {
+		return IndexRecord{}, false
+	}
+	return r, true
+}
+
+// indexMeta holds every field the index needs across all entry types.
+type indexMeta struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Start     time.Time `json:"start"`
+	Minutes   int       `json:"minutes"`
+	Timestamp time.Time `json:"timestamp"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Date      time.Time `json:"date"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// recordFromData builds an index record from a raw entry file.
+// Files that fail to parse are kept as invalid records so they are not
+// re-read on every refresh.
+func recordFromData(name string, data []byte) IndexRecord {
+	r := IndexRecord{File: name}
+
+	var m indexMeta
+	if err := json.Unmarshal(data, &m); err != nil {
+		r.Invalid = true
+		return r
+	}
+
+	r.ID = m.ID
+	r.Type = m.Type
+	if r.Type == "" {
+		r.Type = TypeLog // legacy compatibility, see matchesType
+	}
+	r.Created = m.CreatedAt
+
+	switch r.Type {
+	case TypeLog:
+		r.Start = m.Start
+		r.End = m.Start.Add(time.Duration(m.Minutes) * time.Minute)
+	case TypeSubmit:
+		r.Start = m.From
+		r.End = m.To
+	case TypeCorrection:
+		r.Start = m.Date
+		r.End = m.Date
+	default:
+		r.Start = m.Timestamp
+		r.End = m.Timestamp
+	}
+	return r
+}
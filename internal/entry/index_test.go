@@ -0,0 +1,183 @@
+package entry
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadIndexEmptyDir(t *testing.T) {
+	home := t.TempDir()
+
+	idx, err := LoadIndex(home, "nonexistent")
+	require.NoError(t, err)
+	assert.Empty(t, idx.Find(Query{}))
+}
+
+func TestLoadIndexClassifiesEntries(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+
+	require.NoError(t, WriteEntry(home, slug, testEntry("aaa1111", "log")))
+	require.NoError(t, WriteCheckoutEntry(home, slug, testCheckoutEntry("bbb2222", "main", "feature")))
+	require.NoError(t, WriteCommitEntry(home, slug, CommitEntry{
+		ID:        "ccc3333",
+		Timestamp: time.Date(2025, 6, 16, 12, 0, 0, 0, time.UTC),
+		Message:   "fix",
+	}))
+
+	idx, err := LoadIndex(home, slug)
+	require.NoError(t, err)
+
+	logs := idx.Find(Query{Type: TypeLog})
+	require.Len(t, logs, 1)
+	assert.Equal(t, "aaa1111", logs[0].ID)
+	assert.Equal(t, time.Date(2025, 6, 15, 9, 0, 0, 0, time.UTC), logs[0].Start)
+	assert.Equal(t, time.Date(2025, 6, 15, 10, 0, 0, 0, time.UTC), logs[0].End)
+
+	checkouts := idx.Find(Query{Type: TypeCheckout})
+	require.Len(t, checkouts, 1)
+	assert.Equal(t, checkouts[0].Start, checkouts[0].End)
+
+	assert.Len(t, idx.Find(Query{}), 3)
+}
+
+func TestIndexQueryByRange(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+
+	for i, day := range []int{1, 10, 20} {
+		e := testEntry([]string{"aaa0001", "aaa0002", "aaa0003"}[i], "work")
+		e.Start = time.Date(2025, 6, day, 9, 0, 0, 0, time.UTC)
+		require.NoError(t, WriteEntry(home, slug, e))
+	}
+
+	idx, err := LoadIndex(home, slug)
+	require.NoError(t, err)
+
+	got := idx.Find(Query{
+		Type: TypeLog,
+		From: time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC),
+	})
+	require.Len(t, got, 1)
+	assert.Equal(t, "aaa0002", got[0].ID)
+
+	// Ranges overlapping the end of an entry still match it
+	got = idx.Find(Query{From: time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)})
+	assert.Len(t, got, 3)
+}
+
+func TestIndexRefreshesChangedAndRemovedFiles(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+
+	require.NoError(t, WriteEntry(home, slug, testEntry("aaa1111", "first")))
+	require.NoError(t, WriteEntry(home, slug, testEntry("bbb2222", "second")))
+	_, err := LoadIndex(home, slug)
+	require.NoError(t, err)
+
+	// Change one entry's time range and delete the other
+	changed := testEntry("aaa1111", "first, longer")
+	changed.Minutes = 240
+	require.NoError(t, WriteEntry(home, slug, changed))
+	require.NoError(t, DeleteEntry(home, slug, "bbb2222"))
+
+	idx, err := LoadIndex(home, slug)
+	require.NoError(t, err)
+
+	all := idx.Find(Query{})
+	require.Len(t, all, 1)
+	assert.Equal(t, time.Date(2025, 6, 15, 13, 0, 0, 0, time.UTC), all[0].End)
+	_, ok := idx.Lookup("bbb2222")
+	assert.False(t, ok)
+}
+
+func TestIndexReusesUnchangedRecords(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+
+	require.NoError(t, WriteEntry(home, slug, testEntry("aaa1111", "work")))
+	_, err := LoadIndex(home, slug)
+	require.NoError(t, err)
+
+	// Tamper with the cached record; an unchanged file must not be re-read
+	data, err := os.ReadFile(indexPath(home, slug))
+	require.NoError(t, err)
+	var f indexFile
+	require.NoError(t, json.Unmarshal(data, &f))
+	require.Len(t, f.Records, 1)
+	f.Records[0].Type = TypeCommit
+	data, err = json.Marshal(f)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(indexPath(home, slug), data, 0644))
+
+	idx, err := LoadIndex(home, slug)
+	require.NoError(t, err)
+	rec, ok := idx.Lookup("aaa1111")
+	require.True(t, ok)
+	assert.Equal(t, TypeCommit, rec.Type)
+}
+
+func TestIndexIgnoresStaleVersion(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+
+	require.NoError(t, WriteEntry(home, slug, testEntry("aaa1111", "work")))
+	require.NoError(t, os.WriteFile(indexPath(home, slug),
+		[]byte(`{"version":0,"records":[{"id":"zzz","file":"zzz","type":"log"}]}`), 0644))
+
+	idx, err := LoadIndex(home, slug)
+	require.NoError(t, err)
+	all := idx.Find(Query{})
+	require.Len(t, all, 1)
+	assert.Equal(t, "aaa1111", all[0].ID)
+}
+
+func TestIndexLegacyAndCorruptFiles(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+	dir := project.LogDir(home, slug)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "aaa1111"),
+		[]byte(`{"id":"aaa1111","start":"2025-06-15T09:00:00Z","minutes":30}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bbb2222"), []byte("not json"), 0644))
+
+	idx, err := LoadIndex(home, slug)
+	require.NoError(t, err)
+
+	logs := idx.Find(Query{Type: TypeLog})
+	require.Len(t, logs, 1)
+	assert.Equal(t, "aaa1111", logs[0].ID)
+
+	_, ok := idx.Lookup("bbb2222")
+	assert.False(t, ok)
+
+	records, err := NewDirStore(home).Query(slug, Query{Type: TypeLog})
+	require.NoError(t, err)
+	entries := Decode[Entry](records, TypeLog)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 30, entries[0].Minutes)
+}
+
+func TestReadAllEntriesSkipsIndexFile(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+
+	require.NoError(t, WriteEntry(home, slug, testEntry("aaa1111", "work")))
+	_, err := LoadIndex(home, slug)
+	require.NoError(t, err)
+	_, err = os.Stat(indexPath(home, slug))
+	require.NoError(t, err)
+
+	entries, err := ReadAllEntries(home, slug)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,90 @@
+package entry
+
+import "os"
+
+// purger is implemented by stores that can drop a project's leftover storage
+// (e.g. tombstoned segment files) once all entries have been moved out.
+type purger interface {
+	Purge(slug string) error
+}
+
+// resealer is implemented by stores that can rewrite their files after
+// encryption was turned on or off.
+type resealer interface {
+	Reseal(slug string) (int, error)
+}
+
+// Reseal rewrites the entries of every project sealed or plain, whichever
+// homeDir currently uses (see 'hourgit encrypt'). Both backends are
+// converted, so leftovers of an earlier storage migration are covered too.
+// Returns the number of files rewritten.
+func Reseal(homeDir string) (int, error) {
+	slugs, err := ProjectSlugs(homeDir)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, slug := range slugs {
+		for _, r := range []resealer{NewDirStore(homeDir), NewSegmentStore(homeDir)} {
+			n, err := r.Reseal(slug)
+			total += n
+			if err != nil {
+				return total, err
+			}
+		}
+	}
+	return total, nil
+}
+
+// MigrateStore copies every entry of the given projects from src to dst and
+// then removes them from src. Nothing is removed until every entry has been
+// copied, so a failed migration leaves src intact and can be re-run.
+// Returns the number of entries moved.
+func MigrateStore(src, dst Store, slugs []string) (int, error) {
+	type moved struct {
+		slug string
+		id   string
+	}
+	var done []moved
+
+	for _, slug := range slugs {
+		records, err := src.Query(slug, Query{})
+		if err != nil {
+			return 0, err
+		}
+		for _, r := range records {
+			if err := dst.Write(slug, r.File, r.Data); err != nil {
+				return 0, err
+			}
+			done = append(done, moved{slug: slug, id: r.File})
+		}
+	}
+
+	for _, m := range done {
+		if err := src.Delete(m.slug, m.id); err != nil && err != ErrNotFound {
+			return len(done), err
+		}
+	}
+
+	if p, ok := src.(purger); ok {
+		for _, slug := range slugs {
+			if err := p.Purge(slug); err != nil {
+				return len(done), err
+			}
+		}
+	}
+
+	return len(done), nil
+}
+
+// Purge removes the project's segment directory.
+func (s *SegmentStore) Purge(slug string) error {
+	s.mu.Lock()
+	delete(s.states, slug)
+	s.mu.Unlock()
+	err := os.RemoveAll(s.segmentDir(slug))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package entry
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateStoreMovesAllEntries(t *testing.T) {
+	home := t.TempDir()
+	src := NewSegmentStore(home)
+	dst := NewMemoryStore()
+
+	require.NoError(t, src.Write("a", "aaa1111", []byte(`{"id":"aaa1111","start":"2025-06-15T09:00:00Z","minutes":30}`)))
+	require.NoError(t, src.Write("b", "bbb2222", []byte(`{"id":"bbb2222","type":"checkout","timestamp":"2025-07-01T09:00:00Z"}`)))
+
+	moved, err := MigrateStore(src, dst, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, moved)
+
+	_, err = dst.Read("a", "aaa1111")
+	assert.NoError(t, err)
+	_, err = dst.Read("b", "bbb2222")
+	assert.NoError(t, err)
+
+	// Segments are purged from the source
+	_, err = os.Stat(src.segmentDir("a"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+type failingStore struct {
+	*MemoryStore
+}
+
+func (failingStore) Write(string, string, []byte) error {
+	return errors.New("disk full")
+}
+
+func TestMigrateStoreKeepsSourceOnFailure(t *testing.T) {
+	src := NewMemoryStore()
+	require.NoError(t, src.Write("a", "aaa1111", []byte(`{"id":"aaa1111"}`)))
+
+	_, err := MigrateStore(src, failingStore{NewMemoryStore()}, []string{"a"})
+	assert.EqualError(t, err, "disk full")
+
+	_, err = src.Read("a", "aaa1111")
+	assert.NoError(t, err)
+}
@@ -2,25 +2,15 @@ package entry
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
-	"regexp"
 
+	"github.com/Flyrell/hourgit/internal/journal"
 	"github.com/Flyrell/hourgit/internal/project"
 )
 
-var validIDPattern = regexp.MustCompile(`^[0-9a-f]{7}$`)
-
-// validateID checks that an entry ID matches the expected 7-char hex format.
-func validateID(id string) error {
-	if !validIDPattern.MatchString(id) {
-		return fmt.Errorf("invalid entry ID %q", id)
-	}
-	return nil
-}
-
-// EntryPath returns the filesystem path for a single entry file.
+// EntryPath returns the filesystem path for a single entry file in the files backend.
 func EntryPath(homeDir, slug, id string) (string, error) {
 	if err := validateID(id); err != nil {
 		return "", err
@@ -28,10 +18,9 @@ func EntryPath(homeDir, slug, id string) (string, error) {
 	return filepath.Join(project.LogDir(homeDir, slug), id), nil
 }
 
-// writeTypedEntry marshals data with the given type and writes it to the project's log directory.
+// writeTypedEntry marshals data with the given type and writes it to the project's store.
 func writeTypedEntry(homeDir, slug, id string, data any) error {
-	dir := project.LogDir(homeDir, slug)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := validateID(id); err != nil {
 		return err
 	}
 
@@ -40,46 +29,104 @@ func writeTypedEntry(homeDir, slug, id string, data any) error {
 		return err
 	}
 
-	path, err := EntryPath(homeDir, slug, id)
+	store, err := OpenStore(homeDir)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, jsonData, 0644)
+
+	// Never let one entry silently replace another under the same ID
+	existing, err := store.Read(slug, id)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err == nil {
+		old, next := recordFromData(id, existing), recordFromData(id, jsonData)
+		if !old.Invalid && old.Type != next.Type {
+			return fmt.Errorf("entry ID collision: '%s' already holds a %s entry", id, old.Type)
+		}
+		if !old.Invalid && !sameEntry(old, next) {
+			return fmt.Errorf("entry ID collision: '%s' already holds another %s entry", id, old.Type)
+		}
+	}
+
+	if err := store.Write(slug, id, jsonData); err != nil {
+		return err
+	}
+	return journalEntry(homeDir, slug, id, existing, jsonData)
+}
+
+// sameEntry reports whether two records of the same type are versions of one
+// entry. Entries users edit keep their creation time; git and activity
+// events are never edited, so their time identifies them.
+func sameEntry(a, b IndexRecord) bool {
+	switch a.Type {
+	case TypeLog, TypeSubmit, TypeCorrection:
+		return a.Created.Equal(b.Created)
+	}
+	return a.Start.Equal(b.Start)
 }
 
-// fileData holds the raw bytes and filename from a directory scan.
-type fileData struct {
-	name string
-	data []byte
+// journalEntry records an entry change in the operation journal. A nil before
+// means the entry was created; a nil after means it was deleted. Rewrites
+// that leave the payload unchanged are not recorded.
+func journalEntry(homeDir, slug, id string, before, after []byte) error {
+	if before != nil && after != nil && journal.Equal(before, after) {
+		return nil
+	}
+	return journal.Append(homeDir, journal.Change{
+		Kind:   journal.KindEntry,
+		Slug:   slug,
+		ID:     id,
+		Before: before,
+		After:  after,
+	})
 }
 
-// readAllFiles reads all non-directory files from a project's log directory.
-func readAllFiles(homeDir, slug string) ([]fileData, error) {
-	dir := project.LogDir(homeDir, slug)
-	files, err := os.ReadDir(dir)
-	if os.IsNotExist(err) {
-		return nil, nil
+// RestoreEntry puts a raw entry payload back in place, or deletes the entry
+// when data is nil. It is used to revert journaled changes and is itself
+// journaled.
+func RestoreEntry(homeDir, slug, id string, data []byte) error {
+	if err := validateID(id); err != nil {
+		return err
 	}
+	store, err := OpenStore(homeDir)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var result []fileData
-	for _, f := range files {
-		if f.IsDir() {
-			continue
+	existing, err := store.Read(slug, id)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if data == nil {
+		if existing == nil {
+			return nil
 		}
-		data, err := os.ReadFile(filepath.Join(dir, f.Name()))
-		if err != nil {
-			return nil, err
+		if err := store.Delete(slug, id); err != nil {
+			return err
 		}
-		result = append(result, fileData{name: f.Name(), data: data})
+	} else if err := store.Write(slug, id, data); err != nil {
+		return err
 	}
-	return result, nil
+	return journalEntry(homeDir, slug, id, existing, data)
+}
+
+// readRaw reads the raw payload of a single entry from the project's store.
+func readRaw(homeDir, slug, id string) ([]byte, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+	store, err := OpenStore(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	return store.Read(slug, id)
 }
 
 // matchesType checks if JSON data has a "type" field matching expectedType.
-// For log entries, missing or empty type also matches (legacy compatibility).
+// For log entries, missing or empty type also matches (legacy compatibility;
+// the entry-types schema migration adds the type to such entries).
 func matchesType(data []byte, expectedType string) bool {
 	var raw map[string]json.RawMessage
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -99,21 +146,20 @@ func matchesType(data []byte, expectedType string) bool {
 	return typ == expectedType
 }
 
-// WriteEntry writes a single entry file to the project's log directory.
+// WriteEntry writes a single entry to the project's store.
 func WriteEntry(homeDir, slug string, e Entry) error {
 	e.Type = TypeLog
 	return writeTypedEntry(homeDir, slug, e.ID, e)
 }
 
-// ReadEntry reads a single entry by hash from a project's log directory.
+// ReadEntry reads a single entry by hash from a project's store.
 func ReadEntry(homeDir, slug, id string) (Entry, error) {
-	path, err := EntryPath(homeDir, slug, id)
-	if err != nil {
-		return Entry{}, err
+	data, err := readRaw(homeDir, slug, id)
+	if errors.Is(err, ErrNotFound) {
+		return Entry{}, fmt.Errorf("entry '%s' not found", id)
 	}
-	data, err := os.ReadFile(path)
 	if err != nil {
-		return Entry{}, fmt.Errorf("entry '%s' not found", id)
+		return Entry{}, err
 	}
 
 	var e Entry
@@ -126,72 +172,106 @@ func ReadEntry(homeDir, slug, id string) (Entry, error) {
 	return e, nil
 }
 
-// readAllOfType reads all entries of a given type from a project's log directory.
+// readAllOfType reads all entries of a given type from a project's store.
 func readAllOfType[T any](homeDir, slug, entryType string) ([]T, error) {
-	files, err := readAllFiles(homeDir, slug)
+	records, err := QueryRecords(homeDir, slug, Query{Type: entryType})
+	if err != nil {
+		return nil, err
+	}
+	return Decode[T](records, entryType), nil
+}
+
+// QueryRecords returns every stored record of a project matching q.
+func QueryRecords(homeDir, slug string, q Query) ([]Record, error) {
+	store, err := OpenStore(homeDir)
 	if err != nil {
 		return nil, err
 	}
+	return store.Query(slug, q)
+}
 
+// ListRecords returns metadata for every stored record of a project matching q,
+// without reading payloads where the backend allows it.
+func ListRecords(homeDir, slug string, q Query) ([]IndexRecord, error) {
+	store, err := OpenStore(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	return store.List(slug, q)
+}
+
+// ReadRecord reads a single stored record by ID, whatever its type.
+func ReadRecord(homeDir, slug, id string) (Record, error) {
+	data, err := readRaw(homeDir, slug, id)
+	if err != nil {
+		return Record{}, err
+	}
+	return Record{IndexRecord: recordFromData(id, data), Data: data}, nil
+}
+
+// Decode unmarshals the records of entryType into values of type T.
+// Records of other types or that fail to parse are skipped.
+func Decode[T any](records []Record, entryType string) []T {
 	var entries []T
-	for _, f := range files {
-		if !matchesType(f.data, entryType) {
+	for _, r := range records {
+		if r.Type != entryType {
 			continue
 		}
 		var e T
-		if err := json.Unmarshal(f.data, &e); err != nil {
+		if err := json.Unmarshal(r.Data, &e); err != nil {
 			continue
 		}
 		entries = append(entries, e)
 	}
-	return entries, nil
+	return entries
 }
 
-// ReadAllEntries reads all log entry files from a project's log directory.
+// ReadAllEntries reads all log entries from a project's store.
 func ReadAllEntries(homeDir, slug string) ([]Entry, error) {
 	return readAllOfType[Entry](homeDir, slug, TypeLog)
 }
 
-// IsCheckoutEntry checks if the file at the given path exists and is a checkout entry.
+// IsCheckoutEntry checks if an entry with the given ID exists and is a checkout entry.
 func IsCheckoutEntry(homeDir, slug, id string) bool {
-	path, err := EntryPath(homeDir, slug, id)
-	if err != nil {
-		return false
-	}
-	data, err := os.ReadFile(path)
+	data, err := readRaw(homeDir, slug, id)
 	if err != nil {
 		return false
 	}
 	return matchesType(data, TypeCheckout)
 }
 
-// IsCommitEntry checks if the file at the given path exists and is a commit entry.
+// IsCommitEntry checks if an entry with the given ID exists and is a commit entry.
 func IsCommitEntry(homeDir, slug, id string) bool {
-	path, err := EntryPath(homeDir, slug, id)
-	if err != nil {
-		return false
-	}
-	data, err := os.ReadFile(path)
+	data, err := readRaw(homeDir, slug, id)
 	if err != nil {
 		return false
 	}
 	return matchesType(data, TypeCommit)
 }
 
-// DeleteEntry removes an entry file by hash.
+// DeleteEntry removes an entry by hash.
 func DeleteEntry(homeDir, slug, id string) error {
-	path, err := EntryPath(homeDir, slug, id)
+	if err := validateID(id); err != nil {
+		return err
+	}
+	store, err := OpenStore(homeDir)
 	if err != nil {
 		return err
 	}
-	err = os.Remove(path)
-	if os.IsNotExist(err) {
+	existing, err := store.Read(slug, id)
+	if errors.Is(err, ErrNotFound) {
 		return fmt.Errorf("entry '%s' not found", id)
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(slug, id); err != nil {
+		return err
+	}
+	return journalEntry(homeDir, slug, id, existing, nil)
 }
 
-// WriteCheckoutEntry writes a single checkout entry file to the project's log directory.
+// WriteCheckoutEntry writes a single checkout entry to the project's store.
 func WriteCheckoutEntry(homeDir, slug string, e CheckoutEntry) error {
 	e.Type = TypeCheckout
 	return writeTypedEntry(homeDir, slug, e.ID, e)
@@ -199,13 +279,12 @@ func WriteCheckoutEntry(homeDir, slug string, e CheckoutEntry) error {
 
 // ReadCheckoutEntry reads a single checkout entry by ID.
 func ReadCheckoutEntry(homeDir, slug, id string) (CheckoutEntry, error) {
-	path, err := EntryPath(homeDir, slug, id)
-	if err != nil {
-		return CheckoutEntry{}, err
+	data, err := readRaw(homeDir, slug, id)
+	if errors.Is(err, ErrNotFound) {
+		return CheckoutEntry{}, fmt.Errorf("checkout entry '%s' not found", id)
 	}
-	data, err := os.ReadFile(path)
 	if err != nil {
-		return CheckoutEntry{}, fmt.Errorf("checkout entry '%s' not found", id)
+		return CheckoutEntry{}, err
 	}
 
 	var e CheckoutEntry
@@ -218,51 +297,73 @@ func ReadCheckoutEntry(homeDir, slug, id string) (CheckoutEntry, error) {
 	return e, nil
 }
 
-// WriteSubmitEntry writes a submit marker entry to the project's log directory.
+// WriteSubmitEntry writes a submit marker entry to the project's store.
 func WriteSubmitEntry(homeDir, slug string, e SubmitEntry) error {
 	e.Type = TypeSubmit
 	return writeTypedEntry(homeDir, slug, e.ID, e)
 }
 
-// ReadAllSubmitEntries reads all submit marker entries from a project's log directory.
+// ReadAllSubmitEntries reads all submit marker entries from a project's store.
 func ReadAllSubmitEntries(homeDir, slug string) ([]SubmitEntry, error) {
 	return readAllOfType[SubmitEntry](homeDir, slug, TypeSubmit)
 }
 
-// ReadAllCheckoutEntries reads all checkout entries from a project's log directory.
+// ReadAllCheckoutEntries reads all checkout entries from a project's store.
 func ReadAllCheckoutEntries(homeDir, slug string) ([]CheckoutEntry, error) {
 	return readAllOfType[CheckoutEntry](homeDir, slug, TypeCheckout)
 }
 
-// WriteCommitEntry writes a single commit entry file to the project's log directory.
+// WriteCommitEntry writes a single commit entry to the project's store.
 func WriteCommitEntry(homeDir, slug string, e CommitEntry) error {
 	e.Type = TypeCommit
 	return writeTypedEntry(homeDir, slug, e.ID, e)
 }
 
-// ReadAllCommitEntries reads all commit entries from a project's log directory.
+// ReadAllCommitEntries reads all commit entries from a project's store.
 func ReadAllCommitEntries(homeDir, slug string) ([]CommitEntry, error) {
 	return readAllOfType[CommitEntry](homeDir, slug, TypeCommit)
 }
 
-// WriteActivityStopEntry writes an activity stop entry to the project's log directory.
+// WriteGitEventEntry writes a single git event entry to the project's store.
+func WriteGitEventEntry(homeDir, slug string, e GitEventEntry) error {
+	e.Type = TypeGitEvent
+	return writeTypedEntry(homeDir, slug, e.ID, e)
+}
+
+// ReadAllGitEventEntries reads all git event entries from a project's store.
+func ReadAllGitEventEntries(homeDir, slug string) ([]GitEventEntry, error) {
+	return readAllOfType[GitEventEntry](homeDir, slug, TypeGitEvent)
+}
+
+// WriteActivityStopEntry writes an activity stop entry to the project's store.
 func WriteActivityStopEntry(homeDir, slug string, e ActivityStopEntry) error {
 	e.Type = TypeActivityStop
 	return writeTypedEntry(homeDir, slug, e.ID, e)
 }
 
-// WriteActivityStartEntry writes an activity start entry to the project's log directory.
+// WriteActivityStartEntry writes an activity start entry to the project's store.
 func WriteActivityStartEntry(homeDir, slug string, e ActivityStartEntry) error {
 	e.Type = TypeActivityStart
 	return writeTypedEntry(homeDir, slug, e.ID, e)
 }
 
-// ReadAllActivityStopEntries reads all activity stop entries from a project's log directory.
+// ReadAllActivityStopEntries reads all activity stop entries from a project's store.
 func ReadAllActivityStopEntries(homeDir, slug string) ([]ActivityStopEntry, error) {
 	return readAllOfType[ActivityStopEntry](homeDir, slug, TypeActivityStop)
 }
 
-// ReadAllActivityStartEntries reads all activity start entries from a project's log directory.
+// ReadAllActivityStartEntries reads all activity start entries from a project's store.
 func ReadAllActivityStartEntries(homeDir, slug string) ([]ActivityStartEntry, error) {
 	return readAllOfType[ActivityStartEntry](homeDir, slug, TypeActivityStart)
 }
+
+// WriteCorrectionEntry writes a balance correction entry to the project's store.
+func WriteCorrectionEntry(homeDir, slug string, e CorrectionEntry) error {
+	e.Type = TypeCorrection
+	return writeTypedEntry(homeDir, slug, e.ID, e)
+}
+
+// ReadAllCorrectionEntries reads all balance correction entries from a project's store.
+func ReadAllCorrectionEntries(homeDir, slug string) ([]CorrectionEntry, error) {
+	return readAllOfType[CorrectionEntry](homeDir, slug, TypeCorrection)
+}
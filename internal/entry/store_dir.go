@@ -0,0 +1,168 @@
+package entry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
+)
+
+// DirStore keeps one JSON file per entry in the project's log directory,
+// named by entry ID. Queries go through the per-project index.
+type DirStore struct {
+	homeDir string
+}
+
+// NewDirStore returns a DirStore rooted at homeDir.
+func NewDirStore(homeDir string) *DirStore {
+	return &DirStore{homeDir: homeDir}
+}
+
+// entryPath returns the path of the entry file id in dir. IDs that would
+// reach outside dir, e.g. from a crafted backup or remote, are refused.
+func entryPath(dir, id string) (string, error) {
+	if id == "" || id == "." || strings.Contains(id, "..") || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("invalid entry ID %q", id)
+	}
+	return filepath.Join(dir, id), nil
+}
+
+// SealContext returns what a sealed entry payload is bound to: its ID, so a
+// sealed file copied over another entry's fails to open.
+func SealContext(id string) string {
+	return "entry:" + id
+}
+
+// Write creates or replaces the entry file.
+func (s *DirStore) Write(slug, id string, data []byte) error {
+	dir := project.LogDir(s.homeDir, slug)
+	path, err := entryPath(dir, id)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err = seal.Seal(s.homeDir, data, SealContext(id))
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, data, 0600)
+}
+
+// Read returns the contents of the entry file, decrypted if it is sealed.
+func (s *DirStore) Read(slug, id string) ([]byte, error) {
+	path, err := entryPath(project.LogDir(s.homeDir, slug), id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	sealer, err := seal.Load(s.homeDir)
+	if err != nil {
+		return nil, err
+	}
+	return openEntry(sealer, id, data)
+}
+
+// openEntry decrypts a sealed entry payload. A payload that fails
+// authentication is returned as is, so it surfaces as an unreadable entry
+// (see 'hourgit fsck') rather than failing every read of the project.
+func openEntry(sealer *seal.Sealer, id string, data []byte) ([]byte, error) {
+	plain, err := sealer.Open(data, SealContext(id))
+	if errors.Is(err, seal.ErrCorrupt) {
+		return data, nil
+	}
+	return plain, err
+}
+
+// Reseal rewrites every entry file sealed or plain, whichever homeDir
+// currently uses. The index is rewritten as well.
+func (s *DirStore) Reseal(slug string) (int, error) {
+	dir := project.LogDir(s.homeDir, slug)
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	sealer, err := seal.Load(s.homeDir)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, f := range files {
+		if f.IsDir() || (strings.HasPrefix(f.Name(), ".") && f.Name() != indexFileName) {
+			continue
+		}
+		context := SealContext(f.Name())
+		if f.Name() == indexFileName {
+			context = indexSealContext
+		}
+		changed, err := sealer.RewriteFile(filepath.Join(dir, f.Name()), context)
+		if errors.Is(err, seal.ErrCorrupt) {
+			continue
+		}
+		if err != nil {
+			return count, err
+		}
+		if changed && f.Name() != indexFileName {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Delete removes the entry file.
+func (s *DirStore) Delete(slug, id string) error {
+	path, err := entryPath(project.LogDir(s.homeDir, slug), id)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// List returns index metadata for matching entries without reading them.
+func (s *DirStore) List(slug string, q Query) ([]IndexRecord, error) {
+	idx, err := LoadIndex(s.homeDir, slug)
+	if err != nil {
+		return nil, err
+	}
+	return idx.Find(q), nil
+}
+
+// Query reads every matching entry file. Files removed since the index was
+// refreshed are skipped.
+func (s *DirStore) Query(slug string, q Query) ([]Record, error) {
+	metas, err := s.List(slug, q)
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	for _, m := range metas {
+		data, err := s.Read(slug, m.File)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, Record{IndexRecord: m, Data: data})
+	}
+	return records, nil
+}
@@ -0,0 +1,87 @@
+package entry
+
+import (
+	"sort"
+	"sync"
+)
+
+// MemoryStore keeps entries in memory. It is safe for concurrent use and is
+// intended for tests and dry runs.
+type MemoryStore struct {
+	mu       sync.Mutex
+	projects map[string]map[string][]byte // slug -> id -> payload
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{projects: make(map[string]map[string][]byte)}
+}
+
+// Write stores a copy of the payload.
+func (s *MemoryStore) Write(slug, id string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.projects[slug] == nil {
+		s.projects[slug] = make(map[string][]byte)
+	}
+	s.projects[slug][id] = append([]byte(nil), data...)
+	return nil
+}
+
+// Read returns a copy of the payload for an ID.
+func (s *MemoryStore) Read(slug, id string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.projects[slug][id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Delete removes an entry.
+func (s *MemoryStore) Delete(slug, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.projects[slug][id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.projects[slug], id)
+	return nil
+}
+
+// List returns metadata for matching entries.
+func (s *MemoryStore) List(slug string, q Query) ([]IndexRecord, error) {
+	records, err := s.Query(slug, q)
+	if err != nil {
+		return nil, err
+	}
+	metas := make([]IndexRecord, len(records))
+	for i, r := range records {
+		metas[i] = r.IndexRecord
+	}
+	return metas, nil
+}
+
+// Query returns metadata and payload copies for matching entries.
+func (s *MemoryStore) Query(slug string, q Query) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.projects[slug]))
+	for id := range s.projects[slug] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var records []Record
+	for _, id := range ids {
+		data := s.projects[slug][id]
+		meta := recordFromData(id, data)
+		if !q.matches(meta) {
+			continue
+		}
+		records = append(records, Record{IndexRecord: meta, Data: append([]byte(nil), data...)})
+	}
+	return records, nil
+}
@@ -0,0 +1,66 @@
+package entry
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStoreRoundTrip(t *testing.T) {
+	s := NewMemoryStore()
+
+	require.NoError(t, s.Write("proj", "aaa1111", []byte(`{"id":"aaa1111","type":"log"}`)))
+	data, err := s.Read("proj", "aaa1111")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"aaa1111","type":"log"}`, string(data))
+
+	// Returned payloads are copies
+	data[0] = 'x'
+	again, err := s.Read("proj", "aaa1111")
+	require.NoError(t, err)
+	assert.Equal(t, byte('{'), again[0])
+
+	require.NoError(t, s.Delete("proj", "aaa1111"))
+	_, err = s.Read("proj", "aaa1111")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, s.Delete("proj", "aaa1111"), ErrNotFound)
+}
+
+func TestUseStoreRoutesTypedOperations(t *testing.T) {
+	home := t.TempDir()
+	s := NewMemoryStore()
+	restore := UseStore(home, s)
+
+	require.NoError(t, WriteEntry(home, "proj", testEntry("aaa1111", "work")))
+	require.NoError(t, WriteCheckoutEntry(home, "proj", testCheckoutEntry("bbb2222", "main", "feature")))
+
+	logs, err := ReadAllEntries(home, "proj")
+	require.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.True(t, IsCheckoutEntry(home, "proj", "bbb2222"))
+
+	// Nothing touches the filesystem
+	_, err = os.Stat(project.LogDir(home, "proj"))
+	assert.True(t, os.IsNotExist(err))
+
+	restore()
+	logs, err = ReadAllEntries(home, "proj")
+	require.NoError(t, err)
+	assert.Empty(t, logs)
+}
+
+func TestOpenStoreFollowsConfig(t *testing.T) {
+	home := t.TempDir()
+
+	s, err := OpenStore(home)
+	require.NoError(t, err)
+	assert.IsType(t, &DirStore{}, s)
+
+	require.NoError(t, project.SetStorage(home, project.StorageSegments))
+	s, err = OpenStore(home)
+	require.NoError(t, err)
+	assert.IsType(t, &SegmentStore{}, s)
+}
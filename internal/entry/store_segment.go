@@ -0,0 +1,496 @@
+package entry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
+)
+
+// segmentDirName is the subdirectory of a project's log directory holding segments.
+const segmentDirName = "segments"
+
+// undatedSegment holds entries without a start time.
+const undatedSegment = "undated"
+
+// Segment operations.
+const (
+	segmentOpPut    = "put"
+	segmentOpDelete = "del"
+)
+
+// segmentLine is a single line in a segment file.
+type segmentLine struct {
+	Seq  int64           `json:"seq"`
+	Op   string          `json:"op"`
+	ID   string          `json:"id"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// SegmentStore keeps entries in append-only JSONL files, one per month of the
+// entry's start time (<logdir>/segments/2006-01.jsonl). Updates and deletes are
+// appended as new lines; the latest line per ID wins on replay.
+//
+// The replayed state of each project is kept in memory and caught up with
+// the lines appended since, so a write does not replay the whole history.
+// Superseded lines are dropped by Compact, which Write runs when it starts a
+// new month.
+type SegmentStore struct {
+	homeDir string
+
+	mu     sync.Mutex
+	states map[string]*segmentState // slug -> replayed segments
+}
+
+// NewSegmentStore returns a SegmentStore rooted at homeDir.
+func NewSegmentStore(homeDir string) *SegmentStore {
+	return &SegmentStore{homeDir: homeDir, states: make(map[string]*segmentState)}
+}
+
+var (
+	segmentStoresMu sync.Mutex
+	segmentStores   = make(map[string]*SegmentStore)
+)
+
+// sharedSegmentStore returns the SegmentStore of homeDir that all entry
+// operations of this process go through, so its replayed state is reused.
+func sharedSegmentStore(homeDir string) *SegmentStore {
+	segmentStoresMu.Lock()
+	defer segmentStoresMu.Unlock()
+	s, ok := segmentStores[homeDir]
+	if !ok {
+		s = NewSegmentStore(homeDir)
+		segmentStores[homeDir] = s
+	}
+	return s
+}
+
+// segmentState is the replayed content of all segments for a project.
+type segmentState struct {
+	maxSeq  int64
+	lines   int // lines replayed, live or superseded
+	records map[string]segmentEntry
+	files   map[string]segmentCursor // segment name -> how far it was read
+}
+
+// segmentEntry is a live entry, the segment it lives in and the sequence
+// number of the line that put it there.
+type segmentEntry struct {
+	segment string
+	seq     int64
+	data    []byte
+}
+
+// segmentCursor is a segment file replayed up to offset.
+type segmentCursor struct {
+	info   os.FileInfo
+	offset int64
+}
+
+// segmentDir returns the segment directory for a project.
+func (s *SegmentStore) segmentDir(slug string) string {
+	return filepath.Join(project.LogDir(s.homeDir, slug), segmentDirName)
+}
+
+// segmentSealContext binds a sealed segment line to its segment. The entry
+// ID is inside the sealed line, so it is bound as well.
+func segmentSealContext(segment string) string {
+	return "segment:" + segment
+}
+
+// lockPath returns the lock file serialising appends for a project. Sequence
+// numbers are derived from a replay, so writers must not interleave.
+func (s *SegmentStore) lockPath(slug string) string {
+	return filepath.Join(s.segmentDir(slug), ".lock")
+}
+
+// segmentFor returns the segment name for an entry payload.
+func segmentFor(data []byte) string {
+	r := recordFromData("", data)
+	if r.Start.IsZero() {
+		return undatedSegment
+	}
+	return r.Start.UTC().Format("2006-01")
+}
+
+// segmentNames returns the names (without extension) of all segment files.
+func (s *SegmentStore) segmentNames(slug string) ([]string, error) {
+	files, err := os.ReadDir(s.segmentDir(slug))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".jsonl") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(f.Name(), ".jsonl"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// withState calls fn with the replayed state of a project, caught up with the
+// segment files. The state must not be used once fn returns.
+func (s *SegmentStore) withState(slug string, fn func(st *segmentState) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.states[slug]
+	if st != nil {
+		ok, err := s.catchUp(slug, st)
+		if err != nil || !ok {
+			st = nil
+		}
+	}
+	if st == nil {
+		st = &segmentState{records: make(map[string]segmentEntry), files: make(map[string]segmentCursor)}
+		if _, err := s.catchUp(slug, st); err != nil {
+			delete(s.states, slug)
+			return err
+		}
+	}
+	s.states[slug] = st
+	return fn(st)
+}
+
+// catchUp replays the lines appended to the segment files since st was last
+// caught up, in sequence order. It returns false, leaving st unusable, when a
+// file was removed or rewritten since, e.g. by Compact or Reseal in another
+// process; the state must then be replayed from scratch.
+func (s *SegmentStore) catchUp(slug string, st *segmentState) (bool, error) {
+	names, err := s.segmentNames(slug)
+	if err != nil {
+		return false, err
+	}
+	present := make(map[string]bool, len(names))
+	for _, name := range names {
+		present[name] = true
+	}
+	for name := range st.files {
+		if !present[name] {
+			return false, nil
+		}
+	}
+
+	type located struct {
+		segment string
+		line    segmentLine
+	}
+	sealer, err := seal.Load(s.homeDir)
+	if err != nil {
+		return false, err
+	}
+	var lines []located
+	for _, name := range names {
+		f, err := os.Open(filepath.Join(s.segmentDir(slug), name+".jsonl"))
+		if err != nil {
+			return false, err
+		}
+		data, info, err := readFrom(f, st.files[name])
+		_ = f.Close()
+		if err != nil {
+			return false, err
+		}
+		if info == nil {
+			return false, nil
+		}
+
+		// A torn final line is left for the next catch-up
+		complete := bytes.LastIndexByte(data, '\n') + 1
+		st.files[name] = segmentCursor{info: info, offset: st.files[name].offset + int64(complete)}
+		for raw := range bytes.SplitSeq(data[:complete], []byte("\n")) {
+			if len(raw) == 0 {
+				continue
+			}
+			plain, err := sealer.Open(raw, segmentSealContext(name))
+			if errors.Is(err, seal.ErrCorrupt) {
+				continue
+			}
+			if err != nil {
+				return false, err
+			}
+			var l segmentLine
+			if err := json.Unmarshal(plain, &l); err != nil || l.ID == "" {
+				continue
+			}
+			lines = append(lines, located{segment: name, line: l})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].line.Seq < lines[j].line.Seq
+	})
+	for _, l := range lines {
+		st.lines++
+		if l.line.Seq > st.maxSeq {
+			st.maxSeq = l.line.Seq
+		}
+		switch l.line.Op {
+		case segmentOpPut:
+			st.records[l.line.ID] = segmentEntry{segment: l.segment, seq: l.line.Seq, data: l.line.Data}
+		case segmentOpDelete:
+			delete(st.records, l.line.ID)
+		}
+	}
+	return true, nil
+}
+
+// readFrom returns what f holds past the point prev was read to, and f's
+// info. The info is nil when f is not the file prev was read from, or is
+// shorter than that point.
+func readFrom(f *os.File, prev segmentCursor) ([]byte, os.FileInfo, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if prev.info != nil && (!os.SameFile(prev.info, info) || info.Size() < prev.offset) {
+		return nil, nil, nil
+	}
+	if info.Size() == prev.offset {
+		return nil, info, nil
+	}
+	data, err := io.ReadAll(io.NewSectionReader(f, prev.offset, info.Size()-prev.offset))
+	return data, info, err
+}
+
+// appendLine appends a single line to a segment file.
+func (s *SegmentStore) appendLine(slug, segment string, l segmentLine) error {
+	dir := s.segmentDir(slug)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	if data, err = seal.Seal(s.homeDir, data, segmentSealContext(segment)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, segment+".jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Write appends the entry to the segment of its start month. If the entry
+// previously lived in another segment, a delete is appended there first.
+// Starting a new month's segment compacts the others.
+func (s *SegmentStore) Write(slug, id string, data []byte) error {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return err
+	}
+
+	return fsutil.WithLock(s.lockPath(slug), func() error {
+		segment := segmentFor(data)
+		rollover := false
+		err := s.withState(slug, func(st *segmentState) error {
+			_, exists := st.files[segment]
+			rollover = !exists && segment != undatedSegment && len(st.files) > 0
+
+			seq := st.maxSeq + 1
+			if existing, ok := st.records[id]; ok && existing.segment != segment {
+				if err := s.appendLine(slug, existing.segment, segmentLine{Seq: seq, Op: segmentOpDelete, ID: id}); err != nil {
+					return err
+				}
+				seq++
+			}
+			return s.appendLine(slug, segment, segmentLine{Seq: seq, Op: segmentOpPut, ID: id, Data: compact.Bytes()})
+		})
+		if err != nil || !rollover {
+			return err
+		}
+		_, err = s.compact(slug)
+		return err
+	})
+}
+
+// Read returns the latest payload for an ID.
+func (s *SegmentStore) Read(slug, id string) ([]byte, error) {
+	var data []byte
+	err := s.withState(slug, func(st *segmentState) error {
+		e, ok := st.records[id]
+		if !ok {
+			return ErrNotFound
+		}
+		data = e.data
+		return nil
+	})
+	return data, err
+}
+
+// Delete appends a delete marker to the segment holding the entry.
+func (s *SegmentStore) Delete(slug, id string) error {
+	if _, err := os.Stat(s.segmentDir(slug)); os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	return fsutil.WithLock(s.lockPath(slug), func() error {
+		return s.withState(slug, func(st *segmentState) error {
+			e, ok := st.records[id]
+			if !ok {
+				return ErrNotFound
+			}
+			return s.appendLine(slug, e.segment, segmentLine{Seq: st.maxSeq + 1, Op: segmentOpDelete, ID: id})
+		})
+	})
+}
+
+// Superseded returns the number of segment lines of a project that no longer
+// hold a live entry: replaced or deleted entries and delete markers.
+func (s *SegmentStore) Superseded(slug string) (int, error) {
+	n := 0
+	err := s.withState(slug, func(st *segmentState) error {
+		n = st.lines - len(st.records)
+		return nil
+	})
+	return n, err
+}
+
+// Compact rewrites the segment files of a project with only the lines of
+// live entries, removing segments left empty. Returns the number of lines
+// dropped.
+func (s *SegmentStore) Compact(slug string) (int, error) {
+	if _, err := os.Stat(s.segmentDir(slug)); os.IsNotExist(err) {
+		return 0, nil
+	}
+	n := 0
+	err := fsutil.WithLock(s.lockPath(slug), func() error {
+		var err error
+		n, err = s.compact(slug)
+		return err
+	})
+	return n, err
+}
+
+// compact is Compact with the project's lock held.
+func (s *SegmentStore) compact(slug string) (int, error) {
+	sealer, err := seal.Load(s.homeDir)
+	if err != nil {
+		return 0, err
+	}
+	dropped := 0
+	err = s.withState(slug, func(st *segmentState) error {
+		dropped = st.lines - len(st.records)
+		if dropped == 0 {
+			return nil
+		}
+
+		live := make(map[string][]segmentLine, len(st.files))
+		for id, e := range st.records {
+			live[e.segment] = append(live[e.segment], segmentLine{Seq: e.seq, Op: segmentOpPut, ID: id, Data: e.data})
+		}
+		for name := range st.files {
+			path := filepath.Join(s.segmentDir(slug), name+".jsonl")
+			lines := live[name]
+			if len(lines) == 0 {
+				if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+				continue
+			}
+			sort.Slice(lines, func(i, j int) bool { return lines[i].Seq < lines[j].Seq })
+			var buf bytes.Buffer
+			for _, l := range lines {
+				data, err := json.Marshal(l)
+				if err != nil {
+					return err
+				}
+				if data, err = sealer.Seal(data, segmentSealContext(name)); err != nil {
+					return err
+				}
+				buf.Write(data)
+				buf.WriteByte('\n')
+			}
+			if err := fsutil.WriteFileAtomic(path, buf.Bytes(), 0600); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	// The rewritten files are replayed afresh
+	s.mu.Lock()
+	delete(s.states, slug)
+	s.mu.Unlock()
+	return dropped, err
+}
+
+// List returns metadata for matching entries.
+func (s *SegmentStore) List(slug string, q Query) ([]IndexRecord, error) {
+	records, err := s.Query(slug, q)
+	if err != nil {
+		return nil, err
+	}
+	metas := make([]IndexRecord, len(records))
+	for i, r := range records {
+		metas[i] = r.IndexRecord
+	}
+	return metas, nil
+}
+
+// Query returns metadata and payloads for matching entries.
+func (s *SegmentStore) Query(slug string, q Query) ([]Record, error) {
+	var records []Record
+	err := s.withState(slug, func(st *segmentState) error {
+		ids := make([]string, 0, len(st.records))
+		for id := range st.records {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			e := st.records[id]
+			meta := recordFromData(id, e.data)
+			if !q.matches(meta) {
+				continue
+			}
+			records = append(records, Record{IndexRecord: meta, Data: e.data})
+		}
+		return nil
+	})
+	return records, err
+}
+
+// Reseal rewrites every segment file sealed or plain, whichever homeDir
+// currently uses. Each line is sealed on its own, so appends stay cheap.
+func (s *SegmentStore) Reseal(slug string) (int, error) {
+	names, err := s.segmentNames(slug)
+	if err != nil || len(names) == 0 {
+		return 0, err
+	}
+	sealer, err := seal.Load(s.homeDir)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	err = fsutil.WithLock(s.lockPath(slug), func() error {
+		for _, name := range names {
+			changed, err := sealer.RewriteLines(filepath.Join(s.segmentDir(slug), name+".jsonl"), segmentSealContext(name))
+			if err != nil {
+				return err
+			}
+			if changed {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
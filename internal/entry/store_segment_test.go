@@ -0,0 +1,236 @@
+package entry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useSegmentStore(t *testing.T, home string) *SegmentStore {
+	t.Helper()
+	s := NewSegmentStore(home)
+	t.Cleanup(UseStore(home, s))
+	return s
+}
+
+func segmentFile(home, slug, name string) string {
+	return filepath.Join(project.LogDir(home, slug), segmentDirName, name+".jsonl")
+}
+
+func TestSegmentStoreWriteReadDelete(t *testing.T) {
+	home := t.TempDir()
+	useSegmentStore(t, home)
+
+	require.NoError(t, WriteEntry(home, "proj", testEntry("aaa1111", "work")))
+	e, err := ReadEntry(home, "proj", "aaa1111")
+	require.NoError(t, err)
+	assert.Equal(t, "work", e.Message)
+
+	_, err = os.Stat(segmentFile(home, "proj", "2025-06"))
+	require.NoError(t, err)
+
+	require.NoError(t, DeleteEntry(home, "proj", "aaa1111"))
+	_, err = ReadEntry(home, "proj", "aaa1111")
+	assert.EqualError(t, err, "entry 'aaa1111' not found")
+	assert.EqualError(t, DeleteEntry(home, "proj", "aaa1111"), "entry 'aaa1111' not found")
+}
+
+func TestSegmentStoreLatestWriteWins(t *testing.T) {
+	home := t.TempDir()
+	useSegmentStore(t, home)
+
+	require.NoError(t, WriteEntry(home, "proj", testEntry("aaa1111", "first")))
+	require.NoError(t, WriteEntry(home, "proj", testEntry("aaa1111", "second")))
+
+	entries, err := ReadAllEntries(home, "proj")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "second", entries[0].Message)
+}
+
+func TestSegmentStoreMovesEntryBetweenMonths(t *testing.T) {
+	home := t.TempDir()
+	s := useSegmentStore(t, home)
+
+	e := testEntry("aaa1111", "work")
+	require.NoError(t, WriteEntry(home, "proj", e))
+	e.Start = time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, WriteEntry(home, "proj", e))
+
+	records, err := s.Query("proj", Query{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, e.Start, records[0].Start)
+
+	june, err := s.Query("proj", Query{To: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	assert.Empty(t, june)
+}
+
+func TestSegmentStoreQueryByTypeAndRange(t *testing.T) {
+	home := t.TempDir()
+	s := useSegmentStore(t, home)
+
+	for i, day := range []int{1, 10, 20} {
+		e := testEntry([]string{"aaa0001", "aaa0002", "aaa0003"}[i], "work")
+		e.Start = time.Date(2025, 6, day, 9, 0, 0, 0, time.UTC)
+		require.NoError(t, WriteEntry(home, "proj", e))
+	}
+	require.NoError(t, WriteCheckoutEntry(home, "proj", testCheckoutEntry("bbb2222", "main", "feature")))
+
+	got, err := s.List("proj", Query{
+		Type: TypeLog,
+		From: time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "aaa0002", got[0].ID)
+
+	checkouts, err := ReadAllCheckoutEntries(home, "proj")
+	require.NoError(t, err)
+	assert.Len(t, checkouts, 1)
+}
+
+func TestSegmentStoreIgnoresTornLines(t *testing.T) {
+	home := t.TempDir()
+	useSegmentStore(t, home)
+
+	require.NoError(t, WriteEntry(home, "proj", testEntry("aaa1111", "work")))
+
+	f, err := os.OpenFile(segmentFile(home, "proj", "2025-06"), os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":2,"op":"put","id":"bbb`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	entries, err := ReadAllEntries(home, "proj")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "aaa1111", entries[0].ID)
+}
+
+func TestSegmentStoreCompactsPayload(t *testing.T) {
+	home := t.TempDir()
+	useSegmentStore(t, home)
+
+	require.NoError(t, WriteEntry(home, "proj", testEntry("aaa1111", "work")))
+
+	data, err := os.ReadFile(segmentFile(home, "proj", "2025-06"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 1)
+}
+
+func TestSegmentStoreSealsLines(t *testing.T) {
+	home := t.TempDir()
+	s := useSegmentStore(t, home)
+	require.NoError(t, WriteEntry(home, "proj", testEntry("aaa1111", "plain")))
+
+	t.Setenv(seal.EnvPassphrase, "secret")
+	_, err := seal.Setup(home, seal.KeyPassphrase, "secret")
+	require.NoError(t, err)
+	count, err := s.Reseal("proj")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.NoError(t, WriteEntry(home, "proj", testEntry("bbb2222", "sealed")))
+
+	data, err := os.ReadFile(segmentFile(home, "proj", "2025-06"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.True(t, seal.IsSealed([]byte(line)))
+	}
+
+	entries, err := ReadAllEntries(home, "proj")
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func segmentLines(t *testing.T, home, slug, name string) []string {
+	t.Helper()
+	data, err := os.ReadFile(segmentFile(home, slug, name))
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestSegmentStoreCatchesUpWithOtherWriters(t *testing.T) {
+	home := t.TempDir()
+	writer, reader := NewSegmentStore(home), NewSegmentStore(home)
+
+	require.NoError(t, writer.Write("proj", "aaa1111", []byte(`{"id":"aaa1111","type":"log","start":"2025-06-15T09:00:00Z","message":"first"}`)))
+	data, err := reader.Read("proj", "aaa1111")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "first")
+
+	require.NoError(t, writer.Write("proj", "aaa1111", []byte(`{"id":"aaa1111","type":"log","start":"2025-06-15T09:00:00Z","message":"second"}`)))
+	data, err = reader.Read("proj", "aaa1111")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "second")
+	assert.Equal(t, int64(len(strings.Join(segmentLines(t, home, "proj", "2025-06"), "\n"))+1),
+		reader.states["proj"].files["2025-06"].offset, "only the appended line is replayed")
+
+	// A rewrite by another store is replayed from scratch
+	_, err = writer.Compact("proj")
+	require.NoError(t, err)
+	require.NoError(t, writer.Delete("proj", "aaa1111"))
+	_, err = reader.Read("proj", "aaa1111")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSegmentStoreCompact(t *testing.T) {
+	home := t.TempDir()
+	s := useSegmentStore(t, home)
+
+	for _, msg := range []string{"first", "second", "third"} {
+		require.NoError(t, WriteEntry(home, "proj", testEntry("aaa1111", msg)))
+	}
+	require.NoError(t, WriteEntry(home, "proj", testEntry("bbb2222", "gone")))
+	require.NoError(t, DeleteEntry(home, "proj", "bbb2222"))
+
+	superseded, err := s.Superseded("proj")
+	require.NoError(t, err)
+	assert.Equal(t, 4, superseded)
+
+	dropped, err := s.Compact("proj")
+	require.NoError(t, err)
+	assert.Equal(t, 4, dropped)
+	assert.Len(t, segmentLines(t, home, "proj", "2025-06"), 1)
+
+	e, err := ReadEntry(home, "proj", "aaa1111")
+	require.NoError(t, err)
+	assert.Equal(t, "third", e.Message)
+
+	// New writes sort after the compacted lines
+	require.NoError(t, WriteEntry(home, "proj", testEntry("aaa1111", "fourth")))
+	e, err = ReadEntry(home, "proj", "aaa1111")
+	require.NoError(t, err)
+	assert.Equal(t, "fourth", e.Message)
+}
+
+func TestSegmentStoreCompactsOnNewMonth(t *testing.T) {
+	home := t.TempDir()
+	useSegmentStore(t, home)
+
+	require.NoError(t, WriteEntry(home, "proj", testEntry("aaa1111", "first")))
+	require.NoError(t, WriteEntry(home, "proj", testEntry("aaa1111", "second")))
+	assert.Len(t, segmentLines(t, home, "proj", "2025-06"), 2)
+
+	e := testEntry("bbb2222", "july")
+	e.Start = time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, WriteEntry(home, "proj", e))
+
+	assert.Len(t, segmentLines(t, home, "proj", "2025-06"), 1)
+	assert.Len(t, segmentLines(t, home, "proj", "2025-07"), 1)
+	entries, err := ReadAllEntries(home, "proj")
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
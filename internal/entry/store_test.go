@@ -1,9 +1,14 @@
 package entry
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -404,11 +409,140 @@ func TestValidateID(t *testing.T) {
 	assert.NoError(t, validateID("abc1234"))
 	assert.NoError(t, validateID("0000000"))
 	assert.NoError(t, validateID("abcdef0"))
+	assert.NoError(t, validateID("abc12345"))     // longer IDs
+	assert.NoError(t, validateID("abcdef012345")) // current default length
 
 	assert.Error(t, validateID("nope"))
 	assert.Error(t, validateID(""))
-	assert.Error(t, validateID("abc123"))   // 6 chars
-	assert.Error(t, validateID("abc12345")) // 8 chars
-	assert.Error(t, validateID("ABCDEF0"))  // uppercase
-	assert.Error(t, validateID("abc123g"))  // non-hex
+	assert.Error(t, validateID("abc123"))                                                            // 6 chars
+	assert.Error(t, validateID("abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789a")) // 65 chars
+	assert.Error(t, validateID("ABCDEF0"))                                                           // uppercase
+	assert.Error(t, validateID("abc123g"))                                                           // non-hex
+}
+
+func TestWriteRejectsCrossTypeCollision(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+
+	require.NoError(t, WriteEntry(home, slug, testEntry("abc1234", "work")))
+
+	err := WriteCheckoutEntry(home, slug, testCheckoutEntry("abc1234", "main", "feature"))
+	assert.EqualError(t, err, "entry ID collision: 'abc1234' already holds a log entry")
+
+	e, err := ReadEntry(home, slug, "abc1234")
+	require.NoError(t, err)
+	assert.Equal(t, "work", e.Message)
+}
+
+func TestWriteRejectsSameTypeCollision(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+
+	e := testEntry("abc1234", "work")
+	require.NoError(t, WriteEntry(home, slug, e))
+
+	// Edits keep the creation time
+	e.Message = "edited"
+	e.Minutes = 90
+	require.NoError(t, WriteEntry(home, slug, e))
+
+	other := testEntry("abc1234", "other work")
+	other.CreatedAt = other.CreatedAt.Add(time.Hour)
+	err := WriteEntry(home, slug, other)
+	assert.EqualError(t, err, "entry ID collision: 'abc1234' already holds another log entry")
+
+	got, err := ReadEntry(home, slug, "abc1234")
+	require.NoError(t, err)
+	assert.Equal(t, "edited", got.Message)
+
+	c := testCheckoutEntry("def5678", "main", "feature")
+	require.NoError(t, WriteCheckoutEntry(home, slug, c))
+	c.Timestamp = c.Timestamp.Add(time.Minute)
+	assert.Error(t, WriteCheckoutEntry(home, slug, c))
+}
+
+// --- Journal tests ---
+
+func TestWritesAndDeletesAreJournaled(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+
+	journal.Begin("log add")
+	require.NoError(t, WriteEntry(home, slug, testEntry("abc1234", "first")))
+	require.NoError(t, WriteEntry(home, slug, testEntry("abc1234", "first"))) // unchanged, not recorded
+	journal.Begin("log edit")
+	require.NoError(t, WriteEntry(home, slug, testEntry("abc1234", "second")))
+	journal.Begin("log remove")
+	require.NoError(t, DeleteEntry(home, slug, "abc1234"))
+
+	history, err := journal.EntryHistory(home, slug, "abc1234")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Nil(t, history[0].Before)
+	assert.Contains(t, string(history[1].Before), "first")
+	assert.Contains(t, string(history[1].After), "second")
+	assert.Equal(t, "log remove", history[2].Command)
+	assert.Nil(t, history[2].After)
+}
+
+func TestRestoreEntry(t *testing.T) {
+	home := t.TempDir()
+	slug := "test-project"
+
+	require.NoError(t, WriteEntry(home, slug, testEntry("abc1234", "work")))
+	rec, err := ReadRecord(home, slug, "abc1234")
+	require.NoError(t, err)
+
+	require.NoError(t, DeleteEntry(home, slug, "abc1234"))
+	require.NoError(t, RestoreEntry(home, slug, "abc1234", rec.Data))
+	got, err := ReadEntry(home, slug, "abc1234")
+	require.NoError(t, err)
+	assert.Equal(t, "work", got.Message)
+
+	require.NoError(t, RestoreEntry(home, slug, "abc1234", nil))
+	_, err = ReadEntry(home, slug, "abc1234")
+	assert.Error(t, err)
+
+	// Restoring an absent entry to absent is a no-op
+	assert.NoError(t, RestoreEntry(home, slug, "abc1234", nil))
+}
+
+func TestDirStoreRejectsPathIDs(t *testing.T) {
+	home := t.TempDir()
+	store := NewDirStore(home)
+
+	for _, id := range []string{"../../../../.bashrc", "a/b", `a\b`, "..", ""} {
+		assert.Error(t, store.Write("test-project", id, []byte("{}")), id)
+		_, err := store.Read("test-project", id)
+		assert.Error(t, err, id)
+		assert.Error(t, store.Delete("test-project", id), id)
+	}
+	_, err := os.Stat(filepath.Join(home, ".bashrc"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDirStoreBindsSealedEntryToID(t *testing.T) {
+	home := t.TempDir()
+	store := NewDirStore(home)
+	t.Setenv(seal.EnvPassphrase, "secret")
+	_, err := seal.Setup(home, seal.KeyPassphrase, "secret")
+	require.NoError(t, err)
+	require.NoError(t, store.Write("test-project", "aaa1111", []byte(`{"id":"aaa1111","message":"first"}`)))
+	require.NoError(t, store.Write("test-project", "bbb2222", []byte(`{"id":"bbb2222","message":"second"}`)))
+
+	// A sealed entry copied over another one's file is left unreadable
+	dir := project.LogDir(home, "test-project")
+	data, err := os.ReadFile(filepath.Join(dir, "aaa1111"))
+	require.NoError(t, err)
+	info, err := os.Stat(filepath.Join(dir, "aaa1111"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bbb2222"), data, 0600))
+
+	got, err := store.Read("test-project", "bbb2222")
+	require.NoError(t, err)
+	assert.True(t, seal.IsSealed(got))
+	got, err = store.Read("test-project", "aaa1111")
+	require.NoError(t, err)
+	assert.Contains(t, string(got), "first")
 }
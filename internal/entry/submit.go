@@ -1,12 +1,20 @@
 package entry
 
-import "time"
+import (
+	"time"
 
-// SubmitEntry marks a date range as submitted.
+	"github.com/Flyrell/hourgit/internal/rounding"
+)
+
+// SubmitEntry marks a date range as submitted. It records the submitted
+// total and the rounding policy it was computed with.
 type SubmitEntry struct {
-	ID        string    `json:"id"`
-	Type      string    `json:"type"`
-	From      time.Time `json:"from"`
-	To        time.Time `json:"to"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Minutes    int             `json:"minutes,omitempty"`     // rounded total
+	RawMinutes int             `json:"raw_minutes,omitempty"` // total before rounding
+	Rounding   rounding.Policy `json:"rounding,omitzero"`
+	CreatedAt  time.Time       `json:"created_at"`
 }
@@ -0,0 +1,446 @@
+// Package fsck checks hourgit data for integrity problems and repairs the
+// ones that can be fixed without losing information.
+package fsck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
+)
+
+// Problem kinds reported by Check.
+const (
+	KindCorrupt       = "corrupt"
+	KindUnknownType   = "unknown-type"
+	KindIDMismatch    = "id-mismatch"
+	KindUnpaired      = "unpaired-activity"
+	KindDuplicate     = "duplicate-checkout"
+	KindOverlap       = "overlapping-logs"
+	KindMissingRepo   = "missing-repo"
+	KindMissingHook   = "missing-hook"
+	KindOrphanProject = "orphan-project"
+	KindSuperseded    = "superseded-lines"
+)
+
+// compacter is implemented by stores that keep superseded versions of
+// entries until compacted.
+type compacter interface {
+	Superseded(slug string) (int, error)
+	Compact(slug string) (int, error)
+}
+
+// knownTypes lists the entry types this version understands.
+var knownTypes = map[string]bool{
+	entry.TypeLog:           true,
+	entry.TypeCheckout:      true,
+	entry.TypeCommit:        true,
+	entry.TypeSubmit:        true,
+	entry.TypeActivityStop:  true,
+	entry.TypeActivityStart: true,
+	entry.TypeCorrection:    true,
+	entry.TypeGitEvent:      true,
+}
+
+// Problem is a single integrity issue.
+type Problem struct {
+	Kind     string
+	Slug     string // project directory, if the problem is inside one
+	ID       string // entry ID (storage key), if the problem concerns an entry
+	Detail   string
+	Repaired bool
+
+	repair func() error
+}
+
+// Repairable reports whether Repair can fix the problem.
+func (p Problem) Repairable() bool {
+	return p.repair != nil
+}
+
+// Report is the result of a check.
+type Report struct {
+	Problems []Problem
+}
+
+// Repairable returns the number of problems Repair can fix.
+func (r *Report) Repairable() int {
+	n := 0
+	for _, p := range r.Problems {
+		if p.Repairable() && !p.Repaired {
+			n++
+		}
+	}
+	return n
+}
+
+// Repair fixes every repairable problem, marking each as repaired. It stops
+// at the first failure and returns the number of problems fixed so far.
+func (r *Report) Repair() (int, error) {
+	n := 0
+	for i := range r.Problems {
+		p := &r.Problems[i]
+		if !p.Repairable() || p.Repaired {
+			continue
+		}
+		if err := p.repair(); err != nil {
+			return n, fmt.Errorf("repairing %s %s: %w", p.Kind, p.ID, err)
+		}
+		p.Repaired = true
+		n++
+	}
+	return n, nil
+}
+
+// QuarantineDir returns the directory that corrupt entries are moved to.
+func QuarantineDir(homeDir string) string {
+	return filepath.Join(project.DataDir(homeDir), ".quarantine")
+}
+
+// ResealQuarantine rewrites quarantined entries sealed or plain, whichever
+// homeDir currently uses. Entries that cannot be opened are left alone.
+// Returns the number of files rewritten.
+func ResealQuarantine(homeDir string) (int, error) {
+	sealer, err := seal.Load(homeDir)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	err = filepath.WalkDir(QuarantineDir(homeDir), func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		changed, err := sealer.RewriteFile(path, entry.SealContext(d.Name()))
+		if errors.Is(err, seal.ErrCorrupt) {
+			return nil
+		}
+		if changed {
+			count++
+		}
+		return err
+	})
+	return count, err
+}
+
+// Check scans the config and every project directory for problems.
+func Check(homeDir string) (*Report, error) {
+	cfg, err := project.ReadConfig(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	store, err := entry.OpenStore(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	slugs, err := entry.ProjectSlugs(homeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{}
+	known := make(map[string]bool, len(cfg.Projects))
+	for _, p := range cfg.Projects {
+		known[p.Slug] = true
+	}
+
+	for _, slug := range slugs {
+		records, err := store.Query(slug, entry.Query{Invalid: true})
+		if err != nil {
+			return nil, err
+		}
+		r.checkRecords(homeDir, store, slug, records)
+		r.checkActivity(homeDir, slug, records)
+		r.checkCheckouts(homeDir, slug, records)
+		r.checkOverlaps(slug, records)
+		if c, ok := store.(compacter); ok {
+			if err := r.checkSuperseded(c, slug); err != nil {
+				return nil, err
+			}
+		}
+		if !known[slug] {
+			r.checkOrphan(homeDir, slug, len(records))
+		}
+	}
+
+	for _, p := range cfg.Projects {
+		r.checkRepos(homeDir, p)
+	}
+
+	return r, nil
+}
+
+// checkRecords reports unreadable entries, unknown types, and IDs that
+// don't match their storage key.
+func (r *Report) checkRecords(homeDir string, store entry.Store, slug string, records []entry.Record) {
+	for _, rec := range records {
+		switch {
+		case rec.Invalid:
+			r.add(Problem{
+				Kind:   KindCorrupt,
+				Slug:   slug,
+				ID:     rec.File,
+				Detail: "entry is not valid JSON",
+				repair: func() error { return quarantine(homeDir, store, slug, rec) },
+			})
+		case !knownTypes[rec.Type]:
+			r.add(Problem{
+				Kind:   KindUnknownType,
+				Slug:   slug,
+				ID:     rec.File,
+				Detail: fmt.Sprintf("unknown entry type %q (written by a newer version?)", rec.Type),
+			})
+		case rec.ID != rec.File:
+			p := Problem{
+				Kind:   KindIDMismatch,
+				Slug:   slug,
+				ID:     rec.File,
+				Detail: fmt.Sprintf("entry declares ID %q", rec.ID),
+			}
+			if entry.IsValidID(rec.File) {
+				p.repair = func() error { return rewriteID(homeDir, slug, rec) }
+			}
+			r.add(p)
+		}
+	}
+}
+
+// checkActivity reports activity starts followed by another start and stops
+// without a start. The latest start per repo may legitimately be open.
+func (r *Report) checkActivity(homeDir, slug string, records []entry.Record) {
+	type event struct {
+		id    string
+		start bool
+		at    time.Time
+	}
+	byRepo := make(map[string][]event)
+	for _, e := range entry.Decode[entry.ActivityStartEntry](records, entry.TypeActivityStart) {
+		byRepo[e.Repo] = append(byRepo[e.Repo], event{id: e.ID, start: true, at: e.Timestamp})
+	}
+	for _, e := range entry.Decode[entry.ActivityStopEntry](records, entry.TypeActivityStop) {
+		byRepo[e.Repo] = append(byRepo[e.Repo], event{id: e.ID, at: e.Timestamp})
+	}
+
+	repos := make([]string, 0, len(byRepo))
+	for repo := range byRepo {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	for _, repo := range repos {
+		events := byRepo[repo]
+		sort.SliceStable(events, func(i, j int) bool {
+			if !events[i].at.Equal(events[j].at) {
+				return events[i].at.Before(events[j].at)
+			}
+			// A stop at the same instant closes the start
+			return events[i].start && !events[j].start
+		})
+
+		var open *event
+		for i := range events {
+			ev := events[i]
+			if ev.start {
+				if open != nil {
+					start := *open
+					r.add(Problem{
+						Kind:   KindUnpaired,
+						Slug:   slug,
+						ID:     start.id,
+						Detail: fmt.Sprintf("activity start at %s in %s has no stop", start.at.Format(time.RFC3339), repo),
+						repair: func() error { return writeStop(homeDir, slug, repo, start.id, start.at) },
+					})
+				}
+				open = &events[i]
+				continue
+			}
+			if open == nil {
+				r.add(Problem{
+					Kind:   KindUnpaired,
+					Slug:   slug,
+					ID:     ev.id,
+					Detail: fmt.Sprintf("activity stop at %s in %s has no start", ev.at.Format(time.RFC3339), repo),
+				})
+			}
+			open = nil
+		}
+	}
+}
+
+// checkCheckouts reports checkout entries recording the same event twice.
+// The entry with the lowest ID is kept.
+func (r *Report) checkCheckouts(homeDir, slug string, records []entry.Record) {
+	seen := make(map[string]string)
+	for _, c := range entry.Decode[entry.CheckoutEntry](records, entry.TypeCheckout) {
+		key := strings.Join([]string{c.Timestamp.UTC().Format(time.RFC3339Nano), c.Previous, c.Next, c.Repo}, "\x00")
+		kept, dup := seen[key]
+		if !dup {
+			seen[key] = c.ID
+			continue
+		}
+		id := c.ID
+		r.add(Problem{
+			Kind:   KindDuplicate,
+			Slug:   slug,
+			ID:     id,
+			Detail: fmt.Sprintf("same checkout as %s (%s → %s)", kept, c.Previous, c.Next),
+			repair: func() error { return entry.DeleteEntry(homeDir, slug, id) },
+		})
+	}
+}
+
+// checkOverlaps reports manual log entries whose time ranges overlap.
+func (r *Report) checkOverlaps(slug string, records []entry.Record) {
+	logs := entry.Decode[entry.Entry](records, entry.TypeLog)
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].Start.Before(logs[j].Start) })
+
+	var latest *entry.Entry
+	for i := range logs {
+		e := &logs[i]
+		if latest != nil && e.Start.Before(logEnd(*latest)) {
+			r.add(Problem{
+				Kind:   KindOverlap,
+				Slug:   slug,
+				ID:     e.ID,
+				Detail: fmt.Sprintf("overlaps %s on %s", latest.ID, e.Start.Format("2006-01-02")),
+			})
+		}
+		if latest == nil || logEnd(*e).After(logEnd(*latest)) {
+			latest = e
+		}
+	}
+}
+
+// checkSuperseded reports segment lines left behind by updated and deleted
+// entries. Repairing compacts the project's segments.
+func (r *Report) checkSuperseded(c compacter, slug string) error {
+	n, err := c.Superseded(slug)
+	if err != nil || n == 0 {
+		return err
+	}
+	r.add(Problem{
+		Kind:   KindSuperseded,
+		Slug:   slug,
+		Detail: fmt.Sprintf("%d superseded segment line(s)", n),
+		repair: func() error {
+			_, err := c.Compact(slug)
+			return err
+		},
+	})
+	return nil
+}
+
+// checkOrphan reports a project directory with no project in the config.
+// Empty directories are removed on repair; ones holding entries are left alone.
+func (r *Report) checkOrphan(homeDir, slug string, entries int) {
+	p := Problem{Kind: KindOrphanProject, Slug: slug}
+	if entries == 0 {
+		p.Detail = "empty directory with no project in config"
+		p.repair = func() error { return os.RemoveAll(project.LogDir(homeDir, slug)) }
+	} else {
+		p.Detail = fmt.Sprintf("%d entries but no project in config", entries)
+	}
+	r.add(p)
+}
+
+// checkRepos reports assigned repositories that are gone or lack the hook.
+func (r *Report) checkRepos(homeDir string, p project.ProjectEntry) {
+	for _, repo := range p.Repos {
+		if _, err := os.Stat(repo); errors.Is(err, os.ErrNotExist) {
+			projectID := p.ID
+			r.add(Problem{
+				Kind:   KindMissingRepo,
+				Slug:   p.Slug,
+				Detail: fmt.Sprintf("repository %s no longer exists", repo),
+				repair: func() error { return removeRepo(homeDir, projectID, repo) },
+			})
+			continue
+		}
+		if !project.HookInstalled(repo) {
+			r.add(Problem{
+				Kind:   KindMissingHook,
+				Slug:   p.Slug,
+				Detail: fmt.Sprintf("repository %s has no hourgit hook (run 'hourgit init' there)", repo),
+			})
+		}
+	}
+}
+
+func (r *Report) add(p Problem) {
+	r.Problems = append(r.Problems, p)
+}
+
+// logEnd returns the end time of a log entry.
+func logEnd(e entry.Entry) time.Time {
+	return e.Start.Add(time.Duration(e.Minutes) * time.Minute)
+}
+
+// quarantine moves an unreadable entry out of the store into QuarantineDir.
+func quarantine(homeDir string, store entry.Store, slug string, rec entry.Record) error {
+	dir := filepath.Join(QuarantineDir(homeDir), slug)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := seal.Seal(homeDir, rec.Data, entry.SealContext(rec.File))
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFileAtomic(filepath.Join(dir, rec.File), data, 0600); err != nil {
+		return err
+	}
+	return store.Delete(slug, rec.File)
+}
+
+// rewriteID sets the entry's ID field to its storage key.
+func rewriteID(homeDir, slug string, rec entry.Record) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Data, &fields); err != nil {
+		return err
+	}
+	id, err := json.Marshal(rec.File)
+	if err != nil {
+		return err
+	}
+	fields["id"] = id
+	data, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return err
+	}
+	return entry.RestoreEntry(homeDir, slug, rec.File, data)
+}
+
+// writeStop closes an unpaired activity start with a zero-length stop at the
+// start's own timestamp, so no untracked time is invented.
+func writeStop(homeDir, slug, repo, startID string, at time.Time) error {
+	id, err := entry.NewID(homeDir, slug, repo+startID+"fsck")
+	if err != nil {
+		return err
+	}
+	return entry.WriteActivityStopEntry(homeDir, slug, entry.ActivityStopEntry{
+		ID:        id,
+		Timestamp: at,
+		Repo:      repo,
+	})
+}
+
+// removeRepo drops a repository from a project's repos list.
+func removeRepo(homeDir, projectID, repo string) error {
+	return project.UpdateConfig(homeDir, func(cfg *project.Config) error {
+		if p := project.FindProjectByID(cfg, projectID); p != nil {
+			project.RemoveRepoFromProject(p, repo)
+		}
+		return nil
+	})
+}
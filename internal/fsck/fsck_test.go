@@ -0,0 +1,241 @@
+package fsck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupProject(t *testing.T) (homeDir string, proj *project.ProjectEntry) {
+	t.Helper()
+	homeDir = t.TempDir()
+	p, err := project.CreateProject(homeDir, "Fsck Test")
+	require.NoError(t, err)
+	return homeDir, p
+}
+
+func kinds(r *Report) []string {
+	var out []string
+	for _, p := range r.Problems {
+		out = append(out, p.Kind)
+	}
+	return out
+}
+
+func writeRaw(t *testing.T, homeDir, slug, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(project.LogDir(homeDir, slug), name), []byte(content), 0644))
+}
+
+func TestCheckCleanData(t *testing.T) {
+	homeDir, p := setupProject(t)
+	require.NoError(t, entry.WriteEntry(homeDir, p.Slug, entry.Entry{
+		ID:      "aaa1111",
+		Start:   time.Date(2025, 6, 15, 9, 0, 0, 0, time.UTC),
+		Minutes: 60,
+	}))
+
+	r, err := Check(homeDir)
+	require.NoError(t, err)
+	assert.Empty(t, r.Problems)
+}
+
+func TestCheckCorruptEntryIsQuarantined(t *testing.T) {
+	homeDir, p := setupProject(t)
+	writeRaw(t, homeDir, p.Slug, "bad1111", "not json")
+
+	r, err := Check(homeDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{KindCorrupt}, kinds(r))
+	assert.Equal(t, "bad1111", r.Problems[0].ID)
+
+	n, err := r.Repair()
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	data, err := os.ReadFile(filepath.Join(QuarantineDir(homeDir), p.Slug, "bad1111"))
+	require.NoError(t, err)
+	assert.Equal(t, "not json", string(data))
+	_, err = os.Stat(filepath.Join(project.LogDir(homeDir, p.Slug), "bad1111"))
+	assert.True(t, os.IsNotExist(err))
+
+	// The quarantine directory is not mistaken for a project
+	r, err = Check(homeDir)
+	require.NoError(t, err)
+	assert.Empty(t, r.Problems)
+}
+
+func TestCheckUnknownTypeIsReportOnly(t *testing.T) {
+	homeDir, p := setupProject(t)
+	writeRaw(t, homeDir, p.Slug, "fff1111", `{"id":"fff1111","type":"timer"}`)
+
+	r, err := Check(homeDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{KindUnknownType}, kinds(r))
+	assert.False(t, r.Problems[0].Repairable())
+}
+
+func TestCheckIDMismatchRewritesID(t *testing.T) {
+	homeDir, p := setupProject(t)
+	writeRaw(t, homeDir, p.Slug, "aaa1111",
+		`{"id":"bbb2222","type":"log","start":"2025-06-15T09:00:00Z","minutes":30}`)
+
+	r, err := Check(homeDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{KindIDMismatch}, kinds(r))
+
+	_, err = r.Repair()
+	require.NoError(t, err)
+
+	e, err := entry.ReadEntry(homeDir, p.Slug, "aaa1111")
+	require.NoError(t, err)
+	assert.Equal(t, "aaa1111", e.ID)
+	assert.Equal(t, 30, e.Minutes)
+}
+
+func TestCheckUnpairedActivity(t *testing.T) {
+	homeDir, p := setupProject(t)
+	base := time.Date(2025, 6, 15, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, entry.WriteActivityStartEntry(homeDir, p.Slug, entry.ActivityStartEntry{ID: "a000001", Timestamp: base, Repo: "/repo"}))
+	require.NoError(t, entry.WriteActivityStartEntry(homeDir, p.Slug, entry.ActivityStartEntry{ID: "a000002", Timestamp: base.Add(time.Hour), Repo: "/repo"}))
+	require.NoError(t, entry.WriteActivityStopEntry(homeDir, p.Slug, entry.ActivityStopEntry{ID: "b000001", Timestamp: base.Add(2 * time.Hour), Repo: "/repo"}))
+	require.NoError(t, entry.WriteActivityStopEntry(homeDir, p.Slug, entry.ActivityStopEntry{ID: "b000002", Timestamp: base.Add(3 * time.Hour), Repo: "/repo"}))
+	// The latest start is still open, which is fine
+	require.NoError(t, entry.WriteActivityStartEntry(homeDir, p.Slug, entry.ActivityStartEntry{ID: "a000003", Timestamp: base.Add(4 * time.Hour), Repo: "/repo"}))
+
+	r, err := Check(homeDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{KindUnpaired, KindUnpaired}, kinds(r))
+	assert.Equal(t, "a000001", r.Problems[0].ID)
+	assert.True(t, r.Problems[0].Repairable())
+	assert.Equal(t, "b000002", r.Problems[1].ID)
+	assert.False(t, r.Problems[1].Repairable())
+
+	_, err = r.Repair()
+	require.NoError(t, err)
+
+	stops, err := entry.ReadAllActivityStopEntries(homeDir, p.Slug)
+	require.NoError(t, err)
+	require.Len(t, stops, 3)
+
+	r, err = Check(homeDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{KindUnpaired}, kinds(r)) // the orphan stop remains
+}
+
+func TestCheckDuplicateCheckouts(t *testing.T) {
+	homeDir, p := setupProject(t)
+	ts := time.Date(2025, 6, 15, 9, 0, 0, 0, time.UTC)
+	for _, id := range []string{"c000002", "c000001"} {
+		require.NoError(t, entry.WriteCheckoutEntry(homeDir, p.Slug, entry.CheckoutEntry{
+			ID: id, Timestamp: ts, Previous: "main", Next: "feature", Repo: "/repo",
+		}))
+	}
+
+	r, err := Check(homeDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{KindDuplicate}, kinds(r))
+	assert.Equal(t, "c000002", r.Problems[0].ID)
+
+	_, err = r.Repair()
+	require.NoError(t, err)
+	checkouts, err := entry.ReadAllCheckoutEntries(homeDir, p.Slug)
+	require.NoError(t, err)
+	require.Len(t, checkouts, 1)
+	assert.Equal(t, "c000001", checkouts[0].ID)
+}
+
+func TestCheckOverlappingLogs(t *testing.T) {
+	homeDir, p := setupProject(t)
+	start := time.Date(2025, 6, 15, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, entry.WriteEntry(homeDir, p.Slug, entry.Entry{ID: "d000001", Start: start, Minutes: 120}))
+	require.NoError(t, entry.WriteEntry(homeDir, p.Slug, entry.Entry{ID: "d000002", Start: start.Add(time.Hour), Minutes: 30}))
+	require.NoError(t, entry.WriteEntry(homeDir, p.Slug, entry.Entry{ID: "d000003", Start: start.Add(2 * time.Hour), Minutes: 30}))
+
+	r, err := Check(homeDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{KindOverlap}, kinds(r))
+	assert.Equal(t, "d000002", r.Problems[0].ID)
+	assert.Contains(t, r.Problems[0].Detail, "d000001")
+	assert.False(t, r.Problems[0].Repairable())
+}
+
+func TestCheckRepos(t *testing.T) {
+	homeDir, p := setupProject(t)
+
+	withHook := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(withHook, ".git", "hooks"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(withHook, ".git", "hooks", "post-checkout"),
+		[]byte("#!/bin/sh\n"+project.HookMarker+"\n"), 0755))
+	withoutHook := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(withoutHook, ".git"), 0755))
+	missing := filepath.Join(t.TempDir(), "gone")
+
+	require.NoError(t, project.UpdateConfig(homeDir, func(cfg *project.Config) error {
+		project.FindProjectByID(cfg, p.ID).Repos = []string{withHook, withoutHook, missing}
+		return nil
+	}))
+
+	r, err := Check(homeDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{KindMissingHook, KindMissingRepo}, kinds(r))
+
+	_, err = r.Repair()
+	require.NoError(t, err)
+
+	cfg, err := project.ReadConfig(homeDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{withHook, withoutHook}, project.FindProjectByID(cfg, p.ID).Repos)
+}
+
+func TestCheckOrphanProjectDirs(t *testing.T) {
+	homeDir, _ := setupProject(t)
+	require.NoError(t, os.MkdirAll(project.LogDir(homeDir, "empty-orphan"), 0755))
+	require.NoError(t, entry.WriteEntry(homeDir, "full-orphan", entry.Entry{
+		ID: "e000001", Start: time.Date(2025, 6, 15, 9, 0, 0, 0, time.UTC), Minutes: 10,
+	}))
+
+	r, err := Check(homeDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{KindOrphanProject, KindOrphanProject}, kinds(r))
+	assert.Equal(t, "empty-orphan", r.Problems[0].Slug)
+	assert.True(t, r.Problems[0].Repairable())
+	assert.False(t, r.Problems[1].Repairable())
+
+	_, err = r.Repair()
+	require.NoError(t, err)
+	_, err = os.Stat(project.LogDir(homeDir, "empty-orphan"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(project.LogDir(homeDir, "full-orphan"))
+	assert.NoError(t, err)
+}
+
+func TestCheckSupersededSegmentLines(t *testing.T) {
+	homeDir, p := setupProject(t)
+	require.NoError(t, project.SetStorage(homeDir, project.StorageSegments))
+	e := entry.Entry{ID: "aaa1111", Start: time.Date(2025, 6, 15, 9, 0, 0, 0, time.UTC), Minutes: 60}
+	require.NoError(t, entry.WriteEntry(homeDir, p.Slug, e))
+	e.Minutes = 90
+	require.NoError(t, entry.WriteEntry(homeDir, p.Slug, e))
+
+	r, err := Check(homeDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{KindSuperseded}, kinds(r))
+	assert.Equal(t, "1 superseded segment line(s)", r.Problems[0].Detail)
+
+	_, err = r.Repair()
+	require.NoError(t, err)
+	r, err = Check(homeDir)
+	require.NoError(t, err)
+	assert.Empty(t, r.Problems)
+
+	got, err := entry.ReadEntry(homeDir, p.Slug, "aaa1111")
+	require.NoError(t, err)
+	assert.Equal(t, 90, got.Minutes)
+}
@@ -0,0 +1,43 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomicCreatesAndReplaces(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+
+	require.NoError(t, WriteFileAtomic(path, []byte("first"), 0644))
+	require.NoError(t, WriteFileAtomic(path, []byte("second"), 0644))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	// No temporary files are left behind
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestWriteFileAtomicSetsPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hook")
+
+	require.NoError(t, WriteFileAtomic(path, []byte("#!/bin/sh\n"), 0755))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
+func TestWriteFileAtomicMissingDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "file")
+
+	assert.Error(t, WriteFileAtomic(path, []byte("x"), 0644))
+}
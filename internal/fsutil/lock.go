@@ -0,0 +1,49 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FileLock is an exclusive advisory lock on a file, shared across processes.
+// Locks are not reentrant: acquiring the same path twice, even from one
+// process, blocks until the first lock is released.
+type FileLock struct {
+	f *os.File
+}
+
+// Lock blocks until it holds an exclusive lock on path, creating the file
+// (and its directory) if needed.
+func Lock(path string) (*FileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &FileLock{f: f}, nil
+}
+
+// Unlock releases the lock.
+func (l *FileLock) Unlock() error {
+	err := unlockFile(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WithLock runs fn while holding the lock on path.
+func WithLock(path string, fn func() error) error {
+	l, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Unlock() }()
+	return fn()
+}
@@ -0,0 +1,62 @@
+package fsutil
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockIsExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "test.lock")
+
+	l, err := Lock(path)
+	require.NoError(t, err)
+
+	acquired := make(chan struct{})
+	go func() {
+		l2, err := Lock(path)
+		if err == nil {
+			_ = l2.Unlock()
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second lock acquired while first was held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, l.Unlock())
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("second lock not acquired after release")
+	}
+}
+
+func TestWithLockSerialisesReadModifyWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter.lock")
+
+	counter := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = WithLock(path, func() error {
+				v := counter
+				time.Sleep(time.Millisecond)
+				counter = v + 1
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 20, counter)
+}
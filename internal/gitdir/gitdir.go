@@ -0,0 +1,169 @@
+// Package gitdir locates the git directories behind a work tree. In a plain
+// clone they are all <repo>/.git, but in a linked worktree .git is a file
+// pointing into the main repository's .git/worktrees, and in a submodule into
+// the superproject's .git/modules.
+package gitdir
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotRepo is returned for a directory that is not a git work tree.
+var ErrNotRepo = errors.New("not a git repository")
+
+// Dirs are the git directories of a work tree.
+type Dirs struct {
+	Git    string // the work tree's own git dir, holding its HEAD and reflog
+	Common string // the git dir shared by all worktrees, holding refs
+	Hooks  string // the hooks git runs: core.hooksPath, else the common git dir's hooks
+	Super  string // the superproject's work tree when the work tree is a submodule
+}
+
+// Worktree reports whether the work tree is a linked worktree.
+func (d Dirs) Worktree() bool {
+	return d.Git != d.Common
+}
+
+// Submodule reports whether the work tree is a submodule of another.
+func (d Dirs) Submodule() bool {
+	return d.Super != ""
+}
+
+// Resolve returns the git directories of the work tree at dir. A plain
+// clone is resolved without running git (see resolvePlain); anything else as
+// reported by git rev-parse. Without git, or when git does not recognise the
+// repository, it follows dir/.git itself: a directory, or a file naming the
+// git dir.
+func Resolve(dir string) (Dirs, error) {
+	if d, ok := resolvePlain(dir); ok {
+		return d, nil
+	}
+	out, err := exec.Command("git", "-C", dir, "rev-parse",
+		"--git-dir", "--git-common-dir", "--git-path", "hooks", "--show-superproject-working-tree").Output()
+	if err != nil {
+		return resolveDotGit(dir)
+	}
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	if len(lines) < 3 {
+		return resolveDotGit(dir)
+	}
+	d := Dirs{Git: absPath(dir, lines[0]), Common: absPath(dir, lines[1]), Hooks: absPath(dir, lines[2])}
+	if len(lines) > 3 {
+		d.Super = lines[3]
+	}
+	return d, nil
+}
+
+// resolvePlain resolves the git directories of the work tree at dir when
+// dir/.git is a directory, which is the case for every work tree but linked
+// worktrees and submodules. It gives up when core.hooksPath may be set: when
+// a config file git reads mentions it or includes other files, or config is
+// passed in the environment.
+func resolvePlain(dir string) (Dirs, bool) {
+	dotGit := filepath.Join(dir, ".git")
+	if info, err := os.Stat(dotGit); err != nil || !info.IsDir() {
+		return Dirs{}, false
+	}
+	if os.Getenv("GIT_CONFIG_PARAMETERS") != "" || os.Getenv("GIT_CONFIG_COUNT") != "" {
+		return Dirs{}, false
+	}
+	for _, path := range configFiles(dotGit) {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Dirs{}, false
+		}
+		data = bytes.ToLower(data)
+		if bytes.Contains(data, []byte("hookspath")) || bytes.Contains(data, []byte("include")) {
+			return Dirs{}, false
+		}
+	}
+	return Dirs{Git: dotGit, Common: dotGit, Hooks: filepath.Join(dotGit, "hooks")}, true
+}
+
+// systemConfigs are where git builds commonly read their system config from.
+var systemConfigs = []string{"/etc/gitconfig", "/usr/local/etc/gitconfig", "/opt/homebrew/etc/gitconfig"}
+
+// configFiles returns the config files git reads for the repository at
+// dotGit: its own, the global and the system ones.
+func configFiles(dotGit string) []string {
+	files := []string{filepath.Join(dotGit, "config"), filepath.Join(dotGit, "config.worktree")}
+	if global := os.Getenv("GIT_CONFIG_GLOBAL"); global != "" {
+		files = append(files, global)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		xdg := os.Getenv("XDG_CONFIG_HOME")
+		if xdg == "" {
+			xdg = filepath.Join(home, ".config")
+		}
+		files = append(files, filepath.Join(xdg, "git", "config"), filepath.Join(home, ".gitconfig"))
+	}
+	if os.Getenv("GIT_CONFIG_NOSYSTEM") == "" {
+		if system := os.Getenv("GIT_CONFIG_SYSTEM"); system != "" {
+			files = append(files, system)
+		} else {
+			files = append(files, systemConfigs...)
+		}
+	}
+	return files
+}
+
+// resolveDotGit resolves the git directories from dir/.git and, for linked
+// worktrees, the commondir file of their git dir.
+func resolveDotGit(dir string) (Dirs, error) {
+	dotGit := filepath.Join(dir, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return Dirs{}, ErrNotRepo
+	}
+	if info.IsDir() {
+		return Dirs{Git: dotGit, Common: dotGit, Hooks: filepath.Join(dotGit, "hooks")}, nil
+	}
+
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return Dirs{}, err
+	}
+	target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return Dirs{}, ErrNotRepo
+	}
+	d := Dirs{Git: absPath(dir, strings.TrimSpace(target))}
+	d.Common = d.Git
+	if data, err := os.ReadFile(filepath.Join(d.Git, "commondir")); err == nil {
+		d.Common = absPath(d.Git, strings.TrimSpace(string(data)))
+	}
+	d.Hooks = filepath.Join(d.Common, "hooks")
+	return d, nil
+}
+
+// Submodules returns the work trees of the checked-out submodules of the
+// work tree at dir, nested ones included.
+func Submodules(dir string) ([]string, error) {
+	out, err := exec.Command("git", "-C", dir, "submodule", "--quiet", "foreach", "--recursive",
+		`echo "$toplevel/$sm_path"`).Output()
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			paths = append(paths, filepath.Clean(line))
+		}
+	}
+	return paths, nil
+}
+
+// absPath resolves path relative to dir.
+func absPath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(dir, path)
+}
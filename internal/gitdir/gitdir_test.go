@@ -0,0 +1,136 @@
+package gitdir
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// run runs a git command in dir.
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	args = append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com",
+		"-c", "protocol.file.allow=always"}, args...)
+	out, err := exec.Command("git", args...).CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+// newRepo creates a repository with one commit.
+func newRepo(t *testing.T) string {
+	t.Helper()
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	run(t, dir, "init", "-q", "-b", "main")
+	run(t, dir, "commit", "-q", "--allow-empty", "-m", "init")
+	return dir
+}
+
+func TestResolvePlainRepo(t *testing.T) {
+	dir := newRepo(t)
+
+	d, err := Resolve(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".git"), d.Git)
+	assert.Equal(t, filepath.Join(dir, ".git"), d.Common)
+	assert.Equal(t, filepath.Join(dir, ".git", "hooks"), d.Hooks)
+	assert.False(t, d.Worktree())
+	assert.False(t, d.Submodule())
+}
+
+func TestResolveHooksPath(t *testing.T) {
+	dir := newRepo(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0755))
+	run(t, dir, "config", "core.hooksPath", ".husky/_")
+
+	d, err := Resolve(filepath.Join(dir, "src"))
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".husky", "_"), d.Hooks)
+}
+
+func TestResolveLinkedWorktree(t *testing.T) {
+	dir := newRepo(t)
+	wt := filepath.Join(filepath.Dir(dir), filepath.Base(dir)+"-wt")
+	t.Cleanup(func() { _ = os.RemoveAll(wt) })
+	run(t, dir, "worktree", "add", "-q", "-b", "feature", wt)
+
+	d, err := Resolve(wt)
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".git", "worktrees", filepath.Base(wt)), d.Git)
+	assert.Equal(t, filepath.Join(dir, ".git"), d.Common)
+	assert.Equal(t, filepath.Join(dir, ".git", "hooks"), d.Hooks)
+	assert.True(t, d.Worktree())
+}
+
+func TestResolveSubmodule(t *testing.T) {
+	lib := newRepo(t)
+	dir := newRepo(t)
+	run(t, dir, "submodule", "add", "-q", lib, "lib")
+
+	d, err := Resolve(filepath.Join(dir, "lib"))
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".git", "modules", "lib"), d.Git)
+	assert.Equal(t, d.Git, d.Common)
+	assert.True(t, d.Submodule())
+	assert.Equal(t, dir, d.Super)
+
+	subs, err := Submodules(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "lib")}, subs)
+}
+
+func TestResolveWithoutGit(t *testing.T) {
+	main := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(main, ".git", "worktrees", "wt"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(main, ".git", "worktrees", "wt", "commondir"), []byte("../..\n"), 0644))
+	wt := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+filepath.Join(main, ".git", "worktrees", "wt")+"\n"), 0644))
+
+	d, err := Resolve(main)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(main, ".git"), d.Git)
+	assert.Equal(t, filepath.Join(main, ".git"), d.Common)
+
+	d, err = Resolve(wt)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(main, ".git", "worktrees", "wt"), d.Git)
+	assert.Equal(t, filepath.Join(main, ".git"), d.Common)
+	assert.True(t, d.Worktree())
+}
+
+func TestResolveNotRepo(t *testing.T) {
+	_, err := Resolve(t.TempDir())
+	assert.ErrorIs(t, err, ErrNotRepo)
+}
+
+func TestResolvePlainRepoWithoutRunningGit(t *testing.T) {
+	dir := newRepo(t)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	// A git that leaves a mark when run
+	bin := t.TempDir()
+	mark := filepath.Join(bin, "ran")
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "git"), []byte("#!/bin/sh\n: > "+mark+"\nexit 1\n"), 0755))
+	t.Setenv("PATH", bin)
+
+	d, err := Resolve(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".git"), d.Git)
+	assert.Equal(t, filepath.Join(dir, ".git", "hooks"), d.Hooks)
+	assert.NoFileExists(t, mark)
+
+	// core.hooksPath is left to git
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "config"), []byte("[core]\n\thooksPath = .husky/_\n"), 0644))
+	_, err = Resolve(dir)
+	require.NoError(t, err)
+	assert.FileExists(t, mark)
+}
@@ -6,14 +6,22 @@ import (
 	"time"
 )
 
-// GenerateID creates a 7-character hex ID from a name and the current timestamp.
-func GenerateID(name string) string {
-	seed := name + "\x00" + fmt.Sprintf("%d", time.Now().UnixNano())
-	return GenerateIDFromSeed(seed)
-}
+// EntryIDLength is the length of newly generated entry IDs. IDs are prefixes
+// of a seed's hash; stored IDs can be longer when a collision forced an
+// extension, and entries created by older versions use 7 characters.
+const EntryIDLength = 12
 
 // GenerateIDFromSeed creates a deterministic 7-character hex ID from a seed string.
 func GenerateIDFromSeed(seed string) string {
-	hash := sha256.Sum256([]byte(seed))
-	return fmt.Sprintf("%x", hash[:4])[:7]
+	return Hash(seed)[:7]
+}
+
+// Hash returns the full 64-character hex SHA-256 of a seed string.
+func Hash(seed string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(seed)))
+}
+
+// TimeSeed returns a seed made unique by the current timestamp.
+func TimeSeed(name string) string {
+	return name + "\x00" + fmt.Sprintf("%d", time.Now().UnixNano())
 }
@@ -9,17 +9,6 @@ import (
 
 var hexPattern = regexp.MustCompile(`^[0-9a-f]{7}$`)
 
-func TestGenerateIDFormat(t *testing.T) {
-	id := GenerateID("test-project")
-	assert.Regexp(t, hexPattern, id)
-}
-
-func TestGenerateIDUniqueness(t *testing.T) {
-	id1 := GenerateID("test-project")
-	id2 := GenerateID("test-project")
-	assert.NotEqual(t, id1, id2, "IDs from successive calls should differ due to timestamp")
-}
-
 func TestGenerateIDFromSeedDeterministic(t *testing.T) {
 	id1 := GenerateIDFromSeed("fixed-seed")
 	id2 := GenerateIDFromSeed("fixed-seed")
@@ -36,3 +25,13 @@ func TestGenerateIDFromSeedDifferentInputs(t *testing.T) {
 	id2 := GenerateIDFromSeed("seed-b")
 	assert.NotEqual(t, id1, id2)
 }
+
+func TestHashIsFullSHA256(t *testing.T) {
+	h := Hash("fixed-seed")
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{64}$`), h)
+	assert.Equal(t, GenerateIDFromSeed("fixed-seed"), h[:7])
+}
+
+func TestTimeSeedUniqueness(t *testing.T) {
+	assert.NotEqual(t, TimeSeed("log"), TimeSeed("log"))
+}
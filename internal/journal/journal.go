@@ -0,0 +1,400 @@
+// Package journal keeps an append-only log of every change hourgit makes to
+// entries and config, with before/after snapshots, so operations can be
+// inspected and undone.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/hashutil"
+	"github.com/Flyrell/hourgit/internal/paths"
+	"github.com/Flyrell/hourgit/internal/seal"
+)
+
+// Change kinds.
+const (
+	KindEntry      = "entry"       // an entry payload in a project
+	KindConfig     = "config"      // the whole config.json
+	KindProjectDir = "project_dir" // a project data directory rename (slugs)
+)
+
+// CommandUndo is the command recorded for operations made by undo.
+const CommandUndo = "undo"
+
+// CommandAutoMigrate is the command recorded for the data migrations run
+// before a command when the data comes from an older hourgit.
+const CommandAutoMigrate = "auto-migrate"
+
+// automatic lists the commands that write on their own rather than at the
+// user's request: the watcher, the sync run by the git hooks on every
+// checkout, commit, merge and rewrite, and automatic migrations. undo skips
+// them, so it reverts what the user last did.
+var automatic = map[string]bool{"watch": true, "sync": true, CommandAutoMigrate: true}
+
+// maxSize is the journal size past which appending drops its oldest
+// operations, down to half of it.
+var maxSize int64 = 16 << 20
+
+// SealContext binds sealed journal lines to the journal.
+const SealContext = "journal"
+
+// Change is a single journaled mutation. A nil Before means the object did
+// not exist; a nil After means it was deleted.
+type Change struct {
+	Op      string          `json:"op"`
+	Command string          `json:"command"`
+	Time    time.Time       `json:"time"`
+	User    string          `json:"user,omitempty"`
+	Reverts string          `json:"reverts,omitempty"`
+	Kind    string          `json:"kind"`
+	Slug    string          `json:"slug,omitempty"`
+	ID      string          `json:"id,omitempty"`
+	Before  json.RawMessage `json:"before,omitempty"`
+	After   json.RawMessage `json:"after,omitempty"`
+}
+
+// Operation groups the changes made by one command or action.
+type Operation struct {
+	ID      string
+	Command string
+	Time    time.Time
+	User    string
+	Reverts string
+	Changes []Change
+}
+
+var (
+	mu      sync.Mutex
+	current struct {
+		id      string
+		command string
+		reverts string
+	}
+)
+
+// Path returns the path to the journal file.
+func Path(homeDir string) string {
+	return filepath.Join(paths.For(homeDir).Data, "journal.jsonl")
+}
+
+// lockPath returns the lock file serialising journal appends.
+func lockPath(homeDir string) string {
+	return filepath.Join(paths.For(homeDir).Data, "journal.lock")
+}
+
+// Begin starts a new operation. Changes appended afterwards are grouped
+// under it until the next call.
+func Begin(command string) {
+	mu.Lock()
+	defer mu.Unlock()
+	current.id = hashutil.Hash(hashutil.TimeSeed(command))[:hashutil.EntryIDLength]
+	current.command = command
+	current.reverts = ""
+}
+
+// BeginUndo starts an operation that reverts the operation with the given ID.
+func BeginUndo(opID string) {
+	Begin(CommandUndo)
+	mu.Lock()
+	defer mu.Unlock()
+	current.reverts = opID
+}
+
+// Append records a change under the current operation.
+func Append(homeDir string, c Change) error {
+	mu.Lock()
+	if current.id == "" {
+		current.id = hashutil.Hash(hashutil.TimeSeed(""))[:hashutil.EntryIDLength]
+	}
+	c.Op = current.id
+	c.Command = current.command
+	c.Reverts = current.reverts
+	mu.Unlock()
+
+	c.Time = time.Now().UTC()
+	c.User = whoami()
+	c.Before = compact(c.Before)
+	c.After = compact(c.After)
+
+	line, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if line, err = seal.Seal(homeDir, line, SealContext); err != nil {
+		return fmt.Errorf("recording journal: %w", err)
+	}
+
+	err = fsutil.WithLock(lockPath(homeDir), func() error {
+		f, err := os.OpenFile(Path(homeDir), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(append(line, '\n')); err != nil {
+			_ = f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return trim(homeDir)
+	})
+	if err != nil {
+		return fmt.Errorf("recording journal: %w", err)
+	}
+	return nil
+}
+
+// trim drops the oldest operations once the journal outgrows maxSize, keeping
+// the newest ones that fit in half of it. The caller holds the journal lock.
+func trim(homeDir string) error {
+	info, err := os.Stat(Path(homeDir))
+	if err != nil || info.Size() <= maxSize {
+		return err
+	}
+	data, err := os.ReadFile(Path(homeDir))
+	if err != nil {
+		return err
+	}
+
+	type line struct {
+		raw []byte
+		op  string
+	}
+	sealer, err := seal.Load(homeDir)
+	if err != nil {
+		return err
+	}
+	var lines []line
+	size := make(map[string]int64)
+	for raw := range bytes.SplitSeq(data, []byte("\n")) {
+		plain, err := sealer.Open(raw, SealContext)
+		if err != nil {
+			continue
+		}
+		var c Change
+		if err := json.Unmarshal(plain, &c); err != nil || c.Op == "" {
+			continue
+		}
+		lines = append(lines, line{raw, c.Op})
+		size[c.Op] += int64(len(raw)) + 1
+	}
+
+	// Keep whole operations, newest first, while they fit
+	keep := make(map[string]bool)
+	var kept int64
+	for i := len(lines) - 1; i >= 0; i-- {
+		op := lines[i].op
+		if keep[op] {
+			continue
+		}
+		if kept+size[op] > maxSize/2 && len(keep) > 0 {
+			break
+		}
+		keep[op] = true
+		kept += size[op]
+	}
+
+	var buf bytes.Buffer
+	for _, l := range lines {
+		if keep[l.op] {
+			buf.Write(l.raw)
+			buf.WriteByte('\n')
+		}
+	}
+	return fsutil.WriteFileAtomic(Path(homeDir), buf.Bytes(), 0600)
+}
+
+// Read returns every change in the journal, oldest first. Lines that fail to
+// parse (e.g. a torn final write) are skipped.
+func Read(homeDir string) ([]Change, error) {
+	f, err := os.Open(Path(homeDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	sealer, err := seal.Load(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		data, err := sealer.Open(scanner.Bytes(), SealContext)
+		if errors.Is(err, seal.ErrCorrupt) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var c Change
+		if err := json.Unmarshal(data, &c); err != nil || c.Op == "" {
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes, scanner.Err()
+}
+
+// Reseal rewrites the journal sealed or plain, whichever homeDir currently
+// uses. Returns whether it changed.
+func Reseal(homeDir string) (bool, error) {
+	sealer, err := seal.Load(homeDir)
+	if err != nil {
+		return false, err
+	}
+	var changed bool
+	err = fsutil.WithLock(lockPath(homeDir), func() error {
+		var err error
+		changed, err = sealer.RewriteLines(Path(homeDir), SealContext)
+		return err
+	})
+	return changed, err
+}
+
+// Operations returns the journal grouped into operations, oldest first.
+func Operations(homeDir string) ([]Operation, error) {
+	changes, err := Read(homeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []Operation
+	index := make(map[string]int)
+	for _, c := range changes {
+		i, ok := index[c.Op]
+		if !ok {
+			i = len(ops)
+			index[c.Op] = i
+			ops = append(ops, Operation{
+				ID:      c.Op,
+				Command: c.Command,
+				Time:    c.Time,
+				User:    c.User,
+				Reverts: c.Reverts,
+			})
+		}
+		ops[i].Changes = append(ops[i].Changes, c)
+	}
+	return ops, nil
+}
+
+// Undoable returns the operations undo may revert, newest first: everything
+// not yet reverted, except undo operations and automatic writes.
+func Undoable(homeDir string) ([]Operation, error) {
+	ops, err := Operations(homeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	reverted := make(map[string]bool)
+	for _, op := range ops {
+		if op.Reverts != "" {
+			reverted[op.Reverts] = true
+		}
+	}
+
+	var undoable []Operation
+	for i := len(ops) - 1; i >= 0; i-- {
+		op := ops[i]
+		if op.Command == CommandUndo || automatic[op.Command] || reverted[op.ID] {
+			continue
+		}
+		undoable = append(undoable, op)
+	}
+	return undoable, nil
+}
+
+// EntryHistory returns every change to an entry, oldest first.
+func EntryHistory(homeDir, slug, id string) ([]Change, error) {
+	changes, err := Read(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	var history []Change
+	for _, c := range changes {
+		if c.Kind == KindEntry && c.Slug == slug && c.ID == id {
+			history = append(history, c)
+		}
+	}
+	return history, nil
+}
+
+// EntryRef identifies an entry that appears in the journal.
+type EntryRef struct {
+	Slug string
+	ID   string
+}
+
+// FindEntries returns the distinct entries in the journal whose ID starts
+// with prefix. An exact ID match hides longer IDs sharing the prefix.
+func FindEntries(homeDir, prefix string) ([]EntryRef, error) {
+	changes, err := Read(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[EntryRef]bool)
+	var exact, partial []EntryRef
+	for _, c := range changes {
+		if c.Kind != KindEntry || len(c.ID) < len(prefix) || c.ID[:len(prefix)] != prefix {
+			continue
+		}
+		ref := EntryRef{Slug: c.Slug, ID: c.ID}
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		if c.ID == prefix {
+			exact = append(exact, ref)
+		} else {
+			partial = append(partial, ref)
+		}
+	}
+	if len(exact) > 0 {
+		return exact, nil
+	}
+	return partial, nil
+}
+
+// Equal reports whether two snapshots hold the same JSON document.
+func Equal(a, b []byte) bool {
+	return bytes.Equal(compact(a), compact(b))
+}
+
+// compact strips insignificant whitespace from a JSON snapshot.
+func compact(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+	return buf.Bytes()
+}
+
+// whoami returns user@host for the current process.
+func whoami() string {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		return name + "@" + host
+	}
+	return name
+}
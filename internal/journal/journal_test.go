@@ -0,0 +1,163 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/seal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendGroupsChangesByOperation(t *testing.T) {
+	home := t.TempDir()
+
+	Begin("log add")
+	require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "p", ID: "aaa1111", After: []byte(`{"id": "aaa1111"}`)}))
+	require.NoError(t, Append(home, Change{Kind: KindConfig, Before: []byte(`{}`), After: []byte(`{"storage":"segments"}`)}))
+	Begin("log remove")
+	require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "p", ID: "aaa1111", Before: []byte(`{"id":"aaa1111"}`)}))
+
+	ops, err := Operations(home)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	assert.Equal(t, "log add", ops[0].Command)
+	assert.Len(t, ops[0].Changes, 2)
+	assert.Equal(t, "log remove", ops[1].Command)
+	assert.NotEqual(t, ops[0].ID, ops[1].ID)
+
+	// Snapshots are stored compacted; After is absent on deletes
+	assert.Equal(t, `{"id":"aaa1111"}`, string(ops[0].Changes[0].After))
+	assert.Nil(t, ops[1].Changes[0].After)
+	assert.NotEmpty(t, ops[0].Changes[0].User)
+	assert.False(t, ops[0].Changes[0].Time.IsZero())
+}
+
+func TestAppendSealsWhenEncrypted(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(seal.EnvPassphrase, "secret")
+	_, err := seal.Setup(home, seal.KeyPassphrase, "secret")
+	require.NoError(t, err)
+
+	Begin("log add")
+	require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "p", ID: "aaa1111", After: []byte(`{"message":"ACME-123 on feature/acme"}`)}))
+
+	data, err := os.ReadFile(Path(home))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "ACME")
+	assert.NotContains(t, string(data), "feature/acme")
+	info, err := os.Stat(Path(home))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	changes, err := Read(home)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Contains(t, string(changes[0].After), "ACME-123")
+}
+
+func TestReadMissingJournal(t *testing.T) {
+	changes, err := Read(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestReadSkipsTornLines(t *testing.T) {
+	home := t.TempDir()
+
+	Begin("log add")
+	require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "p", ID: "aaa1111", After: []byte(`{}`)}))
+	f, err := os.OpenFile(Path(home), os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"op":"x","kind":"en`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	changes, err := Read(home)
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+}
+
+func TestUndoableSkipsRevertedUndoAndAutomaticOperations(t *testing.T) {
+	home := t.TempDir()
+
+	Begin("log add")
+	require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "p", ID: "aaa1111", After: []byte(`{}`)}))
+	Begin("log edit")
+	require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "p", ID: "aaa1111", Before: []byte(`{}`), After: []byte(`{"m":1}`)}))
+	Begin("watch")
+	require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "p", ID: "bbb2222", After: []byte(`{}`)}))
+	Begin("sync")
+	require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "p", ID: "ccc3333", After: []byte(`{}`)}))
+	Begin(CommandAutoMigrate)
+	require.NoError(t, Append(home, Change{Kind: KindConfig, Before: []byte(`{}`), After: []byte(`{"v":2}`)}))
+
+	ops, err := Undoable(home)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	assert.Equal(t, "log edit", ops[0].Command)
+	assert.Equal(t, "log add", ops[1].Command)
+
+	BeginUndo(ops[0].ID)
+	require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "p", ID: "aaa1111", Before: []byte(`{"m":1}`), After: []byte(`{}`)}))
+
+	ops, err = Undoable(home)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	assert.Equal(t, "log add", ops[0].Command)
+}
+
+func TestEntryHistoryAndFindEntries(t *testing.T) {
+	home := t.TempDir()
+
+	Begin("log add")
+	require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "p", ID: "abc1234567", After: []byte(`{}`)}))
+	require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "q", ID: "abc1299999", After: []byte(`{}`)}))
+	Begin("log remove")
+	require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "p", ID: "abc1234567", Before: []byte(`{}`)}))
+
+	history, err := EntryHistory(home, "p", "abc1234567")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "log remove", history[1].Command)
+
+	refs, err := FindEntries(home, "abc12")
+	require.NoError(t, err)
+	assert.Len(t, refs, 2)
+
+	refs, err = FindEntries(home, "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, []EntryRef{{Slug: "p", ID: "abc1234567"}}, refs)
+}
+
+func TestEqualIgnoresFormatting(t *testing.T) {
+	assert.True(t, Equal([]byte("{\n  \"a\": 1\n}"), []byte(`{"a":1}`)))
+	assert.False(t, Equal([]byte(`{"a":1}`), []byte(`{"a":2}`)))
+}
+
+func TestAppendTrimsOldestOperations(t *testing.T) {
+	home := t.TempDir()
+	defer func(size int64) { maxSize = size }(maxSize)
+	maxSize = 2000
+
+	for i := range 20 {
+		Begin(fmt.Sprintf("log add %d", i))
+		require.NoError(t, Append(home, Change{Kind: KindEntry, Slug: "p", ID: fmt.Sprintf("aaa%04d", i), After: []byte(`{"message":"some work"}`)}))
+		require.NoError(t, Append(home, Change{Kind: KindConfig, After: []byte(`{"projects":[]}`)}))
+	}
+
+	info, err := os.Stat(Path(home))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, info.Size(), maxSize)
+
+	ops, err := Operations(home)
+	require.NoError(t, err)
+	require.NotEmpty(t, ops)
+	assert.Less(t, len(ops), 20)
+	assert.Equal(t, "log add 19", ops[len(ops)-1].Command)
+	for _, op := range ops {
+		// Operations are dropped whole
+		assert.Len(t, op.Changes, 2, op.Command)
+	}
+}
@@ -0,0 +1,200 @@
+// Package paths decides where hourgit keeps its files. By default it follows
+// the XDG base directory conventions: config in $XDG_CONFIG_HOME/hourgit,
+// entries in $XDG_DATA_HOME/hourgit, watcher state in $XDG_STATE_HOME/hourgit
+// and the watcher PID file in $XDG_RUNTIME_DIR/hourgit. HOURGIT_HOME puts
+// everything in one directory instead, e.g. to keep a separate data root per
+// client.
+//
+// Every function that touches hourgit files takes the user's home directory.
+// The layout for a home directory is derived from it alone, unless the
+// command line registered the environment's layout for it with Use — so
+// tests working in a temporary home never see the real environment.
+package paths
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// EnvHome is the environment variable holding a single data root.
+const EnvHome = "HOURGIT_HOME"
+
+// appName is the subdirectory used in each XDG base directory.
+const appName = "hourgit"
+
+// envVars are the variables that choose a layout. They are passed on to the
+// watcher service so it uses the same files.
+var envVars = []string{EnvHome, "XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_STATE_HOME"}
+
+// Layout holds the directories hourgit uses.
+type Layout struct {
+	Config     string   // config.json
+	Data       string   // project entries, journal, backups
+	State      string   // watcher state
+	Runtime    string   // watcher PID file
+	ConfigHome string   // the XDG config home, for other programs' files such as systemd units
+	Env        []string // KEY=value for each variable that chose this layout
+}
+
+// Resolve returns the layout for homeDir given an environment lookup.
+// Relative XDG paths are ignored, as the specification requires.
+func Resolve(homeDir string, getenv func(string) string) Layout {
+	xdg := func(name string, fallback ...string) string {
+		if dir := getenv(name); filepath.IsAbs(dir) {
+			return dir
+		}
+		return filepath.Join(append([]string{homeDir}, fallback...)...)
+	}
+
+	l := Layout{ConfigHome: xdg("XDG_CONFIG_HOME", ".config")}
+	for _, name := range envVars {
+		if v := getenv(name); v != "" {
+			l.Env = append(l.Env, name+"="+v)
+		}
+	}
+
+	if root := getenv(EnvHome); root != "" {
+		if abs, err := filepath.Abs(root); err == nil {
+			root = abs
+		}
+		l.Config, l.Data, l.State, l.Runtime = root, root, root, root
+		return l
+	}
+
+	l.Config = filepath.Join(l.ConfigHome, appName)
+	l.Data = filepath.Join(xdg("XDG_DATA_HOME", ".local", "share"), appName)
+	l.State = filepath.Join(xdg("XDG_STATE_HOME", ".local", "state"), appName)
+	l.Runtime = l.State
+	if dir := getenv("XDG_RUNTIME_DIR"); filepath.IsAbs(dir) {
+		l.Runtime = filepath.Join(dir, appName)
+	}
+	return l
+}
+
+// Default returns the layout for homeDir without any environment overrides.
+func Default(homeDir string) Layout {
+	return Resolve(homeDir, func(string) string { return "" })
+}
+
+var (
+	layoutsMu sync.Mutex
+	layouts   = make(map[string]Layout)
+)
+
+// Use makes For(homeDir) return l. Call the returned function to restore the
+// default.
+func Use(homeDir string, l Layout) (restore func()) {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+	layouts[homeDir] = l
+	return func() {
+		layoutsMu.Lock()
+		defer layoutsMu.Unlock()
+		delete(layouts, homeDir)
+	}
+}
+
+// For returns the layout registered for homeDir, or its default layout.
+func For(homeDir string) Layout {
+	layoutsMu.Lock()
+	l, ok := layouts[homeDir]
+	layoutsMu.Unlock()
+	if ok {
+		return l
+	}
+	return Default(homeDir)
+}
+
+// LegacyDir returns ~/.hourgit, where versions before XDG support kept
+// everything. The installer still keeps the binary in its bin/ directory.
+func LegacyDir(homeDir string) string {
+	return filepath.Join(homeDir, ".hourgit")
+}
+
+// Files of the legacy directory that don't belong in the data directory. bin/
+// and downloads/ belong to the installer.
+const (
+	legacyConfig    = "config.json"
+	legacyState     = "watch.state"
+	legacyPID       = "watch.pid"
+	legacyBin       = "bin"
+	legacyDownloads = "downloads"
+)
+
+// MigrateLegacy moves data from the legacy directory into l: the config to
+// l.Config, watcher state and PID file to l.State and l.Runtime, and
+// everything else — projects, journal, backups — to l.Data. The installer's
+// files and lock files stay. Nothing happens when l already has a config or
+// the legacy directory has none. The config moves last, so an interrupted
+// migration is picked up again by the next run. Returns whether data moved.
+func MigrateLegacy(homeDir string, l Layout) (bool, error) {
+	legacy := LegacyDir(homeDir)
+	if l.Data == legacy || l.Config == legacy {
+		return false, nil
+	}
+	if _, err := os.Stat(filepath.Join(legacy, legacyConfig)); err != nil {
+		return false, nil
+	}
+	if _, err := os.Stat(filepath.Join(l.Config, legacyConfig)); err == nil {
+		return false, nil
+	}
+
+	items, err := os.ReadDir(legacy)
+	if err != nil {
+		return false, err
+	}
+	for _, item := range items {
+		name := item.Name()
+		var dest string
+		switch {
+		case name == legacyConfig || name == legacyBin || name == legacyDownloads || strings.HasSuffix(name, ".lock"):
+			continue
+		case name == legacyState:
+			dest = filepath.Join(l.State, name)
+		case name == legacyPID:
+			dest = filepath.Join(l.Runtime, name)
+		default:
+			dest = filepath.Join(l.Data, name)
+		}
+		if err := move(filepath.Join(legacy, name), dest); err != nil {
+			return false, err
+		}
+	}
+	if err := move(filepath.Join(legacy, legacyConfig), filepath.Join(l.Config, legacyConfig)); err != nil {
+		return false, err
+	}
+
+	// Leave the directory only if the installer still needs it
+	if rest, err := os.ReadDir(legacy); err == nil {
+		onlyLocks := true
+		for _, item := range rest {
+			if !strings.HasSuffix(item.Name(), ".lock") {
+				onlyLocks = false
+			}
+		}
+		if onlyLocks {
+			_ = os.RemoveAll(legacy)
+		}
+	}
+	return true, nil
+}
+
+// move renames src to dest, creating dest's parent directory.
+func move(src, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("cannot move %s: %s already exists", src, dest)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dest); err != nil {
+		return fmt.Errorf("cannot move %s to %s: %w (move it by hand, or set %s=%s to keep using it)", src, dest, err, EnvHome, filepath.Dir(src))
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestResolveDefaults(t *testing.T) {
+	l := Default("/home/user")
+
+	assert.Equal(t, "/home/user/.config/hourgit", l.Config)
+	assert.Equal(t, "/home/user/.local/share/hourgit", l.Data)
+	assert.Equal(t, "/home/user/.local/state/hourgit", l.State)
+	assert.Equal(t, "/home/user/.local/state/hourgit", l.Runtime)
+	assert.Equal(t, "/home/user/.config", l.ConfigHome)
+	assert.Empty(t, l.Env)
+}
+
+func TestResolveXDG(t *testing.T) {
+	l := Resolve("/home/user", env(map[string]string{
+		"XDG_CONFIG_HOME": "/cfg",
+		"XDG_DATA_HOME":   "/data",
+		"XDG_STATE_HOME":  "relative/state",
+		"XDG_RUNTIME_DIR": "/run/user/1000",
+	}))
+
+	assert.Equal(t, "/cfg/hourgit", l.Config)
+	assert.Equal(t, "/data/hourgit", l.Data)
+	assert.Equal(t, "/home/user/.local/state/hourgit", l.State, "relative paths are ignored")
+	assert.Equal(t, "/run/user/1000/hourgit", l.Runtime)
+	assert.Equal(t, []string{"XDG_CONFIG_HOME=/cfg", "XDG_DATA_HOME=/data", "XDG_STATE_HOME=relative/state"}, l.Env)
+}
+
+func TestResolveHourgitHome(t *testing.T) {
+	l := Resolve("/home/user", env(map[string]string{
+		EnvHome:         "/clients/acme",
+		"XDG_DATA_HOME": "/data",
+	}))
+
+	assert.Equal(t, "/clients/acme", l.Config)
+	assert.Equal(t, "/clients/acme", l.Data)
+	assert.Equal(t, "/clients/acme", l.State)
+	assert.Equal(t, "/clients/acme", l.Runtime)
+	assert.Contains(t, l.Env, "HOURGIT_HOME=/clients/acme")
+}
+
+func TestUseOverridesLayout(t *testing.T) {
+	custom := Layout{Config: "/x", Data: "/x", State: "/x", Runtime: "/x"}
+	restore := Use("/home/user", custom)
+	assert.Equal(t, custom, For("/home/user"))
+	assert.Equal(t, Default("/home/other"), For("/home/other"))
+
+	restore()
+	assert.Equal(t, Default("/home/user"), For("/home/user"))
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	home := t.TempDir()
+	legacy := LegacyDir(home)
+	for _, dir := range []string{"acme", "bin", "downloads", ".backups"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(legacy, dir), 0755))
+	}
+	for _, name := range []string{"config.json", "journal.jsonl", "watch.state", "watch.pid", "config.lock", "acme/aaaa11112222", "bin/hourgit"} {
+		require.NoError(t, os.WriteFile(filepath.Join(legacy, name), []byte(name), 0644))
+	}
+	l := Default(home)
+
+	moved, err := MigrateLegacy(home, l)
+	require.NoError(t, err)
+	assert.True(t, moved)
+
+	assert.FileExists(t, filepath.Join(l.Config, "config.json"))
+	assert.FileExists(t, filepath.Join(l.Data, "journal.jsonl"))
+	assert.FileExists(t, filepath.Join(l.Data, "acme", "aaaa11112222"))
+	assert.DirExists(t, filepath.Join(l.Data, ".backups"))
+	assert.FileExists(t, filepath.Join(l.State, "watch.state"))
+	assert.FileExists(t, filepath.Join(l.Runtime, "watch.pid"))
+	assert.FileExists(t, filepath.Join(legacy, "bin", "hourgit"), "the installed binary stays")
+	assert.DirExists(t, filepath.Join(legacy, "downloads"))
+	assert.NoFileExists(t, filepath.Join(legacy, "config.json"))
+
+	moved, err = MigrateLegacy(home, l)
+	require.NoError(t, err)
+	assert.False(t, moved)
+}
+
+func TestMigrateLegacyRemovesEmptyDir(t *testing.T) {
+	home := t.TempDir()
+	legacy := LegacyDir(home)
+	require.NoError(t, os.MkdirAll(legacy, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "config.json"), []byte("{}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "config.lock"), nil, 0644))
+
+	moved, err := MigrateLegacy(home, Default(home))
+	require.NoError(t, err)
+	assert.True(t, moved)
+	assert.NoDirExists(t, legacy)
+}
+
+func TestMigrateLegacySkipsWhenNotNeeded(t *testing.T) {
+	home := t.TempDir()
+	legacy := LegacyDir(home)
+	require.NoError(t, os.MkdirAll(legacy, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "config.json"), []byte("{}"), 0644))
+
+	// HOURGIT_HOME pointing at the legacy directory keeps using it
+	moved, err := MigrateLegacy(home, Layout{Config: legacy, Data: legacy, State: legacy, Runtime: legacy})
+	require.NoError(t, err)
+	assert.False(t, moved)
+
+	// A config in the new location wins
+	l := Default(home)
+	require.NoError(t, os.MkdirAll(l.Config, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(l.Config, "config.json"), []byte("{}"), 0644))
+	moved, err = MigrateLegacy(home, l)
+	require.NoError(t, err)
+	assert.False(t, moved)
+	assert.FileExists(t, filepath.Join(legacy, "config.json"))
+}
+
+func TestMigrateLegacyRefusesToOverwrite(t *testing.T) {
+	home := t.TempDir()
+	legacy := LegacyDir(home)
+	require.NoError(t, os.MkdirAll(filepath.Join(legacy, "acme"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "config.json"), []byte("{}"), 0644))
+	l := Default(home)
+	require.NoError(t, os.MkdirAll(filepath.Join(l.Data, "acme"), 0755))
+
+	_, err := MigrateLegacy(home, l)
+	assert.ErrorContains(t, err, "already exists")
+	assert.FileExists(t, filepath.Join(legacy, "config.json"), "the config stays until everything moved")
+}
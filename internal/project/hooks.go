@@ -0,0 +1,63 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HookNames are the git hooks hourgit installs: post-checkout records branch
+// switches, the others sync commits, merges and rebases as they happen.
+var HookNames = []string{"post-checkout", "post-commit", "post-merge", "post-rewrite"}
+
+// Hook managers that own a repository's git hooks.
+const (
+	HookManagerHusky     = "husky"
+	HookManagerLefthook  = "lefthook"
+	HookManagerPreCommit = "pre-commit"
+)
+
+// lefthookConfigs are the config files lefthook reads, in its order.
+var lefthookConfigs = []string{"lefthook.yml", ".lefthook.yml", "lefthook.yaml", ".lefthook.yaml"}
+
+// HookManager is a tool that owns a repository's git hooks, so hourgit's
+// hooks go into its config rather than the hooks directory.
+type HookManager struct {
+	Name   string // HookManagerHusky, HookManagerLefthook or HookManagerPreCommit
+	Config string // the file hourgit's post-checkout hook goes in
+}
+
+// DetectHookManager returns the hook manager of the repository at repoDir,
+// or nil: husky when core.hooksPath points into .husky, lefthook or
+// pre-commit when their config file exists.
+func DetectHookManager(repoDir string) *HookManager {
+	if rel, err := filepath.Rel(repoDir, repoGitDirs(repoDir).Hooks); err == nil &&
+		(rel == ".husky" || strings.HasPrefix(rel, ".husky"+string(filepath.Separator))) {
+		return &HookManager{Name: HookManagerHusky, Config: filepath.Join(repoDir, ".husky", "post-checkout")}
+	}
+	for _, name := range lefthookConfigs {
+		if path := filepath.Join(repoDir, name); fileExists(path) {
+			return &HookManager{Name: HookManagerLefthook, Config: path}
+		}
+	}
+	if path := filepath.Join(repoDir, ".pre-commit-config.yaml"); fileExists(path) {
+		return &HookManager{Name: HookManagerPreCommit, Config: path}
+	}
+	return nil
+}
+
+// HookInstalled reports whether hourgit's post-checkout hook is installed in
+// repoDir: in its hooks directory, or in the config of its hook manager.
+func HookInstalled(repoDir string) bool {
+	path := HookPath(repoDir, "post-checkout")
+	if m := DetectHookManager(repoDir); m != nil {
+		path = m.Config
+	}
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), HookMarker)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
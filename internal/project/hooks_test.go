@@ -0,0 +1,64 @@
+package project
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectHookManager(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git", "hooks"), 0755))
+
+	assert.Nil(t, DetectHookManager(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".pre-commit-config.yaml"), []byte("repos: []\n"), 0644))
+	m := DetectHookManager(dir)
+	require.NotNil(t, m)
+	assert.Equal(t, HookManagerPreCommit, m.Name)
+
+	// lefthook wins over pre-commit, which it typically runs
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lefthook.yml"), []byte("pre-commit:\n"), 0644))
+	m = DetectHookManager(dir)
+	require.NotNil(t, m)
+	assert.Equal(t, HookManagerLefthook, m.Name)
+	assert.Equal(t, filepath.Join(dir, "lefthook.yml"), m.Config)
+}
+
+func TestDetectHookManagerHusky(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	for _, args := range [][]string{{"init", "-q"}, {"config", "core.hooksPath", ".husky/_"}} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	m := DetectHookManager(dir)
+
+	require.NotNil(t, m)
+	assert.Equal(t, HookManagerHusky, m.Name)
+	assert.Equal(t, filepath.Join(dir, ".husky", "post-checkout"), m.Config)
+}
+
+func TestHookInstalled(t *testing.T) {
+	dir := t.TempDir()
+	hooksDir := filepath.Join(dir, ".git", "hooks")
+	require.NoError(t, os.MkdirAll(hooksDir, 0755))
+
+	assert.False(t, HookInstalled(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(hooksDir, "post-checkout"), []byte("#!/bin/sh\n"+HookMarker+"\n"), 0755))
+	assert.True(t, HookInstalled(dir))
+
+	// With a hook manager, the hook has to be in the manager's config
+	config := filepath.Join(dir, "lefthook.yml")
+	require.NoError(t, os.WriteFile(config, []byte("pre-commit:\n"), 0644))
+	assert.False(t, HookInstalled(dir))
+
+	require.NoError(t, os.WriteFile(config, []byte(HookMarker+"\npost-checkout:\n"), 0644))
+	assert.True(t, HookInstalled(dir))
+}
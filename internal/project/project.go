@@ -6,30 +6,44 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/gitdir"
 	"github.com/Flyrell/hourgit/internal/hashutil"
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/Flyrell/hourgit/internal/paths"
+	"github.com/Flyrell/hourgit/internal/rounding"
+	"github.com/Flyrell/hourgit/internal/rules"
 	"github.com/Flyrell/hourgit/internal/schedule"
+	"github.com/Flyrell/hourgit/internal/seal"
 	"github.com/Flyrell/hourgit/internal/stringutil"
 )
 
-// HookMarker is the comment marker written into the post-checkout hook.
+// HookMarker is the comment marker written into the hooks hourgit installs.
 // Use this constant for detection — never redefine it elsewhere.
 const HookMarker = "# Installed by hourgit"
 
 var appVersion = "dev"
 
+// SchemaVersion is the current layout version of config.json, entry files and
+// repo markers. Bump it together with a new migration in internal/schema.
+const SchemaVersion = 2
+
 // SetVersion sets the app version stamped into config.json on write.
 func SetVersion(v string) {
 	appVersion = v
 }
 
-// RepoConfig is the per-repo marker stored in .git/.hourgit.
+// RepoConfig is the per-repo marker stored in .git/.hourgit — in the git dir
+// of each work tree, so linked worktrees keep their own sync state.
 type RepoConfig struct {
-	Project   string     `json:"project"`
-	ProjectID string     `json:"project_id,omitempty"`
-	LastSync  *time.Time `json:"last_sync,omitempty"`
+	SchemaVersion int        `json:"schema_version,omitempty"`
+	Project       string     `json:"project"`
+	ProjectID     string     `json:"project_id,omitempty"`
+	LastSync      *time.Time `json:"last_sync,omitempty"`
 }
 
 // ProjectEntry represents a single project in the global registry.
@@ -41,44 +55,99 @@ type ProjectEntry struct {
 	Schedules            []schedule.ScheduleEntry `json:"schedules,omitempty"`
 	Precise              bool                     `json:"precise,omitempty"`
 	IdleThresholdMinutes int                      `json:"idle_threshold_minutes,omitempty"`
+	Timezone             string                   `json:"timezone,omitempty"`
+	Rounding             rounding.Policy          `json:"rounding,omitzero"`
+	Overtime             string                   `json:"overtime,omitempty"`
+	BalanceStart         string                   `json:"balance_start,omitempty"`
+	RepoMerge            string                   `json:"repo_merge,omitempty"`
+	Priority             int                      `json:"priority,omitempty"`
+	Rules                []rules.Rule             `json:"rules,omitempty"`
+	Attribution          Attribution              `json:"attribution,omitzero"`
+	Submodules           string                   `json:"submodules,omitempty"`
 }
 
 // Config holds the global hourgit configuration including projects and defaults.
 type Config struct {
 	Version         string                   `json:"version"`
+	SchemaVersion   int                      `json:"schema_version,omitempty"`
 	Defaults        []schedule.ScheduleEntry `json:"defaults"`
 	Projects        []ProjectEntry           `json:"projects"`
 	LastUpdateCheck *time.Time               `json:"last_update_check,omitempty"`
 	LatestVersion   string                   `json:"latest_version,omitempty"`
+	Storage         string                   `json:"storage,omitempty"`
+	Overlap         string                   `json:"overlap,omitempty"`
+}
+
+// Storage backends for entry data.
+const (
+	StorageFiles    = "files"    // one JSON file per entry (default)
+	StorageSegments = "segments" // append-only JSONL segment per month
+)
+
+// GetStorage returns the configured storage backend, defaulting to StorageFiles.
+func GetStorage(cfg *Config) string {
+	if cfg.Storage == "" {
+		return StorageFiles
+	}
+	return cfg.Storage
+}
+
+// SetStorage records the storage backend in the config.
+func SetStorage(homeDir, storage string) error {
+	if storage != StorageFiles && storage != StorageSegments {
+		return fmt.Errorf("unknown storage backend %q (supported: %s, %s)", storage, StorageFiles, StorageSegments)
+	}
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		cfg.Storage = storage
+		return nil
+	})
 }
 
-// HourgitDir returns the global hourgit config directory.
-func HourgitDir(homeDir string) string {
-	return filepath.Join(homeDir, ".hourgit")
+// ConfigDir returns the directory holding config.json.
+func ConfigDir(homeDir string) string {
+	return paths.For(homeDir).Config
+}
+
+// DataDir returns the directory holding project entries and the journal.
+func DataDir(homeDir string) string {
+	return paths.For(homeDir).Data
 }
 
 // ConfigPath returns the path to the global config.json.
 func ConfigPath(homeDir string) string {
-	return filepath.Join(HourgitDir(homeDir), "config.json")
+	return filepath.Join(ConfigDir(homeDir), "config.json")
+}
+
+// configLockPath returns the path to the lock file guarding config.json.
+func configLockPath(homeDir string) string {
+	return filepath.Join(ConfigDir(homeDir), "config.lock")
 }
 
 // LogDir returns the directory for a project's log entries.
 func LogDir(homeDir, slug string) string {
-	return filepath.Join(HourgitDir(homeDir), slug)
+	return filepath.Join(DataDir(homeDir), slug)
 }
 
+// ConfigSealContext binds a sealed config.json to the config.
+const ConfigSealContext = "config"
+
 // ReadConfig reads the global hourgit configuration.
 // Returns a fresh config with factory defaults if the file does not exist.
+// A config without a schema version predates versioning (version 0).
 func ReadConfig(homeDir string) (*Config, error) {
 	data, err := os.ReadFile(ConfigPath(homeDir))
 	if errors.Is(err, os.ErrNotExist) {
 		return &Config{
-			Defaults: schedule.DefaultSchedules(),
+			SchemaVersion: SchemaVersion,
+			Defaults:      schedule.DefaultSchedules(),
 		}, nil
 	}
 	if err != nil {
 		return nil, err
 	}
+	if data, err = seal.Open(homeDir, data, ConfigSealContext); err != nil {
+		return nil, err
+	}
 
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
@@ -87,9 +156,10 @@ func ReadConfig(homeDir string) (*Config, error) {
 	return &cfg, nil
 }
 
-// WriteConfig writes the global hourgit configuration, creating the directory if needed.
+// WriteConfig atomically writes the global hourgit configuration, creating the
+// directory if needed. Use UpdateConfig for read-modify-write changes.
 func WriteConfig(homeDir string, cfg *Config) error {
-	dir := HourgitDir(homeDir)
+	dir := ConfigDir(homeDir)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
@@ -100,7 +170,100 @@ func WriteConfig(homeDir string, cfg *Config) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(ConfigPath(homeDir), data, 0644)
+	if data, err = seal.Seal(homeDir, data, ConfigSealContext); err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(ConfigPath(homeDir), data, 0600)
+}
+
+// ResealConfig rewrites config.json sealed or plain, whichever homeDir
+// currently uses. Returns whether it changed.
+func ResealConfig(homeDir string) (bool, error) {
+	sealer, err := seal.Load(homeDir)
+	if err != nil {
+		return false, err
+	}
+	var changed bool
+	err = fsutil.WithLock(configLockPath(homeDir), func() error {
+		var err error
+		changed, err = sealer.RewriteFile(ConfigPath(homeDir), ConfigSealContext)
+		return err
+	})
+	return changed, err
+}
+
+// UpdateConfig reads the config, applies fn and writes the result while
+// holding the config lock, so concurrent hourgit processes cannot lose each
+// other's changes. Nothing is written if fn returns an error. Significant
+// changes are recorded in the operation journal.
+func UpdateConfig(homeDir string, fn func(cfg *Config) error) error {
+	return fsutil.WithLock(configLockPath(homeDir), func() error {
+		cfg, err := ReadConfig(homeDir)
+		if err != nil {
+			return err
+		}
+		before, err := journalSnapshot(cfg)
+		if err != nil {
+			return err
+		}
+		if err := fn(cfg); err != nil {
+			return err
+		}
+		if err := WriteConfig(homeDir, cfg); err != nil {
+			return err
+		}
+
+		after, err := journalSnapshot(cfg)
+		if err != nil {
+			return err
+		}
+		if journal.Equal(before, after) {
+			return nil
+		}
+		return journal.Append(homeDir, journal.Change{
+			Kind:   journal.KindConfig,
+			Before: before,
+			After:  after,
+		})
+	})
+}
+
+// journalSnapshot returns the config as recorded in the operation journal.
+// Bookkeeping fields (version stamps, update check) are left out so they never
+// count as a change or block an undo.
+func journalSnapshot(cfg *Config) ([]byte, error) {
+	c := *cfg
+	c.Version = ""
+	c.SchemaVersion = 0
+	c.LastUpdateCheck = nil
+	c.LatestVersion = ""
+	return json.Marshal(c)
+}
+
+// ConfigSnapshot returns the current config in the form recorded by the
+// operation journal.
+func ConfigSnapshot(homeDir string) ([]byte, error) {
+	cfg, err := ReadConfig(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	return journalSnapshot(cfg)
+}
+
+// RestoreConfig replaces the config with a journal snapshot, keeping the
+// current bookkeeping fields. The restore is itself journaled.
+func RestoreConfig(homeDir string, snapshot []byte) error {
+	var restored Config
+	if err := json.Unmarshal(snapshot, &restored); err != nil {
+		return fmt.Errorf("invalid config snapshot: %w", err)
+	}
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		restored.SchemaVersion = cfg.SchemaVersion
+		restored.LastUpdateCheck = cfg.LastUpdateCheck
+		restored.LatestVersion = cfg.LatestVersion
+		*cfg = restored
+		return nil
+	})
 }
 
 // FindProject looks up a project by name in the config.
@@ -164,10 +327,48 @@ func ResolveOrCreate(homeDir, identifier string, promptCreate func(name string)
 	return &ResolveOrCreateResult{Entry: entry, Created: true}, nil
 }
 
+// repoGitDirs returns the git directories of repoDir, assuming a plain
+// repository at repoDir/.git when they cannot be resolved.
+func repoGitDirs(repoDir string) gitdir.Dirs {
+	d, err := gitdir.Resolve(repoDir)
+	if err != nil {
+		dotGit := filepath.Join(repoDir, ".git")
+		return gitdir.Dirs{Git: dotGit, Common: dotGit, Hooks: filepath.Join(dotGit, "hooks")}
+	}
+	return d
+}
+
+// RepoConfigPath returns the path of the per-repo hourgit config of the work
+// tree at repoDir: .hourgit in its git dir.
+func RepoConfigPath(repoDir string) string {
+	return filepath.Join(repoGitDirs(repoDir).Git, ".hourgit")
+}
+
+// HookPath returns the path of the named git hook of repoDir, in the hooks
+// directory git runs: core.hooksPath when set, else the hooks of the common
+// git dir, shared by all worktrees of a repository.
+func HookPath(repoDir, name string) string {
+	return filepath.Join(repoGitDirs(repoDir).Hooks, name)
+}
+
 // ReadRepoConfig reads the per-repo hourgit config from .git/.hourgit.
-// Returns nil if the file does not exist.
+// A linked worktree without a config of its own inherits the assignment of
+// the main worktree, but not its sync state. Returns nil if there is none.
 func ReadRepoConfig(repoDir string) (*RepoConfig, error) {
-	data, err := os.ReadFile(filepath.Join(repoDir, ".git", ".hourgit"))
+	d := repoGitDirs(repoDir)
+	rc, err := readRepoConfigFile(filepath.Join(d.Git, ".hourgit"))
+	if rc != nil || err != nil || !d.Worktree() {
+		return rc, err
+	}
+	rc, err = readRepoConfigFile(filepath.Join(d.Common, ".hourgit"))
+	if rc != nil {
+		rc.LastSync = nil
+	}
+	return rc, err
+}
+
+func readRepoConfigFile(path string) (*RepoConfig, error) {
+	data, err := os.ReadFile(path)
 	if errors.Is(err, os.ErrNotExist) {
 		return nil, nil
 	}
@@ -182,13 +383,19 @@ func ReadRepoConfig(repoDir string) (*RepoConfig, error) {
 	return &rc, nil
 }
 
-// WriteRepoConfig writes the per-repo hourgit config to .git/.hourgit.
+// WriteRepoConfig writes the per-repo hourgit config to .git/.hourgit,
+// stamped with the current schema version.
 func WriteRepoConfig(repoDir string, rc *RepoConfig) error {
+	return writeRepoConfigFile(RepoConfigPath(repoDir), rc)
+}
+
+func writeRepoConfigFile(path string, rc *RepoConfig) error {
+	rc.SchemaVersion = SchemaVersion
 	data, err := json.MarshalIndent(rc, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(repoDir, ".git", ".hourgit"), data, 0644)
+	return fsutil.WriteFileAtomic(path, data, 0600)
 }
 
 // RemoveRepoFromProject removes repoDir from the project's repos list.
@@ -205,91 +412,112 @@ func RemoveRepoFromProject(entry *ProjectEntry, repoDir string) {
 // CreateProject creates a new project in the config.
 // Returns an error if a project with the same name already exists.
 func CreateProject(homeDir, name string) (*ProjectEntry, error) {
-	cfg, err := ReadConfig(homeDir)
-	if err != nil {
-		return nil, err
-	}
-
-	if existing := FindProject(cfg, name); existing != nil {
-		return nil, fmt.Errorf("project '%s' already exists (%s)", name, existing.ID)
-	}
-
-	entry := ProjectEntry{
-		ID:        hashutil.GenerateID(name),
-		Name:      name,
-		Slug:      stringutil.Slugify(name),
-		Repos:     []string{},
-		Schedules: GetDefaults(cfg),
-	}
-	cfg.Projects = append(cfg.Projects, entry)
+	var entry ProjectEntry
+	err := UpdateConfig(homeDir, func(cfg *Config) error {
+		if existing := FindProject(cfg, name); existing != nil {
+			return fmt.Errorf("project '%s' already exists (%s)", name, existing.ID)
+		}
 
-	if err := os.MkdirAll(LogDir(homeDir, entry.Slug), 0755); err != nil {
-		return nil, err
-	}
+		entry = ProjectEntry{
+			ID:        newProjectID(cfg, hashutil.TimeSeed(name)),
+			Name:      name,
+			Slug:      stringutil.Slugify(name),
+			Repos:     []string{},
+			Schedules: GetDefaults(cfg),
+		}
+		cfg.Projects = append(cfg.Projects, entry)
 
-	if err := WriteConfig(homeDir, cfg); err != nil {
+		return os.MkdirAll(LogDir(homeDir, entry.Slug), 0755)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return &entry, nil
 }
 
-// AssignProject assigns a repository to an existing project.
-// It adds repoDir to the project's repos list (deduplicated) and writes the per-repo config.
-func AssignProject(homeDir, repoDir string, entry *ProjectEntry) error {
-	cfg, err := ReadConfig(homeDir)
-	if err != nil {
-		return err
+// projectIDLength is the length of new project IDs, short enough to type.
+const projectIDLength = 7
+
+// newProjectID returns an ID for a new project. Like entry IDs, it is a
+// prefix of seed's hash, extended one character at a time while it matches
+// a project's ID or name, which ResolveProject would confuse it with.
+func newProjectID(cfg *Config, seed string) string {
+	hash := hashutil.Hash(seed)
+	for n := projectIDLength; n < len(hash); n++ {
+		if ResolveProject(cfg, hash[:n]) == nil {
+			return hash[:n]
+		}
 	}
+	return hash
+}
 
-	cfgEntry := FindProjectByID(cfg, entry.ID)
-	if cfgEntry == nil {
-		return fmt.Errorf("project '%s' not found in registry", entry.Name)
-	}
+// AssignProject assigns a repository to an existing project.
+// It adds repoDir to the project's repos list (deduplicated) and writes the
+// per-repo config. Assigning a linked worktree also assigns the main one
+// when that has no config yet, so the repository's other worktrees follow.
+func AssignProject(homeDir, repoDir string, entry *ProjectEntry) error {
+	var rc RepoConfig
+	err := UpdateConfig(homeDir, func(cfg *Config) error {
+		cfgEntry := FindProjectByID(cfg, entry.ID)
+		if cfgEntry == nil {
+			return fmt.Errorf("project '%s' not found in registry", entry.Name)
+		}
 
-	// Add repo if not already present
-	found := false
-	for _, r := range cfgEntry.Repos {
-		if r == repoDir {
-			found = true
-			break
+		// Add repo if not already present
+		found := false
+		for _, r := range cfgEntry.Repos {
+			if r == repoDir {
+				found = true
+				break
+			}
+		}
+		if !found {
+			cfgEntry.Repos = append(cfgEntry.Repos, repoDir)
 		}
-	}
-	if !found {
-		cfgEntry.Repos = append(cfgEntry.Repos, repoDir)
-	}
 
-	if err := WriteConfig(homeDir, cfg); err != nil {
+		rc = RepoConfig{Project: cfgEntry.Name, ProjectID: cfgEntry.ID}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	return WriteRepoConfig(repoDir, &RepoConfig{Project: cfgEntry.Name, ProjectID: cfgEntry.ID})
+	d := repoGitDirs(repoDir)
+	if d.Worktree() {
+		common := filepath.Join(d.Common, ".hourgit")
+		if existing, err := readRepoConfigFile(common); err == nil && existing == nil {
+			shared := rc
+			if err := writeRepoConfigFile(common, &shared); err != nil {
+				return err
+			}
+		}
+	}
+	return writeRepoConfigFile(filepath.Join(d.Git, ".hourgit"), &rc)
 }
 
 // RemoveProject removes a project from the config by ID or name.
 // Returns the removed entry so the caller can handle cleanup.
 func RemoveProject(homeDir, identifier string) (*ProjectEntry, error) {
-	cfg, err := ReadConfig(homeDir)
-	if err != nil {
-		return nil, err
-	}
-
-	idx := -1
-	for i := range cfg.Projects {
-		if cfg.Projects[i].ID == identifier || cfg.Projects[i].Name == identifier {
-			idx = i
-			break
+	var removed ProjectEntry
+	err := UpdateConfig(homeDir, func(cfg *Config) error {
+		idx := -1
+		for i := range cfg.Projects {
+			if cfg.Projects[i].ID == identifier || cfg.Projects[i].Name == identifier {
+				idx = i
+				break
+			}
 		}
-	}
 
-	if idx == -1 {
-		return nil, fmt.Errorf("project '%s' not found", identifier)
-	}
-
-	removed := cfg.Projects[idx]
-	cfg.Projects = append(cfg.Projects[:idx], cfg.Projects[idx+1:]...)
+		if idx == -1 {
+			return fmt.Errorf("project '%s' not found", identifier)
+		}
 
-	if err := WriteConfig(homeDir, cfg); err != nil {
+		removed = cfg.Projects[idx]
+		cfg.Projects = append(cfg.Projects[:idx], cfg.Projects[idx+1:]...)
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -298,7 +526,7 @@ func RemoveProject(homeDir, identifier string) (*ProjectEntry, error) {
 
 // RemoveRepoConfig deletes the per-repo hourgit config (.git/.hourgit).
 func RemoveRepoConfig(repoDir string) error {
-	path := filepath.Join(repoDir, ".git", ".hourgit")
+	path := RepoConfigPath(repoDir)
 	err := os.Remove(path)
 	if errors.Is(err, os.ErrNotExist) {
 		return nil
@@ -316,22 +544,18 @@ func GetDefaults(cfg *Config) []schedule.ScheduleEntry {
 
 // SetDefaults updates the default schedules in the config.
 func SetDefaults(homeDir string, schedules []schedule.ScheduleEntry) error {
-	cfg, err := ReadConfig(homeDir)
-	if err != nil {
-		return err
-	}
-	cfg.Defaults = schedules
-	return WriteConfig(homeDir, cfg)
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		cfg.Defaults = schedules
+		return nil
+	})
 }
 
 // ResetDefaults resets the default schedules to factory settings.
 func ResetDefaults(homeDir string) error {
-	cfg, err := ReadConfig(homeDir)
-	if err != nil {
-		return err
-	}
-	cfg.Defaults = schedule.DefaultSchedules()
-	return WriteConfig(homeDir, cfg)
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		cfg.Defaults = schedule.DefaultSchedules()
+		return nil
+	})
 }
 
 // GetSchedules returns the schedules for a project, falling back to defaults if empty.
@@ -345,28 +569,26 @@ func GetSchedules(cfg *Config, projectID string) []schedule.ScheduleEntry {
 
 // SetSchedules updates the schedules for a project in the config.
 func SetSchedules(homeDir, projectID string, schedules []schedule.ScheduleEntry) error {
-	cfg, err := ReadConfig(homeDir)
-	if err != nil {
-		return err
-	}
-
-	entry := FindProjectByID(cfg, projectID)
-	if entry == nil {
-		return fmt.Errorf("project '%s' not found", projectID)
-	}
-
-	entry.Schedules = schedules
-	return WriteConfig(homeDir, cfg)
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.Schedules = schedules
+		return nil
+	})
 }
 
 // ResetSchedules resets a project's schedules to the current defaults.
 func ResetSchedules(homeDir, projectID string) error {
-	cfg, err := ReadConfig(homeDir)
-	if err != nil {
-		return err
-	}
-	defaults := GetDefaults(cfg)
-	return SetSchedules(homeDir, projectID, defaults)
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.Schedules = GetDefaults(cfg)
+		return nil
+	})
 }
 
 // DefaultIdleThresholdMinutes is the default idle threshold for precise mode.
@@ -383,16 +605,14 @@ func GetPreciseMode(cfg *Config, projectID string) bool {
 
 // SetPreciseMode enables or disables precise mode for a project.
 func SetPreciseMode(homeDir, projectID string, precise bool) error {
-	cfg, err := ReadConfig(homeDir)
-	if err != nil {
-		return err
-	}
-	entry := FindProjectByID(cfg, projectID)
-	if entry == nil {
-		return fmt.Errorf("project '%s' not found", projectID)
-	}
-	entry.Precise = precise
-	return WriteConfig(homeDir, cfg)
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.Precise = precise
+		return nil
+	})
 }
 
 // GetIdleThreshold returns the idle threshold in minutes for a project.
@@ -407,16 +627,372 @@ func GetIdleThreshold(cfg *Config, projectID string) int {
 
 // SetIdleThreshold sets the idle threshold in minutes for a project.
 func SetIdleThreshold(homeDir, projectID string, minutes int) error {
-	cfg, err := ReadConfig(homeDir)
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.IdleThresholdMinutes = minutes
+		return nil
+	})
+}
+
+// Location returns the time zone a project's days are counted in: its
+// configured time zone, or the local one when none is set.
+func Location(entry *ProjectEntry) *time.Location {
+	if entry == nil || entry.Timezone == "" {
+		return time.Local
+	}
+	loc, err := schedule.LoadLocation(entry.Timezone)
 	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// SetTimezone sets the IANA time zone of a project. An empty name clears it,
+// falling back to the local time zone.
+func SetTimezone(homeDir, projectID, name string) error {
+	if name != "" {
+		if _, err := schedule.LoadLocation(name); err != nil {
+			return err
+		}
+	}
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.Timezone = name
+		return nil
+	})
+}
+
+// SetRounding sets the rounding policy of a project. The zero policy turns
+// rounding off.
+func SetRounding(homeDir, projectID string, policy rounding.Policy) error {
+	if err := policy.Validate(); err != nil {
 		return err
 	}
-	entry := FindProjectByID(cfg, projectID)
-	if entry == nil {
-		return fmt.Errorf("project '%s' not found", projectID)
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.Rounding = policy
+		return nil
+	})
+}
+
+// Overtime policies — what happens to checkout time outside schedule windows.
+const (
+	OvertimeIgnore   = "ignore"   // drop it (default)
+	OvertimeSeparate = "separate" // report it as overtime, apart from scheduled time
+	OvertimeActivity = "activity" // count it as work where precise-mode activity was recorded
+)
+
+// ValidateOvertime checks that policy is a known overtime policy. The empty
+// string is the default, ignore.
+func ValidateOvertime(policy string) error {
+	switch policy {
+	case "", OvertimeIgnore, OvertimeSeparate, OvertimeActivity:
+		return nil
+	}
+	return fmt.Errorf("invalid overtime policy %q (supported: ignore, separate, activity)", policy)
+}
+
+// SetOvertime sets the overtime policy of a project. Ignore, the default, is
+// stored as the empty string.
+func SetOvertime(homeDir, projectID, policy string) error {
+	if err := ValidateOvertime(policy); err != nil {
+		return err
+	}
+	if policy == OvertimeIgnore {
+		policy = ""
+	}
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.Overtime = policy
+		return nil
+	})
+}
+
+// Repo merge policies — who gets the time when branches of several of a
+// project's repositories are checked out at once.
+const (
+	RepoMergeLatest = "latest" // the repository checked out last (default)
+	RepoMergeSplit  = "split"  // split evenly between the repositories
+	RepoMergeActive = "active" // the repository with precise-mode activity, else the latest
+)
+
+// ValidateRepoMerge checks that policy is a known repo merge policy. The
+// empty string is the default, latest.
+func ValidateRepoMerge(policy string) error {
+	switch policy {
+	case "", RepoMergeLatest, RepoMergeSplit, RepoMergeActive:
+		return nil
+	}
+	return fmt.Errorf("invalid repo merge policy %q (supported: latest, split, active)", policy)
+}
+
+// SetRepoMerge sets the repo merge policy of a project. Latest, the default,
+// is stored as the empty string.
+func SetRepoMerge(homeDir, projectID, policy string) error {
+	if err := ValidateRepoMerge(policy); err != nil {
+		return err
+	}
+	if policy == RepoMergeLatest {
+		policy = ""
+	}
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.RepoMerge = policy
+		return nil
+	})
+}
+
+// Submodule policies — which project the checkouts of a submodule without a
+// project of its own count toward.
+const (
+	SubmodulesOwn    = "own"    // none; the submodule is a repository of its own (default)
+	SubmodulesParent = "parent" // the project of the superproject
+)
+
+// ValidateSubmodules checks that policy is a known submodule policy. The
+// empty string is the default, own.
+func ValidateSubmodules(policy string) error {
+	switch policy {
+	case "", SubmodulesOwn, SubmodulesParent:
+		return nil
+	}
+	return fmt.Errorf("invalid submodule policy %q (supported: own, parent)", policy)
+}
+
+// SetSubmodules sets the submodule policy of a project. Own, the default, is
+// stored as the empty string.
+func SetSubmodules(homeDir, projectID, policy string) error {
+	if err := ValidateSubmodules(policy); err != nil {
+		return err
+	}
+	if policy == SubmodulesOwn {
+		policy = ""
+	}
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.Submodules = policy
+		return nil
+	})
+}
+
+// Attribution strategies — how checkouts and commits become worked time.
+const (
+	AttributionCheckout = "checkout" // every scheduled minute on the branch checked out (default)
+	AttributionCommits  = "commits"  // sessions estimated from commit timestamps
+)
+
+// Defaults of the commits attribution strategy, in minutes.
+const (
+	DefaultCommitMaxGap = 120
+	DefaultCommitLeadIn = 30
+)
+
+// Attribution is the storable attribution strategy of a project. The zero
+// value attributes checkout sessions.
+type Attribution struct {
+	Strategy string `json:"strategy"`          // checkout or commits
+	MaxGap   int    `json:"max_gap,omitempty"` // minutes between two commits of one session (commits)
+	LeadIn   int    `json:"lead_in,omitempty"` // minutes of work before the first commit of a session (commits)
+}
+
+// ParseAttribution parses a strategy written as checkout or
+// commits[:MAXGAP[:LEADIN]], e.g. "commits:90:15". The gap and lead-in
+// default to DefaultCommitMaxGap and DefaultCommitLeadIn.
+func ParseAttribution(spec string) (Attribution, error) {
+	spec = strings.TrimSpace(strings.ToLower(spec))
+	if spec == "" || spec == AttributionCheckout {
+		return Attribution{}, nil
+	}
+
+	parts := strings.Split(spec, ":")
+	if parts[0] != AttributionCommits || len(parts) > 3 {
+		return Attribution{}, fmt.Errorf("invalid attribution %q (expected checkout or commits[:MAXGAP[:LEADIN]], e.g. commits:120:30)", spec)
+	}
+	a := Attribution{Strategy: AttributionCommits, MaxGap: DefaultCommitMaxGap, LeadIn: DefaultCommitLeadIn}
+	for i, target := range []*int{&a.MaxGap, &a.LeadIn} {
+		if len(parts) <= i+1 {
+			break
+		}
+		v, err := strconv.Atoi(parts[i+1])
+		if err != nil {
+			return Attribution{}, fmt.Errorf("invalid attribution minutes %q (expected a number, e.g. 120)", parts[i+1])
+		}
+		*target = v
+	}
+	return a, a.Validate()
+}
+
+// Validate checks that the strategy is either checkout or fully specified.
+func (a Attribution) Validate() error {
+	switch a.Strategy {
+	case "", AttributionCheckout:
+		return nil
+	case AttributionCommits:
+	default:
+		return fmt.Errorf("invalid attribution strategy %q (supported: checkout, commits)", a.Strategy)
+	}
+	if a.MaxGap < 1 || a.MaxGap > 24*60 {
+		return fmt.Errorf("invalid attribution max gap %d (expected 1-1440 minutes)", a.MaxGap)
+	}
+	if a.LeadIn < 0 || a.LeadIn > 24*60 {
+		return fmt.Errorf("invalid attribution lead-in %d (expected 0-1440 minutes)", a.LeadIn)
+	}
+	return nil
+}
+
+// String describes the strategy for display, e.g. "commits, 120 min gap,
+// 30 min lead-in".
+func (a Attribution) String() string {
+	if a.Strategy != AttributionCommits {
+		return AttributionCheckout
+	}
+	return fmt.Sprintf("commits, %d min gap, %d min lead-in", a.MaxGap, a.LeadIn)
+}
+
+// SetAttribution sets the attribution strategy of a project. Checkout, the
+// default, is stored as the zero value.
+func SetAttribution(homeDir, projectID string, a Attribution) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	if a.Strategy == AttributionCheckout {
+		a = Attribution{}
+	}
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.Attribution = a
+		return nil
+	})
+}
+
+// Overlap policies — which project gets the time when branches of several
+// projects are checked out at once.
+const (
+	OverlapRecent   = "recent"   // the project checked out most recently (default)
+	OverlapPriority = "priority" // the project with the highest priority, else the most recent
+	OverlapSplit    = "split"    // split evenly between the projects
+	OverlapOff      = "off"      // every project keeps its time
+)
+
+// ValidateOverlap checks that policy is a known overlap policy. The empty
+// string is the default, recent.
+func ValidateOverlap(policy string) error {
+	switch policy {
+	case "", OverlapRecent, OverlapPriority, OverlapSplit, OverlapOff:
+		return nil
+	}
+	return fmt.Errorf("invalid overlap policy %q (supported: recent, priority, split, off)", policy)
+}
+
+// GetOverlap returns the configured overlap policy, defaulting to
+// OverlapRecent.
+func GetOverlap(cfg *Config) string {
+	if cfg.Overlap == "" {
+		return OverlapRecent
+	}
+	return cfg.Overlap
+}
+
+// SetOverlap sets the overlap policy shared by all projects. Recent, the
+// default, is stored as the empty string.
+func SetOverlap(homeDir, policy string) error {
+	if err := ValidateOverlap(policy); err != nil {
+		return err
+	}
+	if policy == OverlapRecent {
+		policy = ""
 	}
-	entry.IdleThresholdMinutes = minutes
-	return WriteConfig(homeDir, cfg)
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		cfg.Overlap = policy
+		return nil
+	})
+}
+
+// SetPriority sets the priority of a project under the priority overlap
+// policy. Higher wins; the default is 0.
+func SetPriority(homeDir, projectID string, priority int) error {
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.Priority = priority
+		return nil
+	})
+}
+
+// SetBalanceStart sets the date (YYYY-MM-DD) from which the flextime balance
+// of a project is counted. An empty date turns the balance off.
+func SetBalanceStart(homeDir, projectID, date string) error {
+	if date != "" {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("invalid balance start date %q (expected YYYY-MM-DD)", date)
+		}
+	}
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.BalanceStart = date
+		return nil
+	})
+}
+
+// AddRule appends a task rule to a project. Rules apply in order; the first
+// that matches wins.
+func AddRule(homeDir, projectID string, rule rules.Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	return UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		entry.Rules = append(entry.Rules, rule)
+		return nil
+	})
+}
+
+// RemoveRule removes the task rule at index (1-based, as listed) from a
+// project and returns it.
+func RemoveRule(homeDir, projectID string, index int) (rules.Rule, error) {
+	var removed rules.Rule
+	err := UpdateConfig(homeDir, func(cfg *Config) error {
+		entry := FindProjectByID(cfg, projectID)
+		if entry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
+		if index < 1 || index > len(entry.Rules) {
+			return fmt.Errorf("rule %d not found (project has %d)", index, len(entry.Rules))
+		}
+		removed = entry.Rules[index-1]
+		entry.Rules = append(entry.Rules[:index-1], entry.Rules[index:]...)
+		return nil
+	})
+	return removed, err
 }
 
 // AnyPreciseProject checks if any project in the config has precise mode enabled.
@@ -430,44 +1006,54 @@ func AnyPreciseProject(cfg *Config) bool {
 }
 
 // RenameProject renames a project by ID, updating the config, data directory, and repo configs.
-// Returns the updated ProjectEntry or an error if the new name conflicts.
+// The data directory is moved once the config is committed; if the move fails,
+// the config is put back. Returns the updated ProjectEntry or an error if the
+// new name conflicts.
 func RenameProject(homeDir, projectID, newName string) (*ProjectEntry, error) {
-	cfg, err := ReadConfig(homeDir)
-	if err != nil {
-		return nil, err
-	}
+	var entry ProjectEntry
+	var oldName, oldSlug string
+	err := UpdateConfig(homeDir, func(cfg *Config) error {
+		cfgEntry := FindProjectByID(cfg, projectID)
+		if cfgEntry == nil {
+			return fmt.Errorf("project '%s' not found", projectID)
+		}
 
-	entry := FindProjectByID(cfg, projectID)
-	if entry == nil {
-		return nil, fmt.Errorf("project '%s' not found", projectID)
-	}
+		// Check for name conflict
+		if existing := FindProject(cfg, newName); existing != nil && existing.ID != projectID {
+			return fmt.Errorf("project '%s' already exists (%s)", newName, existing.ID)
+		}
 
-	// Check for name conflict
-	if existing := FindProject(cfg, newName); existing != nil && existing.ID != projectID {
-		return nil, fmt.Errorf("project '%s' already exists (%s)", newName, existing.ID)
+		oldName, oldSlug = cfgEntry.Name, cfgEntry.Slug
+		cfgEntry.Name = newName
+		cfgEntry.Slug = stringutil.Slugify(newName)
+		entry = *cfgEntry
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	oldSlug := entry.Slug
-	newSlug := stringutil.Slugify(newName)
-
 	// Rename data directory if slug changed
-	if newSlug != oldSlug {
-		oldDir := LogDir(homeDir, oldSlug)
-		newDir := LogDir(homeDir, newSlug)
-		if _, err := os.Stat(oldDir); err == nil {
-			if err := os.Rename(oldDir, newDir); err != nil {
-				return nil, fmt.Errorf("could not rename data directory: %w", err)
+	if entry.Slug != oldSlug {
+		moved, err := moveLogDir(homeDir, oldSlug, entry.Slug)
+		if err != nil {
+			err = fmt.Errorf("could not rename data directory: %w", err)
+			revertErr := UpdateConfig(homeDir, func(cfg *Config) error {
+				if cfgEntry := FindProjectByID(cfg, projectID); cfgEntry != nil {
+					cfgEntry.Name, cfgEntry.Slug = oldName, oldSlug
+				}
+				return nil
+			})
+			if revertErr != nil {
+				return nil, fmt.Errorf("%w (and could not restore the project's old name: %v)", err, revertErr)
 			}
-		} else if !errors.Is(err, os.ErrNotExist) {
 			return nil, err
 		}
-	}
-
-	entry.Name = newName
-	entry.Slug = newSlug
-
-	if err := WriteConfig(homeDir, cfg); err != nil {
-		return nil, err
+		if moved {
+			if err := journalDirMove(homeDir, oldSlug, entry.Slug); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// Best-effort update repo configs
@@ -480,13 +1066,70 @@ func RenameProject(homeDir, projectID, newName string) (*ProjectEntry, error) {
 		_ = WriteRepoConfig(repoDir, rc)
 	}
 
-	return entry, nil
+	return &entry, nil
+}
+
+// moveLogDir moves a project's data directory from one slug to another.
+// Returns false when there is no directory to move.
+func moveLogDir(homeDir, fromSlug, toSlug string) (bool, error) {
+	oldDir := LogDir(homeDir, fromSlug)
+	if _, err := os.Stat(oldDir); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if err := os.Rename(oldDir, LogDir(homeDir, toSlug)); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// RemoveHookFromRepo removes the hourgit section from the post-checkout hook.
-// If the hook becomes empty after removal, it is deleted.
+// journalDirMove records a project data directory rename in the operation journal.
+func journalDirMove(homeDir, fromSlug, toSlug string) error {
+	before, err := json.Marshal(fromSlug)
+	if err != nil {
+		return err
+	}
+	after, err := json.Marshal(toSlug)
+	if err != nil {
+		return err
+	}
+	return journal.Append(homeDir, journal.Change{
+		Kind:   journal.KindProjectDir,
+		Before: before,
+		After:  after,
+	})
+}
+
+// MoveLogDir renames a project's data directory from one slug to another. It
+// is used to revert journaled renames and is itself journaled.
+func MoveLogDir(homeDir, fromSlug, toSlug string) error {
+	from := LogDir(homeDir, fromSlug)
+	to := LogDir(homeDir, toSlug)
+	if _, err := os.Stat(from); err != nil {
+		return fmt.Errorf("project directory '%s' not found", fromSlug)
+	}
+	if _, err := os.Stat(to); err == nil {
+		return fmt.Errorf("project directory '%s' already exists", toSlug)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return err
+	}
+	return journalDirMove(homeDir, fromSlug, toSlug)
+}
+
+// RemoveHookFromRepo removes the hourgit section from each of the hooks
+// hourgit installs. A hook that becomes empty after removal is deleted.
 func RemoveHookFromRepo(repoDir string) error {
-	hookPath := filepath.Join(repoDir, ".git", "hooks", "post-checkout")
+	for _, name := range HookNames {
+		if err := removeHook(HookPath(repoDir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeHook(hookPath string) error {
 	data, err := os.ReadFile(hookPath)
 	if errors.Is(err, os.ErrNotExist) {
 		return nil
@@ -511,5 +1154,5 @@ func RemoveHookFromRepo(repoDir string) error {
 	}
 
 	// Write back the non-hourgit portion
-	return os.WriteFile(hookPath, []byte(before+"\n"), 0755)
+	return fsutil.WriteFileAtomic(hookPath, []byte(before+"\n"), 0755)
 }
@@ -1,11 +1,19 @@
 package project
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/Flyrell/hourgit/internal/hashutil"
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/Flyrell/hourgit/internal/paths"
+	"github.com/Flyrell/hourgit/internal/rounding"
+	"github.com/Flyrell/hourgit/internal/rules"
 	"github.com/Flyrell/hourgit/internal/schedule"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,16 +21,20 @@ import (
 
 var hexPattern = regexp.MustCompile(`^[0-9a-f]{7}$`)
 
-func TestHourgitDir(t *testing.T) {
-	assert.Equal(t, "/home/user/.hourgit", HourgitDir("/home/user"))
-}
-
 func TestConfigPath(t *testing.T) {
-	assert.Equal(t, "/home/user/.hourgit/config.json", ConfigPath("/home/user"))
+	assert.Equal(t, "/home/user/.config/hourgit/config.json", ConfigPath("/home/user"))
 }
 
 func TestLogDir(t *testing.T) {
-	assert.Equal(t, "/home/user/.hourgit/my-project", LogDir("/home/user", "my-project"))
+	assert.Equal(t, "/home/user/.local/share/hourgit/my-project", LogDir("/home/user", "my-project"))
+}
+
+func TestPathsFollowRegisteredLayout(t *testing.T) {
+	restore := paths.Use("/home/user", paths.Layout{Config: "/data/hg", Data: "/data/hg"})
+	defer restore()
+
+	assert.Equal(t, "/data/hg/config.json", ConfigPath("/home/user"))
+	assert.Equal(t, "/data/hg/my-project", LogDir("/home/user", "my-project"))
 }
 
 func TestReadConfigMissing(t *testing.T) {
@@ -88,6 +100,49 @@ func TestRepoConfigRoundTrip(t *testing.T) {
 	assert.Equal(t, "My Project", loaded.Project)
 }
 
+// linkWorktree lays out a linked worktree of the repository at main the way
+// git does: a .git file naming its git dir, which names the common one.
+func linkWorktree(t *testing.T, main, name string) string {
+	t.Helper()
+	gitDir := filepath.Join(main, ".git", "worktrees", name)
+	require.NoError(t, os.MkdirAll(gitDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "commondir"), []byte("../..\n"), 0644))
+	wt := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+gitDir+"\n"), 0644))
+	return wt
+}
+
+func TestRepoConfigLinkedWorktree(t *testing.T) {
+	home := t.TempDir()
+	main := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(main, ".git"), 0755))
+	wt := linkWorktree(t, main, "wt")
+	other := linkWorktree(t, main, "other")
+
+	entry, err := CreateProject(home, "My Project")
+	require.NoError(t, err)
+	require.NoError(t, AssignProject(home, wt, entry))
+
+	assert.FileExists(t, filepath.Join(main, ".git", "worktrees", "wt", ".hourgit"))
+	assert.Equal(t, filepath.Join(main, ".git", "hooks", "post-checkout"), HookPath(wt, "post-checkout"))
+
+	// The worktree keeps its own sync state
+	synced := time.Date(2025, 6, 15, 14, 30, 0, 0, time.UTC)
+	rc, err := ReadRepoConfig(wt)
+	require.NoError(t, err)
+	rc.LastSync = &synced
+	require.NoError(t, WriteRepoConfig(wt, rc))
+
+	// The main worktree was assigned too, and other worktrees inherit it
+	for _, dir := range []string{main, other} {
+		rc, err := ReadRepoConfig(dir)
+		require.NoError(t, err)
+		require.NotNil(t, rc, dir)
+		assert.Equal(t, entry.ID, rc.ProjectID)
+		assert.Nil(t, rc.LastSync)
+	}
+}
+
 func TestRemoveRepoFromProject(t *testing.T) {
 	entry := &ProjectEntry{
 		ID:    "abc1234",
@@ -211,6 +266,17 @@ func TestCreateProjectDuplicate(t *testing.T) {
 	assert.Contains(t, err.Error(), "already exists")
 }
 
+func TestNewProjectIDAvoidsExistingProjects(t *testing.T) {
+	hash := hashutil.Hash("seed")
+	cfg := &Config{Projects: []ProjectEntry{
+		{ID: hash[:7], Name: "Taken ID"},
+		{ID: "aaaaaaa", Name: hash[:8]},
+	}}
+
+	assert.Equal(t, hash[:9], newProjectID(cfg, "seed"))
+	assert.Equal(t, hash[:7], newProjectID(&Config{}, "seed"))
+}
+
 func TestAssignProject(t *testing.T) {
 	home := t.TempDir()
 	repo := t.TempDir()
@@ -339,6 +405,26 @@ func TestRemoveHookFromRepoMerged(t *testing.T) {
 	assert.NotContains(t, string(data), "hourgit")
 }
 
+func TestRemoveHookFromRepoAllHooks(t *testing.T) {
+	dir := t.TempDir()
+	hooksDir := filepath.Join(dir, ".git", "hooks")
+	require.NoError(t, os.MkdirAll(hooksDir, 0755))
+	for _, name := range HookNames {
+		require.NoError(t, os.WriteFile(filepath.Join(hooksDir, name), []byte("#!/bin/sh\n# Installed by hourgit\necho hourgit\n"), 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(hooksDir, "pre-commit"), []byte("#!/bin/sh\necho lint\n"), 0755))
+
+	err := RemoveHookFromRepo(dir)
+	assert.NoError(t, err)
+
+	for _, name := range HookNames {
+		_, err = os.Stat(filepath.Join(hooksDir, name))
+		assert.True(t, os.IsNotExist(err), name)
+	}
+	_, err = os.Stat(filepath.Join(hooksDir, "pre-commit"))
+	assert.NoError(t, err)
+}
+
 func TestRemoveHookFromRepoMissing(t *testing.T) {
 	dir := t.TempDir()
 	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git", "hooks"), 0755))
@@ -394,6 +480,124 @@ func TestIdleThresholdGetSet(t *testing.T) {
 	assert.Equal(t, 15, GetIdleThreshold(cfg, entry.ID))
 }
 
+func TestTimezoneGetSet(t *testing.T) {
+	home := t.TempDir()
+	entry, err := CreateProject(home, "Test")
+	require.NoError(t, err)
+	assert.Equal(t, time.Local, Location(entry), "unset falls back to the local zone")
+
+	require.NoError(t, SetTimezone(home, entry.ID, "Asia/Tokyo"))
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", Location(FindProjectByID(cfg, entry.ID)).String())
+
+	assert.ErrorContains(t, SetTimezone(home, entry.ID, "Nowhere/City"), "invalid time zone")
+	assert.ErrorContains(t, SetTimezone(home, "nonexistent", "UTC"), "not found")
+
+	require.NoError(t, SetTimezone(home, entry.ID, ""))
+	cfg, err = ReadConfig(home)
+	require.NoError(t, err)
+	assert.Empty(t, FindProjectByID(cfg, entry.ID).Timezone)
+}
+
+func TestRoundingGetSet(t *testing.T) {
+	home := t.TempDir()
+	entry, err := CreateProject(home, "Test")
+	require.NoError(t, err)
+
+	policy := rounding.Policy{Increment: 15, Mode: rounding.ModeUp, Scope: rounding.ScopeTaskDay}
+	require.NoError(t, SetRounding(home, entry.ID, policy))
+	data, err := os.ReadFile(ConfigPath(home))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"rounding"`)
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, policy, FindProjectByID(cfg, entry.ID).Rounding)
+
+	assert.ErrorContains(t, SetRounding(home, entry.ID, rounding.Policy{Increment: 15}), "invalid rounding mode")
+	assert.ErrorContains(t, SetRounding(home, "nonexistent", policy), "not found")
+
+	require.NoError(t, SetRounding(home, entry.ID, rounding.Policy{}))
+	data, err = os.ReadFile(ConfigPath(home))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"rounding"`, "the zero policy is not stored")
+}
+
+func TestOvertimeGetSet(t *testing.T) {
+	home := t.TempDir()
+	entry, err := CreateProject(home, "Test")
+	require.NoError(t, err)
+
+	require.NoError(t, SetOvertime(home, entry.ID, OvertimeSeparate))
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, OvertimeSeparate, FindProjectByID(cfg, entry.ID).Overtime)
+
+	assert.ErrorContains(t, SetOvertime(home, entry.ID, "always"), "invalid overtime policy")
+	assert.ErrorContains(t, SetOvertime(home, "nonexistent", OvertimeActivity), "not found")
+
+	require.NoError(t, SetOvertime(home, entry.ID, OvertimeIgnore))
+	data, err := os.ReadFile(ConfigPath(home))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"overtime"`, "the default is not stored")
+}
+
+func TestRepoMergeGetSet(t *testing.T) {
+	home := t.TempDir()
+	entry, err := CreateProject(home, "Test")
+	require.NoError(t, err)
+
+	require.NoError(t, SetRepoMerge(home, entry.ID, RepoMergeSplit))
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, RepoMergeSplit, FindProjectByID(cfg, entry.ID).RepoMerge)
+
+	assert.ErrorContains(t, SetRepoMerge(home, entry.ID, "first"), "invalid repo merge policy")
+	assert.ErrorContains(t, SetRepoMerge(home, "nonexistent", RepoMergeActive), "not found")
+
+	require.NoError(t, SetRepoMerge(home, entry.ID, RepoMergeLatest))
+	data, err := os.ReadFile(ConfigPath(home))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"repo_merge"`, "the default is not stored")
+}
+
+func TestSubmodulesGetSet(t *testing.T) {
+	home := t.TempDir()
+	entry, err := CreateProject(home, "Test")
+	require.NoError(t, err)
+
+	require.NoError(t, SetSubmodules(home, entry.ID, SubmodulesParent))
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, SubmodulesParent, FindProjectByID(cfg, entry.ID).Submodules)
+
+	assert.ErrorContains(t, SetSubmodules(home, entry.ID, "child"), "invalid submodule policy")
+
+	require.NoError(t, SetSubmodules(home, entry.ID, SubmodulesOwn))
+	data, err := os.ReadFile(ConfigPath(home))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"submodules"`, "the default is not stored")
+}
+
+func TestBalanceStartGetSet(t *testing.T) {
+	home := t.TempDir()
+	entry, err := CreateProject(home, "Test")
+	require.NoError(t, err)
+
+	require.NoError(t, SetBalanceStart(home, entry.ID, "2025-01-01"))
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-01", FindProjectByID(cfg, entry.ID).BalanceStart)
+
+	assert.ErrorContains(t, SetBalanceStart(home, entry.ID, "01/01/2025"), "invalid balance start date")
+	assert.ErrorContains(t, SetBalanceStart(home, "nonexistent", "2025-01-01"), "not found")
+
+	require.NoError(t, SetBalanceStart(home, entry.ID, ""))
+	data, err := os.ReadFile(ConfigPath(home))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"balance_start"`)
+}
+
 func TestPreciseModeSetNotFound(t *testing.T) {
 	home := t.TempDir()
 
@@ -505,6 +709,27 @@ func TestRenameProjectHappyPath(t *testing.T) {
 	assert.Equal(t, "New Name", rc.Project)
 }
 
+func TestRenameProjectRestoresConfigWhenMoveFails(t *testing.T) {
+	home := t.TempDir()
+
+	entry, err := CreateProject(home, "Old Name")
+	require.NoError(t, err)
+
+	// A non-empty directory at the new slug cannot be renamed onto
+	require.NoError(t, os.MkdirAll(LogDir(home, "new-name"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(LogDir(home, "new-name"), "stray"), nil, 0644))
+
+	_, err = RenameProject(home, entry.ID, "New Name")
+	assert.ErrorContains(t, err, "could not rename data directory")
+
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, "Old Name", cfg.Projects[0].Name)
+	assert.Equal(t, "old-name", cfg.Projects[0].Slug)
+	_, err = os.Stat(LogDir(home, "old-name"))
+	assert.NoError(t, err)
+}
+
 func TestRenameProjectSameSlug(t *testing.T) {
 	home := t.TempDir()
 
@@ -589,3 +814,221 @@ func TestFindProjectByID(t *testing.T) {
 	assert.Nil(t, FindProjectByID(cfg, "nonexistent"))
 }
 
+func TestSetStorage(t *testing.T) {
+	home := t.TempDir()
+
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, StorageFiles, GetStorage(cfg))
+
+	require.NoError(t, SetStorage(home, StorageSegments))
+	cfg, err = ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, StorageSegments, GetStorage(cfg))
+
+	assert.EqualError(t, SetStorage(home, "cloud"), `unknown storage backend "cloud" (supported: files, segments)`)
+}
+
+func TestUpdateConfigConcurrentWriters(t *testing.T) {
+	home := t.TempDir()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := CreateProject(home, fmt.Sprintf("Project %d", i))
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Len(t, cfg.Projects, 10)
+}
+
+func TestUpdateConfigErrorSkipsWrite(t *testing.T) {
+	home := t.TempDir()
+
+	err := UpdateConfig(home, func(cfg *Config) error {
+		cfg.Storage = StorageSegments
+		return fmt.Errorf("boom")
+	})
+	assert.EqualError(t, err, "boom")
+
+	_, err = os.Stat(ConfigPath(home))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestUpdateConfigJournalsChanges(t *testing.T) {
+	home := t.TempDir()
+
+	journal.Begin("project add")
+	_, err := CreateProject(home, "Journaled")
+	require.NoError(t, err)
+
+	// A no-op update records nothing
+	require.NoError(t, UpdateConfig(home, func(cfg *Config) error { return nil }))
+
+	changes, err := journal.Read(home)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, journal.KindConfig, changes[0].Kind)
+	assert.Equal(t, "project add", changes[0].Command)
+	assert.NotContains(t, string(changes[0].Before), "Journaled")
+	assert.Contains(t, string(changes[0].After), "Journaled")
+}
+
+func TestRestoreConfig(t *testing.T) {
+	home := t.TempDir()
+
+	_, err := CreateProject(home, "First")
+	require.NoError(t, err)
+	snapshot, err := ConfigSnapshot(home)
+	require.NoError(t, err)
+
+	_, err = CreateProject(home, "Second")
+	require.NoError(t, err)
+	require.NoError(t, RestoreConfig(home, snapshot))
+
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	require.Len(t, cfg.Projects, 1)
+	assert.Equal(t, "First", cfg.Projects[0].Name)
+}
+
+func TestRenameProjectJournalsDirMove(t *testing.T) {
+	home := t.TempDir()
+
+	p, err := CreateProject(home, "Old")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(LogDir(home, p.Slug), 0755))
+
+	journal.Begin("project edit")
+	_, err = RenameProject(home, p.ID, "New")
+	require.NoError(t, err)
+
+	changes, err := journal.Read(home)
+	require.NoError(t, err)
+	last := changes[len(changes)-1]
+	assert.Equal(t, journal.KindProjectDir, last.Kind)
+	assert.JSONEq(t, `"old"`, string(last.Before))
+	assert.JSONEq(t, `"new"`, string(last.After))
+
+	require.NoError(t, MoveLogDir(home, "new", "old"))
+	_, err = os.Stat(LogDir(home, "old"))
+	assert.NoError(t, err)
+	assert.Error(t, MoveLogDir(home, "new", "old"))
+}
+
+func TestOverlapGetSet(t *testing.T) {
+	home := t.TempDir()
+
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, OverlapRecent, GetOverlap(cfg))
+
+	require.NoError(t, SetOverlap(home, OverlapSplit))
+	cfg, err = ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, OverlapSplit, GetOverlap(cfg))
+
+	assert.ErrorContains(t, SetOverlap(home, "first"), "invalid overlap policy")
+
+	require.NoError(t, SetOverlap(home, OverlapRecent))
+	data, err := os.ReadFile(ConfigPath(home))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"overlap"`, "the default is not stored")
+}
+
+func TestPriorityGetSet(t *testing.T) {
+	home := t.TempDir()
+	entry, err := CreateProject(home, "Test")
+	require.NoError(t, err)
+
+	require.NoError(t, SetPriority(home, entry.ID, 2))
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, 2, FindProjectByID(cfg, entry.ID).Priority)
+
+	assert.ErrorContains(t, SetPriority(home, "nonexistent", 1), "not found")
+}
+
+func TestAddRemoveRule(t *testing.T) {
+	home := t.TempDir()
+	entry, err := CreateProject(home, "Test")
+	require.NoError(t, err)
+
+	ticket := rules.Rule{Branch: `^feature/(PROJ-\d+)`, Task: "$1"}
+	fixes := rules.Rule{Commit: `^fix:`, Category: rules.CategoryMaintenance}
+	require.NoError(t, AddRule(home, entry.ID, ticket))
+	require.NoError(t, AddRule(home, entry.ID, fixes))
+	assert.ErrorContains(t, AddRule(home, entry.ID, rules.Rule{Branch: "("}), "assigns nothing")
+	assert.ErrorContains(t, AddRule(home, "nonexistent", ticket), "not found")
+
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, []rules.Rule{ticket, fixes}, FindProjectByID(cfg, entry.ID).Rules)
+
+	removed, err := RemoveRule(home, entry.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, ticket, removed)
+	_, err = RemoveRule(home, entry.ID, 2)
+	assert.ErrorContains(t, err, "rule 2 not found")
+
+	cfg, err = ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, []rules.Rule{fixes}, FindProjectByID(cfg, entry.ID).Rules)
+}
+
+func TestParseAttribution(t *testing.T) {
+	tests := []struct {
+		spec string
+		want Attribution
+		err  string
+	}{
+		{spec: "", want: Attribution{}},
+		{spec: "checkout", want: Attribution{}},
+		{spec: "commits", want: Attribution{Strategy: AttributionCommits, MaxGap: DefaultCommitMaxGap, LeadIn: DefaultCommitLeadIn}},
+		{spec: "Commits:90", want: Attribution{Strategy: AttributionCommits, MaxGap: 90, LeadIn: DefaultCommitLeadIn}},
+		{spec: "commits:90:0", want: Attribution{Strategy: AttributionCommits, MaxGap: 90, LeadIn: 0}},
+		{spec: "sessions", err: "invalid attribution"},
+		{spec: "commits:x", err: "invalid attribution minutes"},
+		{spec: "commits:0", err: "invalid attribution max gap"},
+		{spec: "commits:60:-5", err: "invalid attribution lead-in"},
+		{spec: "commits:1:2:3", err: "invalid attribution"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseAttribution(tt.spec)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAttributionGetSet(t *testing.T) {
+	home := t.TempDir()
+	entry, err := CreateProject(home, "Test")
+	require.NoError(t, err)
+
+	commits := Attribution{Strategy: AttributionCommits, MaxGap: 90, LeadIn: 15}
+	require.NoError(t, SetAttribution(home, entry.ID, commits))
+	cfg, err := ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, commits, FindProjectByID(cfg, entry.ID).Attribution)
+	assert.Equal(t, "commits, 90 min gap, 15 min lead-in", commits.String())
+
+	require.NoError(t, SetAttribution(home, entry.ID, Attribution{Strategy: AttributionCheckout}))
+	cfg, err = ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, Attribution{}, FindProjectByID(cfg, entry.ID).Attribution)
+	assert.Equal(t, "checkout", Attribution{}.String())
+
+	assert.ErrorContains(t, SetAttribution(home, entry.ID, Attribution{Strategy: "guess"}), "invalid attribution strategy")
+}
@@ -4,12 +4,14 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
 )
 
 // CheckoutRecord represents a single checkout event parsed from git reflog output.
 type CheckoutRecord struct {
 	CommitRef string
-	Timestamp time.Time
+	Timestamp time.Time // keeps the UTC offset git recorded
 	Previous  string
 	Next      string
 }
@@ -17,10 +19,21 @@ type CheckoutRecord struct {
 // CommitRecord represents a single commit event parsed from git reflog output.
 type CommitRecord struct {
 	CommitRef string
-	Timestamp time.Time
+	Timestamp time.Time // keeps the UTC offset git recorded
 	Message   string
 }
 
+// EventRecord represents a merge, rebase, cherry-pick, reset, pull or branch
+// rename parsed from git reflog output.
+type EventRecord struct {
+	CommitRef string
+	Timestamp time.Time // keeps the UTC offset git recorded
+	Kind      string    // entry.EventMerge, EventRebase, ...
+	Message   string    // the reflog subject, e.g. "rebase (pick): add login form"
+	Previous  string    // old branch name, for renames
+	Next      string    // new branch name, for renames
+}
+
 // reflogLinePattern matches git reflog lines with --date=iso format.
 // Example: "abc1234 HEAD@{2025-06-15 14:30:00 +0200}: checkout: moving from main to feature-x"
 var reflogLinePattern = regexp.MustCompile(
@@ -34,6 +47,30 @@ var commitLinePattern = regexp.MustCompile(
 	`^([0-9a-f]+)\s+HEAD@\{(\d{4}-\d{2}-\d{2}\s+\d{2}:\d{2}:\d{2}\s+[+-]\d{4})\}:\s+commit(?:\s+\(amend\))?:\s+(.+)$`,
 )
 
+// eventLinePattern matches any git reflog line, capturing its subject.
+var eventLinePattern = regexp.MustCompile(
+	`^([0-9a-f]+)\s+HEAD@\{(\d{4}-\d{2}-\d{2}\s+\d{2}:\d{2}:\d{2}\s+[+-]\d{4})\}:\s+(.+)$`,
+)
+
+// eventKinds classifies reflog subjects into event kinds.
+// Examples: "merge feature-x: Fast-forward", "rebase (pick): add login form",
+// "rebase -i (finish): returning to refs/heads/feature", "cherry-pick: fix typo",
+// "reset: moving to HEAD~1", "pull --rebase (finish): returning to refs/heads/main".
+var eventKinds = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{entry.EventMerge, regexp.MustCompile(`^merge \S+: `)},
+	{entry.EventRebase, regexp.MustCompile(`^rebase( -[im])?( \([a-z]+\))?: |^rebase finished: `)},
+	{entry.EventCherryPick, regexp.MustCompile(`^cherry-pick: `)},
+	{entry.EventReset, regexp.MustCompile(`^reset: `)},
+	{entry.EventPull, regexp.MustCompile(`^pull( [^:]+)?: `)},
+}
+
+// renamePattern matches branch renames, e.g.
+// "Branch: renamed refs/heads/old to refs/heads/new".
+var renamePattern = regexp.MustCompile(`^[Bb]ranch: renamed (\S+) to (\S+)$`)
+
 // ParseReflog parses git reflog output and returns checkout records.
 // Only "checkout: moving from X to Y" lines are matched; all other lines are skipped.
 // Records are returned in reflog order (newest first).
@@ -63,7 +100,7 @@ func ParseReflog(output string) []CheckoutRecord {
 
 		records = append(records, CheckoutRecord{
 			CommitRef: commitRef,
-			Timestamp: ts.UTC(),
+			Timestamp: ts,
 			Previous:  prev,
 			Next:      next,
 		})
@@ -100,10 +137,58 @@ func ParseCommits(output string) []CommitRecord {
 
 		records = append(records, CommitRecord{
 			CommitRef: commitRef,
-			Timestamp: ts.UTC(),
+			Timestamp: ts,
 			Message:   message,
 		})
 	}
 
 	return records
 }
+
+// ParseEvents parses git reflog output and returns merge, rebase,
+// cherry-pick, reset, pull and branch rename records. Checkouts, commits and
+// other lines are skipped. Records are returned in reflog order (newest
+// first).
+func ParseEvents(output string) []EventRecord {
+	var records []EventRecord
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		matches := eventLinePattern.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		ts, err := time.Parse("2006-01-02 15:04:05 -0700", matches[2])
+		if err != nil {
+			continue
+		}
+
+		rec := EventRecord{
+			CommitRef: matches[1],
+			Timestamp: ts,
+			Message:   matches[3],
+		}
+		if rename := renamePattern.FindStringSubmatch(rec.Message); rename != nil {
+			rec.Kind = entry.EventBranchRename
+			rec.Previous = strings.TrimPrefix(rename[1], "refs/heads/")
+			rec.Next = strings.TrimPrefix(rename[2], "refs/heads/")
+		}
+		for _, k := range eventKinds {
+			if rec.Kind == "" && k.pattern.MatchString(rec.Message) {
+				rec.Kind = k.kind
+			}
+		}
+		if rec.Kind == "" {
+			continue
+		}
+
+		records = append(records, rec)
+	}
+
+	return records
+}
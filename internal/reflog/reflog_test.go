@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Flyrell/hourgit/internal/entry"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,7 +15,7 @@ func TestParseReflogStandardCheckout(t *testing.T) {
 
 	assert.Len(t, records, 1)
 	assert.Equal(t, "abc1234", records[0].CommitRef)
-	assert.Equal(t, time.Date(2025, 6, 15, 14, 30, 0, 0, time.UTC), records[0].Timestamp)
+	assert.Equal(t, time.Date(2025, 6, 15, 14, 30, 0, 0, time.UTC), records[0].Timestamp.UTC())
 	assert.Equal(t, "main", records[0].Previous)
 	assert.Equal(t, "feature-x", records[0].Next)
 }
@@ -73,14 +74,17 @@ def5678 HEAD@{2025-06-15 14:00:00 +0000}: checkout: moving from main to develop`
 	assert.Equal(t, "develop", records[1].Next)
 }
 
-func TestParseReflogTimezoneConversion(t *testing.T) {
+func TestParseReflogKeepsOffset(t *testing.T) {
 	// +0200 means 14:30 local = 12:30 UTC
 	input := `abc1234 HEAD@{2025-06-15 14:30:00 +0200}: checkout: moving from main to feature`
 
 	records := ParseReflog(input)
 
 	assert.Len(t, records, 1)
-	assert.Equal(t, time.Date(2025, 6, 15, 12, 30, 0, 0, time.UTC), records[0].Timestamp)
+	assert.Equal(t, time.Date(2025, 6, 15, 12, 30, 0, 0, time.UTC), records[0].Timestamp.UTC())
+	_, offset := records[0].Timestamp.Zone()
+	assert.Equal(t, 2*60*60, offset)
+	assert.Equal(t, 14, records[0].Timestamp.Hour())
 }
 
 func TestParseCommitsStandardCommit(t *testing.T) {
@@ -90,7 +94,7 @@ func TestParseCommitsStandardCommit(t *testing.T) {
 
 	assert.Len(t, records, 1)
 	assert.Equal(t, "abc1234", records[0].CommitRef)
-	assert.Equal(t, time.Date(2025, 6, 15, 14, 30, 0, 0, time.UTC), records[0].Timestamp)
+	assert.Equal(t, time.Date(2025, 6, 15, 14, 30, 0, 0, time.UTC), records[0].Timestamp.UTC())
 	assert.Equal(t, "add login form", records[0].Message)
 }
 
@@ -101,7 +105,7 @@ func TestParseCommitsAmendCommit(t *testing.T) {
 
 	assert.Len(t, records, 1)
 	assert.Equal(t, "def5678", records[0].CommitRef)
-	assert.Equal(t, time.Date(2025, 6, 15, 10, 0, 0, 0, time.UTC), records[0].Timestamp)
+	assert.Equal(t, time.Date(2025, 6, 15, 10, 0, 0, 0, time.UTC), records[0].Timestamp.UTC())
 	assert.Equal(t, "fix typo in README", records[0].Message)
 }
 
@@ -141,12 +145,63 @@ aab9012 HEAD@{2025-06-15 10:00:00 +0000}: commit: initial scaffold`
 	assert.Equal(t, "initial scaffold", records[2].Message)
 }
 
-func TestParseCommitsTimezoneConversion(t *testing.T) {
+func TestParseCommitsKeepsOffset(t *testing.T) {
 	// +0200 means 14:30 local = 12:30 UTC
 	input := `abc1234 HEAD@{2025-06-15 14:30:00 +0200}: commit: deploy hotfix`
 
 	records := ParseCommits(input)
 
 	assert.Len(t, records, 1)
-	assert.Equal(t, time.Date(2025, 6, 15, 12, 30, 0, 0, time.UTC), records[0].Timestamp)
+	assert.Equal(t, time.Date(2025, 6, 15, 12, 30, 0, 0, time.UTC), records[0].Timestamp.UTC())
+	_, offset := records[0].Timestamp.Zone()
+	assert.Equal(t, 2*60*60, offset)
+	assert.Equal(t, 14, records[0].Timestamp.Hour())
+}
+
+func TestParseEvents(t *testing.T) {
+	input := `a000001 HEAD@{2025-06-15 18:00:00 +0000}: Branch: renamed refs/heads/feature-x to refs/heads/PROJ-12-login
+a000002 HEAD@{2025-06-15 17:00:00 +0000}: pull --rebase (finish): returning to refs/heads/main
+a000003 HEAD@{2025-06-15 16:30:00 +0000}: pull: Fast-forward
+a000004 HEAD@{2025-06-15 16:00:00 +0000}: reset: moving to HEAD~1
+a000005 HEAD@{2025-06-15 15:00:00 +0000}: cherry-pick: fix typo
+a000006 HEAD@{2025-06-15 14:00:00 +0000}: rebase -i (finish): returning to refs/heads/feature-x
+a000007 HEAD@{2025-06-15 13:50:00 +0000}: rebase (pick): add login form
+a000008 HEAD@{2025-06-15 13:40:00 +0000}: rebase (start): checkout main
+a000009 HEAD@{2025-06-15 13:00:00 +0000}: merge develop: Merge made by the 'ort' strategy.
+a000010 HEAD@{2025-06-15 12:00:00 +0000}: commit: implement feature
+a000011 HEAD@{2025-06-15 11:00:00 +0000}: checkout: moving from main to feature-x`
+
+	records := ParseEvents(input)
+
+	kinds := make([]string, len(records))
+	for i, r := range records {
+		kinds[i] = r.Kind
+	}
+	assert.Equal(t, []string{
+		entry.EventBranchRename, entry.EventPull, entry.EventPull, entry.EventReset, entry.EventCherryPick,
+		entry.EventRebase, entry.EventRebase, entry.EventRebase, entry.EventMerge,
+	}, kinds)
+
+	assert.Equal(t, "feature-x", records[0].Previous)
+	assert.Equal(t, "PROJ-12-login", records[0].Next)
+	assert.Equal(t, "a000007", records[6].CommitRef)
+	assert.Equal(t, "rebase (pick): add login form", records[6].Message)
+	assert.Equal(t, time.Date(2025, 6, 15, 13, 0, 0, 0, time.UTC), records[8].Timestamp.UTC())
+	assert.Equal(t, "merge develop: Merge made by the 'ort' strategy.", records[8].Message)
+}
+
+func TestParseEventsLegacyRebase(t *testing.T) {
+	input := `abc1234 HEAD@{2025-06-15 13:30:00 +0000}: rebase: checkout feature
+def5678 HEAD@{2025-06-15 13:40:00 +0000}: rebase finished: refs/heads/feature onto abc1234`
+
+	records := ParseEvents(input)
+
+	assert.Len(t, records, 2)
+	assert.Equal(t, entry.EventRebase, records[0].Kind)
+	assert.Equal(t, entry.EventRebase, records[1].Kind)
+}
+
+func TestParseEventsEmptyInput(t *testing.T) {
+	assert.Empty(t, ParseEvents(""))
+	assert.Empty(t, ParseEvents(`abc1234 HEAD@{not-a-date}: reset: moving to HEAD~1`))
 }
@@ -0,0 +1,386 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/journal"
+	"github.com/Flyrell/hourgit/internal/project"
+)
+
+// side is a snapshot of one version of the data: the merge base, the remote
+// or the local data.
+type side struct {
+	cfg     *project.Config
+	entries map[string]map[string][]byte // slug -> id -> payload
+}
+
+// emptySide stands in for a missing merge base.
+func emptySide() *side {
+	return &side{cfg: &project.Config{}, entries: map[string]map[string][]byte{}}
+}
+
+// loadSide reads the config and all entries stored under home. Remote and
+// base trees are read with the backend their own config names, and their
+// entry IDs are checked before they are used.
+func loadSide(home string, local bool) (*side, error) {
+	cfg, err := project.ReadConfig(home)
+	if err != nil {
+		return nil, err
+	}
+	var store entry.Store
+	if local {
+		store, err = entry.OpenStore(home)
+	} else {
+		store, err = entry.NewStore(home, project.GetStorage(cfg))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slugs, err := entry.ProjectSlugs(home)
+	if err != nil {
+		return nil, err
+	}
+	s := &side{cfg: cfg, entries: make(map[string]map[string][]byte)}
+	for _, slug := range slugs {
+		records, err := store.Query(slug, entry.Query{})
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[string][]byte, len(records))
+		for _, r := range records {
+			// Remote and base IDs end up as file names locally
+			if !local && !entry.IsValidID(r.File) {
+				return nil, fmt.Errorf("invalid entry ID %q in remote data", r.File)
+			}
+			byID[r.File] = r.Data
+		}
+		s.entries[slug] = byID
+	}
+	return s, nil
+}
+
+// merge applies the changes made on the remote since base to the local data.
+// Projects are matched by ID and entries by project directory and ID; where
+// both sides changed the same thing, the local version is kept and the
+// conflict reported. All writes are journaled, so a pull can be undone.
+func merge(homeDir string, base, remote *side, result *Result) error {
+	if err := mergeConfig(homeDir, base.cfg, remote.cfg, result); err != nil {
+		return err
+	}
+
+	// Read local entries after the config merge, which may have moved
+	// project directories.
+	local, err := loadSide(homeDir, true)
+	if err != nil {
+		return err
+	}
+
+	slugs := make(map[string]bool)
+	for _, s := range []*side{base, local, remote} {
+		for slug := range s.entries {
+			slugs[slug] = true
+		}
+	}
+	for _, slug := range sortedKeys(slugs) {
+		ids := make(map[string]bool)
+		for _, s := range []*side{base, local, remote} {
+			for id := range s.entries[slug] {
+				ids[id] = true
+			}
+		}
+		for _, id := range sortedKeys(ids) {
+			b, l, r := base.entries[slug][id], local.entries[slug][id], remote.entries[slug][id]
+			if err := mergeEntry(homeDir, slug, id, b, l, r, result); err != nil {
+				return err
+			}
+		}
+		if err := dropDuplicateEvents(homeDir, slug, local.entries[slug], result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeEntry settles one entry given its base, local and remote payloads
+// (nil when absent).
+func mergeEntry(homeDir, slug, id string, b, l, r []byte, result *Result) error {
+	switch {
+	case journal.Equal(l, r), journal.Equal(r, b):
+		// Same on both sides, or unchanged on the remote
+		return nil
+	case journal.Equal(l, b):
+		// Changed only on the remote
+		switch {
+		case r == nil:
+			result.EntriesDeleted++
+		case l == nil:
+			result.EntriesAdded++
+		default:
+			result.EntriesUpdated++
+		}
+		return entry.RestoreEntry(homeDir, slug, id, r)
+	case l == nil && b != nil:
+		// Deleted here but edited on the remote: keep the edit
+		result.Conflicts = append(result.Conflicts, fmt.Sprintf("%s/%s: deleted here but changed on the remote, kept the remote version", slug, id))
+		result.EntriesAdded++
+		return entry.RestoreEntry(homeDir, slug, id, r)
+	case r == nil:
+		// Deleted on the remote but edited here: keep the edit
+		result.Conflicts = append(result.Conflicts, fmt.Sprintf("%s/%s: deleted on the remote but changed here, kept the local version", slug, id))
+		return nil
+	case sameEvent(l, r):
+		// A seeded git event recorded on both machines
+		return nil
+	}
+	result.Conflicts = append(result.Conflicts, fmt.Sprintf("%s/%s: changed on both machines, kept the local version", slug, id))
+	return nil
+}
+
+// gitEvent holds the fields identifying a checkout, commit or git event entry.
+type gitEvent struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Previous  string    `json:"previous"`
+	Next      string    `json:"next"`
+	CommitRef string    `json:"commit_ref"`
+	Kind      string    `json:"kind"`
+	Message   string    `json:"message"`
+}
+
+// eventKey identifies the git event a checkout, commit or git event entry
+// records, independent of its ID and of the machine-specific repo path. Other entry
+// types have no key.
+func eventKey(data []byte) string {
+	var e gitEvent
+	if err := json.Unmarshal(data, &e); err != nil {
+		return ""
+	}
+	ts := e.Timestamp.UTC().Format(time.RFC3339)
+	switch e.Type {
+	case entry.TypeCheckout:
+		return e.Type + "|" + ts + "|" + e.Previous + "|" + e.Next + "|" + e.CommitRef
+	case entry.TypeCommit:
+		return e.Type + "|" + ts + "|" + e.CommitRef
+	case entry.TypeGitEvent:
+		return e.Type + "|" + ts + "|" + e.Kind + "|" + e.CommitRef + "|" + e.Message
+	}
+	return ""
+}
+
+// sameEvent reports whether two payloads record the same git event.
+func sameEvent(a, b []byte) bool {
+	key := eventKey(a)
+	return key != "" && key == eventKey(b)
+}
+
+// dropDuplicateEvents removes checkout, commit and git event entries that
+// record the same git event as another entry in the project. 'hourgit sync'
+// derives their IDs from the event, but an ID may have been lengthened to
+// avoid a collision or kept at the legacy length on one machine, so the same
+// event can arrive under two IDs. The entry that existed locally is kept;
+// otherwise the shortest ID.
+func dropDuplicateEvents(homeDir, slug string, before map[string][]byte, result *Result) error {
+	records, err := entry.QueryRecords(homeDir, slug, entry.Query{})
+	if err != nil {
+		return err
+	}
+
+	byKey := make(map[string][]string)
+	for _, r := range records {
+		if key := eventKey(r.Data); key != "" {
+			byKey[key] = append(byKey[key], r.File)
+		}
+	}
+	for _, ids := range byKey {
+		if len(ids) < 2 {
+			continue
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			_, li := before[ids[i]]
+			_, lj := before[ids[j]]
+			if li != lj {
+				return li
+			}
+			if len(ids[i]) != len(ids[j]) {
+				return len(ids[i]) < len(ids[j])
+			}
+			return ids[i] < ids[j]
+		})
+		for _, id := range ids[1:] {
+			if err := entry.DeleteEntry(homeDir, slug, id); err != nil {
+				return err
+			}
+			result.Duplicates++
+		}
+	}
+	return nil
+}
+
+// mergeConfig applies remote changes to the default schedule and to the
+// project list, matching projects by ID. Repo paths are machine-specific:
+// repos assigned on the remote are added, but none are removed. The storage
+// backend and bookkeeping fields stay local.
+func mergeConfig(homeDir string, base, remote *project.Config, result *Result) error {
+	var moves [][2]string
+	err := project.UpdateConfig(homeDir, func(cfg *project.Config) error {
+		switch {
+		case jsonEqual(remote.Defaults, base.Defaults), jsonEqual(cfg.Defaults, remote.Defaults):
+		case jsonEqual(cfg.Defaults, base.Defaults):
+			cfg.Defaults = remote.Defaults
+		default:
+			result.Conflicts = append(result.Conflicts, "default schedule: changed on both machines, kept the local version")
+		}
+
+		ids := make(map[string]bool)
+		for _, c := range []*project.Config{base, cfg, remote} {
+			for _, p := range c.Projects {
+				ids[p.ID] = true
+			}
+		}
+
+		var merged []project.ProjectEntry
+		for _, id := range projectOrder(cfg, remote, ids) {
+			b, l, r := project.FindProjectByID(base, id), project.FindProjectByID(cfg, id), project.FindProjectByID(remote, id)
+			switch {
+			case l == nil && r == nil:
+				continue
+			case r == nil:
+				if b != nil && sameProject(l, b) {
+					result.ProjectsRemoved++
+					continue
+				}
+				merged = append(merged, *l)
+			case l == nil:
+				if b != nil && sameProject(r, b) {
+					// Removed here and untouched on the remote
+					continue
+				}
+				p := *r
+				p.Repos = append([]string{}, r.Repos...)
+				merged = append(merged, p)
+				result.ProjectsAdded++
+			default:
+				p := *l
+				switch {
+				case (b != nil && sameProject(r, b)) || sameProject(l, r):
+				case b != nil && sameProject(l, b):
+					p = *r
+					result.ProjectsUpdated++
+					if l.Slug != r.Slug {
+						moves = append(moves, [2]string{l.Slug, r.Slug})
+					}
+				default:
+					result.Conflicts = append(result.Conflicts, fmt.Sprintf("project '%s': changed on both machines, kept the local version", l.Name))
+				}
+				p.Repos = mergeRepos(l.Repos, repos(b), r.Repos)
+				merged = append(merged, p)
+			}
+		}
+
+		for i := range merged {
+			for j := i + 1; j < len(merged); j++ {
+				if merged[i].Name == merged[j].Name || merged[i].Slug == merged[j].Slug {
+					return fmt.Errorf("projects '%s' (%s) and '%s' (%s) clash after merging — rename one of them and try again",
+						merged[i].Name, merged[i].ID, merged[j].Name, merged[j].ID)
+				}
+			}
+		}
+		cfg.Projects = merged
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Follow project renames from the remote so local entries stay with
+	// their project
+	for _, m := range moves {
+		if _, err := os.Stat(project.LogDir(homeDir, m[0])); err != nil {
+			continue
+		}
+		if err := project.MoveLogDir(homeDir, m[0], m[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// projectOrder lists project IDs in local order, then remote order, then any
+// left over from the base.
+func projectOrder(local, remote *project.Config, ids map[string]bool) []string {
+	var order []string
+	seen := make(map[string]bool)
+	for _, c := range []*project.Config{local, remote} {
+		for _, p := range c.Projects {
+			if !seen[p.ID] {
+				seen[p.ID] = true
+				order = append(order, p.ID)
+			}
+		}
+	}
+	for _, id := range sortedKeys(ids) {
+		if !seen[id] {
+			order = append(order, id)
+		}
+	}
+	return order
+}
+
+// sameProject compares two projects ignoring their repos.
+func sameProject(a, b *project.ProjectEntry) bool {
+	x, y := *a, *b
+	x.Repos, y.Repos = nil, nil
+	return jsonEqual(x, y)
+}
+
+// repos returns a project's repos, or nil for a missing project.
+func repos(p *project.ProjectEntry) []string {
+	if p == nil {
+		return nil
+	}
+	return p.Repos
+}
+
+// mergeRepos returns the local repos plus those assigned on the remote since
+// base.
+func mergeRepos(local, base, remote []string) []string {
+	result := append([]string{}, local...)
+	have := make(map[string]bool)
+	for _, r := range local {
+		have[r] = true
+	}
+	inBase := make(map[string]bool)
+	for _, r := range base {
+		inBase[r] = true
+	}
+	for _, r := range remote {
+		if !have[r] && !inBase[r] {
+			result = append(result, r)
+			have[r] = true
+		}
+	}
+	return result
+}
+
+// jsonEqual compares two values by their JSON encoding.
+func jsonEqual(a, b any) bool {
+	x, err1 := json.Marshal(a)
+	y, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && string(x) == string(y)
+}
+
+// sortedKeys returns the keys of a set in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
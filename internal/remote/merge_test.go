@@ -0,0 +1,80 @@
+package remote
+
+import (
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeReposAddsButNeverRemoves(t *testing.T) {
+	local := []string{"/home/me/app"}
+	base := []string{"/home/me/app", "/Users/me/old"}
+	remote := []string{"/Users/me/app"}
+
+	assert.Equal(t, []string{"/home/me/app", "/Users/me/app"}, mergeRepos(local, base, remote))
+}
+
+func TestEventKey(t *testing.T) {
+	a := []byte(`{"id":"aaaa","type":"checkout","timestamp":"2025-06-16T10:00:00Z","previous":"main","next":"dev","repo":"/a"}`)
+	b := []byte(`{"id":"bbbb","type":"checkout","timestamp":"2025-06-16T12:00:00+02:00","previous":"main","next":"dev","repo":"/b"}`)
+	log := []byte(`{"id":"cccc","type":"log","start":"2025-06-16T10:00:00Z"}`)
+
+	assert.True(t, sameEvent(a, b))
+	assert.Empty(t, eventKey(log))
+	assert.False(t, sameEvent(log, log))
+}
+
+func TestMergeConfigByProjectID(t *testing.T) {
+	home := t.TempDir()
+	kept, err := project.CreateProject(home, "Kept")
+	require.NoError(t, err)
+	removed, err := project.CreateProject(home, "Removed")
+	require.NoError(t, err)
+	base, err := project.ReadConfig(home)
+	require.NoError(t, err)
+
+	// The remote removed one project, edited another and added a third
+	remote := &project.Config{Projects: []project.ProjectEntry{
+		{ID: kept.ID, Name: "Kept", Slug: kept.Slug, Repos: []string{"/remote/repo"}, Precise: true},
+		{ID: "new1234", Name: "New", Slug: "new"},
+	}}
+
+	result := &Result{}
+	require.NoError(t, mergeConfig(home, base, remote, result))
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	assert.Nil(t, project.FindProjectByID(cfg, removed.ID))
+	k := project.FindProjectByID(cfg, kept.ID)
+	require.NotNil(t, k)
+	assert.True(t, k.Precise)
+	assert.Equal(t, []string{"/remote/repo"}, k.Repos)
+	assert.NotNil(t, project.FindProjectByID(cfg, "new1234"))
+	assert.Equal(t, Result{ProjectsAdded: 1, ProjectsUpdated: 1, ProjectsRemoved: 1}, *result)
+}
+
+func TestMergeConfigRejectsNameClash(t *testing.T) {
+	home := t.TempDir()
+	_, err := project.CreateProject(home, "Acme")
+	require.NoError(t, err)
+
+	remote := &project.Config{Projects: []project.ProjectEntry{{ID: "other12", Name: "Acme", Slug: "acme"}}}
+	err = mergeConfig(home, &project.Config{}, remote, &Result{})
+	assert.ErrorContains(t, err, "clash after merging")
+}
+
+func TestLoadSideRejectsInvalidRemoteIDs(t *testing.T) {
+	home := t.TempDir()
+	proj, err := project.CreateProject(home, "Acme")
+	require.NoError(t, err)
+	require.NoError(t, project.SetStorage(home, project.StorageSegments))
+	data := []byte(`{"id":"x","type":"log","start":"2025-06-16T10:00:00Z","minutes":30}`)
+	require.NoError(t, entry.NewSegmentStore(home).Write(proj.Slug, "../../../../.bashrc", data))
+
+	_, err = loadSide(home, false)
+
+	assert.EqualError(t, err, `invalid entry ID "../../../../.bashrc" in remote data`)
+}
@@ -0,0 +1,384 @@
+// Package remote keeps the hourgit data directory in a git repository and
+// synchronises it with a remote, so several machines share one set of
+// projects and entries.
+//
+// Only config.json and entry data are versioned; caches, locks, the journal
+// and machine-local state are ignored. When config.json lives outside the
+// data directory, a copy of it is kept at the repository root. Pulls never use git's line merge:
+// the remote and merge-base trees are read back through the entry stores and
+// merged per project and per entry (see merge.go).
+package remote
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/seal"
+)
+
+// Name and Branch are the git remote and branch used for syncing.
+const (
+	Name   = "origin"
+	Branch = "main"
+)
+
+// remoteRef is the remote-tracking ref of Branch.
+const remoteRef = "refs/remotes/" + Name + "/" + Branch
+
+// configFile is the name of config.json in the repository.
+const configFile = "config.json"
+
+// gitignore lists what stays local to each machine.
+const gitignore = `# Managed by hourgit: only config.json and entries are synced
+*.lock
+.lock
+.index
+.backups/
+.quarantine/
+journal.jsonl
+watch.pid
+watch.state
+hourgit.key
+`
+
+// ErrNotConfigured is returned when the data directory has no remote.
+var ErrNotConfigured = errors.New("no remote configured (run 'hourgit remote add <url>' first)")
+
+// Result summarises a pull or push.
+type Result struct {
+	URL             string
+	UpToDate        bool // nothing came in from the remote
+	Pushed          bool
+	EntriesAdded    int
+	EntriesUpdated  int
+	EntriesDeleted  int
+	Duplicates      int // seeded checkout/commit entries recorded on both machines under different IDs
+	ProjectsAdded   int
+	ProjectsUpdated int
+	ProjectsRemoved int
+	Conflicts       []string // changed on both sides; the local version was kept
+}
+
+// dataDir returns the directory kept under version control.
+func dataDir(homeDir string) string {
+	return project.DataDir(homeDir)
+}
+
+// lockPath returns the lock held for the whole of a pull or push.
+func lockPath(homeDir string) string {
+	return filepath.Join(dataDir(homeDir), "remote.lock")
+}
+
+// git runs a git command in dir and returns its trimmed output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// isRepo reports whether the data directory is a git repository of its own.
+func isRepo(homeDir string) bool {
+	info, err := os.Stat(filepath.Join(dataDir(homeDir), ".git"))
+	return err == nil && info.IsDir()
+}
+
+// URL returns the configured remote URL, or ErrNotConfigured.
+func URL(homeDir string) (string, error) {
+	if !isRepo(homeDir) {
+		return "", ErrNotConfigured
+	}
+	url, err := git(dataDir(homeDir), "remote", "get-url", Name)
+	if err != nil {
+		return "", ErrNotConfigured
+	}
+	return url, nil
+}
+
+// Add turns the data directory into a git repository, points it at url and
+// pushes. If the remote already holds data from another machine, it is
+// merged in first.
+func Add(homeDir, url string) (*Result, error) {
+	dir := dataDir(homeDir)
+	if _, err := os.Stat(project.ConfigPath(homeDir)); err != nil {
+		return nil, fmt.Errorf("no hourgit data to sync")
+	}
+	if existing, err := URL(homeDir); err == nil {
+		return nil, fmt.Errorf("remote already configured: %s", existing)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	if !isRepo(homeDir) {
+		if _, err := git(dir, "init", "-q"); err != nil {
+			return nil, err
+		}
+		if _, err := git(dir, "symbolic-ref", "HEAD", "refs/heads/"+Branch); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeGitignore(dir); err != nil {
+		return nil, err
+	}
+	// Commits need an identity; fall back to one naming this machine
+	if _, err := git(dir, "config", "user.email"); err != nil {
+		host, _ := os.Hostname()
+		if _, err := git(dir, "config", "user.name", "hourgit"); err != nil {
+			return nil, err
+		}
+		if _, err := git(dir, "config", "user.email", "hourgit@"+host); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := git(dir, "remote", "add", Name, url); err != nil {
+		return nil, err
+	}
+	return Push(homeDir)
+}
+
+// Pull commits local changes, fetches the remote and merges it into the
+// local data.
+func Pull(homeDir string) (*Result, error) {
+	url, err := URL(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{URL: url}
+	err = fsutil.WithLock(lockPath(homeDir), func() error {
+		return pull(homeDir, result)
+	})
+	return result, err
+}
+
+// Push pulls, then pushes the merged data to the remote.
+func Push(homeDir string) (*Result, error) {
+	url, err := URL(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{URL: url}
+	err = fsutil.WithLock(lockPath(homeDir), func() error {
+		if err := pull(homeDir, result); err != nil {
+			return err
+		}
+		if _, err := git(dataDir(homeDir), "push", "-q", Name, "HEAD:refs/heads/"+Branch); err != nil {
+			return err
+		}
+		result.Pushed = true
+		return nil
+	})
+	return result, err
+}
+
+// pull merges the remote branch into the local data. Callers hold the lock.
+func pull(homeDir string, result *Result) error {
+	dir := dataDir(homeDir)
+	if err := writeGitignore(dir); err != nil {
+		return err
+	}
+	if err := copyConfig(homeDir); err != nil {
+		return err
+	}
+	if err := commitAll(dir, "Record local changes"); err != nil {
+		return err
+	}
+	if _, err := git(dir, "fetch", "-q", Name); err != nil {
+		return err
+	}
+
+	if _, err := git(dir, "rev-parse", "--verify", "-q", remoteRef); err != nil {
+		// Nothing has been pushed yet
+		result.UpToDate = true
+		return nil
+	}
+	if _, err := git(dir, "merge-base", "--is-ancestor", remoteRef, "HEAD"); err == nil {
+		result.UpToDate = true
+		return nil
+	}
+
+	base, _ := git(dir, "merge-base", "HEAD", remoteRef)
+
+	tmp, err := os.MkdirTemp("", "hourgit-remote-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	remoteSide, err := readTree(homeDir, remoteRef, filepath.Join(tmp, "remote"))
+	if err != nil {
+		return err
+	}
+	baseSide := emptySide()
+	if base != "" {
+		if baseSide, err = readTree(homeDir, base, filepath.Join(tmp, "base")); err != nil {
+			return err
+		}
+	}
+
+	// Record the remote as merged, keeping the local tree; the data is then
+	// merged by hourgit itself.
+	if _, err := git(dir, "merge", "-q", "-s", "ours", "--no-ff", "--no-commit", "--allow-unrelated-histories", remoteRef); err != nil {
+		return err
+	}
+	if err := merge(homeDir, baseSide, remoteSide, result); err != nil {
+		_, _ = git(dir, "merge", "--abort")
+		return err
+	}
+	if err := copyConfig(homeDir); err != nil {
+		return err
+	}
+	return commitAll(dir, "Merge changes from "+Name)
+}
+
+// writeGitignore keeps the ignore list up to date with this version.
+func writeGitignore(dir string) error {
+	path := filepath.Join(dir, ".gitignore")
+	if current, err := os.ReadFile(path); err == nil && string(current) == gitignore {
+		return nil
+	}
+	return fsutil.WriteFileAtomic(path, []byte(gitignore), 0644)
+}
+
+// RefreshConfig updates the versioned copy of config.json, e.g. after it was
+// encrypted, so plain data does not linger in the data directory until the
+// next pull.
+func RefreshConfig(homeDir string) error {
+	if !isRepo(homeDir) {
+		return nil
+	}
+	return copyConfig(homeDir)
+}
+
+// copyConfig refreshes the versioned copy of config.json when the config
+// directory is not the data directory.
+func copyConfig(homeDir string) error {
+	src, dest := project.ConfigPath(homeDir), filepath.Join(dataDir(homeDir), configFile)
+	if src == dest {
+		return nil
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(dest, data, 0600)
+}
+
+// commitAll commits every change in the data directory. A pending merge is
+// committed even when the tree did not change.
+func commitAll(dir, message string) error {
+	if _, err := git(dir, "add", "-A"); err != nil {
+		return err
+	}
+	status, err := git(dir, "status", "--porcelain")
+	if err != nil {
+		return err
+	}
+	_, merging := os.Stat(filepath.Join(dir, ".git", "MERGE_HEAD"))
+	if status == "" && merging != nil {
+		return nil
+	}
+	host, _ := os.Hostname()
+	_, err = git(dir, "commit", "-q", "--no-verify", "-m", fmt.Sprintf("%s (%s)", message, host))
+	return err
+}
+
+// readTree loads the data as of rev, extracted under home. Encrypted data
+// must use the local key.
+func readTree(homeDir, rev, home string) (*side, error) {
+	if err := extractTree(dataDir(homeDir), rev, home); err != nil {
+		return nil, err
+	}
+	if err := checkKey(homeDir, home); err != nil {
+		return nil, err
+	}
+	cfg, err := project.ReadConfig(home)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SchemaVersion > project.SchemaVersion {
+		return nil, fmt.Errorf("remote data uses schema version %d, but this version of hourgit only supports up to %d — please update hourgit", cfg.SchemaVersion, project.SchemaVersion)
+	}
+	return loadSide(home, false)
+}
+
+// checkKey makes sure encrypted data extracted under home can be opened with
+// homeDir's key. Plain data is always readable.
+func checkKey(homeDir, home string) error {
+	theirs, err := seal.ReadHeader(home)
+	if err != nil || theirs == nil {
+		return err
+	}
+	ours, err := seal.ReadHeader(homeDir)
+	if err != nil {
+		return err
+	}
+	if !seal.SameKey(ours, theirs) {
+		return fmt.Errorf("the remote data is encrypted with another key — copy %s from a machine that pushed it into %s and run 'hourgit encrypt'", seal.HeaderName, dataDir(homeDir))
+	}
+	// Keys are cached by salt, so unlocking here opens the extracted data too
+	return seal.Unlock(homeDir)
+}
+
+// extractTree writes the data directory as of rev into home's data and config
+// directories, so it can be read through the usual config and entry
+// functions.
+func extractTree(dir, rev, home string) error {
+	cmd := exec.Command("git", "-C", dir, "archive", "--format=tar", rev)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("git archive: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	target := dataDir(home)
+	tr := tar.NewReader(bytes.NewReader(out))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		path := filepath.Join(target, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(path, target+string(filepath.Separator)) {
+			return fmt.Errorf("unexpected path %q in remote data", hdr.Name)
+		}
+		if hdr.Name == configFile {
+			path = project.ConfigPath(home)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package remote
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/paths"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBareRemote creates an empty bare repository to sync through.
+func newBareRemote(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	out, err := exec.Command("git", "init", "-q", "--bare", dir).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return dir
+}
+
+// newMachine creates a data directory with one project.
+func newMachine(t *testing.T, projectName string) (home string, proj *project.ProjectEntry) {
+	t.Helper()
+	home = t.TempDir()
+	proj, err := project.CreateProject(home, projectName)
+	require.NoError(t, err)
+	return home, proj
+}
+
+func writeLog(t *testing.T, home, slug, id, message string) {
+	t.Helper()
+	require.NoError(t, entry.WriteEntry(home, slug, entry.Entry{
+		ID: id, Start: time.Date(2025, 6, 16, 9, 0, 0, 0, time.UTC), Minutes: 30, Message: message,
+	}))
+}
+
+func readMessage(t *testing.T, home, slug, id string) string {
+	t.Helper()
+	e, err := entry.ReadEntry(home, slug, id)
+	require.NoError(t, err)
+	return e.Message
+}
+
+// syncedPair sets up two machines sharing a project through a remote.
+func syncedPair(t *testing.T) (a, b string, proj *project.ProjectEntry) {
+	t.Helper()
+	url := newBareRemote(t)
+	a, proj = newMachine(t, "Acme")
+	writeLog(t, a, proj.Slug, "aaaa11112222", "first")
+	_, err := Add(a, url)
+	require.NoError(t, err)
+
+	b = t.TempDir()
+	require.NoError(t, os.MkdirAll(project.ConfigDir(b), 0755))
+	require.NoError(t, project.WriteConfig(b, &project.Config{}))
+	_, err = Add(b, url)
+	require.NoError(t, err)
+	return a, b, proj
+}
+
+func TestAddPushesToEmptyRemote(t *testing.T) {
+	url := newBareRemote(t)
+	home, proj := newMachine(t, "Acme")
+	writeLog(t, home, proj.Slug, "aaaa11112222", "first")
+
+	result, err := Add(home, url)
+	require.NoError(t, err)
+	assert.True(t, result.Pushed)
+	assert.True(t, result.UpToDate)
+
+	got, err := URL(home)
+	require.NoError(t, err)
+	assert.Equal(t, url, got)
+
+	files, err := exec.Command("git", "-C", url, "ls-tree", "-r", "--name-only", Branch).Output()
+	require.NoError(t, err)
+	assert.Contains(t, string(files), "config.json")
+	assert.Contains(t, string(files), proj.Slug+"/aaaa11112222")
+	assert.NotContains(t, string(files), "journal.jsonl")
+	assert.NotContains(t, string(files), ".index")
+
+	_, err = Add(home, url)
+	assert.ErrorContains(t, err, "remote already configured")
+}
+
+func TestAddWithoutData(t *testing.T) {
+	_, err := Add(t.TempDir(), newBareRemote(t))
+	assert.EqualError(t, err, "no hourgit data to sync")
+}
+
+func TestPullWithoutRemote(t *testing.T) {
+	home, _ := newMachine(t, "Acme")
+	_, err := Pull(home)
+	assert.ErrorIs(t, err, ErrNotConfigured)
+}
+
+func TestAddMergesExistingRemoteData(t *testing.T) {
+	a, b, proj := syncedPair(t)
+
+	cfg, err := project.ReadConfig(b)
+	require.NoError(t, err)
+	require.NotNil(t, project.FindProjectByID(cfg, proj.ID))
+	assert.Equal(t, "first", readMessage(t, b, proj.Slug, "aaaa11112222"))
+
+	// The first machine picks up a project created on the second one
+	other, err := project.CreateProject(b, "Other")
+	require.NoError(t, err)
+	_, err = Push(b)
+	require.NoError(t, err)
+
+	result, err := Pull(a)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ProjectsAdded)
+	cfg, err = project.ReadConfig(a)
+	require.NoError(t, err)
+	assert.NotNil(t, project.FindProjectByID(cfg, other.ID))
+}
+
+func TestPullMergesPerEntry(t *testing.T) {
+	a, b, proj := syncedPair(t)
+
+	// Both machines log work and edit the shared entry
+	writeLog(t, a, proj.Slug, "bbbb11112222", "from a")
+	writeLog(t, a, proj.Slug, "aaaa11112222", "edited on a")
+	writeLog(t, b, proj.Slug, "cccc11112222", "from b")
+	writeLog(t, b, proj.Slug, "aaaa11112222", "edited on b")
+
+	_, err := Push(a)
+	require.NoError(t, err)
+	result, err := Push(b)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.EntriesAdded)
+	require.Len(t, result.Conflicts, 1)
+	assert.Contains(t, result.Conflicts[0], proj.Slug+"/aaaa11112222")
+	assert.Equal(t, "edited on b", readMessage(t, b, proj.Slug, "aaaa11112222"))
+	assert.Equal(t, "from a", readMessage(t, b, proj.Slug, "bbbb11112222"))
+
+	// The merged state flows back; the conflict was settled on b
+	result, err = Pull(a)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.EntriesAdded)
+	assert.Equal(t, 1, result.EntriesUpdated)
+	assert.Empty(t, result.Conflicts)
+	assert.Equal(t, "edited on b", readMessage(t, a, proj.Slug, "aaaa11112222"))
+	assert.Equal(t, "from b", readMessage(t, a, proj.Slug, "cccc11112222"))
+
+	// Up to date afterwards
+	result, err = Pull(a)
+	require.NoError(t, err)
+	assert.True(t, result.UpToDate)
+}
+
+func TestPullAppliesRemoteDeletes(t *testing.T) {
+	a, b, proj := syncedPair(t)
+
+	require.NoError(t, entry.DeleteEntry(a, proj.Slug, "aaaa11112222"))
+	_, err := Push(a)
+	require.NoError(t, err)
+
+	result, err := Pull(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.EntriesDeleted)
+	_, err = entry.ReadRecord(b, proj.Slug, "aaaa11112222")
+	assert.ErrorIs(t, err, entry.ErrNotFound)
+}
+
+func TestPullFollowsRemoteRename(t *testing.T) {
+	a, b, proj := syncedPair(t)
+
+	renamed, err := project.RenameProject(a, proj.ID, "Acme Corp")
+	require.NoError(t, err)
+	_, err = Push(a)
+	require.NoError(t, err)
+
+	// b logs more work under the old directory before pulling
+	writeLog(t, b, proj.Slug, "dddd11112222", "local work")
+	result, err := Pull(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ProjectsUpdated)
+
+	cfg, err := project.ReadConfig(b)
+	require.NoError(t, err)
+	assert.Equal(t, renamed.Slug, project.FindProjectByID(cfg, proj.ID).Slug)
+	assert.Equal(t, "first", readMessage(t, b, renamed.Slug, "aaaa11112222"))
+	assert.Equal(t, "local work", readMessage(t, b, renamed.Slug, "dddd11112222"))
+}
+
+func TestPullDropsDuplicateSyncedEvents(t *testing.T) {
+	a, b, proj := syncedPair(t)
+
+	ts := time.Date(2025, 6, 16, 10, 0, 0, 0, time.UTC)
+	checkout := func(id, repo string) entry.CheckoutEntry {
+		return entry.CheckoutEntry{ID: id, Timestamp: ts, Previous: "main", Next: "feature", CommitRef: "HEAD@{0}", Repo: repo}
+	}
+	// Same event, same ID, different clone paths
+	require.NoError(t, entry.WriteCheckoutEntry(a, proj.Slug, checkout("eeee11112222", "/home/me/app")))
+	require.NoError(t, entry.WriteCheckoutEntry(b, proj.Slug, checkout("eeee11112222", "/Users/me/app")))
+	// Same event under a legacy-length ID on one machine
+	require.NoError(t, entry.WriteCommitEntry(a, proj.Slug, entry.CommitEntry{ID: "ffff1111", Timestamp: ts, CommitRef: "abc1234", Message: "x"}))
+	require.NoError(t, entry.WriteCommitEntry(b, proj.Slug, entry.CommitEntry{ID: "ffff11112222", Timestamp: ts, CommitRef: "abc1234", Message: "x"}))
+
+	_, err := Push(a)
+	require.NoError(t, err)
+	result, err := Pull(b)
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Conflicts)
+	assert.Equal(t, 1, result.Duplicates)
+	_, err = entry.ReadRecord(b, proj.Slug, "ffff11112222")
+	assert.NoError(t, err, "the local copy is kept")
+	_, err = entry.ReadRecord(b, proj.Slug, "ffff1111")
+	assert.ErrorIs(t, err, entry.ErrNotFound)
+}
+
+func TestPullRejectsNewerSchema(t *testing.T) {
+	a, b, _ := syncedPair(t)
+
+	cfg, err := project.ReadConfig(a)
+	require.NoError(t, err)
+	cfg.SchemaVersion = project.SchemaVersion + 1
+	require.NoError(t, project.WriteConfig(a, cfg))
+	_, err = Push(a)
+	require.NoError(t, err)
+
+	_, err = Pull(b)
+	assert.ErrorContains(t, err, "please update hourgit")
+	_, statErr := os.Stat(filepath.Join(project.DataDir(b), ".git", "MERGE_HEAD"))
+	assert.True(t, os.IsNotExist(statErr), "no merge should be left in progress")
+}
+
+func TestSyncWithSingleDataRoot(t *testing.T) {
+	url := newBareRemote(t)
+	a, proj := newMachine(t, "Acme")
+	writeLog(t, a, proj.Slug, "aaaa11112222", "first")
+	_, err := Add(a, url)
+	require.NoError(t, err)
+
+	// The second machine keeps config and entries together, as with HOURGIT_HOME
+	b := t.TempDir()
+	root := filepath.Join(b, "hourgit")
+	defer paths.Use(b, paths.Layout{Config: root, Data: root, State: root, Runtime: root})()
+	require.NoError(t, project.WriteConfig(b, &project.Config{}))
+	_, err = Add(b, url)
+	require.NoError(t, err)
+
+	cfg, err := project.ReadConfig(b)
+	require.NoError(t, err)
+	assert.NotNil(t, project.FindProjectByID(cfg, proj.ID))
+	assert.Equal(t, "first", readMessage(t, b, proj.Slug, "aaaa11112222"))
+	assert.FileExists(t, filepath.Join(root, "config.json"))
+}
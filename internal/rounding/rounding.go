@@ -0,0 +1,150 @@
+// Package rounding rounds attributed and logged minutes to billing
+// increments, e.g. up to the next 6 or 15 minutes, per entry, per task and
+// day, or per day.
+package rounding
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rounding modes.
+const (
+	ModeUp      = "up"
+	ModeNearest = "nearest"
+	ModeDown    = "down"
+)
+
+// Rounding scopes — the level at which minutes are rounded.
+const (
+	ScopeEntry   = "entry"    // every entry on its own
+	ScopeTaskDay = "task-day" // the total of a task on a day
+	ScopeDay     = "day"      // the total of a day
+)
+
+// Policy is the storable rounding policy of a project. The zero value
+// disables rounding.
+type Policy struct {
+	Increment int    `json:"increment"` // minutes, e.g. 6 or 15
+	Mode      string `json:"mode"`      // up, nearest or down
+	Scope     string `json:"scope"`     // entry, task-day or day
+}
+
+// Parse parses a policy written as INCREMENT[:MODE[:SCOPE]], e.g. "15",
+// "6:up" or "15:nearest:task-day". The mode defaults to nearest and the scope
+// to entry. "off" and the empty string disable rounding.
+func Parse(spec string) (Policy, error) {
+	spec = strings.TrimSpace(strings.ToLower(spec))
+	if spec == "" || spec == "off" {
+		return Policy{}, nil
+	}
+
+	parts := strings.Split(spec, ":")
+	if len(parts) > 3 {
+		return Policy{}, fmt.Errorf("invalid rounding %q (expected INCREMENT[:MODE[:SCOPE]], e.g. 15:up:entry)", spec)
+	}
+	increment, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Policy{}, fmt.Errorf("invalid rounding increment %q (expected minutes, e.g. 15)", parts[0])
+	}
+
+	p := Policy{Increment: increment, Mode: ModeNearest, Scope: ScopeEntry}
+	if len(parts) > 1 {
+		p.Mode = parts[1]
+	}
+	if len(parts) > 2 {
+		p.Scope = parts[2]
+	}
+	return p, p.Validate()
+}
+
+// Validate checks that the policy is either disabled or fully specified.
+func (p Policy) Validate() error {
+	if p == (Policy{}) {
+		return nil
+	}
+	if p.Increment < 1 || p.Increment > 24*60 {
+		return fmt.Errorf("invalid rounding increment %d (expected 1-1440 minutes)", p.Increment)
+	}
+	switch p.Mode {
+	case ModeUp, ModeNearest, ModeDown:
+	default:
+		return fmt.Errorf("invalid rounding mode %q (supported: up, nearest, down)", p.Mode)
+	}
+	switch p.Scope {
+	case ScopeEntry, ScopeTaskDay, ScopeDay:
+	default:
+		return fmt.Errorf("invalid rounding scope %q (supported: entry, task-day, day)", p.Scope)
+	}
+	return nil
+}
+
+// Enabled reports whether the policy changes any minutes.
+func (p Policy) Enabled() bool {
+	return p.Increment > 1
+}
+
+// Round rounds minutes to the policy's increment, regardless of scope.
+func (p Policy) Round(minutes int) int {
+	if !p.Enabled() || minutes <= 0 {
+		return minutes
+	}
+	rest := minutes % p.Increment
+	if rest == 0 {
+		return minutes
+	}
+	down := minutes - rest
+	switch p.Mode {
+	case ModeUp:
+		return down + p.Increment
+	case ModeDown:
+		return down
+	default:
+		if 2*rest >= p.Increment {
+			return down + p.Increment
+		}
+		return down
+	}
+}
+
+// Entry rounds the minutes of a single entry when the policy's scope is
+// entry, and returns them unchanged otherwise.
+func (p Policy) Entry(minutes int) int {
+	return p.roundAt(ScopeEntry, minutes)
+}
+
+// TaskDay rounds the total of a task on a day when the policy's scope is
+// task-day, and returns it unchanged otherwise.
+func (p Policy) TaskDay(minutes int) int {
+	return p.roundAt(ScopeTaskDay, minutes)
+}
+
+// Day rounds the total of a day when the policy's scope is day, and returns
+// it unchanged otherwise.
+func (p Policy) Day(minutes int) int {
+	return p.roundAt(ScopeDay, minutes)
+}
+
+func (p Policy) roundAt(scope string, minutes int) int {
+	if p.Scope != scope {
+		return minutes
+	}
+	return p.Round(minutes)
+}
+
+// String describes the policy, e.g. "15 min, up, per task-day", or "off".
+func (p Policy) String() string {
+	if !p.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d min, %s, per %s", p.Increment, p.Mode, p.Scope)
+}
+
+// Spec returns the policy in the form accepted by Parse.
+func (p Policy) Spec() string {
+	if !p.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d:%s:%s", p.Increment, p.Mode, p.Scope)
+}
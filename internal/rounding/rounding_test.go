@@ -0,0 +1,91 @@
+package rounding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec string
+		want Policy
+	}{
+		{"", Policy{}},
+		{"off", Policy{}},
+		{"15", Policy{Increment: 15, Mode: ModeNearest, Scope: ScopeEntry}},
+		{"6:up", Policy{Increment: 6, Mode: ModeUp, Scope: ScopeEntry}},
+		{" 15:Down:task-day ", Policy{Increment: 15, Mode: ModeDown, Scope: ScopeTaskDay}},
+		{"30:nearest:day", Policy{Increment: 30, Mode: ModeNearest, Scope: ScopeDay}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			p, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"x", "invalid rounding increment"},
+		{"0", "invalid rounding increment 0"},
+		{"15:sideways", "invalid rounding mode"},
+		{"15:up:week", "invalid rounding scope"},
+		{"15:up:day:extra", "expected INCREMENT[:MODE[:SCOPE]]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := Parse(tt.spec)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		mode    string
+		minutes int
+		want    int
+	}{
+		{ModeUp, 0, 0},
+		{ModeUp, 1, 15},
+		{ModeUp, 15, 15},
+		{ModeUp, 16, 30},
+		{ModeDown, 29, 15},
+		{ModeDown, 14, 0},
+		{ModeNearest, 7, 0},
+		{ModeNearest, 8, 15},
+		{ModeNearest, 22, 15},
+		{ModeNearest, 23, 30},
+	}
+	for _, tt := range tests {
+		p := Policy{Increment: 15, Mode: tt.mode, Scope: ScopeEntry}
+		assert.Equal(t, tt.want, p.Round(tt.minutes), "%s %d", tt.mode, tt.minutes)
+	}
+
+	assert.Equal(t, 37, Policy{}.Round(37), "the zero policy keeps minutes")
+	assert.Equal(t, 12, Policy{Increment: 6, Mode: ModeNearest, Scope: ScopeEntry}.Round(10))
+}
+
+func TestScopes(t *testing.T) {
+	p := Policy{Increment: 15, Mode: ModeUp, Scope: ScopeTaskDay}
+
+	assert.Equal(t, 10, p.Entry(10))
+	assert.Equal(t, 15, p.TaskDay(10))
+	assert.Equal(t, 10, p.Day(10))
+}
+
+func TestString(t *testing.T) {
+	p := Policy{Increment: 15, Mode: ModeUp, Scope: ScopeTaskDay}
+
+	assert.Equal(t, "15 min, up, per task-day", p.String())
+	assert.Equal(t, "15:up:task-day", p.Spec())
+	assert.Equal(t, "off", Policy{}.String())
+	assert.Equal(t, "off", Policy{}.Spec())
+}
@@ -0,0 +1,138 @@
+// Package rules maps checkout time to task keys and categories by its branch
+// name, commit message and repository, so that e.g. feature/PROJ-12-login and
+// bugfix/PROJ-12-followup roll up under the same ticket.
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Common categories. Any other name works as well.
+const (
+	CategoryDevelopment = "development"
+	CategoryReview      = "review"
+	CategoryMaintenance = "maintenance"
+)
+
+// Rule is a storable rule. Its matchers are regular expressions; a rule
+// applies when all of its non-empty matchers match.
+type Rule struct {
+	Branch   string `json:"branch,omitempty"`   // matches the branch name, e.g. ^feature/(PROJ-\d+)
+	Commit   string `json:"commit,omitempty"`   // matches the commit message, e.g. ^fix(\(.+\))?:
+	Repo     string `json:"repo,omitempty"`     // matches the repository path
+	Task     string `json:"task,omitempty"`     // task key; $1 or ${name} refer to the first matcher's groups
+	Category string `json:"category,omitempty"` // e.g. development, review or maintenance
+}
+
+// Validate checks that the rule matches something, assigns something and
+// that its expressions compile.
+func (r Rule) Validate() error {
+	_, err := compile(r)
+	return err
+}
+
+// String describes the rule, e.g. "branch ^feature/(PROJ-\d+) → task $1".
+func (r Rule) String() string {
+	var match, assign []string
+	for _, m := range []struct{ name, expr string }{{"branch", r.Branch}, {"commit", r.Commit}, {"repo", r.Repo}} {
+		if m.expr != "" {
+			match = append(match, m.name+" "+m.expr)
+		}
+	}
+	if r.Task != "" {
+		assign = append(assign, "task "+r.Task)
+	}
+	if r.Category != "" {
+		assign = append(assign, "category "+r.Category)
+	}
+	return strings.Join(match, ", ") + " → " + strings.Join(assign, ", ")
+}
+
+// Engine applies a list of rules. The zero value and nil apply none.
+type Engine struct {
+	rules []compiled
+}
+
+type compiled struct {
+	rule                 Rule
+	branch, commit, repo *regexp.Regexp
+}
+
+// Compile prepares rules for Apply. Invalid rules are skipped; check them
+// with Validate when they are stored.
+func Compile(rules []Rule) *Engine {
+	e := &Engine{}
+	for _, r := range rules {
+		if c, err := compile(r); err == nil {
+			e.rules = append(e.rules, c)
+		}
+	}
+	return e
+}
+
+// Apply returns the task key and category of time on branch in repo with
+// the commit message message, per the first rule that matches. Without a
+// match, or when the rule sets no task, the task is the branch name; without
+// a match the category is empty.
+func (e *Engine) Apply(branch, message, repo string) (task, category string) {
+	if e == nil {
+		return branch, ""
+	}
+	for _, c := range e.rules {
+		var template []byte
+		ok := true
+		for _, m := range []struct {
+			re    *regexp.Regexp
+			value string
+		}{{c.branch, branch}, {c.commit, message}, {c.repo, repo}} {
+			if m.re == nil {
+				continue
+			}
+			idx := m.re.FindStringSubmatchIndex(m.value)
+			if idx == nil {
+				ok = false
+				break
+			}
+			if template == nil && c.rule.Task != "" {
+				template = m.re.ExpandString(nil, c.rule.Task, m.value, idx)
+			}
+		}
+		if !ok {
+			continue
+		}
+		task = branch
+		if key := strings.TrimSpace(string(template)); key != "" {
+			task = key
+		}
+		return task, c.rule.Category
+	}
+	return branch, ""
+}
+
+func compile(r Rule) (compiled, error) {
+	if r.Branch == "" && r.Commit == "" && r.Repo == "" {
+		return compiled{}, fmt.Errorf("rule matches nothing (set a branch, commit or repo expression)")
+	}
+	if r.Task == "" && r.Category == "" {
+		return compiled{}, fmt.Errorf("rule assigns nothing (set a task or category)")
+	}
+
+	c := compiled{rule: r}
+	for _, m := range []struct {
+		name string
+		expr string
+		re   **regexp.Regexp
+	}{{"branch", r.Branch, &c.branch}, {"commit", r.Commit, &c.commit}, {"repo", r.Repo, &c.repo}} {
+		if m.expr == "" {
+			continue
+		}
+		re, err := regexp.Compile(m.expr)
+		if err != nil {
+			return compiled{}, fmt.Errorf("invalid %s expression %q: %w", m.name, m.expr, err)
+		}
+		*m.re = re
+	}
+	return c, nil
+}
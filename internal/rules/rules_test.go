@@ -0,0 +1,68 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	engine := Compile([]Rule{
+		{Branch: `^(?:feature|bugfix)/(PROJ-\d+)`, Task: "$1", Category: CategoryDevelopment},
+		{Commit: `^fix(\(.+\))?!?:`, Category: CategoryMaintenance},
+		{Branch: `^review/`, Repo: `/infra$`, Task: "infra review", Category: CategoryReview},
+		{Branch: `^hotfix/(?P<ticket>OPS-\d+)`, Task: "${ticket}"},
+	})
+
+	tests := []struct {
+		branch, message, repo string
+		task, category        string
+	}{
+		{"feature/PROJ-12-login", "", "/src/app", "PROJ-12", CategoryDevelopment},
+		{"bugfix/PROJ-12-followup", "fix: typo", "/src/app", "PROJ-12", CategoryDevelopment},
+		{"main", "fix(api): handle nil", "/src/app", "main", CategoryMaintenance},
+		{"main", "feat: add export", "/src/app", "main", ""},
+		{"review/pr-7", "", "/src/infra", "infra review", CategoryReview},
+		{"review/pr-7", "", "/src/app", "review/pr-7", ""},
+		{"hotfix/OPS-3-disk", "", "", "OPS-3", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch+" "+tt.message, func(t *testing.T) {
+			task, category := engine.Apply(tt.branch, tt.message, tt.repo)
+			assert.Equal(t, tt.task, task)
+			assert.Equal(t, tt.category, category)
+		})
+	}
+}
+
+func TestApplyNilEngine(t *testing.T) {
+	var engine *Engine
+
+	task, category := engine.Apply("feature/x", "msg", "/repo")
+
+	assert.Equal(t, "feature/x", task)
+	assert.Equal(t, "", category)
+}
+
+func TestCompileSkipsInvalidRules(t *testing.T) {
+	engine := Compile([]Rule{
+		{Branch: `(`, Task: "broken"},
+		{Branch: `^feature/`, Category: CategoryDevelopment},
+	})
+
+	_, category := engine.Apply("feature/x", "", "")
+	assert.Equal(t, CategoryDevelopment, category)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Rule{Branch: `^feature/(\w+)`, Task: "$1"}.Validate())
+	assert.ErrorContains(t, Rule{Task: "x"}.Validate(), "matches nothing")
+	assert.ErrorContains(t, Rule{Branch: "x"}.Validate(), "assigns nothing")
+	assert.ErrorContains(t, Rule{Commit: "(", Category: "x"}.Validate(), "invalid commit expression")
+}
+
+func TestString(t *testing.T) {
+	r := Rule{Branch: `^feature/(PROJ-\d+)`, Repo: "/app$", Task: "$1", Category: CategoryDevelopment}
+
+	assert.Equal(t, `branch ^feature/(PROJ-\d+), repo /app$ → task $1, category development`, r.String())
+}
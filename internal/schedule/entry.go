@@ -26,6 +26,10 @@ type ScheduleEntry struct {
 	Ranges   []TimeRange `json:"ranges"`
 	RRule    string      `json:"rrule"`              // RFC 5545 RRULE string (always present)
 	Override bool        `json:"override,omitempty"` // when true, replaces all previous windows for matching days
+	Timezone string      `json:"timezone,omitempty"` // IANA time zone of the ranges; empty = the project's
+	// SplitAtMidnight splits overnight ranges so the minutes after midnight
+	// count towards the next day instead of the day the range started.
+	SplitAtMidnight bool `json:"split_at_midnight,omitempty"`
 }
 
 // DefaultSchedules returns the default working schedule: Mon-Fri 9am-5pm.
@@ -49,6 +53,9 @@ func ToEntry(s Schedule) ScheduleEntry {
 	if s.RRule != nil {
 		e.RRule = s.RRule.String()
 	}
+	if s.Location != nil {
+		e.Timezone = s.Location.String()
+	}
 	return e
 }
 
@@ -68,8 +75,8 @@ func FromEntry(e ScheduleEntry) (Schedule, error) {
 		if err != nil {
 			return Schedule{}, fmt.Errorf("invalid to time %q: %w", r.To, err)
 		}
-		if !from.Before(to) {
-			return Schedule{}, fmt.Errorf("start time %s must be before end time %s", r.From, r.To)
+		if from == to {
+			return Schedule{}, fmt.Errorf("start time %s must differ from end time %s", r.From, r.To)
 		}
 		ranges[i] = TimeOfDayRange{From: from, To: to}
 	}
@@ -88,12 +95,20 @@ func FromEntry(e ScheduleEntry) (Schedule, error) {
 		s.RRule = r
 	}
 
+	if e.Timezone != "" {
+		loc, err := LoadLocation(e.Timezone)
+		if err != nil {
+			return Schedule{}, err
+		}
+		s.Location = loc
+	}
+
 	return s, nil
 }
 
 // ValidateRanges validates a slice of TimeRange values for use by the CLI
-// during interactive input. It checks that each range has from < to and
-// that ranges don't overlap.
+// during interactive input. It checks that each range has from != to (a range
+// ending before it starts is overnight) and that ranges don't overlap.
 func ValidateRanges(ranges []TimeRange) error {
 	parsed := make([]TimeOfDayRange, len(ranges))
 	for i, r := range ranges {
@@ -105,8 +120,8 @@ func ValidateRanges(ranges []TimeRange) error {
 		if err != nil {
 			return fmt.Errorf("invalid to time %q: %w", r.To, err)
 		}
-		if !from.Before(to) {
-			return fmt.Errorf("start time %s must be before end time %s", r.From, r.To)
+		if from == to {
+			return fmt.Errorf("start time %s must differ from end time %s", r.From, r.To)
 		}
 		parsed[i] = TimeOfDayRange{From: from, To: to}
 	}
@@ -114,27 +129,39 @@ func ValidateRanges(ranges []TimeRange) error {
 }
 
 // validateNoOverlap checks that no two ranges overlap. Ranges are assumed to
-// already be individually valid (from < to). Sorts a copy by start time and
-// checks each pair.
+// already be individually valid (from != to). Overnight ranges run into the
+// next day, so every range is also checked against the next day's copy of the
+// others: 22:00-06:00 and 05:00-09:00 overlap.
 func validateNoOverlap(ranges []TimeOfDayRange) error {
 	if len(ranges) < 2 {
 		return nil
 	}
 
-	sorted := make([]TimeOfDayRange, len(ranges))
-	copy(sorted, ranges)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].From.Before(sorted[j].From)
+	type span struct {
+		from, to int // minutes since midnight of the first day
+		r        TimeOfDayRange
+	}
+	spans := make([]span, 0, 2*len(ranges))
+	for _, r := range ranges {
+		w := TimeWindow{From: r.From, To: r.To}
+		from, to := r.From.Minutes(), w.End()
+		spans = append(spans, span{from, to, r}, span{from + 24*60, to + 24*60, r})
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].from < spans[j].from
 	})
 
-	for i := 1; i < len(sorted); i++ {
-		prev := sorted[i-1]
-		curr := sorted[i]
-		// Overlap if curr.From < prev.To
-		if curr.From.Before(prev.To) {
+	for i := 1; i < len(spans); i++ {
+		prev := spans[i-1]
+		curr := spans[i]
+		if curr.from < prev.to {
+			first, second := prev.r, curr.r
+			if second.From.Before(first.From) {
+				first, second = second, first
+			}
 			return fmt.Errorf("time ranges overlap: %s-%s and %s-%s",
-				prev.From.String(), prev.To.String(),
-				curr.From.String(), curr.To.String())
+				first.From.String(), first.To.String(),
+				second.From.String(), second.To.String())
 		}
 	}
 
@@ -186,9 +186,17 @@ func TestFromEntryInvalidTo(t *testing.T) {
 
 func TestFromEntryOverMidnight(t *testing.T) {
 	entry := ScheduleEntry{Ranges: []TimeRange{{From: "22:00", To: "06:00"}}, RRule: "FREQ=DAILY"}
+	s, err := FromEntry(entry)
+	require.NoError(t, err)
+	assert.Equal(t, TimeOfDay{Hour: 22}, s.Ranges[0].From)
+	assert.Equal(t, TimeOfDay{Hour: 6}, s.Ranges[0].To)
+}
+
+func TestFromEntryEmptyRange(t *testing.T) {
+	entry := ScheduleEntry{Ranges: []TimeRange{{From: "09:00", To: "09:00"}}, RRule: "FREQ=DAILY"}
 	_, err := FromEntry(entry)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "must be before end time")
+	assert.Contains(t, err.Error(), "must differ from end time")
 }
 
 func TestFromEntryOverlappingRanges(t *testing.T) {
@@ -211,6 +219,23 @@ func TestFromEntryInvalidRRule(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid rrule")
 }
 
+func TestFromEntryInvalidTimezone(t *testing.T) {
+	entry := ScheduleEntry{Ranges: []TimeRange{{From: "09:00", To: "17:00"}}, RRule: "FREQ=DAILY", Timezone: "Nowhere/City"}
+	_, err := FromEntry(entry)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid time zone")
+}
+
+func TestRoundTripWithTimezone(t *testing.T) {
+	entry := ScheduleEntry{Ranges: []TimeRange{{From: "09:00", To: "17:00"}}, RRule: "FREQ=DAILY", Timezone: "America/New_York"}
+
+	s, err := FromEntry(entry)
+	require.NoError(t, err)
+	require.NotNil(t, s.Location)
+	assert.Equal(t, "America/New_York", s.Location.String())
+	assert.Equal(t, "America/New_York", ToEntry(s).Timezone)
+}
+
 func TestRoundTrip(t *testing.T) {
 	r, err := rrule.NewRRule(rrule.ROption{
 		Freq:      rrule.WEEKLY,
@@ -308,8 +333,30 @@ func TestValidateRanges(t *testing.T) {
 
 	t.Run("over-midnight range", func(t *testing.T) {
 		err := ValidateRanges([]TimeRange{{From: "22:00", To: "06:00"}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("over-midnight range with a day range", func(t *testing.T) {
+		err := ValidateRanges([]TimeRange{
+			{From: "22:00", To: "02:00"},
+			{From: "09:00", To: "17:00"},
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("over-midnight range running into the next day's range", func(t *testing.T) {
+		err := ValidateRanges([]TimeRange{
+			{From: "05:00", To: "09:00"},
+			{From: "22:00", To: "06:00"},
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "overlap: 05:00-09:00 and 22:00-06:00")
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+		err := ValidateRanges([]TimeRange{{From: "22:00", To: "22:00"}})
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "must be before end time")
+		assert.Contains(t, err.Error(), "must differ from end time")
 	})
 
 	t.Run("invalid from time", func(t *testing.T) {
@@ -7,10 +7,31 @@ import (
 	"github.com/teambition/rrule-go"
 )
 
-// TimeWindow represents a working time range within a single day.
+// TimeWindow represents a working time range starting on a given day. When To
+// is not after From the window is overnight and ends on the next day.
 type TimeWindow struct {
-	From TimeOfDay
-	To   TimeOfDay
+	From     TimeOfDay
+	To       TimeOfDay
+	Location *time.Location // time zone of From and To; nil = the project's
+}
+
+// Overnight reports whether the window ends on the next day.
+func (w TimeWindow) Overnight() bool {
+	return w.From.Overnight(w.To)
+}
+
+// End returns the end of the window in minutes since midnight of the day it
+// starts on; overnight windows end past 1440.
+func (w TimeWindow) End() int {
+	if w.Overnight() {
+		return w.To.Minutes() + 24*60
+	}
+	return w.To.Minutes()
+}
+
+// Minutes returns the length of the window in minutes.
+func (w TimeWindow) Minutes() int {
+	return w.End() - w.From.Minutes()
 }
 
 // DaySchedule represents all working time windows for a specific date.
@@ -22,9 +43,12 @@ type DaySchedule struct {
 // ExpandSchedules evaluates schedule entries into concrete day-by-day working
 // hours between from and to (inclusive). RRULEs are expanded, one-off dates
 // are checked for inclusion, and bare entries (no rrule, no date) are skipped.
+// Overnight windows belong to the day they start on, unless the entry splits
+// them at midnight; the part after midnight then belongs to the next day.
 // The result is sorted by date, then by window start time within each day.
 func ExpandSchedules(entries []ScheduleEntry, from, to time.Time) ([]DaySchedule, error) {
 	dayMap := make(map[string][]TimeWindow)
+	first, last := from.Format("2006-01-02"), to.Format("2006-01-02")
 
 	for _, entry := range entries {
 		s, err := FromEntry(entry)
@@ -32,30 +56,48 @@ func ExpandSchedules(entries []ScheduleEntry, from, to time.Time) ([]DaySchedule
 			return nil, err
 		}
 
-		windows := make([]TimeWindow, len(s.Ranges))
-		for i, r := range s.Ranges {
-			windows[i] = TimeWindow(r)
+		// Split overnight windows into a part before and after midnight
+		var windows, spill []TimeWindow
+		for _, r := range s.Ranges {
+			w := TimeWindow{From: r.From, To: r.To, Location: s.Location}
+			if entry.SplitAtMidnight && w.Overnight() && w.To != (TimeOfDay{}) {
+				spill = append(spill, TimeWindow{To: w.To, Location: s.Location})
+				w.To = TimeOfDay{}
+			}
+			windows = append(windows, w)
 		}
 
 		if s.RRule != nil {
+			// A split entry reaches into the range from the day before
+			start := from
+			if len(spill) > 0 {
+				start = from.AddDate(0, 0, -1)
+			}
 			// For unbounded recurring rules (no DTSTART), set DTSTART to
 			// the range start so Between() covers the requested window.
 			// For bounded rules (single dates, date ranges), preserve DTSTART.
 			opts := s.RRule.OrigOptions
 			if opts.Dtstart.IsZero() {
-				opts.Dtstart = from
+				opts.Dtstart = start
 			}
 			r, err := rrule.NewRRule(opts)
 			if err != nil {
 				return nil, err
 			}
-			dates := r.Between(from, to, true)
+			dates := r.Between(start, to, true)
 			for _, d := range dates {
-				key := d.Format("2006-01-02")
-				if entry.Override {
-					dayMap[key] = append([]TimeWindow{}, windows...)
-				} else {
-					dayMap[key] = append(dayMap[key], windows...)
+				if key := d.Format("2006-01-02"); key >= first {
+					if entry.Override {
+						dayMap[key] = append([]TimeWindow{}, windows...)
+					} else {
+						dayMap[key] = append(dayMap[key], windows...)
+					}
+				}
+				if len(spill) == 0 {
+					continue
+				}
+				if key := d.AddDate(0, 0, 1).Format("2006-01-02"); key >= first && key <= last {
+					dayMap[key] = append(dayMap[key], spill...)
 				}
 			}
 		}
@@ -254,3 +254,71 @@ func TestExpandSchedulesSortedByDate(t *testing.T) {
 	assert.Equal(t, 15, result[1].Date.Day())
 	assert.Equal(t, 20, result[2].Date.Day())
 }
+
+func TestExpandSchedulesTimezone(t *testing.T) {
+	entries := []ScheduleEntry{
+		{Ranges: []TimeRange{{From: "09:00", To: "12:00"}}, RRule: "FREQ=WEEKLY;BYDAY=MO"},
+		{Ranges: []TimeRange{{From: "14:00", To: "17:00"}}, RRule: "FREQ=WEEKLY;BYDAY=MO", Timezone: "America/New_York"},
+	}
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 2, 23, 59, 59, 0, time.UTC)
+
+	result, err := ExpandSchedules(entries, from, to)
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Len(t, result[0].Windows, 2)
+	assert.Nil(t, result[0].Windows[0].Location, "no zone means the project's")
+	require.NotNil(t, result[0].Windows[1].Location)
+	assert.Equal(t, "America/New_York", result[0].Windows[1].Location.String())
+}
+
+func TestExpandSchedulesOvernightStaysOnStartDay(t *testing.T) {
+	entries := []ScheduleEntry{
+		{Ranges: []TimeRange{{From: "22:00", To: "06:00"}}, RRule: "FREQ=WEEKLY;BYDAY=MO"},
+	}
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 7, 0, 0, 0, 0, time.UTC)
+
+	result, err := ExpandSchedules(entries, from, to)
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC), result[0].Date)
+	require.Len(t, result[0].Windows, 1)
+	w := result[0].Windows[0]
+	assert.True(t, w.Overnight())
+	assert.Equal(t, 480, w.Minutes())
+}
+
+func TestExpandSchedulesSplitAtMidnight(t *testing.T) {
+	entries := []ScheduleEntry{
+		{Ranges: []TimeRange{{From: "22:00", To: "06:00"}}, RRule: "FREQ=WEEKLY;BYDAY=SU,MO", SplitAtMidnight: true},
+	}
+	// Sun Feb 1 is the day before the range; Sun Feb 8's tail is past it
+	from := time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 8, 0, 0, 0, 0, time.UTC)
+
+	result, err := ExpandSchedules(entries, from, to)
+
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+
+	// Mon Feb 2: the tail of Sunday's shift, then the start of Monday's
+	assert.Equal(t, 2, result[0].Date.Day())
+	require.Len(t, result[0].Windows, 2)
+	assert.Equal(t, TimeWindow{From: TimeOfDay{}, To: TimeOfDay{Hour: 6}}, result[0].Windows[0])
+	assert.Equal(t, TimeWindow{From: TimeOfDay{Hour: 22}, To: TimeOfDay{}}, result[0].Windows[1])
+	assert.Equal(t, 360, result[0].Windows[0].Minutes())
+	assert.Equal(t, 120, result[0].Windows[1].Minutes())
+
+	// Tue Feb 3: the tail of Monday's shift
+	assert.Equal(t, 3, result[1].Date.Day())
+	require.Len(t, result[1].Windows, 1)
+	assert.Equal(t, 360, result[1].Windows[0].Minutes())
+
+	// Sun Feb 8: only the start of the shift
+	assert.Equal(t, 8, result[2].Date.Day())
+	require.Len(t, result[2].Windows, 1)
+	assert.Equal(t, 120, result[2].Windows[0].Minutes())
+}
@@ -7,9 +7,16 @@ import (
 	"time"
 )
 
-// FormatTimeRange formats "HH:MM" times into "H:MM AM - H:MM PM".
+// FormatTimeRange formats "HH:MM" times into "H:MM AM - H:MM PM". Overnight
+// ranges are marked with "(next day)"; a range ending at midnight is not.
 func FormatTimeRange(from, to string) string {
-	return fmt.Sprintf("%s - %s", format12h(from), format12h(to))
+	result := fmt.Sprintf("%s - %s", format12h(from), format12h(to))
+	f, errFrom := parseTimeOfDay(from)
+	t, errTo := parseTimeOfDay(to)
+	if errFrom == nil && errTo == nil && f.Overnight(t) && t != (TimeOfDay{}) {
+		result += " (next day)"
+	}
+	return result
 }
 
 // FormatRRule returns a human-readable description of an RRULE string.
@@ -99,6 +106,12 @@ func FormatScheduleEntry(e ScheduleEntry) string {
 	} else {
 		result = timeRange
 	}
+	if e.Timezone != "" {
+		result += " (" + e.Timezone + ")"
+	}
+	if e.SplitAtMidnight {
+		result += " (split at midnight)"
+	}
 	if e.Override {
 		result += " (override)"
 	}
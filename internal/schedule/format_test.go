@@ -16,6 +16,8 @@ func TestFormatTimeRange(t *testing.T) {
 		{"00:00", "12:00", "12:00 AM - 12:00 PM"},
 		{"13:30", "22:00", "1:30 PM - 10:00 PM"},
 		{"06:00", "14:30", "6:00 AM - 2:30 PM"},
+		{"22:00", "06:00", "10:00 PM - 6:00 AM (next day)"},
+		{"18:00", "00:00", "6:00 PM - 12:00 AM"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.from+"-"+tt.to, func(t *testing.T) {
@@ -110,6 +112,16 @@ func TestFormatScheduleEntry(t *testing.T) {
 			entry: ScheduleEntry{Ranges: []TimeRange{{From: "08:00", To: "16:00"}}, RRule: "FREQ=WEEKLY;BYDAY=MO", Override: true},
 			want:  "8:00 AM - 4:00 PM, every Monday (override)",
 		},
+		{
+			name:  "recurring in another time zone",
+			entry: ScheduleEntry{Ranges: []TimeRange{{From: "09:00", To: "17:00"}}, RRule: "FREQ=WEEKLY;BYDAY=MO", Timezone: "America/New_York"},
+			want:  "9:00 AM - 5:00 PM, every Monday (America/New_York)",
+		},
+		{
+			name:  "overnight split at midnight",
+			entry: ScheduleEntry{Ranges: []TimeRange{{From: "22:00", To: "06:00"}}, RRule: "FREQ=WEEKLY;BYDAY=MO", SplitAtMidnight: true},
+			want:  "10:00 PM - 6:00 AM (next day), every Monday (split at midnight)",
+		},
 		{
 			name: "multiple ranges",
 			entry: ScheduleEntry{
@@ -2,14 +2,16 @@ package schedule
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/teambition/rrule-go"
 )
 
 // Schedule is the parsed in-memory representation of a schedule entry.
 type Schedule struct {
-	Ranges []TimeOfDayRange // one or more time ranges (always at least one)
-	RRule  *rrule.RRule     // recurrence rule (always present for storable schedules)
+	Ranges   []TimeOfDayRange // one or more time ranges (always at least one)
+	RRule    *rrule.RRule     // recurrence rule (always present for storable schedules)
+	Location *time.Location   // time zone of the ranges; nil = the project's
 }
 
 // TimeOfDay represents a clock time without a date component.
@@ -30,3 +32,14 @@ func (t TimeOfDay) Before(other TimeOfDay) bool {
 	}
 	return t.Minute < other.Minute
 }
+
+// Minutes returns t as minutes since midnight.
+func (t TimeOfDay) Minutes() int {
+	return t.Hour*60 + t.Minute
+}
+
+// Overnight reports whether a range from t to end wraps past midnight, e.g.
+// 22:00-06:00. An end of 00:00 means midnight at the end of the day.
+func (t TimeOfDay) Overnight(end TimeOfDay) bool {
+	return !t.Before(end)
+}
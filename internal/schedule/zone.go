@@ -0,0 +1,20 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoadLocation resolves an IANA time zone name such as "Europe/Prague".
+// "Local" and the empty string are rejected — a stored zone must mean the
+// same thing on every machine.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("invalid time zone %q (expected an IANA name like Europe/Prague)", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q (expected an IANA name like Europe/Prague)", name)
+	}
+	return loc, nil
+}
@@ -0,0 +1,23 @@
+package schedule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLocation(t *testing.T) {
+	loc, err := LoadLocation("Europe/Prague")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Prague", loc.String())
+
+	loc, err = LoadLocation("UTC")
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", loc.String())
+
+	for _, name := range []string{"", "Local", "Mars/Olympus", "+02:00"} {
+		_, err := LoadLocation(name)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,103 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+)
+
+// registry lists every migration in version order. The last version must
+// equal project.SchemaVersion.
+var registry = []Migration{
+	{
+		Version:     1,
+		Name:        "entry-types",
+		Description: "Set the type of entries written before entry types existed to log",
+		Run:         migrateEntryTypes,
+	},
+	{
+		Version:     2,
+		Name:        "repo-project-ids",
+		Description: "Record the project ID in repo markers that only name their project",
+		Run:         migrateRepoProjectIDs,
+	},
+}
+
+// migrateEntryTypes adds "type": "log" to entries without a type.
+func migrateEntryTypes(homeDir string, dryRun bool) ([]string, error) {
+	slugs, err := entry.ProjectSlugs(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	store, err := entry.OpenStore(homeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, slug := range slugs {
+		records, err := store.Query(slug, entry.Query{})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(r.Data, &fields); err != nil {
+				continue
+			}
+			if t, ok := fields["type"]; ok && string(t) != `""` && string(t) != "null" {
+				continue
+			}
+
+			changes = append(changes, fmt.Sprintf("%s/%s: set type to %s", slug, r.File, entry.TypeLog))
+			if dryRun {
+				continue
+			}
+			fields["type"], _ = json.Marshal(entry.TypeLog)
+			data, err := json.MarshalIndent(fields, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			if err := store.Write(slug, r.File, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return changes, nil
+}
+
+// migrateRepoProjectIDs fills in project_id in the markers of assigned repos.
+// Older markers only name the project, which breaks when it is renamed.
+// Markers that cannot be written are left as they are.
+func migrateRepoProjectIDs(homeDir string, dryRun bool) ([]string, error) {
+	cfg, err := project.ReadConfig(homeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, p := range cfg.Projects {
+		for _, repo := range p.Repos {
+			rc, err := project.ReadRepoConfig(repo)
+			if err != nil || rc == nil || rc.ProjectID != "" {
+				continue
+			}
+
+			change := fmt.Sprintf("%s: set project_id to %s", repo, p.ID)
+			if dryRun {
+				changes = append(changes, change)
+				continue
+			}
+			rc.ProjectID = p.ID
+			rc.Project = p.Name
+			// Readers still resolve the marker by name; stamp records it
+			if err := project.WriteRepoConfig(repo, rc); err != nil {
+				continue
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
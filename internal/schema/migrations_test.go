@@ -0,0 +1,40 @@
+package schema
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateEntryTypes(t *testing.T) {
+	home := t.TempDir()
+	s := entry.NewMemoryStore()
+	t.Cleanup(entry.UseStore(home, s))
+
+	require.NoError(t, s.Write("p", "aaa1111", []byte(`{"id":"aaa1111","minutes":30}`)))
+	require.NoError(t, s.Write("p", "bbb2222", []byte(`{"id":"bbb2222","type":"","minutes":30}`)))
+	require.NoError(t, s.Write("p", "ccc3333", []byte(`{"id":"ccc3333","type":"checkout"}`)))
+	require.NoError(t, entry.WriteEntry(home, "p", entry.Entry{ID: "ddd4444", Minutes: 30}))
+
+	// Projects are discovered by their data directory
+	require.NoError(t, os.MkdirAll(project.LogDir(home, "p"), 0755))
+
+	changes, err := migrateEntryTypes(home, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p/aaa1111: set type to log", "p/bbb2222: set type to log"}, changes)
+
+	records, err := s.Query("p", entry.Query{})
+	require.NoError(t, err)
+	for _, r := range records {
+		assert.Contains(t, string(r.Data), `"type"`)
+	}
+
+	// Idempotent
+	changes, err = migrateEntryTypes(home, false)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
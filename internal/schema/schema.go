@@ -0,0 +1,295 @@
+// Package schema versions hourgit's stored data and migrates older layouts
+// of config.json, entry files and repo markers to the current one.
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/paths"
+	"github.com/Flyrell/hourgit/internal/project"
+)
+
+// Migration upgrades stored data to the schema version it produces.
+// Migrations must be idempotent: running one on data that is already in
+// the new layout changes nothing.
+type Migration struct {
+	Version     int
+	Name        string
+	Description string
+	// Run applies the migration, or only reports what it would change when
+	// dryRun is set. It returns one line per changed object.
+	Run func(homeDir string, dryRun bool) ([]string, error)
+}
+
+// Step is the outcome of one migration.
+type Step struct {
+	Migration Migration
+	Changes   []string
+}
+
+// Result describes a migration run.
+type Result struct {
+	From   int
+	To     int
+	Backup string // backup directory, empty for dry runs
+	Steps  []Step
+}
+
+// NewerSchemaError is returned when the data was written by a newer hourgit.
+type NewerSchemaError struct {
+	Found int
+}
+
+func (e *NewerSchemaError) Error() string {
+	return fmt.Sprintf("hourgit data uses schema version %d, but this version of hourgit only supports up to %d — please update hourgit", e.Found, project.SchemaVersion)
+}
+
+// Migrations returns the registered migrations in version order.
+func Migrations() []Migration {
+	return registry
+}
+
+// BackupDir returns the directory holding pre-migration backups.
+func BackupDir(homeDir string) string {
+	return filepath.Join(project.DataDir(homeDir), ".backups")
+}
+
+// unstampedPath returns the file listing repo markers a migration could not
+// stamp, with the version each was left at.
+func unstampedPath(homeDir string) string {
+	return filepath.Join(paths.For(homeDir).State, "unstamped-markers.json")
+}
+
+// readUnstamped returns the markers the last migration could not stamp.
+func readUnstamped(homeDir string) map[string]int {
+	unstamped := make(map[string]int)
+	if data, err := os.ReadFile(unstampedPath(homeDir)); err == nil {
+		_ = json.Unmarshal(data, &unstamped)
+	}
+	return unstamped
+}
+
+// Detect returns the oldest schema version found in config.json and the
+// markers of its assigned repos. Nothing needs migrating when there is no
+// config yet. Markers a migration could not stamp, e.g. in a read-only
+// checkout, are skipped while they keep the version they were left at, so
+// they do not trigger a migration, and its backup, on every run.
+func Detect(homeDir string) (int, error) {
+	if _, err := os.Stat(project.ConfigPath(homeDir)); errors.Is(err, os.ErrNotExist) {
+		return project.SchemaVersion, nil
+	}
+	cfg, err := project.ReadConfig(homeDir)
+	if err != nil {
+		return 0, err
+	}
+	if cfg.SchemaVersion > project.SchemaVersion {
+		return 0, &NewerSchemaError{Found: cfg.SchemaVersion}
+	}
+
+	version := cfg.SchemaVersion
+	unstamped := readUnstamped(homeDir)
+	for _, p := range cfg.Projects {
+		for _, repo := range p.Repos {
+			rc, err := project.ReadRepoConfig(repo)
+			if err != nil || rc == nil {
+				continue
+			}
+			if left, ok := unstamped[repo]; ok && left == rc.SchemaVersion {
+				continue
+			}
+			if rc.SchemaVersion > project.SchemaVersion {
+				return 0, &NewerSchemaError{Found: rc.SchemaVersion}
+			}
+			if rc.SchemaVersion < version {
+				version = rc.SchemaVersion
+			}
+		}
+	}
+	return version, nil
+}
+
+// Pending returns the migrations needed to bring data at the given version
+// up to date.
+func Pending(from int) []Migration {
+	var pending []Migration
+	for _, m := range registry {
+		if m.Version > from {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// Migrate runs every pending migration. Unless dryRun is set, the data is
+// backed up first and stamped with the current schema version afterwards.
+// Returns a result with no steps when everything is up to date.
+func Migrate(homeDir string, dryRun bool) (*Result, error) {
+	from, err := Detect(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{From: from, To: project.SchemaVersion}
+	pending := Pending(from)
+	if len(pending) == 0 {
+		return result, nil
+	}
+
+	if !dryRun {
+		result.Backup, err = Backup(homeDir, from)
+		if err != nil {
+			return nil, fmt.Errorf("backing up before migration: %w", err)
+		}
+	}
+
+	for _, m := range pending {
+		changes, err := m.Run(homeDir, dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		result.Steps = append(result.Steps, Step{Migration: m, Changes: changes})
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := stamp(homeDir); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// stamp records the current schema version in config.json and in the
+// markers of all assigned repos. Markers that cannot be written are recorded
+// for Detect to skip rather than failing the migration.
+func stamp(homeDir string) error {
+	var repos []string
+	err := project.UpdateConfig(homeDir, func(cfg *project.Config) error {
+		cfg.SchemaVersion = project.SchemaVersion
+		for _, p := range cfg.Projects {
+			repos = append(repos, p.Repos...)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	unstamped := make(map[string]int)
+	for _, repo := range repos {
+		rc, err := project.ReadRepoConfig(repo)
+		if err != nil || rc == nil || rc.SchemaVersion == project.SchemaVersion {
+			continue
+		}
+		left := rc.SchemaVersion
+		if err := project.WriteRepoConfig(repo, rc); err != nil {
+			unstamped[repo] = left
+		}
+	}
+	return writeUnstamped(homeDir, unstamped)
+}
+
+// writeUnstamped records the markers a migration could not stamp, removing
+// the record when there are none.
+func writeUnstamped(homeDir string, unstamped map[string]int) error {
+	if len(unstamped) == 0 {
+		err := os.Remove(unstampedPath(homeDir))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	data, err := json.MarshalIndent(unstamped, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(unstampedPath(homeDir)), 0755); err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(unstampedPath(homeDir), data, 0600)
+}
+
+// Backup copies the data directory, config.json and the markers of all
+// assigned repos to a new directory under BackupDir. Returns the backup
+// directory.
+func Backup(homeDir string, from int) (string, error) {
+	root := project.DataDir(homeDir)
+	backups := BackupDir(homeDir)
+	dest := filepath.Join(backups, fmt.Sprintf("%s-v%d", time.Now().Format("20060102-150405"), from))
+	for n := 2; ; n++ {
+		if _, err := os.Stat(dest); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		dest = filepath.Join(backups, fmt.Sprintf("%s-v%d-%d", time.Now().Format("20060102-150405"), from, n))
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if path == backups || path == filepath.Join(root, ".git") {
+			// Older backups, and the history kept by 'hourgit remote'
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !d.Type().IsRegular() || strings.HasSuffix(d.Name(), ".lock") {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		return copyFile(path, filepath.Join(dest, rel))
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := copyFile(project.ConfigPath(homeDir), filepath.Join(dest, "config.json")); err != nil {
+		return "", err
+	}
+
+	cfg, err := project.ReadConfig(homeDir)
+	if err != nil {
+		return "", err
+	}
+	markers := make(map[string]string)
+	for _, p := range cfg.Projects {
+		for _, repo := range p.Repos {
+			data, err := os.ReadFile(project.RepoConfigPath(repo))
+			if err == nil {
+				markers[repo] = string(data)
+			}
+		}
+	}
+	if len(markers) > 0 {
+		data, err := json.MarshalIndent(markers, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		if err := fsutil.WriteFileAtomic(filepath.Join(dest, "repo-markers.json"), data, 0600); err != nil {
+			return "", err
+		}
+	}
+	return dest, nil
+}
+
+// copyFile copies a regular file, creating the destination directory.
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(dst, data, 0600)
+}
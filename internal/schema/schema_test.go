@@ -0,0 +1,200 @@
+package schema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLegacyData sets up an unversioned config with one project, a repo
+// marker without a project ID, and an entry without a type.
+func writeLegacyData(t *testing.T) (home, repo string) {
+	t.Helper()
+	home = t.TempDir()
+	repo = t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".git"), 0755))
+
+	cfg := `{"version":"0.1.0","defaults":[],"projects":[{"id":"abc1234","name":"Legacy","slug":"legacy","repos":["` +
+		filepath.ToSlash(repo) + `"]}]}`
+	require.NoError(t, os.MkdirAll(project.LogDir(home, "legacy"), 0755))
+	require.NoError(t, os.MkdirAll(project.ConfigDir(home), 0755))
+	require.NoError(t, os.WriteFile(project.ConfigPath(home), []byte(cfg), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".git", ".hourgit"), []byte(`{"project":"Legacy"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(project.LogDir(home, "legacy"), "aaa1111"),
+		[]byte(`{"id":"aaa1111","start":"2025-06-15T09:00:00Z","minutes":30,"message":"old"}`), 0644))
+	return home, repo
+}
+
+func TestRegistryMatchesSchemaVersion(t *testing.T) {
+	ms := Migrations()
+	require.NotEmpty(t, ms)
+	for i, m := range ms {
+		assert.Equal(t, i+1, m.Version, "migrations must be numbered consecutively")
+		assert.NotEmpty(t, m.Name)
+		assert.NotNil(t, m.Run)
+	}
+	assert.Equal(t, project.SchemaVersion, ms[len(ms)-1].Version)
+}
+
+func TestDetectWithoutConfig(t *testing.T) {
+	home := t.TempDir()
+
+	version, err := Detect(home)
+	require.NoError(t, err)
+	assert.Equal(t, project.SchemaVersion, version)
+
+	result, err := Migrate(home, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Steps)
+	_, err = os.Stat(project.ConfigDir(home))
+	assert.True(t, os.IsNotExist(err), "nothing should be created")
+}
+
+func TestNewConfigIsCurrent(t *testing.T) {
+	home := t.TempDir()
+	_, err := project.CreateProject(home, "Fresh")
+	require.NoError(t, err)
+
+	version, err := Detect(home)
+	require.NoError(t, err)
+	assert.Equal(t, project.SchemaVersion, version)
+}
+
+func TestDetectNewerSchema(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, os.MkdirAll(project.ConfigDir(home), 0755))
+	require.NoError(t, os.WriteFile(project.ConfigPath(home), []byte(`{"schema_version":99}`), 0644))
+
+	_, err := Detect(home)
+	var newer *NewerSchemaError
+	require.ErrorAs(t, err, &newer)
+	assert.Equal(t, 99, newer.Found)
+
+	_, err = Migrate(home, true)
+	assert.ErrorAs(t, err, &newer)
+}
+
+func TestMigrateDryRunChangesNothing(t *testing.T) {
+	home, repo := writeLegacyData(t)
+
+	result, err := Migrate(home, true)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.From)
+	assert.Empty(t, result.Backup)
+	require.Len(t, result.Steps, len(Migrations()))
+	assert.Equal(t, []string{"legacy/aaa1111: set type to log"}, result.Steps[0].Changes)
+	assert.Len(t, result.Steps[1].Changes, 1)
+
+	version, err := Detect(home)
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+	rc, err := project.ReadRepoConfig(repo)
+	require.NoError(t, err)
+	assert.Empty(t, rc.ProjectID)
+	_, err = os.Stat(BackupDir(home))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestMigrateAppliesBacksUpAndStamps(t *testing.T) {
+	home, repo := writeLegacyData(t)
+
+	result, err := Migrate(home, false)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Backup)
+
+	// Data is migrated and stamped
+	data, err := os.ReadFile(filepath.Join(project.LogDir(home, "legacy"), "aaa1111"))
+	require.NoError(t, err)
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.Equal(t, "log", fields["type"])
+
+	rc, err := project.ReadRepoConfig(repo)
+	require.NoError(t, err)
+	assert.Equal(t, "abc1234", rc.ProjectID)
+	assert.Equal(t, project.SchemaVersion, rc.SchemaVersion)
+
+	cfg, err := project.ReadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, project.SchemaVersion, cfg.SchemaVersion)
+
+	// The backup holds the original layout
+	original, err := os.ReadFile(filepath.Join(result.Backup, "legacy", "aaa1111"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(original), `"type"`)
+	backedUpConfig, err := os.ReadFile(filepath.Join(result.Backup, "config.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(backedUpConfig), `"version":"0.1.0"`)
+	markers, err := os.ReadFile(filepath.Join(result.Backup, "repo-markers.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(markers), `{\"project\":\"Legacy\"}`)
+
+	// A second run has nothing to do
+	result, err = Migrate(home, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Steps)
+}
+
+func TestDetectOutdatedRepoMarker(t *testing.T) {
+	home, repo := writeLegacyData(t)
+	_, err := Migrate(home, false)
+	require.NoError(t, err)
+
+	// A marker restored from an old copy is detected on its own
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".git", ".hourgit"), []byte(`{"project":"Legacy"}`), 0644))
+	version, err := Detect(home)
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+}
+
+func TestDetectSkipsUnstampedMarker(t *testing.T) {
+	home, repo := writeLegacyData(t)
+	_, err := Migrate(home, false)
+	require.NoError(t, err)
+	marker := filepath.Join(repo, ".git", ".hourgit")
+	require.NoError(t, os.WriteFile(marker, []byte(`{"project":"Legacy","project_id":"abc1234","schema_version":1}`), 0644))
+
+	// A marker the last migration could not stamp is not migrated again
+	require.NoError(t, writeUnstamped(home, map[string]int{repo: 1}))
+	version, err := Detect(home)
+	require.NoError(t, err)
+	assert.Equal(t, project.SchemaVersion, version)
+	backups, err := os.ReadDir(BackupDir(home))
+	require.NoError(t, err)
+	result, err := Migrate(home, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Backup)
+	after, err := os.ReadDir(BackupDir(home))
+	require.NoError(t, err)
+	assert.Len(t, after, len(backups))
+
+	// Until the marker changes
+	require.NoError(t, os.WriteFile(marker, []byte(`{"project":"Legacy"}`), 0644))
+	version, err = Detect(home)
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+}
+
+func TestMigrateRecordsUnwritableMarker(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	home, repo := writeLegacyData(t)
+	gitDir := filepath.Join(repo, ".git")
+	require.NoError(t, os.Chmod(gitDir, 0555))
+	t.Cleanup(func() { _ = os.Chmod(gitDir, 0755) })
+
+	result, err := Migrate(home, false)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Backup)
+	assert.Equal(t, map[string]int{repo: 0}, readUnstamped(home))
+
+	result, err = Migrate(home, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Backup, "no second backup")
+}
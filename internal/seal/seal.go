@@ -0,0 +1,589 @@
+// Package seal encrypts hourgit data at rest. Encryption is opt-in: it is on
+// when the data directory holds an encryption header (encryption.json), which
+// records how the key is derived but never the key itself.
+//
+// Sealed data is text — a prefix followed by base64 of the nonce and the
+// AES-256-GCM ciphertext — so it fits on one line of a JSONL file. The
+// ciphertext is bound to a context naming what it is, such as an entry's ID,
+// so sealed data copied over other data fails to open. Reads accept
+// sealed and plain data alike, which keeps a conversion that was interrupted
+// half-way readable and lets 'hourgit encrypt' and 'hourgit decrypt' simply be
+// re-run.
+package seal
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/paths"
+)
+
+// Environment variables supplying the key without a prompt.
+const (
+	EnvPassphrase = "HOURGIT_PASSPHRASE"
+	EnvKeyfile    = "HOURGIT_KEYFILE"
+)
+
+// Key sources recorded in the header.
+const (
+	KeyPassphrase = "passphrase"
+	KeyKeyfile    = "keyfile"
+)
+
+// HeaderName is the file name of the encryption header in the data directory.
+const HeaderName = "encryption.json"
+
+// KeyfileName is the default keyfile name in the config directory.
+const KeyfileName = "hourgit.key"
+
+// prefix marks sealed data bound to its context; unboundPrefix marks data
+// sealed before contexts existed, which still opens.
+const (
+	prefix        = "hgseal2:"
+	unboundPrefix = "hgseal1:"
+)
+
+// checkText is sealed into the header to tell a wrong key from a right one.
+const checkText = "hourgit"
+
+// iterations is the PBKDF2-SHA256 work factor for new headers.
+var iterations = 600_000
+
+var (
+	// ErrLocked is returned when data is encrypted and no key is available.
+	ErrLocked = fmt.Errorf("hourgit data is encrypted: set %s or run hourgit in a terminal to enter the passphrase", EnvPassphrase)
+	// ErrWrongKey is returned when the passphrase or keyfile does not match.
+	ErrWrongKey = errors.New("wrong passphrase or keyfile")
+	// ErrCorrupt is returned when sealed data fails authentication.
+	ErrCorrupt = errors.New("sealed data is corrupt or was modified")
+)
+
+// Header describes how the key of an encrypted data directory is derived.
+type Header struct {
+	Version    int    `json:"version"`
+	Key        string `json:"key"` // KeyPassphrase or KeyKeyfile
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Check      string `json:"check"`                // checkText sealed with the key
+	Decrypting bool   `json:"decrypting,omitempty"` // 'hourgit decrypt' is in progress
+}
+
+// SameKey reports whether two headers describe the same key. Two missing
+// headers match.
+func SameKey(a, b *Header) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return bytes.Equal(a.Salt, b.Salt) && a.Iterations == b.Iterations && a.Key == b.Key
+}
+
+// HeaderPath returns the path of the encryption header.
+func HeaderPath(homeDir string) string {
+	return filepath.Join(paths.For(homeDir).Data, HeaderName)
+}
+
+// KeyfilePath returns the keyfile location: $HOURGIT_KEYFILE, or hourgit.key
+// in the config directory.
+func KeyfilePath(homeDir string) string {
+	if path := os.Getenv(EnvKeyfile); path != "" {
+		return path
+	}
+	return filepath.Join(paths.For(homeDir).Config, KeyfileName)
+}
+
+// ReadHeader returns the encryption header, or nil when the data is not
+// encrypted.
+func ReadHeader(homeDir string) (*Header, error) {
+	data, err := os.ReadFile(HeaderPath(homeDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var h Header
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", HeaderName, err)
+	}
+	return &h, nil
+}
+
+// WriteHeader writes the encryption header.
+func WriteHeader(homeDir string, h *Header) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(HeaderPath(homeDir)), 0755); err != nil {
+		return err
+	}
+	defer forgetSealer(homeDir)
+	return fsutil.WriteFileAtomic(HeaderPath(homeDir), append(data, '\n'), 0644)
+}
+
+// RemoveHeader turns encryption off. Only call it once no sealed data is left.
+func RemoveHeader(homeDir string) error {
+	defer forgetSealer(homeDir)
+	err := os.Remove(HeaderPath(homeDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Enabled reports whether new data is sealed.
+func Enabled(homeDir string) bool {
+	s, err := Load(homeDir)
+	return err == nil && s.Enabled()
+}
+
+var (
+	keysMu sync.Mutex
+	keys   = make(map[string][]byte) // hex salt -> derived key
+	askFn  func() (string, error)
+)
+
+// SetPassphraseFunc sets how a passphrase is asked for when the environment
+// does not supply one. Without it, encrypted data cannot be opened.
+func SetPassphraseFunc(fn func() (string, error)) {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	askFn = fn
+}
+
+// Setup starts encrypting homeDir: it writes a new header for a key derived
+// from passphrase, or from the keyfile when kind is KeyKeyfile. A missing
+// keyfile is generated.
+func Setup(homeDir, kind, passphrase string) (*Header, error) {
+	h := &Header{Version: 1, Key: kind, KDF: "pbkdf2-sha256", Iterations: iterations, Salt: make([]byte, 16)}
+	if _, err := rand.Read(h.Salt); err != nil {
+		return nil, err
+	}
+
+	secret := passphrase
+	switch kind {
+	case KeyPassphrase:
+		if secret == "" {
+			return nil, errors.New("passphrase must not be empty")
+		}
+	case KeyKeyfile:
+		var err error
+		if secret, err = readKeyfile(homeDir, true); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown key source %q", kind)
+	}
+
+	key, err := derive(h, secret)
+	if err != nil {
+		return nil, err
+	}
+	check, err := sealWith(key, []byte(checkText), nil)
+	if err != nil {
+		return nil, err
+	}
+	h.Check = string(check)
+	if err := WriteHeader(homeDir, h); err != nil {
+		return nil, err
+	}
+	remember(h, key)
+	return h, nil
+}
+
+// Unlock derives and checks the key of homeDir's data, if it is encrypted.
+// Keys are cached per process.
+func Unlock(homeDir string) error {
+	h, err := ReadHeader(homeDir)
+	if err != nil || h == nil {
+		return err
+	}
+	_, err = keyFor(homeDir, h)
+	return err
+}
+
+// readKeyfile returns the keyfile's contents, generating a random key first
+// when create is set and the file does not exist.
+func readKeyfile(homeDir string, create bool) (string, error) {
+	path := KeyfilePath(homeDir)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && create {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return "", err
+		}
+		data = []byte(hex.EncodeToString(random) + "\n")
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return "", err
+		}
+		err = fsutil.WriteFileAtomic(path, data, 0600)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("hourgit data is encrypted with a keyfile, but %s does not exist (set %s to its location)", path, EnvKeyfile)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(data)), nil
+}
+
+// keyFor returns the key described by h, deriving it on first use.
+func keyFor(homeDir string, h *Header) ([]byte, error) {
+	salt := hex.EncodeToString(h.Salt)
+	keysMu.Lock()
+	key, ok := keys[salt]
+	ask := askFn
+	keysMu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	var secret string
+	switch h.Key {
+	case KeyKeyfile:
+		var err error
+		if secret, err = readKeyfile(homeDir, false); err != nil {
+			return nil, err
+		}
+	default:
+		secret = os.Getenv(EnvPassphrase)
+		if secret == "" {
+			if ask == nil {
+				return nil, ErrLocked
+			}
+			var err error
+			if secret, err = ask(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	key, err := derive(h, secret)
+	if err != nil {
+		return nil, err
+	}
+	if check, err := openWith(key, []byte(h.Check), nil); err != nil || string(check) != checkText {
+		return nil, ErrWrongKey
+	}
+	remember(h, key)
+	return key, nil
+}
+
+// remember caches the key for h's salt.
+func remember(h *Header, key []byte) {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	keys[hex.EncodeToString(h.Salt)] = key
+}
+
+// derive runs the key derivation recorded in h.
+func derive(h *Header, secret string) ([]byte, error) {
+	if h.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("unsupported key derivation %q — please update hourgit", h.KDF)
+	}
+	return pbkdf2.Key(sha256.New, secret, h.Salt, h.Iterations, 32)
+}
+
+// IsSealed reports whether data is sealed.
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(prefix)) || bytes.HasPrefix(data, []byte(unboundPrefix))
+}
+
+// Sealer seals and opens data with a data directory's encryption header,
+// read once. Get one with Load and use it for a batch of entries.
+type Sealer struct {
+	homeDir string
+	header  *Header // nil when the data is not encrypted
+}
+
+// cachedSealer is a Sealer along with the header file it was read from.
+type cachedSealer struct {
+	sealer *Sealer
+	info   os.FileInfo // nil when there was no header
+}
+
+var (
+	sealersMu sync.Mutex
+	sealers   = make(map[string]cachedSealer) // header path -> sealer
+)
+
+// Load returns homeDir's Sealer. The header is only read again when its file
+// changed, so loading per command or per batch of entries costs a stat.
+func Load(homeDir string) (*Sealer, error) {
+	path := HeaderPath(homeDir)
+	info, err := os.Stat(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	sealersMu.Lock()
+	cached, ok := sealers[path]
+	sealersMu.Unlock()
+	if ok && sameFileInfo(cached.info, info) {
+		return cached.sealer, nil
+	}
+
+	h, err := ReadHeader(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	s := &Sealer{homeDir: homeDir, header: h}
+	sealersMu.Lock()
+	sealers[path] = cachedSealer{sealer: s, info: info}
+	sealersMu.Unlock()
+	return s, nil
+}
+
+// sameFileInfo reports whether a and b describe the same unchanged file.
+// Two missing files match.
+func sameFileInfo(a, b os.FileInfo) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return os.SameFile(a, b) && a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
+
+// forgetSealer drops homeDir's cached Sealer once its header changed.
+func forgetSealer(homeDir string) {
+	sealersMu.Lock()
+	defer sealersMu.Unlock()
+	delete(sealers, HeaderPath(homeDir))
+}
+
+// Header returns the encryption header the sealer uses, or nil when the data
+// is not encrypted.
+func (s *Sealer) Header() *Header {
+	return s.header
+}
+
+// Enabled reports whether the sealer seals new data.
+func (s *Sealer) Enabled() bool {
+	return s.header != nil && !s.header.Decrypting
+}
+
+// Seal encrypts data bound to context when the data is encrypted, and
+// returns it unchanged otherwise. Data that is already sealed is returned as
+// is.
+func (s *Sealer) Seal(data []byte, context string) ([]byte, error) {
+	if IsSealed(data) || !s.Enabled() {
+		return data, nil
+	}
+	key, err := keyFor(s.homeDir, s.header)
+	if err != nil {
+		return nil, err
+	}
+	return sealWith(key, data, []byte(context))
+}
+
+// Open decrypts data sealed with context and returns plain data unchanged.
+func (s *Sealer) Open(data []byte, context string) ([]byte, error) {
+	if !IsSealed(data) {
+		return data, nil
+	}
+	if s.header == nil {
+		return nil, fmt.Errorf("found encrypted data, but %s is missing", HeaderPath(s.homeDir))
+	}
+	key, err := keyFor(s.homeDir, s.header)
+	if err != nil {
+		return nil, err
+	}
+	return openWith(key, data, []byte(context))
+}
+
+// SealLines seals each line of a JSONL file separately, so lines can still
+// be appended one at a time.
+func (s *Sealer) SealLines(data []byte, context string) ([]byte, error) {
+	return mapLines(data, func(line []byte) ([]byte, error) { return s.Seal(line, context) })
+}
+
+// OpenLines opens each line of a JSONL file. Lines that fail authentication
+// are dropped, like torn lines in a plain file.
+func (s *Sealer) OpenLines(data []byte, context string) ([]byte, error) {
+	return mapLines(data, func(line []byte) ([]byte, error) {
+		plain, err := s.Open(line, context)
+		if errors.Is(err, ErrCorrupt) {
+			return nil, nil
+		}
+		return plain, err
+	})
+}
+
+// RewriteFile re-writes a file sealed or plain, whichever the data uses now.
+// Missing files are skipped. Returns whether the file changed.
+func (s *Sealer) RewriteFile(path, context string) (bool, error) {
+	return rewrite(path, func(data []byte) ([]byte, error) {
+		converted, err := s.convert(data, context)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return converted, nil
+	})
+}
+
+// RewriteLines is RewriteFile for JSONL files sealed line by line.
+func (s *Sealer) RewriteLines(path, context string) (bool, error) {
+	return rewrite(path, func(data []byte) ([]byte, error) {
+		converted, err := mapLines(data, func(line []byte) ([]byte, error) {
+			converted, err := s.convert(line, context)
+			if errors.Is(err, ErrCorrupt) {
+				return nil, nil
+			}
+			return converted, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return converted, nil
+	})
+}
+
+// convert seals or opens data so it matches whether the data is encrypted.
+// Data already in the wanted form is returned as is, so re-running a
+// conversion is cheap; data sealed before contexts existed is sealed again
+// bound to context.
+func (s *Sealer) convert(data []byte, context string) ([]byte, error) {
+	enabled := s.Enabled()
+	if !IsSealed(data) {
+		if enabled {
+			return s.Seal(data, context)
+		}
+		return data, nil
+	}
+	if enabled && bytes.HasPrefix(data, []byte(prefix)) {
+		return data, nil
+	}
+	plain, err := s.Open(data, context)
+	if err != nil || !enabled {
+		return plain, err
+	}
+	return s.Seal(plain, context)
+}
+
+// Seal is Sealer.Seal with homeDir's Sealer.
+func Seal(homeDir string, data []byte, context string) ([]byte, error) {
+	s, err := Load(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	return s.Seal(data, context)
+}
+
+// Open is Sealer.Open with homeDir's Sealer.
+func Open(homeDir string, data []byte, context string) ([]byte, error) {
+	s, err := Load(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	return s.Open(data, context)
+}
+
+// OpenFor decrypts data sealed with the key h describes, which need not be
+// homeDir's own, as in a sealed backup archive brought from another machine.
+// A keyfile is looked for where homeDir's would be.
+func OpenFor(homeDir string, h *Header, data []byte, context string) ([]byte, error) {
+	return (&Sealer{homeDir: homeDir, header: h}).Open(data, context)
+}
+
+// mapLines applies fn to every non-empty line, dropping lines it maps to nil.
+func mapLines(data []byte, fn func([]byte) ([]byte, error)) ([]byte, error) {
+	var out bytes.Buffer
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		mapped, err := fn(line)
+		if err != nil {
+			return nil, err
+		}
+		if mapped == nil {
+			continue
+		}
+		out.Write(mapped)
+		out.WriteByte('\n')
+	}
+	return out.Bytes(), nil
+}
+
+// rewrite replaces path's contents with convert's result, readable only by
+// the owner like every file hourgit keeps data in.
+func rewrite(path string, convert func([]byte) ([]byte, error)) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	converted, err := convert(data)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(data, converted) {
+		return false, nil
+	}
+	return true, fsutil.WriteFileAtomic(path, converted, 0600)
+}
+
+// sealWith encrypts data with AES-256-GCM under a random nonce, binding it
+// to aad.
+func sealWith(key, data, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	raw := gcm.Seal(nonce, nonce, data, aad)
+	out := make([]byte, len(prefix)+base64.StdEncoding.EncodedLen(len(raw)))
+	copy(out, prefix)
+	base64.StdEncoding.Encode(out[len(prefix):], raw)
+	return out, nil
+}
+
+// openWith decrypts data sealed by sealWith with the same aad. Data sealed
+// before contexts existed opens without one.
+func openWith(key, data, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte(unboundPrefix)) {
+		aad = nil
+	}
+	encoded := bytes.TrimSpace(data[len(prefix):])
+	raw := make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
+	n, err := base64.StdEncoding.Decode(raw, encoded)
+	if err != nil || n < gcm.NonceSize() {
+		return nil, ErrCorrupt
+	}
+	raw = raw[:n]
+	plain, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], aad)
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	return plain, nil
+}
+
+// newGCM returns an AES-GCM cipher for key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,295 @@
+package seal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// Keep key derivation fast in tests
+	iterations = 1000
+}
+
+// forget drops cached keys, as if a new process started.
+func forget() {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	keys = make(map[string][]byte)
+}
+
+// sealer loads home's Sealer.
+func sealer(t *testing.T, home string) *Sealer {
+	t.Helper()
+	s, err := Load(home)
+	require.NoError(t, err)
+	return s
+}
+
+func TestSealDisabledIsPassThrough(t *testing.T) {
+	home := t.TempDir()
+	assert.False(t, Enabled(home))
+
+	data, err := Seal(home, []byte(`{"id":"a"}`), "test")
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"a"}`, string(data))
+
+	data, err = Open(home, []byte(`{"id":"a"}`), "test")
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"a"}`, string(data))
+}
+
+func TestSealRoundTripWithPassphrase(t *testing.T) {
+	home := t.TempDir()
+	_, err := Setup(home, KeyPassphrase, "secret")
+	require.NoError(t, err)
+	assert.True(t, Enabled(home))
+
+	sealed, err := Seal(home, []byte(`{"message":"ACME-123"}`), "test")
+	require.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, string(sealed), "ACME")
+	assert.NotContains(t, string(sealed), "\n")
+
+	again, err := Seal(home, sealed, "test")
+	require.NoError(t, err)
+	assert.Equal(t, sealed, again, "sealing is idempotent")
+
+	// A new process needs the passphrase
+	forget()
+	t.Setenv(EnvPassphrase, "")
+	_, err = Open(home, sealed, "test")
+	assert.ErrorIs(t, err, ErrLocked)
+
+	t.Setenv(EnvPassphrase, "wrong")
+	_, err = Open(home, sealed, "test")
+	assert.ErrorIs(t, err, ErrWrongKey)
+
+	t.Setenv(EnvPassphrase, "secret")
+	plain, err := Open(home, sealed, "test")
+	require.NoError(t, err)
+	assert.Equal(t, `{"message":"ACME-123"}`, string(plain))
+}
+
+func TestPassphraseFunc(t *testing.T) {
+	home := t.TempDir()
+	_, err := Setup(home, KeyPassphrase, "secret")
+	require.NoError(t, err)
+	sealed, err := Seal(home, []byte("x"), "test")
+	require.NoError(t, err)
+
+	forget()
+	t.Setenv(EnvPassphrase, "")
+	asked := 0
+	SetPassphraseFunc(func() (string, error) {
+		asked++
+		return "secret", nil
+	})
+	defer SetPassphraseFunc(nil)
+
+	for range 2 {
+		plain, err := Open(home, sealed, "test")
+		require.NoError(t, err)
+		assert.Equal(t, "x", string(plain))
+	}
+	assert.Equal(t, 1, asked, "the key is cached")
+}
+
+func TestSealWithKeyfile(t *testing.T) {
+	home := t.TempDir()
+	keyfile := filepath.Join(t.TempDir(), "client.key")
+	t.Setenv(EnvKeyfile, keyfile)
+
+	_, err := Setup(home, KeyKeyfile, "")
+	require.NoError(t, err)
+	info, err := os.Stat(keyfile)
+	require.NoError(t, err, "a missing keyfile is generated")
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	sealed, err := Seal(home, []byte("x"), "test")
+	require.NoError(t, err)
+
+	forget()
+	plain, err := Open(home, sealed, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "x", string(plain))
+
+	forget()
+	require.NoError(t, os.Remove(keyfile))
+	_, err = Open(home, sealed, "test")
+	assert.ErrorContains(t, err, "does not exist")
+}
+
+func TestOpenDetectsTampering(t *testing.T) {
+	home := t.TempDir()
+	_, err := Setup(home, KeyPassphrase, "secret")
+	require.NoError(t, err)
+	sealed, err := Seal(home, []byte("hello"), "test")
+	require.NoError(t, err)
+
+	tampered := []byte(string(sealed[:len(sealed)-4]) + "AAA=")
+	_, err = Open(home, tampered, "test")
+	assert.ErrorIs(t, err, ErrCorrupt)
+}
+
+func TestOpenWithoutHeader(t *testing.T) {
+	home := t.TempDir()
+	_, err := Setup(home, KeyPassphrase, "secret")
+	require.NoError(t, err)
+	sealed, err := Seal(home, []byte("hello"), "test")
+	require.NoError(t, err)
+	require.NoError(t, RemoveHeader(home))
+
+	_, err = Open(home, sealed, "test")
+	assert.ErrorContains(t, err, "is missing")
+}
+
+func TestOpenForAnotherHeader(t *testing.T) {
+	home := t.TempDir()
+	h, err := Setup(home, KeyPassphrase, "secret")
+	require.NoError(t, err)
+	sealed, err := Seal(home, []byte("hello"), "test")
+	require.NoError(t, err)
+
+	// Another machine has no header of its own
+	forget()
+	other := t.TempDir()
+	t.Setenv(EnvPassphrase, "wrong")
+	_, err = OpenFor(other, h, sealed, "test")
+	assert.ErrorIs(t, err, ErrWrongKey)
+
+	t.Setenv(EnvPassphrase, "secret")
+	plain, err := OpenFor(other, h, sealed, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(plain))
+}
+
+func TestOpenChecksContext(t *testing.T) {
+	home := t.TempDir()
+	_, err := Setup(home, KeyPassphrase, "secret")
+	require.NoError(t, err)
+	sealed, err := Seal(home, []byte("hello"), "entry:a")
+	require.NoError(t, err)
+
+	// Data copied over another entry's does not open as that entry
+	_, err = Open(home, sealed, "entry:b")
+	assert.ErrorIs(t, err, ErrCorrupt)
+
+	plain, err := Open(home, sealed, "entry:a")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(plain))
+}
+
+func TestUnboundDataIsUpgraded(t *testing.T) {
+	home := t.TempDir()
+	h, err := Setup(home, KeyPassphrase, "secret")
+	require.NoError(t, err)
+	key, err := keyFor(home, h)
+	require.NoError(t, err)
+
+	// Sealed before contexts existed: no associated data
+	bound, err := sealWith(key, []byte("hello"), nil)
+	require.NoError(t, err)
+	unbound := []byte(unboundPrefix + string(bound[len(prefix):]))
+
+	plain, err := Open(home, unbound, "entry:a")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(plain))
+
+	path := filepath.Join(t.TempDir(), "a")
+	require.NoError(t, os.WriteFile(path, unbound, 0600))
+	changed, err := sealer(t, home).RewriteFile(path, "entry:a")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), prefix))
+	_, err = Open(home, data, "entry:b")
+	assert.ErrorIs(t, err, ErrCorrupt)
+}
+
+func TestLoadRereadsChangedHeader(t *testing.T) {
+	home := t.TempDir()
+	first := sealer(t, home)
+	assert.False(t, first.Enabled())
+	assert.Same(t, first, sealer(t, home), "an unchanged header is not read again")
+
+	h, err := Setup(home, KeyPassphrase, "secret")
+	require.NoError(t, err)
+	assert.True(t, sealer(t, home).Enabled())
+
+	// Another process writing the header is noticed as well
+	h.Decrypting = true
+	data, err := json.Marshal(h)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(HeaderPath(home)+".new", data, 0644))
+	require.NoError(t, os.Rename(HeaderPath(home)+".new", HeaderPath(home)))
+	assert.False(t, sealer(t, home).Enabled())
+}
+
+func TestLines(t *testing.T) {
+	home := t.TempDir()
+	_, err := Setup(home, KeyPassphrase, "secret")
+	require.NoError(t, err)
+
+	sealed, err := sealer(t, home).SealLines([]byte("{\"a\":1}\n{\"b\":2}\n"), "test")
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(sealed), "\n"))
+	assert.NotContains(t, string(sealed), `"a"`)
+
+	// A torn final line is dropped
+	torn := append(sealed, []byte("hgseal2:AAAA\n")...)
+	plain, err := sealer(t, home).OpenLines(torn, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "{\"a\":1}\n{\"b\":2}\n", string(plain))
+}
+
+func TestRewriteFile(t *testing.T) {
+	home := t.TempDir()
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"projects":[]}`), 0644))
+
+	changed, err := sealer(t, home).RewriteFile(path, "test")
+	require.NoError(t, err)
+	assert.False(t, changed, "nothing to do without encryption")
+
+	h, err := Setup(home, KeyPassphrase, "secret")
+	require.NoError(t, err)
+	changed, err = sealer(t, home).RewriteFile(path, "test")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, IsSealed(data))
+
+	// While decrypting, files are written back in plain text
+	h.Decrypting = true
+	require.NoError(t, WriteHeader(home, h))
+	assert.False(t, Enabled(home))
+	changed, err = sealer(t, home).RewriteFile(path, "test")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"projects":[]}`, string(data))
+
+	changed, err = sealer(t, home).RewriteFile(filepath.Join(t.TempDir(), "missing"), "test")
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestSameKey(t *testing.T) {
+	a := &Header{Key: KeyPassphrase, Iterations: 1, Salt: []byte{1}}
+	b := &Header{Key: KeyPassphrase, Iterations: 1, Salt: []byte{1}, Decrypting: true}
+	c := &Header{Key: KeyPassphrase, Iterations: 1, Salt: []byte{2}}
+
+	assert.True(t, SameKey(nil, nil))
+	assert.True(t, SameKey(a, b))
+	assert.False(t, SameKey(a, c))
+	assert.False(t, SameKey(a, nil))
+}
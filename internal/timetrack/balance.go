@@ -0,0 +1,108 @@
+package timetrack
+
+import (
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/schedule"
+)
+
+// BalancePeriod holds one calendar month of a flextime balance.
+type BalancePeriod struct {
+	From              time.Time // first counted date of the month
+	To                time.Time // last counted date of the month
+	ScheduledMinutes  int
+	WorkedMinutes     int
+	CorrectionMinutes int
+	BalanceMinutes    int // running balance at the end of the period, carried over from earlier months
+}
+
+// Minutes returns the period's own contribution to the balance.
+func (p BalancePeriod) Minutes() int {
+	return p.WorkedMinutes - p.ScheduledMinutes + p.CorrectionMinutes
+}
+
+// Balance is the running flextime balance of a project: attributed minutes
+// minus scheduled minutes, plus manual corrections, from a start date up to
+// now.
+type Balance struct {
+	From              time.Time
+	To                time.Time
+	Periods           []BalancePeriod // one per month, oldest first
+	ScheduledMinutes  int
+	WorkedMinutes     int
+	CorrectionMinutes int
+}
+
+// Minutes returns the current balance. Positive means hours over target.
+func (b Balance) Minutes() int {
+	return b.WorkedMinutes - b.ScheduledMinutes + b.CorrectionMinutes
+}
+
+// ComputeBalance sums scheduled against attributed minutes for every date
+// from from to now (in now's location), month by month, and adds the
+// corrections dated in that range. Attributed minutes are counted the way the
+// report counts them per opts; separate overtime stays out of the balance. Today's schedule only counts up to now,
+// so the balance does not dip during a working day. daySchedules must
+// include the day before from.
+// Used by: balance, status and report commands.
+func ComputeBalance(
+	checkouts []entry.CheckoutEntry,
+	logs []entry.Entry,
+	commits []entry.CommitEntry,
+	events []entry.GitEventEntry,
+	daySchedules []schedule.DaySchedule,
+	corrections []entry.CorrectionEntry,
+	from time.Time,
+	now time.Time,
+	opts Options,
+	activity ...ActivityEntries,
+) Balance {
+	from, today := DateOf(from), DateOf(now)
+	b := Balance{From: from, To: today}
+	if from.After(today) {
+		return b
+	}
+
+	loc := now.Location()
+	report := BuildDetailedReport(checkouts, logs, commits, events, daySchedules, from, today, now, opts, activity...)
+	scheduleWindows, scheduledMins := buildScheduleLookup(daySchedules, from, today)
+
+	correctionMins := make(map[time.Time]int)
+	for _, c := range corrections {
+		correctionMins[DateOf(c.Date)] += c.Minutes
+	}
+
+	running := 0
+	var period *BalancePeriod
+	for _, day := range report.Dates {
+		if period == nil || day.Month() != period.From.Month() || day.Year() != period.From.Year() {
+			if period != nil {
+				b.Periods = append(b.Periods, *period)
+			}
+			period = &BalancePeriod{From: day}
+		}
+		period.To = day
+
+		scheduled := scheduledMins[day]
+		if day.Equal(today) {
+			dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+			scheduled = overlapMinutes(dayStart, now, day, scheduleWindows[day], loc)
+		}
+		worked, _ := report.DayTotal(day)
+
+		period.ScheduledMinutes += scheduled
+		period.WorkedMinutes += worked
+		period.CorrectionMinutes += correctionMins[day]
+		running += worked - scheduled + correctionMins[day]
+		period.BalanceMinutes = running
+	}
+	b.Periods = append(b.Periods, *period)
+
+	for _, p := range b.Periods {
+		b.ScheduledMinutes += p.ScheduledMinutes
+		b.WorkedMinutes += p.WorkedMinutes
+		b.CorrectionMinutes += p.CorrectionMinutes
+	}
+	return b
+}
@@ -0,0 +1,72 @@
+package timetrack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/rounding"
+	"github.com/Flyrell/hourgit/internal/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func balanceLog(id string, start time.Time, minutes int) entry.Entry {
+	return entry.Entry{ID: id, Start: start, Minutes: minutes, Message: "work", Task: "work"}
+}
+
+func TestComputeBalance(t *testing.T) {
+	days := []schedule.DaySchedule{
+		workday(2025, time.January, 30),
+		workday(2025, time.January, 31),
+		workday(2025, time.February, 3),
+	}
+	logs := []entry.Entry{
+		balanceLog("a1", time.Date(2025, 1, 30, 9, 0, 0, 0, time.UTC), 540),
+		balanceLog("a2", time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), 480),
+		balanceLog("a3", time.Date(2025, 2, 3, 9, 0, 0, 0, time.UTC), 180),
+	}
+	corrections := []entry.CorrectionEntry{
+		{ID: "c1", Date: date(2025, time.February, 1), Minutes: 30},
+		{ID: "c2", Date: date(2024, time.December, 31), Minutes: 600}, // before the start
+	}
+	now := time.Date(2025, 2, 3, 13, 0, 0, 0, time.UTC)
+
+	b := ComputeBalance(nil, logs, nil, nil, days, corrections, date(2025, time.January, 30), now, Options{})
+
+	require.Len(t, b.Periods, 2)
+	assert.Equal(t, BalancePeriod{
+		From: date(2025, time.January, 30), To: date(2025, time.January, 31),
+		ScheduledMinutes: 960, WorkedMinutes: 1020, BalanceMinutes: 60,
+	}, b.Periods[0])
+	assert.Equal(t, BalancePeriod{
+		From: date(2025, time.February, 1), To: date(2025, time.February, 3),
+		ScheduledMinutes: 240, WorkedMinutes: 180, CorrectionMinutes: 30, BalanceMinutes: 30,
+	}, b.Periods[1], "today's schedule counts up to now")
+	assert.Equal(t, -30, b.Periods[1].Minutes())
+
+	assert.Equal(t, 1200, b.ScheduledMinutes)
+	assert.Equal(t, 1200, b.WorkedMinutes)
+	assert.Equal(t, 30, b.CorrectionMinutes)
+	assert.Equal(t, 30, b.Minutes())
+}
+
+func TestComputeBalance_Rounding(t *testing.T) {
+	days := []schedule.DaySchedule{workday(2025, time.January, 2)}
+	logs := []entry.Entry{balanceLog("a1", time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), 470)}
+	now := time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC)
+	policy := rounding.Policy{Increment: 15, Mode: rounding.ModeUp, Scope: rounding.ScopeDay}
+
+	b := ComputeBalance(nil, logs, nil, nil, days, nil, date(2025, time.January, 1), now, Options{Rounding: policy})
+
+	assert.Equal(t, 0, b.Minutes(), "470 minutes round up to the 8h schedule")
+}
+
+func TestComputeBalance_StartInFuture(t *testing.T) {
+	now := time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC)
+
+	b := ComputeBalance(nil, nil, nil, nil, nil, nil, date(2025, time.February, 1), now, Options{})
+
+	assert.Empty(t, b.Periods)
+	assert.Equal(t, 0, b.Minutes())
+}
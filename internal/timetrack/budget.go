@@ -12,39 +12,34 @@ type DayBudget struct {
 	LoggedMinutes    int // total attributed minutes
 	ScheduledMinutes int // total scheduled working minutes
 	RemainingMinutes int // max(0, scheduled - logged)
+	OvertimeMinutes  int // outside schedule windows (separate overtime policy)
 }
 
 // ComputeDayBudget computes the full time attribution for a day including
-// checkout-attributed time (with idle trimming) and manual logs.
+// checkout-attributed time (with idle trimming) and manual logs, attributed
+// and rounded per opts the same way the report does the day's total.
 // Used by: status command.
 func ComputeDayBudget(
 	checkouts []entry.CheckoutEntry,
 	logs []entry.Entry,
 	commits []entry.CommitEntry,
+	events []entry.GitEventEntry,
 	daySchedules []schedule.DaySchedule,
 	targetDate time.Time,
 	now time.Time,
+	opts Options,
 	activity ...ActivityEntries,
 ) DayBudget {
-	year := targetDate.Year()
-	month := targetDate.Month()
-
-	report := BuildReport(checkouts, logs, commits, daySchedules, year, month, now, nil, activity...)
-
-	day := targetDate.Day()
-	loggedMinutes := 0
-	for _, row := range report.Rows {
-		if mins, ok := row.Days[day]; ok {
-			loggedMinutes += mins
-		}
-	}
+	day := DateOf(targetDate)
+	report := BuildDetailedReport(checkouts, logs, commits, events, daySchedules, day, day, now, opts, activity...)
+	loggedMinutes, _ := report.DayTotal(day)
 
 	// Get scheduled minutes for the target day
 	scheduledMinutes := 0
 	for _, ds := range daySchedules {
-		if ds.Date.Day() == day && ds.Date.Month() == month && ds.Date.Year() == year {
+		if DateOf(ds.Date).Equal(day) {
 			for _, w := range ds.Windows {
-				scheduledMinutes += windowMinutes(w)
+				scheduledMinutes += w.Minutes()
 			}
 			break
 		}
@@ -59,12 +54,14 @@ func ComputeDayBudget(
 		LoggedMinutes:    loggedMinutes,
 		ScheduledMinutes: scheduledMinutes,
 		RemainingMinutes: remaining,
+		OvertimeMinutes:  report.Overtime[day],
 	}
 }
 
 // ComputeManualLogBudget computes the manual log budget for a day,
 // counting only manual log entries and submitted/generated entries.
-// excludeID, if non-empty, skips the entry with that ID (for edit).
+// Entries count on their date in targetDate's time zone. excludeID, if
+// non-empty, skips the entry with that ID (for edit).
 // Used by: log and edit budget warnings.
 func ComputeManualLogBudget(
 	logs []entry.Entry,
@@ -79,7 +76,7 @@ func ComputeManualLogBudget(
 		if excludeID != "" && e.ID == excludeID {
 			continue
 		}
-		ey, em, ed := e.Start.Date()
+		ey, em, ed := e.Start.In(targetDate.Location()).Date()
 		if ey == y && em == m && ed == d {
 			loggedMinutes += e.Minutes
 		}
@@ -90,7 +87,7 @@ func ComputeManualLogBudget(
 	for _, ds := range daySchedules {
 		if ds.Date.Day() == d && ds.Date.Month() == m && ds.Date.Year() == y {
 			for _, w := range ds.Windows {
-				scheduledMinutes += windowMinutes(w)
+				scheduledMinutes += w.Minutes()
 			}
 			break
 		}
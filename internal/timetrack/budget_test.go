@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/rounding"
 	"github.com/Flyrell/hourgit/internal/schedule"
 	"github.com/stretchr/testify/assert"
 )
@@ -44,7 +45,7 @@ func TestComputeDayBudgetWithCheckouts(t *testing.T) {
 		},
 	}
 
-	budget := ComputeDayBudget(checkouts, nil, nil, daySchedules, now, now)
+	budget := ComputeDayBudget(checkouts, nil, nil, nil, daySchedules, now, now, Options{})
 
 	// Checked out at 9am, now is 2pm = 5h = 300 minutes of checkout time
 	assert.Equal(t, 300, budget.LoggedMinutes)
@@ -65,18 +66,33 @@ func TestComputeDayBudgetWithLogs(t *testing.T) {
 		},
 	}
 
-	budget := ComputeDayBudget(nil, logs, nil, daySchedules, now, now)
+	budget := ComputeDayBudget(nil, logs, nil, nil, daySchedules, now, now, Options{})
 
 	assert.Equal(t, 150, budget.LoggedMinutes)
 	assert.Equal(t, 480, budget.ScheduledMinutes)
 	assert.Equal(t, 330, budget.RemainingMinutes)
 }
 
+func TestComputeDayBudgetRounded(t *testing.T) {
+	now := time.Date(2025, 6, 11, 14, 0, 0, 0, time.UTC)
+	daySchedules := weekdaySchedule(9, 0, 17, 0)
+
+	logs := []entry.Entry{
+		{ID: "abc1234", Start: time.Date(2025, 6, 11, 9, 0, 0, 0, time.UTC), Minutes: 140, Message: "morning work"},
+	}
+	policy := rounding.Policy{Increment: 30, Mode: rounding.ModeUp, Scope: rounding.ScopeDay}
+
+	budget := ComputeDayBudget(nil, logs, nil, nil, daySchedules, now, now, Options{Rounding: policy})
+
+	assert.Equal(t, 150, budget.LoggedMinutes)
+	assert.Equal(t, 330, budget.RemainingMinutes)
+}
+
 func TestComputeDayBudgetNonWorkingDay(t *testing.T) {
 	now := time.Date(2025, 6, 14, 10, 0, 0, 0, time.UTC) // Saturday
 	daySchedules := weekdaySchedule(9, 0, 17, 0)
 
-	budget := ComputeDayBudget(nil, nil, nil, daySchedules, now, now)
+	budget := ComputeDayBudget(nil, nil, nil, nil, daySchedules, now, now, Options{})
 
 	assert.Equal(t, 0, budget.LoggedMinutes)
 	assert.Equal(t, 0, budget.ScheduledMinutes)
@@ -114,12 +130,12 @@ func TestComputeDayBudgetWithIdleTrimming(t *testing.T) {
 	}
 
 	budgetWithIdle := ComputeDayBudget(
-		checkouts, nil, commits, daySchedules, now, now,
+		checkouts, nil, commits, nil, daySchedules, now, now, Options{},
 		ActivityEntries{Stops: stops, Starts: starts},
 	)
 
 	budgetWithoutIdle := ComputeDayBudget(
-		checkouts, nil, commits, daySchedules, now, now,
+		checkouts, nil, commits, nil, daySchedules, now, now, Options{},
 	)
 
 	// With idle trimming, 2h idle gap should reduce logged time
@@ -13,14 +13,14 @@ import (
 func deductLogOverlaps(
 	segments []sessionSegment,
 	logs []entry.Entry,
-	year int, month time.Month,
+	from, to time.Time,
 	loc *time.Location,
 ) []sessionSegment {
-	// Collect log ranges for the target month
+	// Collect log ranges for the target dates
 	var gaps []idleGap
 	for _, l := range logs {
 		logStart := l.Start.In(loc)
-		if logStart.Year() != year || logStart.Month() != month {
+		if !inRange(DateOf(logStart), from, to) {
 			continue
 		}
 		// Skip checkout-generated entries — they don't reduce checkout time
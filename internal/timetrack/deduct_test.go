@@ -33,22 +33,22 @@ func TestDeductLogOverlaps_LogBetweenCheckouts_Default(t *testing.T) {
 		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 60, Message: "meeting", Task: "meeting"},
 	}
 
-	report := BuildReport(checkouts, logs, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
-	rowA := findRow(report, "A")
-	rowB := findRow(report, "B")
-	rowLog := findRow(report, "meeting")
+	rowA := findDetailedRow(report, "A")
+	rowB := findDetailedRow(report, "B")
+	rowLog := findDetailedRow(report, "meeting")
 
 	assert.NotNil(t, rowA)
 	assert.NotNil(t, rowB)
 	assert.NotNil(t, rowLog)
 
 	// A: 09:00-10:00 = 60min (log carves 10:00-11:00)
-	assert.Equal(t, 60, rowA.Days[2])
+	assert.Equal(t, 60, rowA.Days[date(year, month, 2)].TotalMinutes)
 	// B: 11:00-17:00 = 360min
-	assert.Equal(t, 360, rowB.Days[2])
+	assert.Equal(t, 360, rowB.Days[date(year, month, 2)].TotalMinutes)
 	// Log: 60min
-	assert.Equal(t, 60, rowLog.Days[2])
+	assert.Equal(t, 60, rowLog.Days[date(year, month, 2)].TotalMinutes)
 }
 
 func TestDeductLogOverlaps_LogBetweenCheckouts_Split(t *testing.T) {
@@ -63,18 +63,18 @@ func TestDeductLogOverlaps_LogBetweenCheckouts_Split(t *testing.T) {
 		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 60, Message: "meeting", Task: "meeting"},
 	}
 
-	report := BuildReport(checkouts, logs, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
-	rowA := findRow(report, "A")
-	rowB := findRow(report, "B")
+	rowA := findDetailedRow(report, "A")
+	rowB := findDetailedRow(report, "B")
 
 	assert.NotNil(t, rowA)
 	assert.NotNil(t, rowB)
 
 	// A: 09:00-10:00 = 60min (log carves 10:00-11:00, schedule only goes to 11:00)
-	assert.Equal(t, 60, rowA.Days[2])
+	assert.Equal(t, 60, rowA.Days[date(year, month, 2)].TotalMinutes)
 	// B: 12:00-17:00 = 300min (split schedule, second window)
-	assert.Equal(t, 300, rowB.Days[2])
+	assert.Equal(t, 300, rowB.Days[date(year, month, 2)].TotalMinutes)
 }
 
 // Test Case 2: Log spanning a lunch gap
@@ -90,18 +90,18 @@ func TestDeductLogOverlaps_LogSpanningGap_Default(t *testing.T) {
 		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 120, Message: "meeting", Task: "meeting"},
 	}
 
-	report := BuildReport(checkouts, logs, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
-	rowA := findRow(report, "A")
-	rowB := findRow(report, "B")
+	rowA := findDetailedRow(report, "A")
+	rowB := findDetailedRow(report, "B")
 
 	assert.NotNil(t, rowA)
 	assert.NotNil(t, rowB)
 
 	// A: 09:00-10:00 = 60min (log carves 10:00-12:00, which also takes from B's start)
-	assert.Equal(t, 60, rowA.Days[2])
+	assert.Equal(t, 60, rowA.Days[date(year, month, 2)].TotalMinutes)
 	// B: checkout at 11:00, but log covers 10:00-12:00, so B effective from 12:00-17:00 = 300min
-	assert.Equal(t, 300, rowB.Days[2])
+	assert.Equal(t, 300, rowB.Days[date(year, month, 2)].TotalMinutes)
 }
 
 // Test Case 3: Log at start of checkout
@@ -117,13 +117,13 @@ func TestDeductLogOverlaps_LogAtCheckoutStart_Default(t *testing.T) {
 		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 180, Message: "research", Task: "research"},
 	}
 
-	report := BuildReport(checkouts, logs, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
-	rowA := findRow(report, "A")
+	rowA := findDetailedRow(report, "A")
 	assert.NotNil(t, rowA)
 
 	// A: checkout 10:00-17:00, log carves 10:00-13:00, so A gets 13:00-17:00 = 240min
-	assert.Equal(t, 240, rowA.Days[2])
+	assert.Equal(t, 240, rowA.Days[date(year, month, 2)].TotalMinutes)
 }
 
 // Edge case: Log on different day only affects that day
@@ -138,15 +138,15 @@ func TestDeductLogOverlaps_LogOnDifferentDay(t *testing.T) {
 		{ID: "l1", Start: time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC), Minutes: 60, Message: "meeting", Task: "meeting"},
 	}
 
-	report := BuildReport(checkouts, logs, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
-	rowA := findRow(report, "A")
+	rowA := findDetailedRow(report, "A")
 	assert.NotNil(t, rowA)
 
 	// Day 2: unaffected by log on day 3
-	assert.Equal(t, 480, rowA.Days[2])
+	assert.Equal(t, 480, rowA.Days[date(year, month, 2)].TotalMinutes)
 	// Day 3: log carves 10:00-11:00, A gets 09:00-10:00 + 11:00-17:00 = 420min
-	assert.Equal(t, 420, rowA.Days[3])
+	assert.Equal(t, 420, rowA.Days[date(year, month, 3)].TotalMinutes)
 }
 
 // Edge case: Log fully contains a segment
@@ -163,9 +163,9 @@ func TestDeductLogOverlaps_LogContainsSegment(t *testing.T) {
 		{ID: "l1", Start: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Minutes: 120, Message: "meeting", Task: "meeting"},
 	}
 
-	report := BuildReport(checkouts, logs, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
-	rowA := findRow(report, "A")
+	rowA := findDetailedRow(report, "A")
 	// A: checkout 10:00-11:00, log covers 09:00-11:00, so A is fully removed
 	assert.Nil(t, rowA)
 }
@@ -183,13 +183,13 @@ func TestDeductLogOverlaps_MultipleLogs(t *testing.T) {
 		{ID: "l2", Start: time.Date(2025, 1, 2, 14, 0, 0, 0, time.UTC), Minutes: 60, Message: "meeting2", Task: "meeting2"},
 	}
 
-	report := BuildReport(checkouts, logs, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
-	rowA := findRow(report, "A")
+	rowA := findDetailedRow(report, "A")
 	assert.NotNil(t, rowA)
 
 	// A: 480 - 60 - 60 = 360min (two logs carved out)
-	assert.Equal(t, 360, rowA.Days[2])
+	assert.Equal(t, 360, rowA.Days[date(year, month, 2)].TotalMinutes)
 }
 
 // Edge case: checkout-generated logs should NOT deduct
@@ -201,16 +201,19 @@ func TestDeductLogOverlaps_CheckoutGeneratedSkipped(t *testing.T) {
 		{ID: "c1", Timestamp: time.Date(2024, 12, 31, 10, 0, 0, 0, time.UTC), Previous: "main", Next: "A"},
 	}
 	logs := []entry.Entry{
-		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 120, Message: "A", Task: "A", Source: "checkout-generated"},
+		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 120, Message: "B", Task: "B", Source: "checkout-generated"},
 	}
 
-	report := BuildReport(checkouts, logs, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
-	rowA := findRow(report, "A")
+	rowA := findDetailedRow(report, "A")
+	rowB := findDetailedRow(report, "B")
 	assert.NotNil(t, rowA)
+	assert.NotNil(t, rowB)
 
-	// checkout-generated log should not reduce checkout time — total = 480 + 120
-	assert.Equal(t, 480+120, rowA.TotalMinutes)
+	// checkout-generated log should not reduce checkout time of A
+	assert.Equal(t, 480, rowA.TotalMinutes)
+	assert.Equal(t, 120, rowB.TotalMinutes)
 }
 
 // Activity start/stop + log interaction
@@ -234,13 +237,13 @@ func TestDeductLogOverlaps_WithIdleGaps(t *testing.T) {
 	}
 
 	activity := ActivityEntries{Stops: stops, Starts: starts}
-	report := BuildReport(checkouts, logs, nil, days, year, month, afterMonth(year, month), nil, activity)
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{}, activity)
 
-	rowA := findRow(report, "A")
+	rowA := findDetailedRow(report, "A")
 	assert.NotNil(t, rowA)
 
 	// Schedule: 9-17 = 480min
 	// Idle gap carves 10:00-11:00 = -60min → 420min
 	// Log carves 14:00-15:00 = -60min → 360min
-	assert.Equal(t, 360, rowA.Days[2])
+	assert.Equal(t, 360, rowA.Days[date(year, month, 2)].TotalMinutes)
 }
@@ -5,82 +5,145 @@ import (
 	"time"
 
 	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/rounding"
 	"github.com/Flyrell/hourgit/internal/schedule"
 )
 
 // ExportEntry represents a single time entry for PDF export.
 type ExportEntry struct {
-	Start   time.Time
-	Minutes int
-	Message string
+	Start      time.Time
+	Minutes    int // rounded per the export's policy
+	RawMinutes int // before rounding
+	Message    string
+	Event      string // git event kind (entry.EventMerge, ...) when the entry ends at one
 }
 
 // ExportTaskGroup groups entries under a task name with a subtotal.
 type ExportTaskGroup struct {
 	Task         string
+	Category     string // the category of most of the group's time, empty when uncategorized
 	Entries      []ExportEntry
 	TotalMinutes int
+	RawMinutes   int
 }
 
 // ExportDay holds all task groups for a single day.
 type ExportDay struct {
-	Date         time.Time
-	Groups       []ExportTaskGroup
-	TotalMinutes int
+	Date            time.Time
+	Groups          []ExportTaskGroup
+	TotalMinutes    int
+	RawMinutes      int
+	OvertimeMinutes int // outside schedule windows (separate overtime policy)
 }
 
-// ExportData holds the complete export for a given month.
+// ExportData holds the complete export for a date range.
 type ExportData struct {
-	ProjectName  string
-	Year         int
-	Month        time.Month
-	Days         []ExportDay
-	TotalMinutes int
+	ProjectName     string
+	From            time.Time
+	To              time.Time
+	Days            []ExportDay
+	TotalMinutes    int
+	RawMinutes      int
+	OvertimeMinutes int
+	Rounding        rounding.Policy
+	RepoMinutes     map[string]int // unrounded minutes per repository, "" for logged time
+	ByRepo          bool           // show RepoMinutes below the totals
 }
 
-// BuildExportData builds detailed export data preserving individual entries,
-// grouped by day and task. Checkout attribution on non-generated days produces
-// one synthetic entry per branch-day.
+// BuildExportData builds detailed export data for the dates from..to
+// (inclusive), preserving individual entries, grouped by day and task. Checkout attribution on non-generated days produces
+// one synthetic entry per branch-day. Time is attributed and totalled per
+// opts: entries, task-day and day totals are rounded, the raw minutes are
+// kept alongside.
 func BuildExportData(
 	checkouts []entry.CheckoutEntry,
 	logs []entry.Entry,
 	commits []entry.CommitEntry,
+	events []entry.GitEventEntry,
 	daySchedules []schedule.DaySchedule,
-	year int, month time.Month,
+	from, to time.Time,
 	now time.Time,
 	generatedDays []string,
 	projectName string,
 	detail string,
+	opts Options,
 	activity ...ActivityEntries,
 ) ExportData {
-	daysInMonth := daysIn(year, month)
+	from, to = DateOf(from), DateOf(to)
+	dates := Dates(from, to)
 
-	generatedSet := make(map[int]bool, len(generatedDays))
+	generatedSet := make(map[time.Time]bool, len(generatedDays))
 	for _, ds := range generatedDays {
 		t, err := time.Parse("2006-01-02", ds)
 		if err != nil {
 			continue
 		}
-		if t.Year() == year && t.Month() == month {
-			generatedSet[t.Day()] = true
+		if inRange(t, from, to) {
+			generatedSet[t] = true
 		}
 	}
 
-	scheduleWindows, _ := buildScheduleLookup(daySchedules, year, month)
-
 	loc := now.Location()
-	segments := buildCheckoutSegments(checkouts, commits, year, month, daysInMonth, now)
-	if len(activity) > 0 && (len(activity[0].Stops) > 0 || len(activity[0].Starts) > 0) {
-		segments = trimSegmentsByIdleGaps(segments, activity[0].Stops, activity[0].Starts)
+	scheduleWindows, _ := buildScheduleLookup(daySchedules, from, to)
+
+	end := segmentEnd(to, scheduleWindows)
+	segments := buildAttributedSegments(checkouts, commits, events, logs, from, end, now, scheduleWindows, opts, activity)
+	checkoutBucket := buildSegmentBucket(segments, dates, scheduleWindows, loc)
+
+	// Checkout time outside schedule windows: either regular work or a
+	// separate overtime category
+	overtimeWindows, _ := buildScheduleLookup(daySchedules, from.AddDate(0, 0, -1), to)
+	overtimeEntries := buildOvertimeCellEntries(overtimeSegments(segments, opts.Overtime, activity, now), dates, overtimeWindows, loc)
+	overtimeMins := make(map[time.Time]int)
+	for _, oe := range overtimeEntries {
+		switch {
+		case opts.Overtime == project.OvertimeActivity:
+			if checkoutBucket[oe.task] == nil {
+				checkoutBucket[oe.task] = make(map[time.Time]int)
+			}
+			checkoutBucket[oe.task][oe.day] += oe.minutes
+		case !generatedSet[oe.day]:
+			overtimeMins[oe.day] += oe.minutes
+		}
+	}
+
+	// Unrounded minutes per repository, for the repo breakdown, and per
+	// category of each task and day
+	repoMins := make(map[string]int)
+	type taskDay struct {
+		task string
+		day  time.Time
+	}
+	categoryMins := make(map[taskDay]map[string]int)
+	addCategory := func(task string, day time.Time, category string, mins int) {
+		key := taskDay{task: task, day: day}
+		if categoryMins[key] == nil {
+			categoryMins[key] = make(map[string]int)
+		}
+		categoryMins[key][category] += mins
+	}
+	repoEntries := buildSegmentCellEntries(segments, dates, scheduleWindows, loc)
+	if opts.Overtime == project.OvertimeActivity {
+		repoEntries = append(repoEntries, overtimeEntries...)
+	}
+	for _, ce := range repoEntries {
+		if !generatedSet[ce.day] {
+			repoMins[ce.repo] += ce.minutes
+			addCategory(cleanBranchName(ce.task), ce.day, ce.category, ce.minutes)
+		}
+	}
+	for _, l := range logs {
+		if day := DateOf(l.Start.In(loc)); inRange(day, from, to) {
+			repoMins[""] += l.Minutes
+			addCategory(logTaskKey(l), day, l.Category, l.Minutes)
+		}
 	}
-	// Trim manual log time ranges from checkout segments
-	segments = deductLogOverlaps(segments, logs, year, month, loc)
-	checkoutBucket := buildSegmentBucket(segments, year, month, daysInMonth, scheduleWindows, loc)
 
 	// Zero out checkout attribution for generated days
 	for day := range generatedSet {
-		for branch := range checkoutBucket {
-			delete(checkoutBucket[branch], day)
+		for task := range checkoutBucket {
+			delete(checkoutBucket[task], day)
 		}
 	}
 
@@ -89,14 +152,14 @@ func BuildExportData(
 		task    string
 		entries []ExportEntry
 	}
-	dayGroups := make(map[int]map[string]*dayTask)
+	dayGroups := make(map[time.Time]map[string]*dayTask)
 
 	// Add log entries
 	for _, l := range logs {
-		if l.Start.Year() != year || l.Start.Month() != month {
+		day := DateOf(l.Start.In(loc))
+		if !inRange(day, from, to) {
 			continue
 		}
-		day := l.Start.Day()
 		if dayGroups[day] == nil {
 			dayGroups[day] = make(map[string]*dayTask)
 		}
@@ -107,18 +170,22 @@ func BuildExportData(
 			dayGroups[day][key] = dt
 		}
 		dt.entries = append(dt.entries, ExportEntry{
-			Start:   l.Start,
-			Minutes: l.Minutes,
-			Message: l.Message,
+			Start:      l.Start.In(loc),
+			RawMinutes: l.Minutes,
+			Message:    l.Message,
 		})
 	}
 
 	// Add checkout attribution as entries
-	if detail == "full" && len(commits) > 0 {
-		// Full detail: one ExportEntry per commit segment, preserving messages
-		cellEntries := buildSegmentCellEntries(segments, year, month, daysInMonth, scheduleWindows, now.Location())
+	if detail == "full" && (len(commits) > 0 || len(events) > 0) {
+		// Full detail: one ExportEntry per commit or git event segment,
+		// preserving messages
+		cellEntries := buildSegmentCellEntries(segments, dates, scheduleWindows, loc)
+		if opts.Overtime == project.OvertimeActivity {
+			cellEntries = append(cellEntries, overtimeEntries...)
+		}
 		for _, ce := range cellEntries {
-			cleanedBranch := cleanBranchName(ce.branch)
+			cleanedTask := cleanBranchName(ce.task)
 			day := ce.day
 			if generatedSet[day] {
 				continue
@@ -127,31 +194,32 @@ func BuildExportData(
 				continue
 			}
 			// Apply deduction proportionally via checkoutBucket — skip if bucket was zeroed
-			if checkoutBucket[ce.branch] == nil || checkoutBucket[ce.branch][day] <= 0 {
+			if checkoutBucket[ce.task] == nil || checkoutBucket[ce.task][day] <= 0 {
 				continue
 			}
 			if dayGroups[day] == nil {
 				dayGroups[day] = make(map[string]*dayTask)
 			}
-			dt := dayGroups[day][cleanedBranch]
+			dt := dayGroups[day][cleanedTask]
 			if dt == nil {
-				dt = &dayTask{task: cleanedBranch}
-				dayGroups[day][cleanedBranch] = dt
+				dt = &dayTask{task: cleanedTask}
+				dayGroups[day][cleanedTask] = dt
 			}
 			msg := ce.message
 			if msg == "" {
 				msg = "(uncommitted)"
 			}
 			dt.entries = append(dt.entries, ExportEntry{
-				Start:   ce.start,
-				Minutes: ce.minutes,
-				Message: msg,
+				Start:      ce.start,
+				RawMinutes: ce.minutes,
+				Message:    msg,
+				Event:      ce.event,
 			})
 		}
 	} else {
-		// Summary: one synthetic entry per branch-day
-		for branch, dayMap := range checkoutBucket {
-			cleanedBranch := cleanBranchName(branch)
+		// Summary: one synthetic entry per task-day
+		for task, dayMap := range checkoutBucket {
+			cleanedTask := cleanBranchName(task)
 			for day, mins := range dayMap {
 				if mins <= 0 {
 					continue
@@ -159,15 +227,15 @@ func BuildExportData(
 				if dayGroups[day] == nil {
 					dayGroups[day] = make(map[string]*dayTask)
 				}
-				dt := dayGroups[day][cleanedBranch]
+				dt := dayGroups[day][cleanedTask]
 				if dt == nil {
-					dt = &dayTask{task: cleanedBranch}
-					dayGroups[day][cleanedBranch] = dt
+					dt = &dayTask{task: cleanedTask}
+					dayGroups[day][cleanedTask] = dt
 				}
 				dt.entries = append(dt.entries, ExportEntry{
-					Start:   time.Date(year, month, day, 9, 0, 0, 0, time.UTC),
-					Minutes: mins,
-					Message: cleanedBranch,
+					Start:      time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, loc),
+					RawMinutes: mins,
+					Message:    cleanedTask,
 				})
 			}
 		}
@@ -175,19 +243,21 @@ func BuildExportData(
 
 	// Assemble ExportDays
 	var days []ExportDay
-	for day := 1; day <= daysInMonth; day++ {
+	for _, day := range dates {
 		tasks, ok := dayGroups[day]
-		if !ok {
+		if !ok && overtimeMins[day] == 0 {
 			continue
 		}
 
 		var groups []ExportTaskGroup
 		for _, dt := range tasks {
-			totalMins := 0
-			for _, e := range dt.entries {
-				totalMins += e.Minutes
+			totalMins, rawMins := 0, 0
+			for i, e := range dt.entries {
+				dt.entries[i].Minutes = opts.Rounding.Entry(e.RawMinutes)
+				totalMins += dt.entries[i].Minutes
+				rawMins += e.RawMinutes
 			}
-			if totalMins <= 0 {
+			if rawMins <= 0 {
 				continue
 			}
 			// Sort entries by start time
@@ -196,12 +266,14 @@ func BuildExportData(
 			})
 			groups = append(groups, ExportTaskGroup{
 				Task:         dt.task,
+				Category:     dominantCategory(categoryMins[taskDay{task: dt.task, day: day}]),
 				Entries:      dt.entries,
-				TotalMinutes: totalMins,
+				TotalMinutes: opts.Rounding.TaskDay(totalMins),
+				RawMinutes:   rawMins,
 			})
 		}
 
-		if len(groups) == 0 {
+		if len(groups) == 0 && overtimeMins[day] == 0 {
 			continue
 		}
 
@@ -210,28 +282,37 @@ func BuildExportData(
 			return groups[i].Task < groups[j].Task
 		})
 
-		dayTotal := 0
+		dayTotal, dayRaw := 0, 0
 		for _, g := range groups {
 			dayTotal += g.TotalMinutes
+			dayRaw += g.RawMinutes
 		}
 
 		days = append(days, ExportDay{
-			Date:         time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
-			Groups:       groups,
-			TotalMinutes: dayTotal,
+			Date:            day,
+			Groups:          groups,
+			TotalMinutes:    opts.Rounding.Day(dayTotal),
+			RawMinutes:      dayRaw,
+			OvertimeMinutes: overtimeMins[day],
 		})
 	}
 
-	grandTotal := 0
+	grandTotal, grandRaw, grandOvertime := 0, 0, 0
 	for _, d := range days {
 		grandTotal += d.TotalMinutes
+		grandRaw += d.RawMinutes
+		grandOvertime += d.OvertimeMinutes
 	}
 
 	return ExportData{
-		ProjectName:  projectName,
-		Year:         year,
-		Month:        month,
-		Days:         days,
-		TotalMinutes: grandTotal,
+		ProjectName:     projectName,
+		From:            from,
+		To:              to,
+		Days:            days,
+		TotalMinutes:    grandTotal,
+		RawMinutes:      grandRaw,
+		OvertimeMinutes: grandOvertime,
+		Rounding:        opts.Rounding,
+		RepoMinutes:     repoMins,
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/rounding"
 	"github.com/Flyrell/hourgit/internal/schedule"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,11 +21,11 @@ func TestBuildExportData_LogEntriesGroupedByTask(t *testing.T) {
 		{ID: "l3", Start: time.Date(2025, 1, 2, 14, 0, 0, 0, time.UTC), Minutes: 75, Message: "API design research", Task: ""},
 	}
 
-	data := BuildExportData(nil, logs, nil, days, year, month, afterMonth(year, month), nil, "Test Project", "")
+	data := BuildExportData(nil, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), nil, "Test Project", "", Options{})
 
 	assert.Equal(t, "Test Project", data.ProjectName)
-	assert.Equal(t, 2025, data.Year)
-	assert.Equal(t, time.January, data.Month)
+	assert.Equal(t, date(2025, time.January, 1), data.From)
+	assert.Equal(t, date(2025, time.January, 31), data.To)
 	require.Equal(t, 1, len(data.Days))
 
 	day := data.Days[0]
@@ -57,7 +58,7 @@ func TestBuildExportData_CheckoutAttribution(t *testing.T) {
 		{ID: "c1", Timestamp: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "feature-x"},
 	}
 
-	data := BuildExportData(checkouts, nil, nil, days, year, month, afterMonth(year, month), nil, "Test", "")
+	data := BuildExportData(checkouts, nil, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), nil, "Test", "", Options{})
 
 	require.Equal(t, 1, len(data.Days))
 	day := data.Days[0]
@@ -83,7 +84,7 @@ func TestBuildExportData_GeneratedDaysSkipCheckouts(t *testing.T) {
 
 	generatedDays := []string{"2025-01-02"}
 
-	data := BuildExportData(checkouts, logs, nil, days, year, month, afterMonth(year, month), generatedDays, "Test", "")
+	data := BuildExportData(checkouts, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), generatedDays, "Test", "", Options{})
 
 	// Day 2 should only have the log entry (checkout skipped due to generated)
 	// Day 3 should have checkout attribution
@@ -101,7 +102,7 @@ func TestBuildExportData_GeneratedDaysSkipCheckouts(t *testing.T) {
 func TestBuildExportData_EmptyMonth(t *testing.T) {
 	year, month := 2025, time.January
 
-	data := BuildExportData(nil, nil, nil, nil, year, month, afterMonth(year, month), nil, "Empty", "")
+	data := BuildExportData(nil, nil, nil, nil, nil, firstDay(year, month), lastDay(year, month), afterMonth(year, month), nil, "Empty", "", Options{})
 
 	assert.Equal(t, 0, len(data.Days))
 	assert.Equal(t, 0, data.TotalMinutes)
@@ -116,7 +117,7 @@ func TestBuildExportData_MultipleDaysSorted(t *testing.T) {
 		{ID: "l2", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 30, Message: "work", Task: "task"},
 	}
 
-	data := BuildExportData(nil, logs, nil, days, year, month, afterMonth(year, month), nil, "Test", "")
+	data := BuildExportData(nil, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), nil, "Test", "", Options{})
 
 	require.Equal(t, 2, len(data.Days))
 	// Days should be sorted ascending
@@ -125,6 +126,54 @@ func TestBuildExportData_MultipleDaysSorted(t *testing.T) {
 	assert.Equal(t, 90, data.TotalMinutes)
 }
 
+func TestBuildExportData_CrossMonthRange(t *testing.T) {
+	from, to := date(2025, time.September, 29), date(2025, time.October, 5)
+	days := []schedule.DaySchedule{workday(2025, time.September, 30), workday(2025, time.October, 2)}
+
+	logs := []entry.Entry{
+		{ID: "l1", Start: time.Date(2025, 9, 30, 10, 0, 0, 0, time.UTC), Minutes: 60, Message: "work", Task: "task"},
+		{ID: "l2", Start: time.Date(2025, 10, 2, 10, 0, 0, 0, time.UTC), Minutes: 30, Message: "work", Task: "task"},
+		{ID: "l3", Start: time.Date(2025, 9, 26, 10, 0, 0, 0, time.UTC), Minutes: 45, Message: "work", Task: "task"},
+	}
+
+	data := BuildExportData(nil, logs, nil, nil, days, from, to, afterMonth(2025, time.October), nil, "Test", "", Options{})
+
+	assert.Equal(t, from, data.From)
+	assert.Equal(t, to, data.To)
+	require.Equal(t, 2, len(data.Days))
+	assert.Equal(t, date(2025, time.September, 30), data.Days[0].Date)
+	assert.Equal(t, date(2025, time.October, 2), data.Days[1].Date)
+	assert.Equal(t, 90, data.TotalMinutes)
+}
+
+func TestBuildExportData_SummaryStartsInProjectZone(t *testing.T) {
+	tokyo := mustZone(t, "Asia/Tokyo")
+	day := date(2025, time.January, 6)
+	days := []schedule.DaySchedule{window(day, 9, 17, nil)}
+
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 1, 6, 10, 0, 0, 0, tokyo), Previous: "main", Next: "feature-x"},
+	}
+	logs := []entry.Entry{
+		{ID: "l1", Start: time.Date(2025, 1, 6, 0, 30, 0, 0, time.UTC), Minutes: 30, Message: "sync", Task: "meeting"},
+	}
+
+	data := BuildExportData(checkouts, logs, nil, nil, days, day, day, time.Date(2025, 1, 7, 12, 0, 0, 0, tokyo), nil, "Test", "", Options{})
+
+	require.Len(t, data.Days, 1)
+	for _, g := range data.Days[0].Groups {
+		for _, e := range g.Entries {
+			assert.Equal(t, tokyo, e.Start.Location())
+		}
+		if g.Task == "feature-x" {
+			assert.Equal(t, 9, g.Entries[0].Start.Hour())
+		}
+		if g.Task == "meeting" {
+			assert.Equal(t, 9, g.Entries[0].Start.Hour(), "00:30 UTC is 09:30 in Tokyo")
+		}
+	}
+}
+
 func TestBuildExportData_ScheduleDeduction(t *testing.T) {
 	year, month := 2025, time.January
 	days := []schedule.DaySchedule{workday(year, month, 2)} // 480 min total
@@ -137,7 +186,7 @@ func TestBuildExportData_ScheduleDeduction(t *testing.T) {
 		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 120, Message: "research", Task: "research"},
 	}
 
-	data := BuildExportData(checkouts, logs, nil, days, year, month, afterMonth(year, month), nil, "Test", "")
+	data := BuildExportData(checkouts, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), nil, "Test", "", Options{})
 
 	require.Equal(t, 1, len(data.Days))
 	day := data.Days[0]
@@ -172,7 +221,7 @@ func TestBuildExportData_FullDetailWithCommits(t *testing.T) {
 		{ID: "cm2", Timestamp: time.Date(2025, 1, 2, 14, 0, 0, 0, time.UTC), Message: "Fix validation", CommitRef: "def5678", Branch: "feature-x"},
 	}
 
-	data := BuildExportData(checkouts, nil, commits, days, year, month, afterMonth(year, month), nil, "Test", "full")
+	data := BuildExportData(checkouts, nil, commits, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), nil, "Test", "full", Options{})
 
 	require.Equal(t, 1, len(data.Days))
 	day := data.Days[0]
@@ -192,6 +241,31 @@ func TestBuildExportData_FullDetailWithCommits(t *testing.T) {
 	assert.Equal(t, 180, group.Entries[2].Minutes) // 14:00-17:00
 }
 
+func TestBuildExportData_FullDetailLabelsGitEvents(t *testing.T) {
+	year, month := 2025, time.January
+	days := []schedule.DaySchedule{workday(year, month, 2)} // 9-17
+
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "feature-x"},
+	}
+
+	events := []entry.GitEventEntry{
+		{ID: "e1", Timestamp: time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC), Kind: entry.EventRebase, Branch: "feature-x", Message: "rebase (finish): returning to refs/heads/feature-x"},
+	}
+
+	data := BuildExportData(checkouts, nil, nil, events, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), nil, "Test", "full", Options{})
+
+	require.Equal(t, 1, len(data.Days))
+	require.Equal(t, 1, len(data.Days[0].Groups))
+	entries := data.Days[0].Groups[0].Entries
+	require.Equal(t, 2, len(entries))
+	assert.Equal(t, entry.EventRebase, entries[0].Event)
+	assert.Equal(t, "rebase (finish): returning to refs/heads/feature-x", entries[0].Message)
+	assert.Equal(t, 240, entries[0].Minutes) // 9:00-13:00
+	assert.Equal(t, "", entries[1].Event)
+	assert.Equal(t, "(uncommitted)", entries[1].Message)
+}
+
 func TestBuildExportData_FullDetailNoCommitsFallsBackToSummary(t *testing.T) {
 	year, month := 2025, time.January
 	days := []schedule.DaySchedule{workday(year, month, 2)} // 9-17
@@ -200,7 +274,7 @@ func TestBuildExportData_FullDetailNoCommitsFallsBackToSummary(t *testing.T) {
 		{ID: "c1", Timestamp: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "feature-x"},
 	}
 
-	data := BuildExportData(checkouts, nil, nil, days, year, month, afterMonth(year, month), nil, "Test", "full")
+	data := BuildExportData(checkouts, nil, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), nil, "Test", "full", Options{})
 
 	require.Equal(t, 1, len(data.Days))
 	day := data.Days[0]
@@ -227,7 +301,7 @@ func TestBuildExportData_SummaryDetailWithCommits(t *testing.T) {
 	}
 
 	// Summary mode: one synthetic entry despite commits existing
-	data := BuildExportData(checkouts, nil, commits, days, year, month, afterMonth(year, month), nil, "Test", "summary")
+	data := BuildExportData(checkouts, nil, commits, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), nil, "Test", "summary", Options{})
 
 	require.Equal(t, 1, len(data.Days))
 	day := data.Days[0]
@@ -235,3 +309,39 @@ func TestBuildExportData_SummaryDetailWithCommits(t *testing.T) {
 	assert.Equal(t, 1, len(day.Groups[0].Entries))
 	assert.Equal(t, "feature-x", day.Groups[0].Entries[0].Message)
 }
+
+func TestBuildExportData_Rounding(t *testing.T) {
+	year, month := 2025, time.January
+	days := []schedule.DaySchedule{workday(year, month, 2), workday(year, month, 3)}
+
+	logs := []entry.Entry{
+		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 10, Message: "a", Task: "research"},
+		{ID: "l2", Start: time.Date(2025, 1, 2, 11, 0, 0, 0, time.UTC), Minutes: 10, Message: "b", Task: "research"},
+		{ID: "l3", Start: time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC), Minutes: 50, Message: "c", Task: "review"},
+	}
+
+	policy := rounding.Policy{Increment: 15, Mode: rounding.ModeUp, Scope: rounding.ScopeEntry}
+	data := BuildExportData(nil, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), nil, "Test Project", "", Options{Rounding: policy})
+
+	require.Equal(t, 2, len(data.Days))
+	group := data.Days[0].Groups[0]
+	assert.Equal(t, 15, group.Entries[0].Minutes)
+	assert.Equal(t, 10, group.Entries[0].RawMinutes)
+	assert.Equal(t, 30, group.TotalMinutes)
+	assert.Equal(t, 20, group.RawMinutes)
+	assert.Equal(t, 30, data.Days[0].TotalMinutes)
+	assert.Equal(t, 60, data.Days[1].TotalMinutes)
+	assert.Equal(t, 90, data.TotalMinutes)
+	assert.Equal(t, 70, data.RawMinutes)
+	assert.Equal(t, policy, data.Rounding)
+
+	policy.Scope = rounding.ScopeDay
+	data = BuildExportData(nil, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), nil, "Test Project", "", Options{Rounding: policy})
+
+	assert.Equal(t, 10, data.Days[0].Groups[0].Entries[0].Minutes)
+	assert.Equal(t, 20, data.Days[0].Groups[0].TotalMinutes)
+	assert.Equal(t, 30, data.Days[0].TotalMinutes)
+	assert.Equal(t, 60, data.Days[1].TotalMinutes)
+	assert.Equal(t, 90, data.TotalMinutes)
+	assert.Equal(t, 70, data.RawMinutes)
+}
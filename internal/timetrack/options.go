@@ -0,0 +1,31 @@
+package timetrack
+
+import (
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/rounding"
+	"github.com/Flyrell/hourgit/internal/rules"
+)
+
+// Options are the policies time is attributed and totalled with. The zero
+// value attributes checkout sessions, unrounded, ignoring time outside the
+// schedule and other projects, the latest checked-out repository winning.
+type Options struct {
+	Rounding  rounding.Policy // applied to cell, day and report totals
+	Overtime  string          // checkout time outside schedule windows, see project.OvertimeIgnore
+	RepoMerge string          // time several repositories claim at once, see project.RepoMergeLatest
+	TaskRules []rules.Rule    // group checkout time by task instead of branch
+	Strategy  Strategy        // how sessions are derived, checkout sessions when nil
+	Overlap   Overlap         // time other projects claim
+}
+
+// ProjectOptions returns the options a project's config selects. Overlap is
+// left to the caller, as it depends on the other projects.
+func ProjectOptions(p *project.ProjectEntry) Options {
+	return Options{
+		Rounding:  p.Rounding,
+		Overtime:  p.Overtime,
+		RepoMerge: p.RepoMerge,
+		TaskRules: p.Rules,
+		Strategy:  NewStrategy(p.Attribution),
+	}
+}
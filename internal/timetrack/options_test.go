@@ -0,0 +1,27 @@
+package timetrack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/rounding"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectOptions(t *testing.T) {
+	p := &project.ProjectEntry{
+		Rounding:    rounding.Policy{Increment: 15, Mode: rounding.ModeUp, Scope: rounding.ScopeEntry},
+		Overtime:    project.OvertimeSeparate,
+		RepoMerge:   project.RepoMergeSplit,
+		Attribution: project.Attribution{Strategy: project.AttributionCommits, MaxGap: 120, LeadIn: 30},
+	}
+
+	opts := ProjectOptions(p)
+
+	assert.Equal(t, p.Rounding, opts.Rounding)
+	assert.Equal(t, project.OvertimeSeparate, opts.Overtime)
+	assert.Equal(t, project.RepoMergeSplit, opts.RepoMerge)
+	assert.Equal(t, CommitSessions{MaxGap: 2 * time.Hour, LeadIn: 30 * time.Minute}, opts.Strategy)
+	assert.Empty(t, opts.Overlap.Claims)
+}
@@ -0,0 +1,206 @@
+package timetrack
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/schedule"
+)
+
+// Overlap describes the time other projects claim, so that time two projects
+// claim at once is counted only once. The zero value resolves nothing.
+type Overlap struct {
+	Policy   string  // project.OverlapRecent, OverlapPriority, OverlapSplit or OverlapOff
+	Project  string  // ID of the project being computed
+	Priority int     // its priority under OverlapPriority
+	Claims   []Claim // time claimed by the other projects
+}
+
+// Claim is a span of time another project attributes to itself.
+type Claim struct {
+	Project  string
+	Priority int
+	From     time.Time
+	To       time.Time
+	Since    time.Time // when the claiming branch was checked out
+	Logged   bool      // a manual log, which always wins
+}
+
+// ProjectClaims returns the time a project claims for the dates from..to:
+// its attributed checkout time within its schedule windows and its manual
+// logs, attributed per the strategy and repo merge policy of opts. now must be
+// in the project's time zone.
+func ProjectClaims(
+	projectID string,
+	priority int,
+	checkouts []entry.CheckoutEntry,
+	logs []entry.Entry,
+	commits []entry.CommitEntry,
+	events []entry.GitEventEntry,
+	daySchedules []schedule.DaySchedule,
+	from, to time.Time,
+	now time.Time,
+	opts Options,
+	activity ...ActivityEntries,
+) []Claim {
+	scheduleWindows, _ := buildScheduleLookup(daySchedules, from, to)
+	segments := buildAttributedSegments(checkouts, commits, events, logs, from, segmentEnd(to, scheduleWindows), now, scheduleWindows,
+		Options{RepoMerge: opts.RepoMerge, Strategy: opts.Strategy}, activity)
+
+	var claims []Claim
+	for _, seg := range segments {
+		if seg.branch == "" {
+			continue
+		}
+		for day, windows := range scheduleWindows {
+			for _, w := range windows {
+				wStart, wEnd := WindowBounds(day, w, now.Location())
+				claimFrom, claimTo := laterOf(seg.from, wStart), earlierOf(seg.to, wEnd)
+				if claimTo.After(claimFrom) {
+					claims = append(claims, Claim{Project: projectID, Priority: priority, From: claimFrom, To: claimTo, Since: seg.since})
+				}
+			}
+		}
+	}
+	for _, l := range logs {
+		if l.Source == "checkout-generated" || !inRange(DateOf(l.Start.In(now.Location())), from, to) {
+			continue
+		}
+		claims = append(claims, Claim{
+			Project:  projectID,
+			Priority: priority,
+			From:     l.Start,
+			To:       l.Start.Add(time.Duration(l.Minutes) * time.Minute),
+			Since:    l.Start,
+			Logged:   true,
+		})
+	}
+	sort.Slice(claims, func(i, j int) bool { return claims[i].From.Before(claims[j].From) })
+	return claims
+}
+
+// resolveProjectOverlap removes from segments the time other projects win
+// per overlap policy: a manual log of another project always wins; otherwise
+// the project checked out most recently (recent, the default), the one with
+// the highest priority (priority, falling back to recent), or every project
+// an even share (split). Ties go to the project with the lower ID, so every
+// project resolves an overlap the same way. cuts are passed on as in
+// mergeRepoSegments.
+func resolveProjectOverlap(segments []sessionSegment, overlap Overlap, cuts []time.Time) []sessionSegment {
+	if len(overlap.Claims) == 0 || overlap.Policy == project.OverlapOff {
+		return segments
+	}
+
+	var result []sessionSegment
+	for _, seg := range segments {
+		var claims []Claim
+		bounds := []time.Time{seg.from, seg.to}
+		for _, c := range overlap.Claims {
+			if c.To.After(seg.from) && c.From.Before(seg.to) {
+				claims = append(claims, c)
+				bounds = append(bounds, c.From, c.To)
+			}
+		}
+		if len(claims) == 0 || seg.branch == "" {
+			result = append(result, seg)
+			continue
+		}
+		bounds = append(bounds, cuts...)
+		sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+
+		for i := 0; i+1 < len(bounds); i++ {
+			from, to := laterOf(bounds[i], seg.from), earlierOf(bounds[i+1], seg.to)
+			if !to.After(from) {
+				continue
+			}
+			var covering []Claim
+			for _, c := range claims {
+				if !c.From.After(from) && !c.To.Before(to) {
+					covering = append(covering, c)
+				}
+			}
+			keepFrom, keepTo := keptShare(seg, from, to, covering, overlap)
+			if !keepTo.After(keepFrom) {
+				continue
+			}
+			if n := len(result); n > 0 && result[n-1].since.Equal(seg.since) && result[n-1].branch == seg.branch &&
+				result[n-1].repo == seg.repo && result[n-1].to.Equal(keepFrom) {
+				result[n-1].to = keepTo
+				continue
+			}
+			piece := seg
+			piece.from, piece.to = keepFrom, keepTo
+			result = append(result, piece)
+		}
+	}
+	return result
+}
+
+// keptShare returns the part of [from, to) the segment keeps against the
+// claims covering all of it.
+func keptShare(seg sessionSegment, from, to time.Time, covering []Claim, overlap Overlap) (time.Time, time.Time) {
+	if len(covering) == 0 {
+		return from, to
+	}
+	for _, c := range covering {
+		if c.Logged {
+			return from, from
+		}
+	}
+
+	if overlap.Policy == project.OverlapSplit {
+		projects := []string{overlap.Project}
+		for _, c := range covering {
+			if !slices.Contains(projects, c.Project) {
+				projects = append(projects, c.Project)
+			}
+		}
+		sort.Strings(projects)
+		minutes := int(to.Sub(from).Minutes())
+		at := from
+		for n, id := range projects {
+			share := minutes / len(projects)
+			if n < minutes%len(projects) {
+				share++
+			}
+			end := at.Add(time.Duration(share) * time.Minute)
+			if id == overlap.Project {
+				return at, end
+			}
+			at = end
+		}
+		return from, from
+	}
+
+	for _, c := range covering {
+		if overlap.Policy == project.OverlapPriority && c.Priority != overlap.Priority {
+			if c.Priority > overlap.Priority {
+				return from, from
+			}
+			continue
+		}
+		if c.Since.After(seg.since) || (c.Since.Equal(seg.since) && c.Project < overlap.Project) {
+			return from, from
+		}
+	}
+	return from, to
+}
+
+// laterOf returns the later of a and b.
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// earlierOf returns the earlier of a and b.
+func earlierOf(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
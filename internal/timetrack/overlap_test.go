@@ -0,0 +1,104 @@
+package timetrack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// twoProjectDay has project "a" check out api at 9am and project "b" check
+// out web at 11am on Jan 2, 2025, a 9am-5pm workday for both.
+func twoProjectDay() (a, b []entry.CheckoutEntry, now time.Time) {
+	a = []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "api", Repo: "/a"},
+	}
+	b = []entry.CheckoutEntry{
+		{ID: "c2", Timestamp: time.Date(2025, 1, 2, 11, 0, 0, 0, time.UTC), Previous: "main", Next: "web", Repo: "/b"},
+	}
+	return a, b, time.Date(2025, 1, 2, 18, 0, 0, 0, time.UTC)
+}
+
+// dayTotal builds the report of a project on Jan 2, 2025 and returns its total.
+func dayTotal(checkouts []entry.CheckoutEntry, logs []entry.Entry, overlap Overlap, now time.Time) int {
+	day := date(2025, time.January, 2)
+	report := BuildDetailedReport(checkouts, logs, nil, nil, oneWorkday(), day, day, now, Options{Overlap: overlap})
+	total, _ := report.Total()
+	return total
+}
+
+func oneWorkday() []schedule.DaySchedule {
+	return []schedule.DaySchedule{workday(2025, time.January, 2)}
+}
+
+func TestProjectClaims(t *testing.T) {
+	_, b, now := twoProjectDay()
+	logs := []entry.Entry{{ID: "l1", Start: time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC), Minutes: 30, Message: "standup"}}
+	day := date(2025, time.January, 2)
+
+	claims := ProjectClaims("b", 2, b, logs, nil, nil, oneWorkday(), day, day, now, Options{})
+
+	require.Len(t, claims, 2)
+	assert.Equal(t, Claim{
+		Project: "b", Priority: 2,
+		From: time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC), To: time.Date(2025, 1, 2, 8, 30, 0, 0, time.UTC),
+		Since: time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC), Logged: true,
+	}, claims[0])
+	assert.Equal(t, time.Date(2025, 1, 2, 11, 0, 0, 0, time.UTC), claims[1].From)
+	assert.Equal(t, time.Date(2025, 1, 2, 17, 0, 0, 0, time.UTC), claims[1].To, "cut to the schedule")
+	assert.Equal(t, claims[1].From, claims[1].Since)
+}
+
+func TestBuildDetailedReport_OverlapPolicies(t *testing.T) {
+	a, b, now := twoProjectDay()
+	day := date(2025, time.January, 2)
+	aClaims := ProjectClaims("a", 0, a, nil, nil, nil, oneWorkday(), day, day, now, Options{})
+	bClaims := ProjectClaims("b", 0, b, nil, nil, nil, oneWorkday(), day, day, now, Options{})
+
+	tests := []struct {
+		policy    string
+		aPriority int
+		wantA     int
+		wantB     int
+		why       string
+	}{
+		{project.OverlapRecent, 0, 120, 360, "web was checked out last"},
+		{project.OverlapPriority, 1, 480, 0, "a has the higher priority"},
+		{project.OverlapPriority, 0, 120, 360, "a tie falls back to recent"},
+		{project.OverlapSplit, 0, 300, 180, "the overlap is split in half"},
+		{project.OverlapOff, 0, 480, 360, "both keep their time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			for i := range aClaims {
+				aClaims[i].Priority = tt.aPriority
+			}
+			gotA := dayTotal(a, nil, Overlap{Policy: tt.policy, Project: "a", Priority: tt.aPriority, Claims: bClaims}, now)
+			gotB := dayTotal(b, nil, Overlap{Policy: tt.policy, Project: "b", Claims: aClaims}, now)
+
+			assert.Equal(t, tt.wantA, gotA, tt.why)
+			assert.Equal(t, tt.wantB, gotB, tt.why)
+		})
+	}
+}
+
+func TestBuildDetailedReport_OverlapLoggedTimeWins(t *testing.T) {
+	a, _, now := twoProjectDay()
+	day := date(2025, time.January, 2)
+	meeting := []entry.Entry{{ID: "l1", Start: time.Date(2025, 1, 2, 14, 0, 0, 0, time.UTC), Minutes: 60, Message: "planning"}}
+	claims := ProjectClaims("b", 0, nil, meeting, nil, nil, oneWorkday(), day, day, now, Options{})
+
+	got := dayTotal(a, nil, Overlap{Policy: project.OverlapPriority, Project: "a", Priority: 5, Claims: claims}, now)
+
+	assert.Equal(t, 420, got)
+}
+
+func TestResolveProjectOverlap_NoClaims(t *testing.T) {
+	segments := []sessionSegment{{branch: "a", from: t9am, to: t12pm}}
+
+	assert.Equal(t, segments, resolveProjectOverlap(segments, Overlap{Policy: project.OverlapSplit}, nil))
+}
@@ -0,0 +1,171 @@
+package timetrack
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/schedule"
+)
+
+// activeSpan is a period in which the watcher saw file changes, from an
+// activity_start to the next activity_stop.
+type activeSpan struct {
+	from time.Time
+	to   time.Time
+}
+
+// buildActiveSpans merges the activity spans of all repositories (see
+// buildRepoActiveSpans) into one sorted list without overlaps.
+func buildActiveSpans(activity []ActivityEntries, now time.Time) []activeSpan {
+	var spans []activeSpan
+	for _, repoSpans := range buildRepoActiveSpans(activity, now) {
+		spans = append(spans, repoSpans...)
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].from.Before(spans[j].from) })
+	var merged []activeSpan
+	for _, span := range spans {
+		if n := len(merged); n > 0 && !span.from.After(merged[n-1].to) {
+			if span.to.After(merged[n-1].to) {
+				merged[n-1].to = span.to
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
+
+// buildRepoActiveSpans pairs each activity start with the next stop after it
+// in the same repository, and returns the spans per repository, oldest first.
+// A start without a later stop is still active and runs until now.
+func buildRepoActiveSpans(activity []ActivityEntries, now time.Time) map[string][]activeSpan {
+	if len(activity) == 0 {
+		return nil
+	}
+	stops := make(map[string][]time.Time)
+	for _, s := range activity[0].Stops {
+		stops[s.Repo] = append(stops[s.Repo], s.Timestamp)
+	}
+	starts := make(map[string][]time.Time)
+	for _, s := range activity[0].Starts {
+		starts[s.Repo] = append(starts[s.Repo], s.Timestamp)
+	}
+
+	spans := make(map[string][]activeSpan)
+	for repo, repoStarts := range starts {
+		repoStops := stops[repo]
+		sort.Slice(repoStarts, func(i, j int) bool { return repoStarts[i].Before(repoStarts[j]) })
+		sort.Slice(repoStops, func(i, j int) bool { return repoStops[i].Before(repoStops[j]) })
+
+		stopIdx := 0
+		for i, start := range repoStarts {
+			for stopIdx < len(repoStops) && repoStops[stopIdx].Before(start) {
+				stopIdx++
+			}
+			end := now
+			if stopIdx < len(repoStops) {
+				end = repoStops[stopIdx]
+			}
+			// No stop recorded before the next start, e.g. after a crash
+			if i+1 < len(repoStarts) && repoStarts[i+1].Before(end) {
+				end = repoStarts[i+1]
+			}
+			if end.After(start) {
+				spans[repo] = append(spans[repo], activeSpan{from: start, to: end})
+			}
+		}
+	}
+	return spans
+}
+
+// overtimeSegments returns the parts of segments that count as overtime
+// candidates under the overtime policy: all of them for separate, only the
+// parts inside recorded activity for activity, and none for ignore. Only the
+// time outside schedule windows is overtime; see overtimeMinutes.
+func overtimeSegments(segments []sessionSegment, overtime string, activity []ActivityEntries, now time.Time) []sessionSegment {
+	switch overtime {
+	case project.OvertimeSeparate:
+		return segments
+	case project.OvertimeActivity:
+		spans := buildActiveSpans(activity, now)
+		var result []sessionSegment
+		for _, seg := range segments {
+			for _, span := range spans {
+				from, to := seg.from, seg.to
+				if span.from.After(from) {
+					from = span.from.Truncate(time.Minute)
+				}
+				if span.to.Before(to) {
+					to = span.to.Truncate(time.Minute)
+				}
+				if to.After(from) {
+					clipped := seg
+					clipped.from, clipped.to = from, to
+					result = append(result, clipped)
+				}
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// overtimeMinutes computes how many minutes of [from, to) fall on the date day
+// (in loc) outside its schedule windows and outside the part after midnight
+// of the overnight windows of the day before. Days without a schedule count
+// in full. scheduleWindows must include the day before day.
+func overtimeMinutes(from, to time.Time, day time.Time, scheduleWindows map[time.Time][]schedule.TimeWindow, loc *time.Location) int {
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	dayEnd := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+	if dayStart.After(from) {
+		from = dayStart
+	}
+	if dayEnd.Before(to) {
+		to = dayEnd
+	}
+	if !to.After(from) {
+		return 0
+	}
+
+	total := int(math.Round(to.Sub(from).Minutes()))
+	total -= overlapMinutes(from, to, day, scheduleWindows[day], loc)
+	prev := day.AddDate(0, 0, -1)
+	total -= overlapMinutes(from, to, prev, scheduleWindows[prev], loc)
+	return max(total, 0)
+}
+
+// buildOvertimeCellEntries converts overtime segments into per-day cell
+// entries holding the time outside schedule windows.
+func buildOvertimeCellEntries(
+	segments []sessionSegment,
+	dates []time.Time,
+	scheduleWindows map[time.Time][]schedule.TimeWindow,
+	loc *time.Location,
+) []segmentCellEntry {
+	var entries []segmentCellEntry
+	for _, seg := range segments {
+		if seg.branch == "" {
+			continue
+		}
+		for _, day := range dates {
+			mins := overtimeMinutes(seg.from, seg.to, day, scheduleWindows, loc)
+			if mins > 0 {
+				entries = append(entries, segmentCellEntry{
+					branch:   seg.branch,
+					task:     seg.taskKey(),
+					category: seg.category,
+					repo:     seg.repo,
+					day:      day,
+					minutes:  mins,
+					message:  seg.message,
+					event:    seg.event,
+					start:    seg.from.In(loc),
+				})
+			}
+		}
+	}
+	return entries
+}
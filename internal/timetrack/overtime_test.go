@@ -0,0 +1,148 @@
+package timetrack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eveningHotfix checks out a hotfix branch at 9am on Jan 2, 2025 and keeps it
+// until 8pm, three hours past the end of the 9am-5pm schedule.
+func eveningHotfix() ([]entry.CheckoutEntry, []schedule.DaySchedule, time.Time) {
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "hotfix"},
+	}
+	days := []schedule.DaySchedule{workday(2025, time.January, 2)}
+	now := time.Date(2025, 1, 2, 20, 0, 0, 0, time.UTC)
+	return checkouts, days, now
+}
+
+func TestBuildActiveSpans(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 1, 2, h, m, 0, 0, time.UTC) }
+	activity := []ActivityEntries{{
+		Starts: []entry.ActivityStartEntry{
+			{Timestamp: at(18, 0), Repo: "/a"},
+			{Timestamp: at(18, 30), Repo: "/b"},
+			{Timestamp: at(21, 0), Repo: "/a"},
+		},
+		Stops: []entry.ActivityStopEntry{
+			{Timestamp: at(19, 0), Repo: "/a"},
+			{Timestamp: at(19, 30), Repo: "/b"},
+		},
+	}}
+
+	spans := buildActiveSpans(activity, at(22, 0))
+
+	assert.Equal(t, []activeSpan{
+		{from: at(18, 0), to: at(19, 30)},
+		{from: at(21, 0), to: at(22, 0)},
+	}, spans)
+	assert.Nil(t, buildActiveSpans(nil, at(22, 0)))
+}
+
+func TestOvertimeMinutes(t *testing.T) {
+	day := date(2025, time.January, 2)
+	windows := map[time.Time][]schedule.TimeWindow{day: workday(2025, time.January, 2).Windows}
+
+	from := time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 3, 1, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 8*60, overtimeMinutes(from, to, day, windows, time.UTC), "8-9am and 5pm-midnight")
+	assert.Equal(t, 60, overtimeMinutes(from, to, day.AddDate(0, 0, 1), windows, time.UTC), "unscheduled day counts in full")
+	assert.Equal(t, 0, overtimeMinutes(from, to, day.AddDate(0, 0, 2), windows, time.UTC))
+}
+
+func TestOvertimeMinutes_OvernightWindowOfDayBefore(t *testing.T) {
+	day := date(2025, time.January, 2)
+	windows := map[time.Time][]schedule.TimeWindow{
+		day: {{From: schedule.TimeOfDay{Hour: 22}, To: schedule.TimeOfDay{Hour: 6}}},
+	}
+
+	from := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 3, 8, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 120, overtimeMinutes(from, to, day.AddDate(0, 0, 1), windows, time.UTC), "6-8am after the night shift")
+}
+
+func TestBuildDetailedReport_OvertimeIgnored(t *testing.T) {
+	checkouts, days, now := eveningHotfix()
+	from, to := firstDay(2025, time.January), lastDay(2025, time.January)
+
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, from, to, now, Options{})
+
+	total, _ := report.Total()
+	assert.Equal(t, 480, total)
+	assert.Equal(t, 0, report.OvertimeTotal())
+}
+
+func TestBuildDetailedReport_OvertimeSeparate(t *testing.T) {
+	checkouts, days, now := eveningHotfix()
+	from, to := firstDay(2025, time.January), lastDay(2025, time.January)
+
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, from, to, now, Options{Overtime: project.OvertimeSeparate})
+
+	total, _ := report.Total()
+	assert.Equal(t, 480, total, "overtime is kept out of the regular total")
+	assert.Equal(t, 180, report.Overtime[date(2025, time.January, 2)])
+	assert.Equal(t, 180, report.OvertimeTotal())
+}
+
+func TestBuildDetailedReport_OvertimeFromActivity(t *testing.T) {
+	checkouts, days, now := eveningHotfix()
+	from, to := firstDay(2025, time.January), lastDay(2025, time.January)
+	activity := ActivityEntries{
+		Starts: []entry.ActivityStartEntry{
+			{Timestamp: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)},
+			{Timestamp: time.Date(2025, 1, 2, 18, 0, 0, 0, time.UTC)},
+		},
+		Stops: []entry.ActivityStopEntry{
+			{Timestamp: time.Date(2025, 1, 2, 17, 0, 0, 0, time.UTC)},
+			{Timestamp: time.Date(2025, 1, 2, 19, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, from, to, now, Options{Overtime: project.OvertimeActivity}, activity)
+
+	row := findDetailedRow(report, "hotfix")
+	require.NotNil(t, row)
+	assert.Equal(t, 540, row.TotalMinutes, "8h scheduled plus the hour of activity at 6pm")
+	assert.Equal(t, 0, report.OvertimeTotal())
+
+	// Without activity there is nothing to prove the evening's work
+	report = BuildDetailedReport(checkouts, nil, nil, nil, days, from, to, now, Options{Overtime: project.OvertimeActivity})
+	total, _ := report.Total()
+	assert.Equal(t, 480, total)
+}
+
+func TestBuildExportData_OvertimeSeparate(t *testing.T) {
+	checkouts, days, now := eveningHotfix()
+	checkouts = append(checkouts, entry.CheckoutEntry{
+		ID: "c2", Timestamp: time.Date(2025, 1, 4, 10, 0, 0, 0, time.UTC), Previous: "hotfix", Next: "weekend",
+	})
+	now = time.Date(2025, 1, 4, 11, 0, 0, 0, time.UTC)
+
+	data := BuildExportData(checkouts, nil, nil, nil, days, firstDay(2025, time.January), lastDay(2025, time.January), now, nil, "Test Project", "", Options{Overtime: project.OvertimeSeparate})
+
+	require.Equal(t, 3, len(data.Days))
+	assert.Equal(t, 480, data.Days[0].TotalMinutes)
+	assert.Equal(t, 7*60, data.Days[0].OvertimeMinutes, "5pm to midnight")
+	assert.Equal(t, 24*60, data.Days[1].OvertimeMinutes, "unscheduled day")
+	assert.Empty(t, data.Days[2].Groups, "a day with only overtime")
+	assert.Equal(t, 660, data.Days[2].OvertimeMinutes)
+	assert.Equal(t, 480, data.TotalMinutes)
+	assert.Equal(t, 7*60+24*60+660, data.OvertimeMinutes)
+}
+
+func TestComputeDayBudget_OvertimeSeparate(t *testing.T) {
+	checkouts, days, now := eveningHotfix()
+
+	budget := ComputeDayBudget(checkouts, nil, nil, nil, days, now, now, Options{Overtime: project.OvertimeSeparate})
+
+	assert.Equal(t, 480, budget.LoggedMinutes)
+	assert.Equal(t, 180, budget.OvertimeMinutes)
+}
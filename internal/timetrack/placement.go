@@ -21,17 +21,23 @@ func FindAvailableSlot(
 ) (time.Time, error) {
 	y, m, d := targetDate.Date()
 
-	// Build occupied ranges from existing logs on targetDate
+	// Build occupied ranges from existing logs on targetDate and the next
+	// day, which overnight windows run into
 	type timeRange struct {
-		from, to int // minutes from midnight
+		from, to int // minutes from midnight of targetDate
 	}
+	ny, nm, nd := time.Date(y, m, d+1, 0, 0, 0, 0, loc).Date()
 	var occupied []timeRange
 	for _, l := range existingLogs {
 		ls := l.Start.In(loc)
-		if ls.Year() != y || ls.Month() != m || ls.Day() != d {
+		fromMins := ls.Hour()*60 + ls.Minute()
+		switch {
+		case ls.Year() == y && ls.Month() == m && ls.Day() == d:
+		case ls.Year() == ny && ls.Month() == nm && ls.Day() == nd:
+			fromMins += 24 * 60
+		default:
 			continue
 		}
-		fromMins := ls.Hour()*60 + ls.Minute()
 		occupied = append(occupied, timeRange{from: fromMins, to: fromMins + l.Minutes})
 	}
 	sort.Slice(occupied, func(i, j int) bool {
@@ -40,8 +46,8 @@ func FindAvailableSlot(
 
 	// Walk schedule windows chronologically, find first gap that fits
 	for _, w := range windows {
-		wFrom := w.From.Hour*60 + w.From.Minute
-		wTo := w.To.Hour*60 + w.To.Minute
+		wFrom := w.From.Minutes()
+		wTo := w.End()
 
 		cursor := wFrom
 		for _, occ := range occupied {
@@ -119,3 +119,23 @@ func TestFindAvailableSlot_EmptyWindows(t *testing.T) {
 	_, err = FindAvailableSlot(nil, []schedule.TimeWindow{}, target, 60, time.UTC)
 	assert.Error(t, err)
 }
+
+func TestFindAvailableSlot_OvernightWindow(t *testing.T) {
+	windows := []schedule.TimeWindow{
+		{From: schedule.TimeOfDay{Hour: 22, Minute: 0}, To: schedule.TimeOfDay{Hour: 6, Minute: 0}},
+	}
+	target := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	// 22:00-01:00 is taken the same night, 01:00-03:00 the next morning
+	logs := []entry.Entry{
+		{ID: "l1", Start: time.Date(2025, 1, 2, 22, 0, 0, 0, time.UTC), Minutes: 180},
+		{ID: "l2", Start: time.Date(2025, 1, 3, 1, 0, 0, 0, time.UTC), Minutes: 120},
+	}
+
+	start, err := FindAvailableSlot(logs, windows, target, 120, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 3, 3, 0, 0, 0, time.UTC), start)
+
+	_, err = FindAvailableSlot(logs, windows, target, 240, time.UTC)
+	assert.Error(t, err)
+}
@@ -0,0 +1,205 @@
+package timetrack
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/rules"
+	"github.com/Flyrell/hourgit/internal/schedule"
+)
+
+// buildAttributedSegments builds the segments of every repository for the
+// dates from..to per opts.Strategy, trims their idle gaps, merges the time the
+// repositories claim at once per the repo merge policy, gives up the time
+// other projects win per opts.Overlap, carves out the time of manual logs and
+// assigns tasks and categories per opts.TaskRules.
+func buildAttributedSegments(
+	checkouts []entry.CheckoutEntry,
+	commits []entry.CommitEntry,
+	events []entry.GitEventEntry,
+	logs []entry.Entry,
+	from, to time.Time,
+	now time.Time,
+	scheduleWindows map[time.Time][]schedule.TimeWindow,
+	opts Options,
+	activity []ActivityEntries,
+) []sessionSegment {
+	strategy := opts.Strategy
+	if strategy == nil {
+		strategy = CheckoutSessions{}
+	}
+	segments := strategy.segments(checkouts, commits, events, from, to, now)
+	// Trim idle gaps if activity entries provided
+	if len(activity) > 0 && (len(activity[0].Stops) > 0 || len(activity[0].Starts) > 0) {
+		segments = trimSegmentsByIdleGaps(segments, activity[0].Stops, activity[0].Starts)
+	}
+	var cuts []time.Time
+	for day, windows := range scheduleWindows {
+		for _, w := range windows {
+			start, end := WindowBounds(day, w, now.Location())
+			cuts = append(cuts, start, end)
+		}
+	}
+	segments = mergeRepoSegments(segments, opts.RepoMerge, cuts, activity, now)
+	segments = resolveProjectOverlap(segments, opts.Overlap, cuts)
+	// Trim manual log time ranges from checkout segments
+	segments = deductLogOverlaps(segments, logs, from, to, now.Location())
+	return applyTaskRules(segments, opts.TaskRules)
+}
+
+// applyTaskRules assigns each segment the task and category of the first
+// rule matching its branch, commit message and repository.
+func applyTaskRules(segments []sessionSegment, taskRules []rules.Rule) []sessionSegment {
+	if len(taskRules) == 0 {
+		return segments
+	}
+	engine := rules.Compile(taskRules)
+	for i, seg := range segments {
+		if seg.branch == "" {
+			continue
+		}
+		segments[i].task, segments[i].category = engine.Apply(seg.branch, seg.message, seg.repo)
+	}
+	return segments
+}
+
+// mergeRepoSegments resolves the time that segments of several repositories
+// claim at once, so no minute is counted twice. Per repo merge policy the
+// overlap goes to the repository checked out last (latest, the default), is
+// split evenly between the repositories (split), or goes to the repository
+// the watcher saw file changes in, falling back to the one checked out last
+// (active). Segments of a single repository are returned unchanged.
+//
+// Overlaps are also cut at cuts, e.g. schedule window bounds, so a split
+// stays even within each window rather than only across the whole overlap.
+func mergeRepoSegments(segments []sessionSegment, repoMerge string, cuts []time.Time, activity []ActivityEntries, now time.Time) []sessionSegment {
+	if len(segments) < 2 {
+		return segments
+	}
+	multiRepo := false
+	for _, seg := range segments[1:] {
+		if seg.repo != segments[0].repo {
+			multiRepo = true
+			break
+		}
+	}
+	if !multiRepo {
+		return segments
+	}
+
+	var spans map[string][]activeSpan
+	bounds := make([]time.Time, 0, 2*len(segments)+len(cuts))
+	for _, seg := range segments {
+		bounds = append(bounds, seg.from, seg.to)
+	}
+	bounds = append(bounds, cuts...)
+	if repoMerge == project.RepoMergeActive {
+		spans = buildRepoActiveSpans(activity, now)
+		for _, repoSpans := range spans {
+			for _, span := range repoSpans {
+				bounds = append(bounds, span.from.Truncate(time.Minute), span.to.Truncate(time.Minute))
+			}
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+
+	// Walk the elementary intervals between consecutive bounds and hand each
+	// one to the segments that win it
+	type piece struct {
+		idx      int
+		from, to time.Time
+	}
+	var pieces []piece
+	for i := 0; i+1 < len(bounds); i++ {
+		from, to := bounds[i], bounds[i+1]
+		if !to.After(from) {
+			continue
+		}
+		var covering []int
+		for idx, seg := range segments {
+			if !seg.from.After(from) && !seg.to.Before(to) {
+				covering = append(covering, idx)
+			}
+		}
+		switch {
+		case len(covering) == 0:
+			continue
+		case len(covering) == 1:
+			pieces = append(pieces, piece{idx: covering[0], from: from, to: to})
+			continue
+		}
+
+		switch repoMerge {
+		case project.RepoMergeSplit:
+			sort.Slice(covering, func(a, b int) bool { return segments[covering[a]].repo < segments[covering[b]].repo })
+			minutes := int(to.Sub(from).Minutes())
+			at := from
+			for n, idx := range covering {
+				share := minutes / len(covering)
+				if n < minutes%len(covering) {
+					share++
+				}
+				if share == 0 {
+					continue
+				}
+				end := at.Add(time.Duration(share) * time.Minute)
+				pieces = append(pieces, piece{idx: idx, from: at, to: end})
+				at = end
+			}
+		case project.RepoMergeActive:
+			var active []int
+			for _, idx := range covering {
+				if activeAt(spans[segments[idx].repo], from) {
+					active = append(active, idx)
+				}
+			}
+			if len(active) > 0 {
+				covering = active
+			}
+			pieces = append(pieces, piece{idx: latestSegment(segments, covering), from: from, to: to})
+		default:
+			pieces = append(pieces, piece{idx: latestSegment(segments, covering), from: from, to: to})
+		}
+	}
+
+	// Join the adjacent pieces of each segment back together
+	var merged []sessionSegment
+	lastIdx := -1
+	for _, p := range pieces {
+		if n := len(merged); n > 0 && p.idx == lastIdx && merged[n-1].to.Equal(p.from) {
+			merged[n-1].to = p.to
+			continue
+		}
+		seg := segments[p.idx]
+		seg.from, seg.to = p.from, p.to
+		merged = append(merged, seg)
+		lastIdx = p.idx
+	}
+	return merged
+}
+
+// latestSegment returns the index of the segment whose branch was checked
+// out last, preferring the first repository by name on a tie.
+func latestSegment(segments []sessionSegment, indexes []int) int {
+	best := indexes[0]
+	for _, idx := range indexes[1:] {
+		seg, cur := segments[idx], segments[best]
+		if seg.since.After(cur.since) || (seg.since.Equal(cur.since) && seg.repo < cur.repo) {
+			best = idx
+		}
+	}
+	return best
+}
+
+// activeAt reports whether t falls within one of spans (compared at minute
+// precision, like segments).
+func activeAt(spans []activeSpan, t time.Time) bool {
+	for _, span := range spans {
+		if !span.from.Truncate(time.Minute).After(t) && span.to.Truncate(time.Minute).After(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package timetrack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/rules"
+	"github.com/Flyrell/hourgit/internal/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// twoRepoDay checks out feature-a in /a at 9am, feature-b in /b at 11am and
+// feature-c in /a at 1pm on Jan 2, 2025, a 9am-5pm workday.
+func twoRepoDay() ([]entry.CheckoutEntry, []schedule.DaySchedule, time.Time) {
+	at := func(h int) time.Time { return time.Date(2025, 1, 2, h, 0, 0, 0, time.UTC) }
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: at(9), Previous: "main", Next: "feature-a", Repo: "/a"},
+		{ID: "c2", Timestamp: at(11), Previous: "main", Next: "feature-b", Repo: "/b"},
+		{ID: "c3", Timestamp: at(13), Previous: "feature-a", Next: "feature-c", Repo: "/a"},
+	}
+	return checkouts, []schedule.DaySchedule{workday(2025, time.January, 2)}, at(18)
+}
+
+func rowTotals(report DetailedReportData) map[string]int {
+	totals := make(map[string]int)
+	for _, row := range report.Rows {
+		totals[row.Name] = row.TotalMinutes
+	}
+	return totals
+}
+
+func TestBuildCheckoutSegments_PerRepoTimelines(t *testing.T) {
+	checkouts, _, now := twoRepoDay()
+
+	segments := buildCheckoutSegments(checkouts, nil, nil, date(2025, time.January, 2), date(2025, time.January, 2), now)
+
+	require.Len(t, segments, 3)
+	assert.Equal(t, "feature-a", segments[0].branch)
+	assert.Equal(t, "/a", segments[0].repo)
+	assert.Equal(t, time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC), segments[0].to, "the checkout in /b does not end the session in /a")
+	assert.Equal(t, "feature-b", segments[1].branch)
+	assert.Equal(t, now, segments[1].to)
+	assert.Equal(t, "feature-c", segments[2].branch)
+}
+
+func TestBuildCheckoutSegments_CommitsOfOtherRepo(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2025, 1, 2, h, 0, 0, 0, time.UTC) }
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: at(9), Previous: "main", Next: "fix", Repo: "/a"},
+		{ID: "c2", Timestamp: at(9), Previous: "main", Next: "fix", Repo: "/b"},
+	}
+	commits := []entry.CommitEntry{
+		{ID: "m1", Timestamp: at(10), Branch: "fix", Message: "fix api", Repo: "/b"},
+	}
+
+	segments := buildCheckoutSegments(checkouts, commits, nil, date(2025, time.January, 2), date(2025, time.January, 2), at(12))
+
+	a := filterRepoSegments(segments, "/a")
+	require.Len(t, a, 1, "a commit in /b does not split the session in /a")
+	assert.Equal(t, "", a[0].message)
+	b := filterRepoSegments(segments, "/b")
+	require.Len(t, b, 2)
+	assert.Equal(t, "fix api", b[0].message)
+}
+
+func TestBuildDetailedReport_RepoMergeLatest(t *testing.T) {
+	checkouts, days, now := twoRepoDay()
+
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, firstDay(2025, time.January), lastDay(2025, time.January), now, Options{RepoMerge: project.RepoMergeLatest})
+
+	assert.Equal(t, map[string]int{"feature-a": 120, "feature-b": 120, "feature-c": 240}, rowTotals(report))
+}
+
+func TestBuildDetailedReport_RepoMergeSplit(t *testing.T) {
+	checkouts, days, now := twoRepoDay()
+
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, firstDay(2025, time.January), lastDay(2025, time.January), now, Options{RepoMerge: project.RepoMergeSplit})
+
+	assert.Equal(t, map[string]int{"feature-a": 180, "feature-b": 180, "feature-c": 120}, rowTotals(report))
+	total, _ := report.Total()
+	assert.Equal(t, 480, total, "no minute is counted twice")
+}
+
+func TestBuildDetailedReport_RepoMergeActive(t *testing.T) {
+	checkouts, days, now := twoRepoDay()
+	activity := ActivityEntries{
+		Starts: []entry.ActivityStartEntry{{Timestamp: time.Date(2025, 1, 2, 11, 0, 0, 0, time.UTC), Repo: "/b"}},
+	}
+
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, firstDay(2025, time.January), lastDay(2025, time.January), now, Options{RepoMerge: project.RepoMergeActive}, activity)
+
+	assert.Equal(t, map[string]int{"feature-a": 120, "feature-b": 360}, rowTotals(report))
+}
+
+func TestMergeRepoSegments_SingleRepoUnchanged(t *testing.T) {
+	segments := []sessionSegment{
+		{branch: "a", repo: "/a", from: t9am, to: t11am},
+		{branch: "b", repo: "/a", from: t11am, to: t12pm},
+	}
+
+	assert.Equal(t, segments, mergeRepoSegments(segments, project.RepoMergeSplit, nil, nil, t12pm))
+}
+
+func TestMergeRepoSegments_SplitUnevenMinutes(t *testing.T) {
+	segments := []sessionSegment{
+		{branch: "a", repo: "/a", from: t9am, to: t9am.Add(5 * time.Minute)},
+		{branch: "b", repo: "/b", from: t9am, to: t9am.Add(5 * time.Minute)},
+	}
+
+	merged := mergeRepoSegments(segments, project.RepoMergeSplit, nil, nil, t12pm)
+
+	require.Len(t, merged, 2)
+	assert.Equal(t, 3*time.Minute, merged[0].to.Sub(merged[0].from))
+	assert.Equal(t, 2*time.Minute, merged[1].to.Sub(merged[1].from))
+	assert.Equal(t, merged[0].to, merged[1].from)
+}
+
+func TestRepoBreakdown(t *testing.T) {
+	checkouts, days, now := twoRepoDay()
+	logs := []entry.Entry{{ID: "l1", Start: time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC), Minutes: 30, Message: "call"}}
+	days = append(days, workday(2025, time.January, 3))
+	checkouts = append(checkouts, entry.CheckoutEntry{ID: "c4", Timestamp: time.Date(2025, 1, 2, 17, 0, 0, 0, time.UTC), Previous: "feature-b", Next: "", Repo: "/b"})
+	checkouts = append(checkouts, entry.CheckoutEntry{ID: "c5", Timestamp: time.Date(2025, 1, 2, 17, 0, 0, 0, time.UTC), Previous: "feature-c", Next: "", Repo: "/a"})
+
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, firstDay(2025, time.January), lastDay(2025, time.January), now, Options{})
+	repos := report.RepoBreakdown()
+
+	require.Len(t, repos, 3)
+	assert.Equal(t, "/a", repos[0].Repo)
+	assert.Equal(t, 360, repos[0].TotalMinutes)
+	assert.Equal(t, "/b", repos[1].Repo)
+	assert.Equal(t, 120, repos[1].Days[date(2025, time.January, 2)])
+	assert.Equal(t, "", repos[2].Repo, "logged time has no repository")
+	assert.Equal(t, 30, repos[2].TotalMinutes)
+}
+
+// filterRepoSegments returns only segments of the given repository.
+func filterRepoSegments(segments []sessionSegment, repo string) []sessionSegment {
+	var result []sessionSegment
+	for _, s := range segments {
+		if s.repo == repo {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func TestBuildExportData_RepoMinutes(t *testing.T) {
+	checkouts, days, now := twoRepoDay()
+	logs := []entry.Entry{{ID: "l1", Start: time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC), Minutes: 30, Message: "call"}}
+
+	data := BuildExportData(checkouts, logs, nil, nil, days, firstDay(2025, time.January), lastDay(2025, time.January), now, nil, "Test Project", "", Options{RepoMerge: project.RepoMergeSplit})
+
+	assert.Equal(t, map[string]int{"/a": 300, "/b": 180, "": 30}, data.RepoMinutes)
+}
+
+// ticketDay works on PROJ-12 from 9am to 2pm on two branches and on main
+// from 2pm to 5pm, committing a chore at 3pm, on Jan 2, 2025.
+func ticketDay() ([]entry.CheckoutEntry, []entry.CommitEntry, []schedule.DaySchedule, time.Time, []rules.Rule) {
+	at := func(h int) time.Time { return time.Date(2025, 1, 2, h, 0, 0, 0, time.UTC) }
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: at(9), Previous: "main", Next: "feature/PROJ-12-login"},
+		{ID: "c2", Timestamp: at(12), Previous: "feature/PROJ-12-login", Next: "bugfix/PROJ-12-followup"},
+		{ID: "c3", Timestamp: at(14), Previous: "bugfix/PROJ-12-followup", Next: "main"},
+	}
+	commits := []entry.CommitEntry{
+		{ID: "m1", Timestamp: at(15), Branch: "main", Message: "chore: bump deps"},
+	}
+	taskRules := []rules.Rule{
+		{Branch: `^\w+/(PROJ-\d+)`, Task: "$1", Category: rules.CategoryDevelopment},
+		{Commit: `^chore:`, Category: rules.CategoryMaintenance},
+	}
+	return checkouts, commits, []schedule.DaySchedule{workday(2025, time.January, 2)}, at(17), taskRules
+}
+
+func TestBuildDetailedReport_TaskRules(t *testing.T) {
+	checkouts, commits, days, now, taskRules := ticketDay()
+	day := date(2025, time.January, 2)
+
+	report := BuildDetailedReport(checkouts, nil, commits, nil, days, day, day, now, Options{TaskRules: taskRules})
+
+	assert.Equal(t, map[string]int{"PROJ-12": 300, "main": 180}, rowTotals(report), "both branches roll up under the ticket")
+	ticket := findDetailedRow(report, "PROJ-12")
+	require.NotNil(t, ticket)
+	assert.Equal(t, rules.CategoryDevelopment, ticket.Category)
+	for _, ce := range ticket.Days[day].Entries {
+		assert.Equal(t, "PROJ-12", ce.Task)
+		assert.Equal(t, rules.CategoryDevelopment, ce.Category)
+	}
+
+	chores := findDetailedRow(report, "main")
+	require.NotNil(t, chores)
+	assert.Equal(t, rules.CategoryMaintenance, chores.Category, "only the committed chore matches")
+
+	report = BuildDetailedReport(checkouts, nil, commits, nil, days, day, day, now, Options{})
+	assert.Len(t, report.Rows, 3, "without rules time is grouped by branch")
+}
+
+func TestBuildDetailedReport_TaskRulesPersistedEntries(t *testing.T) {
+	checkouts, commits, days, now, taskRules := ticketDay()
+	day := date(2025, time.January, 2)
+	logs := []entry.Entry{{
+		ID: "e1", Start: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Minutes: 300,
+		Message: "login", Task: "PROJ-12", Category: rules.CategoryDevelopment, Source: "checkout-generated",
+	}}
+
+	report := BuildDetailedReport(checkouts, logs, commits, nil, days, day, day, now, Options{TaskRules: taskRules})
+
+	assert.Equal(t, map[string]int{"PROJ-12": 300, "main": 180}, rowTotals(report), "a submitted ticket day is not counted twice")
+}
+
+func TestBuildExportData_TaskRules(t *testing.T) {
+	checkouts, commits, days, now, taskRules := ticketDay()
+
+	data := BuildExportData(checkouts, nil, commits, nil, days, firstDay(2025, time.January), lastDay(2025, time.January), now, nil, "Test Project", "", Options{TaskRules: taskRules})
+
+	require.Len(t, data.Days, 1)
+	groups := data.Days[0].Groups
+	require.Len(t, groups, 2)
+	assert.Equal(t, "PROJ-12", groups[0].Task)
+	assert.Equal(t, 300, groups[0].TotalMinutes)
+	assert.Equal(t, rules.CategoryDevelopment, groups[0].Category)
+	assert.Equal(t, "main", groups[1].Task)
+	assert.Equal(t, rules.CategoryMaintenance, groups[1].Category)
+}
@@ -51,20 +51,53 @@ func buildIdleGaps(stops []entry.ActivityStopEntry, starts []entry.ActivityStart
 
 // trimSegmentsByIdleGaps removes idle periods from checkout segments.
 // For each segment, idle gaps that overlap are used to split or trim the segment.
+// A segment of a repository is only trimmed by that repository's gaps (and
+// by gaps recorded without a repository); a segment without a repository is
+// trimmed by all of them.
 func trimSegmentsByIdleGaps(segments []sessionSegment, stops []entry.ActivityStopEntry, starts []entry.ActivityStartEntry) []sessionSegment {
 	if len(stops) == 0 || len(starts) == 0 {
 		return segments
 	}
 
-	gaps := buildIdleGaps(stops, starts)
-	if len(gaps) == 0 {
-		return segments
-	}
-
+	gapsByRepo := make(map[string][]idleGap)
 	var result []sessionSegment
 	for _, seg := range segments {
-		trimmed := applyGapsToSegment(seg, gaps)
-		result = append(result, trimmed...)
+		gaps, ok := gapsByRepo[seg.repo]
+		if !ok {
+			gaps = buildIdleGaps(repoStops(stops, seg.repo), repoStarts(starts, seg.repo))
+			gapsByRepo[seg.repo] = gaps
+		}
+		result = append(result, applyGapsToSegment(seg, gaps)...)
+	}
+	return result
+}
+
+// repoStops returns the activity stops recorded in repo or without a
+// repository, or all of them when repo is empty.
+func repoStops(stops []entry.ActivityStopEntry, repo string) []entry.ActivityStopEntry {
+	if repo == "" {
+		return stops
+	}
+	var result []entry.ActivityStopEntry
+	for _, s := range stops {
+		if s.Repo == "" || s.Repo == repo {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// repoStarts returns the activity starts recorded in repo or without a
+// repository, or all of them when repo is empty.
+func repoStarts(starts []entry.ActivityStartEntry, repo string) []entry.ActivityStartEntry {
+	if repo == "" {
+		return starts
+	}
+	var result []entry.ActivityStartEntry
+	for _, s := range starts {
+		if s.Repo == "" || s.Repo == repo {
+			result = append(result, s)
+		}
 	}
 	return result
 }
@@ -102,49 +135,96 @@ func splitSegmentByGap(seg sessionSegment, gap idleGap) []sessionSegment {
 		return nil
 	}
 
+	before, after := seg, seg
+	before.to = gapFrom
+	after.from = gapTo
+
 	// Gap overlaps start only
 	if !gapFrom.After(seg.from) && gapTo.Before(seg.to) {
-		return []sessionSegment{{
-			branch: seg.branch, repo: seg.repo,
-			from: gapTo, to: seg.to, message: seg.message,
-		}}
+		return []sessionSegment{after}
 	}
 
 	// Gap overlaps end only
 	if gapFrom.After(seg.from) && !gapTo.Before(seg.to) {
-		return []sessionSegment{{
-			branch: seg.branch, repo: seg.repo,
-			from: seg.from, to: gapFrom, message: seg.message,
-		}}
+		return []sessionSegment{before}
 	}
 
 	// Gap is strictly inside segment — split into two
-	return []sessionSegment{
-		{branch: seg.branch, repo: seg.repo, from: seg.from, to: gapFrom, message: seg.message},
-		{branch: seg.branch, repo: seg.repo, from: gapTo, to: seg.to, message: seg.message},
-	}
+	return []sessionSegment{before, after}
 }
 
 // sessionSegment represents a sub-block of a checkout session, split by commits.
 type sessionSegment struct {
-	branch  string
-	repo    string
-	from    time.Time
-	to      time.Time
-	message string // commit message, empty for uncommitted trailing segment
+	branch   string
+	repo     string
+	from     time.Time
+	to       time.Time
+	since    time.Time // when the session's branch was checked out
+	message  string    // commit message or git event subject, empty for uncommitted trailing segment
+	event    string    // git event kind (entry.EventMerge, ...) when the segment ends at one
+	task     string    // task assigned by rules, empty for the branch name
+	category string    // category assigned by rules
+}
+
+// taskKey returns the task the segment's time counts for: the one assigned
+// by rules, else its branch.
+func (s sessionSegment) taskKey() string {
+	if s.task != "" {
+		return s.task
+	}
+	return s.branch
 }
 
-// buildCheckoutSegments splits checkout sessions by commits to produce
-// finer-grained time segments. Each commit creates a segment from the previous
-// boundary to the commit timestamp. Time is attributed backwards from the commit
-// — work before a commit is attributed to that commit. Trailing time after the
-// last commit becomes an unnamed segment (uncommitted work).
+// buildCheckoutSegments splits checkout sessions by commits and git events
+// (merges, rebases, cherry-picks, resets, pulls and branch renames) to produce
+// finer-grained time segments. Each commit or event creates a segment from the
+// previous boundary to its timestamp. Time is attributed backwards from the
+// commit — work before a commit is attributed to that commit. Trailing time
+// after the last commit becomes an unnamed segment (uncommitted work). After
+// a branch rename, the session continues under the new name.
 //
-// When no commits exist within a session, the entire session becomes one segment.
+// When no commits or events exist within a session, the entire session
+// becomes one segment.
+//
+// Every repository has its own timeline: a checkout only ends the previous
+// session of the same repository, and only that repository's commits and
+// events split its sessions. Segments of different repositories may therefore overlap;
+// see mergeRepoSegments.
 func buildCheckoutSegments(
 	checkouts []entry.CheckoutEntry,
 	commits []entry.CommitEntry,
-	year int, month time.Month, daysInMonth int,
+	events []entry.GitEventEntry,
+	from, to time.Time,
+	now time.Time,
+) []sessionSegment {
+	byRepo := make(map[string][]entry.CheckoutEntry)
+	for _, c := range checkouts {
+		byRepo[c.Repo] = append(byRepo[c.Repo], c)
+	}
+	repos := make([]string, 0, len(byRepo))
+	for repo := range byRepo {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	var segments []sessionSegment
+	for _, repo := range repos {
+		segments = append(segments, buildRepoSegments(repo, byRepo[repo], commits, events, from, to, now)...)
+	}
+	if len(repos) > 1 {
+		sort.SliceStable(segments, func(i, j int) bool { return segments[i].from.Before(segments[j].from) })
+	}
+	return segments
+}
+
+// buildRepoSegments builds the segments of the timeline of a single
+// repository from its checkouts.
+func buildRepoSegments(
+	repo string,
+	checkouts []entry.CheckoutEntry,
+	commits []entry.CommitEntry,
+	events []entry.GitEventEntry,
+	from, to time.Time,
 	now time.Time,
 ) []sessionSegment {
 	loc := now.Location()
@@ -167,14 +247,13 @@ func buildCheckoutSegments(
 		sorted = deduped
 	}
 
-	monthStart := time.Date(year, month, 1, 0, 0, 0, 0, loc)
-	monthEnd := time.Date(year, month, daysInMonth, 23, 59, 59, 0, loc)
+	rangeStart, rangeEnd := rangeBounds(from, to, loc)
 
 	// Build checkout ranges (same logic as buildCheckoutBucket)
 	var pairs []checkoutRange
 	lastBeforeIdx := -1
 	for i, c := range sorted {
-		if !c.Timestamp.After(monthStart) {
+		if !c.Timestamp.After(rangeStart) {
 			lastBeforeIdx = i
 		}
 	}
@@ -182,20 +261,22 @@ func buildCheckoutSegments(
 	if lastBeforeIdx >= 0 {
 		pairs = append(pairs, checkoutRange{
 			branch: cleanBranchName(sorted[lastBeforeIdx].Next),
-			from:   monthStart,
+			from:   rangeStart,
+			since:  sorted[lastBeforeIdx].Timestamp,
 		})
 	}
 
 	for _, c := range sorted {
-		if c.Timestamp.After(monthStart) && !c.Timestamp.After(monthEnd) {
+		if c.Timestamp.After(rangeStart) && !c.Timestamp.After(rangeEnd) {
 			pairs = append(pairs, checkoutRange{
 				branch: cleanBranchName(c.Next),
 				from:   c.Timestamp,
+				since:  c.Timestamp,
 			})
 		}
 	}
 
-	lastEnd := monthEnd.Add(time.Second)
+	lastEnd := rangeEnd.Add(time.Second)
 	if now.Before(lastEnd) {
 		lastEnd = now
 	}
@@ -210,63 +291,68 @@ func buildCheckoutSegments(
 		pairs[i].to = pairs[i].to.Truncate(time.Minute)
 	}
 
-	// Sort commits chronologically
-	sortedCommits := make([]entry.CommitEntry, len(commits))
-	copy(sortedCommits, commits)
-	sort.Slice(sortedCommits, func(i, j int) bool {
-		return sortedCommits[i].Timestamp.Before(sortedCommits[j].Timestamp)
+	// Sort commits and events chronologically
+	var splits []sessionSplit
+	for _, c := range commits {
+		if repo == "" || c.Repo == "" || c.Repo == repo {
+			splits = append(splits, sessionSplit{at: c.Timestamp, branch: cleanBranchName(c.Branch), message: c.Message})
+		}
+	}
+	for _, e := range events {
+		if repo == "" || e.Repo == "" || e.Repo == repo {
+			split := sessionSplit{at: e.Timestamp, branch: cleanBranchName(e.Branch), message: e.Message, event: e.Kind}
+			if e.Kind == entry.EventBranchRename {
+				split.branch, split.rename = cleanBranchName(e.Previous), cleanBranchName(e.Branch)
+			}
+			splits = append(splits, split)
+		}
+	}
+	sort.SliceStable(splits, func(i, j int) bool {
+		return splits[i].at.Before(splits[j].at)
 	})
 
-	// Split each checkout session by commits
+	// Split each checkout session by commits and events
 	var segments []sessionSegment
 	for _, p := range pairs {
 		if p.branch == "" {
 			continue
 		}
 
-		// Find commits within this session's time range on the same branch
-		var sessionCommits []entry.CommitEntry
-		for _, c := range sortedCommits {
-			if c.Timestamp.Before(p.from) || !c.Timestamp.Before(p.to) {
+		// Split by commits and events on the session's branch: time before
+		// each is attributed to it
+		branch := p.branch
+		boundary := p.from
+		for _, sp := range splits {
+			if sp.at.Before(p.from) || !sp.at.Before(p.to) || sp.branch != branch {
 				continue
 			}
-			if cleanBranchName(c.Branch) == p.branch {
-				sessionCommits = append(sessionCommits, c)
-			}
-		}
-
-		if len(sessionCommits) == 0 {
-			// No commits — single segment for the whole session
-			segments = append(segments, sessionSegment{
-				branch: p.branch,
-				from:   p.from,
-				to:     p.to,
-			})
-			continue
-		}
-
-		// Split by commits: time before each commit attributed to that commit
-		boundary := p.from
-		for _, c := range sessionCommits {
-			commitTime := c.Timestamp.Truncate(time.Minute)
-			if commitTime.After(boundary) {
+			splitTime := sp.at.Truncate(time.Minute)
+			if splitTime.After(boundary) {
 				segments = append(segments, sessionSegment{
-					branch:  p.branch,
-					repo:    c.Repo,
+					branch:  branch,
+					repo:    repo,
 					from:    boundary,
-					to:      commitTime,
-					message: c.Message,
+					to:      splitTime,
+					since:   p.since,
+					message: sp.message,
+					event:   sp.event,
 				})
 			}
-			boundary = commitTime
+			boundary = splitTime
+			if sp.rename != "" {
+				branch = sp.rename
+			}
 		}
 
-		// Trailing time after last commit = uncommitted work
+		// Trailing time after the last commit = uncommitted work, or the
+		// whole session without commits
 		if boundary.Before(p.to) {
 			segments = append(segments, sessionSegment{
-				branch: p.branch,
+				branch: branch,
+				repo:   repo,
 				from:   boundary,
 				to:     p.to,
+				since:  p.since,
 			})
 		}
 	}
@@ -274,52 +360,66 @@ func buildCheckoutSegments(
 	return segments
 }
 
-// buildSegmentBucket aggregates segments into per-branch, per-day minutes
+// sessionSplit is a commit or git event that splits a checkout session.
+type sessionSplit struct {
+	at      time.Time
+	branch  string // branch it was made on; the old name for renames
+	message string
+	event   string // git event kind, empty for commits
+	rename  string // new branch name for renames
+}
+
+// buildSegmentBucket aggregates segments into per-branch, per-date minutes
 // clipped to schedule windows. This replaces buildCheckoutBucket when commits
 // are available.
 func buildSegmentBucket(
 	segments []sessionSegment,
-	year int, month time.Month, daysInMonth int,
-	scheduleWindows map[int][]schedule.TimeWindow,
+	dates []time.Time,
+	scheduleWindows map[time.Time][]schedule.TimeWindow,
 	loc *time.Location,
-) map[string]map[int]int {
-	bucket := make(map[string]map[int]int)
+) map[string]map[time.Time]int {
+	bucket := make(map[string]map[time.Time]int)
 	for _, seg := range segments {
 		if seg.branch == "" {
 			continue
 		}
-		if bucket[seg.branch] == nil {
-			bucket[seg.branch] = make(map[int]int)
+		key := seg.taskKey()
+		if bucket[key] == nil {
+			bucket[key] = make(map[time.Time]int)
 		}
-		for day := 1; day <= daysInMonth; day++ {
+		for _, day := range dates {
 			windows, ok := scheduleWindows[day]
 			if !ok {
 				continue
 			}
-			mins := overlapMinutes(seg.from, seg.to, year, month, day, windows, loc)
+			mins := overlapMinutes(seg.from, seg.to, day, windows, loc)
 			if mins > 0 {
-				bucket[seg.branch][day] += mins
+				bucket[key][day] += mins
 			}
 		}
 	}
 	return bucket
 }
 
-// segmentCellEntry represents a segment's contribution to a specific (branch, day) cell.
+// segmentCellEntry represents a segment's contribution to a specific (task, day) cell.
 type segmentCellEntry struct {
-	branch  string
-	day     int
-	minutes int
-	message string
-	start   time.Time
+	branch   string
+	task     string
+	category string
+	repo     string
+	day      time.Time
+	minutes  int
+	message  string
+	event    string
+	start    time.Time
 }
 
 // buildSegmentCellEntries converts segments into per-day cell entries clipped
 // to schedule windows, preserving commit messages for individual entries.
 func buildSegmentCellEntries(
 	segments []sessionSegment,
-	year int, month time.Month, daysInMonth int,
-	scheduleWindows map[int][]schedule.TimeWindow,
+	dates []time.Time,
+	scheduleWindows map[time.Time][]schedule.TimeWindow,
 	loc *time.Location,
 ) []segmentCellEntry {
 	var entries []segmentCellEntry
@@ -327,19 +427,23 @@ func buildSegmentCellEntries(
 		if seg.branch == "" {
 			continue
 		}
-		for day := 1; day <= daysInMonth; day++ {
+		for _, day := range dates {
 			windows, ok := scheduleWindows[day]
 			if !ok {
 				continue
 			}
-			mins := overlapMinutes(seg.from, seg.to, year, month, day, windows, loc)
+			mins := overlapMinutes(seg.from, seg.to, day, windows, loc)
 			if mins > 0 {
 				entries = append(entries, segmentCellEntry{
-					branch:  seg.branch,
-					day:     day,
-					minutes: mins,
-					message: seg.message,
-					start:   seg.from,
+					branch:   seg.branch,
+					task:     seg.taskKey(),
+					category: seg.category,
+					repo:     seg.repo,
+					day:      day,
+					minutes:  mins,
+					message:  seg.message,
+					event:    seg.event,
+					start:    seg.from.In(loc),
 				})
 			}
 		}
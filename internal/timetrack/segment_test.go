@@ -11,14 +11,13 @@ import (
 
 func TestBuildCheckoutSegments_NoCommits(t *testing.T) {
 	year, month := 2025, time.January
-	daysInMonth := 31
 
 	checkouts := []entry.CheckoutEntry{
 		{ID: "c1", Timestamp: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "feature-a"},
 		{ID: "c2", Timestamp: time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC), Previous: "feature-a", Next: "feature-b"},
 	}
 
-	segments := buildCheckoutSegments(checkouts, nil, year, month, daysInMonth, afterMonth(year, month))
+	segments := buildCheckoutSegments(checkouts, nil, nil, firstDay(year, month), lastDay(year, month), afterMonth(year, month))
 
 	assert.Equal(t, 2, len(segments))
 
@@ -34,7 +33,6 @@ func TestBuildCheckoutSegments_NoCommits(t *testing.T) {
 
 func TestBuildCheckoutSegments_WithCommits(t *testing.T) {
 	year, month := 2025, time.January
-	daysInMonth := 31
 
 	checkouts := []entry.CheckoutEntry{
 		{ID: "c1", Timestamp: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "feature-a"},
@@ -46,7 +44,7 @@ func TestBuildCheckoutSegments_WithCommits(t *testing.T) {
 		{ID: "cm2", Timestamp: time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC), Branch: "feature-a", Message: "feat: second commit"},
 	}
 
-	segments := buildCheckoutSegments(checkouts, commits, year, month, daysInMonth, afterMonth(year, month))
+	segments := buildCheckoutSegments(checkouts, commits, nil, firstDay(year, month), lastDay(year, month), afterMonth(year, month))
 
 	// feature-a session (9:00-15:00) should be split into 3 segments:
 	// 9:00-11:00 (first commit), 11:00-13:00 (second commit), 13:00-15:00 (trailing)
@@ -70,7 +68,6 @@ func TestBuildCheckoutSegments_WithCommits(t *testing.T) {
 
 func TestBuildCheckoutSegments_TrailingUncommittedWork(t *testing.T) {
 	year, month := 2025, time.January
-	daysInMonth := 31
 
 	// Single checkout, no subsequent checkout to end the session
 	checkouts := []entry.CheckoutEntry{
@@ -82,7 +79,7 @@ func TestBuildCheckoutSegments_TrailingUncommittedWork(t *testing.T) {
 	}
 
 	now := time.Date(2025, 1, 2, 16, 0, 0, 0, time.UTC)
-	segments := buildCheckoutSegments(checkouts, commits, year, month, daysInMonth, now)
+	segments := buildCheckoutSegments(checkouts, commits, nil, firstDay(year, month), lastDay(year, month), now)
 
 	assert.Equal(t, 2, len(segments))
 
@@ -101,7 +98,6 @@ func TestBuildCheckoutSegments_TrailingUncommittedWork(t *testing.T) {
 
 func TestBuildCheckoutSegments_CommitsOnDifferentBranch(t *testing.T) {
 	year, month := 2025, time.January
-	daysInMonth := 31
 
 	checkouts := []entry.CheckoutEntry{
 		{ID: "c1", Timestamp: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "feature-a"},
@@ -114,7 +110,7 @@ func TestBuildCheckoutSegments_CommitsOnDifferentBranch(t *testing.T) {
 		{ID: "cm2", Timestamp: time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC), Branch: "feature-b", Message: "feat: also wrong"},
 	}
 
-	segments := buildCheckoutSegments(checkouts, commits, year, month, daysInMonth, afterMonth(year, month))
+	segments := buildCheckoutSegments(checkouts, commits, nil, firstDay(year, month), lastDay(year, month), afterMonth(year, month))
 
 	// feature-a should remain as a single unsplit segment
 	featureSegments := filterSegments(segments, "feature-a")
@@ -124,6 +120,44 @@ func TestBuildCheckoutSegments_CommitsOnDifferentBranch(t *testing.T) {
 	assert.Equal(t, time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC), featureSegments[0].to)
 }
 
+func TestBuildCheckoutSegments_GitEventsSplitSession(t *testing.T) {
+	year, month := 2025, time.January
+
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "feature-a"},
+		{ID: "c2", Timestamp: time.Date(2025, 1, 2, 17, 0, 0, 0, time.UTC), Previous: "feature-b", Next: "main"},
+	}
+
+	commits := []entry.CommitEntry{
+		{ID: "cm1", Timestamp: time.Date(2025, 1, 2, 14, 0, 0, 0, time.UTC), Branch: "feature-b", Message: "feat: after the rename"},
+	}
+
+	events := []entry.GitEventEntry{
+		{ID: "e1", Timestamp: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Kind: entry.EventMerge, Branch: "feature-a", Message: "merge main: Fast-forward"},
+		{ID: "e2", Timestamp: time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC), Kind: entry.EventBranchRename, Branch: "feature-b", Previous: "feature-a", Message: "Branch: renamed refs/heads/feature-a to refs/heads/feature-b"},
+		{ID: "e3", Timestamp: time.Date(2025, 1, 2, 11, 0, 0, 0, time.UTC), Kind: entry.EventReset, Branch: "other", Message: "reset: moving to HEAD~1"},
+	}
+
+	segments := buildCheckoutSegments(checkouts, commits, events, firstDay(year, month), lastDay(year, month), afterMonth(year, month))
+
+	// 9-10 until the merge, 10-12 until the rename, then 12-14 and the
+	// trailing 14-17 under the new name; the reset on another branch is ignored
+	before := filterSegments(segments, "feature-a")
+	assert.Equal(t, 2, len(before))
+	assert.Equal(t, entry.EventMerge, before[0].event)
+	assert.Equal(t, "merge main: Fast-forward", before[0].message)
+	assert.Equal(t, time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), before[0].to)
+	assert.Equal(t, entry.EventBranchRename, before[1].event)
+	assert.Equal(t, time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC), before[1].to)
+
+	after := filterSegments(segments, "feature-b")
+	assert.Equal(t, 2, len(after))
+	assert.Equal(t, "feat: after the rename", after[0].message)
+	assert.Equal(t, "", after[0].event)
+	assert.Equal(t, time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC), after[0].from)
+	assert.Equal(t, time.Date(2025, 1, 2, 17, 0, 0, 0, time.UTC), after[1].to)
+}
+
 func TestBuildDetailedReport_WithCommitsSplitsSession(t *testing.T) {
 	year, month := 2025, time.January
 	from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
@@ -140,13 +174,13 @@ func TestBuildDetailedReport_WithCommitsSplitsSession(t *testing.T) {
 		{ID: "cm2", Timestamp: time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC), Branch: "feature-a", Message: "feat: second"},
 	}
 
-	report := BuildDetailedReport(checkouts, nil, commits, days, from, to, afterMonth(year, month))
+	report := BuildDetailedReport(checkouts, nil, commits, nil, days, from, to, afterMonth(year, month), Options{})
 
 	assert.Equal(t, 1, len(report.Rows))
 	row := findDetailedRow(report, "feature-a")
 	assert.NotNil(t, row)
 
-	cd := row.Days[2]
+	cd := row.Days[date(year, month, 2)]
 	assert.NotNil(t, cd)
 
 	// 3 entries: 2 commit segments + 1 trailing uncommitted segment
@@ -172,36 +206,6 @@ func TestBuildDetailedReport_WithCommitsSplitsSession(t *testing.T) {
 	}
 }
 
-func TestBuildReport_WithCommitsSameTotal(t *testing.T) {
-	year, month := 2025, time.January
-
-	days := []schedule.DaySchedule{workday(year, month, 2)} // 9-17 = 480 min
-
-	checkouts := []entry.CheckoutEntry{
-		{ID: "c1", Timestamp: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "feature-a"},
-	}
-
-	commits := []entry.CommitEntry{
-		{ID: "cm1", Timestamp: time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC), Branch: "feature-a", Message: "fix: first"},
-		{ID: "cm2", Timestamp: time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC), Branch: "feature-a", Message: "feat: second"},
-	}
-
-	now := afterMonth(year, month)
-
-	// With commits
-	reportWithCommits := BuildReport(checkouts, nil, commits, days, year, month, now, nil)
-	// Without commits
-	reportNoCommits := BuildReport(checkouts, nil, nil, days, year, month, now, nil)
-
-	assert.Equal(t, 1, len(reportWithCommits.Rows))
-	assert.Equal(t, 1, len(reportNoCommits.Rows))
-
-	// Total time should be the same regardless of commit splitting
-	assert.Equal(t, reportNoCommits.Rows[0].TotalMinutes, reportWithCommits.Rows[0].TotalMinutes)
-	assert.Equal(t, 480, reportWithCommits.Rows[0].TotalMinutes)
-	assert.Equal(t, 480, reportWithCommits.Rows[0].Days[2])
-}
-
 // filterSegments returns only segments matching the given branch.
 func filterSegments(segments []sessionSegment, branch string) []sessionSegment {
 	var result []sessionSegment
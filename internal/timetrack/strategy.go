@@ -0,0 +1,98 @@
+package timetrack
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+)
+
+// Strategy decides which stretches of time count as work on which branch.
+// Its segments are then trimmed, merged and clipped to the schedule like any
+// other; see buildAttributedSegments. A nil Strategy attributes checkout
+// sessions.
+type Strategy interface {
+	segments(checkouts []entry.CheckoutEntry, commits []entry.CommitEntry, events []entry.GitEventEntry, from, to time.Time, now time.Time) []sessionSegment
+}
+
+// CheckoutSessions attributes all the time a branch is checked out to it,
+// until the next checkout, split by its commits and git events.
+type CheckoutSessions struct{}
+
+func (CheckoutSessions) segments(checkouts []entry.CheckoutEntry, commits []entry.CommitEntry, events []entry.GitEventEntry, from, to time.Time, now time.Time) []sessionSegment {
+	return buildCheckoutSegments(checkouts, commits, events, from, to, now)
+}
+
+// CommitSessions estimates work sessions from commit timestamps alone, in the
+// style of git-hours: commits at most MaxGap apart belong to one session,
+// which starts LeadIn before its first commit. The time up to each commit is
+// attributed to the commit's branch, so a branch that stays checked out
+// without commits counts for nothing. Git events are not work of their own
+// and are ignored.
+type CommitSessions struct {
+	MaxGap time.Duration
+	LeadIn time.Duration
+}
+
+func (s CommitSessions) segments(_ []entry.CheckoutEntry, commits []entry.CommitEntry, _ []entry.GitEventEntry, from, to time.Time, now time.Time) []sessionSegment {
+	rangeStart, rangeEnd := rangeBounds(from, to, now.Location())
+	if now.Before(rangeEnd) {
+		rangeEnd = now
+	}
+
+	byRepo := make(map[string][]entry.CommitEntry)
+	for _, c := range commits {
+		byRepo[c.Repo] = append(byRepo[c.Repo], c)
+	}
+	repos := make([]string, 0, len(byRepo))
+	for repo := range byRepo {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	var segments []sessionSegment
+	for _, repo := range repos {
+		sorted := byRepo[repo]
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+		var prev, since time.Time
+		for i, c := range sorted {
+			start := c.Timestamp.Add(-s.LeadIn)
+			if i > 0 && c.Timestamp.Sub(prev) <= s.MaxGap {
+				start = prev
+			} else {
+				since = start
+			}
+			prev = c.Timestamp
+
+			segFrom, segTo := laterOf(start, rangeStart), earlierOf(c.Timestamp, rangeEnd)
+			if !segTo.After(segFrom) {
+				continue
+			}
+			segments = append(segments, sessionSegment{
+				branch:  cleanBranchName(c.Branch),
+				repo:    repo,
+				from:    segFrom,
+				to:      segTo,
+				since:   since,
+				message: c.Message,
+			})
+		}
+	}
+	if len(repos) > 1 {
+		sort.SliceStable(segments, func(i, j int) bool { return segments[i].from.Before(segments[j].from) })
+	}
+	return segments
+}
+
+// NewStrategy returns the strategy a project's attribution setting selects.
+func NewStrategy(a project.Attribution) Strategy {
+	if a.Strategy == project.AttributionCommits {
+		return CommitSessions{
+			MaxGap: time.Duration(a.MaxGap) * time.Minute,
+			LeadIn: time.Duration(a.LeadIn) * time.Minute,
+		}
+	}
+	return CheckoutSessions{}
+}
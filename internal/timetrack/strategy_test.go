@@ -0,0 +1,102 @@
+package timetrack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var gitHours = CommitSessions{MaxGap: 2 * time.Hour, LeadIn: 30 * time.Minute}
+
+func TestCommitSessions_Segments(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 1, 2, h, m, 0, 0, time.UTC) }
+	commits := []entry.CommitEntry{
+		{ID: "m3", Timestamp: at(11, 30), Branch: "feature", Message: "third"},
+		{ID: "m1", Timestamp: at(10, 0), Branch: "feature", Message: "first"},
+		{ID: "m2", Timestamp: at(11, 0), Branch: "feature", Message: "second"},
+		{ID: "m4", Timestamp: at(12, 0), Branch: "fix", Message: "fix"},
+		{ID: "m5", Timestamp: at(15, 0), Branch: "feature", Message: "later"},
+	}
+	day := date(2025, time.January, 2)
+
+	segments := gitHours.segments(nil, commits, nil, day, day, at(18, 0))
+
+	require.Len(t, segments, 5)
+	assert.Equal(t, sessionSegment{branch: "feature", from: at(9, 30), to: at(10, 0), since: at(9, 30), message: "first"}, segments[0])
+	assert.Equal(t, at(10, 0), segments[1].from, "a commit within the gap continues the session")
+	assert.Equal(t, "fix", segments[3].branch, "time goes to the branch of the commit")
+	assert.Equal(t, at(9, 30), segments[3].since)
+	assert.Equal(t, sessionSegment{branch: "feature", from: at(14, 30), to: at(15, 0), since: at(14, 30), message: "later"}, segments[4],
+		"a commit after a longer gap starts a new session")
+}
+
+func TestCommitSessions_ClippedToRange(t *testing.T) {
+	commits := []entry.CommitEntry{
+		{ID: "m1", Timestamp: time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC), Branch: "feature"},
+		{ID: "m2", Timestamp: time.Date(2025, 1, 2, 0, 30, 0, 0, time.UTC), Branch: "feature"},
+	}
+	day := date(2025, time.January, 2)
+
+	segments := gitHours.segments(nil, commits, nil, day, day, time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC))
+
+	require.Len(t, segments, 1)
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), segments[0].from)
+	assert.Equal(t, time.Date(2025, 1, 1, 22, 30, 0, 0, time.UTC), segments[0].since)
+}
+
+func TestBuildDetailedReport_CommitSessionsIdleBranch(t *testing.T) {
+	// main stays checked out over a week without a commit
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 1, 3, 17, 0, 0, 0, time.UTC), Previous: "feature", Next: "main"},
+	}
+	var days []schedule.DaySchedule
+	for d := 6; d <= 10; d++ {
+		days = append(days, workday(2025, time.January, d))
+	}
+	from, to := date(2025, time.January, 6), date(2025, time.January, 10)
+	now := time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)
+
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, from, to, now, Options{})
+	total, _ := report.Total()
+	assert.Equal(t, 5*480, total, "checkout sessions count the whole week")
+
+	report = BuildDetailedReport(checkouts, nil, nil, nil, days, from, to, now, Options{Strategy: gitHours})
+	total, _ = report.Total()
+	assert.Equal(t, 0, total, "commit sessions count nothing without commits")
+}
+
+func TestBuildDetailedReport_CommitSessions(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 1, 2, h, m, 0, 0, time.UTC) }
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: at(8, 0), Previous: "main", Next: "feature"},
+	}
+	commits := []entry.CommitEntry{
+		{ID: "m1", Timestamp: at(9, 15), Branch: "feature", Message: "early"},
+		{ID: "m2", Timestamp: at(10, 0), Branch: "feature", Message: "login"},
+		{ID: "m3", Timestamp: at(16, 0), Branch: "feature", Message: "logout"},
+	}
+	days := []schedule.DaySchedule{workday(2025, time.January, 2)}
+	day := date(2025, time.January, 2)
+
+	report := BuildDetailedReport(checkouts, nil, commits, nil, days, day, day, at(18, 0), Options{Strategy: gitHours})
+
+	row := findDetailedRow(report, "feature")
+	require.NotNil(t, row)
+	assert.Equal(t, 15+45+30, row.TotalMinutes, "lead-in before 9am falls outside the schedule")
+
+	budget := ComputeDayBudget(checkouts, nil, commits, nil, days, day, at(18, 0), Options{Strategy: gitHours})
+	assert.Equal(t, 90, budget.LoggedMinutes)
+
+	data := BuildExportData(checkouts, nil, commits, nil, days, day, day, at(18, 0), nil, "Test Project", "", Options{Strategy: gitHours})
+	assert.Equal(t, 90, data.TotalMinutes)
+}
+
+func TestNewStrategy(t *testing.T) {
+	assert.Equal(t, CheckoutSessions{}, NewStrategy(project.Attribution{}))
+	assert.Equal(t, gitHours, NewStrategy(project.Attribution{Strategy: project.AttributionCommits, MaxGap: 120, LeadIn: 30}))
+}
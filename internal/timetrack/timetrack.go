@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/project"
+	"github.com/Flyrell/hourgit/internal/rounding"
 	"github.com/Flyrell/hourgit/internal/schedule"
 )
 
@@ -16,21 +18,6 @@ func cleanBranchName(name string) string {
 	return strings.TrimPrefix(name, "remotes/")
 }
 
-// TaskRow holds aggregated time data for a single task (branch or manual log).
-type TaskRow struct {
-	Name         string
-	TotalMinutes int
-	Days         map[int]int // day-of-month -> minutes
-}
-
-// ReportData holds the complete report for a given month.
-type ReportData struct {
-	Year        int
-	Month       time.Month
-	DaysInMonth int
-	Rows        []TaskRow
-}
-
 // CellEntry represents a single entry within a report cell.
 type CellEntry struct {
 	ID        string
@@ -38,332 +25,226 @@ type CellEntry struct {
 	Minutes   int
 	Message   string
 	Task      string
+	Category  string // e.g. development or review, empty when uncategorized
 	Source    string
+	Repo      string       // repository of checkout time, empty for logs
 	Persisted bool         // false = in-memory generated, true = saved to disk
 	Entry     *entry.Entry // pointer to original entry (nil for in-memory generated)
 }
 
-// CellData holds all entries for one (task, day) cell.
+// CellData holds all entries for one (task, day) cell. Entry minutes are
+// raw; TotalMinutes is rounded per the report's policy.
 type CellData struct {
 	Entries      []CellEntry
 	TotalMinutes int
+	RawMinutes   int // total before rounding
 }
 
 // DetailedTaskRow holds entry-level time data for a single task.
 type DetailedTaskRow struct {
 	Name         string
+	Category     string // the category of most of the row's time, empty when uncategorized
 	TotalMinutes int
-	Days         map[int]*CellData
+	RawMinutes   int // total before rounding
+	Days         map[time.Time]*CellData
 }
 
 // DetailedReportData holds the complete entry-level report for a date range.
 type DetailedReportData struct {
-	Year          int
-	Month         time.Month
-	DaysInMonth   int
 	From          time.Time
 	To            time.Time
+	Dates         []time.Time // every date from From to To
 	Rows          []DetailedTaskRow
-	ScheduledDays map[int]bool // day-of-month -> true if day has scheduled working hours
-}
-
-// ActivityEntries holds optional activity entries for precise mode idle trimming.
-type ActivityEntries struct {
-	Stops  []entry.ActivityStopEntry
-	Starts []entry.ActivityStartEntry
-}
-
-// BuildReport computes a monthly time report from checkout entries, manual log
-// entries, and expanded day schedules. Time is attributed to branches based on
-// checkout ranges clipped to schedule windows. Days listed in generatedDays
-// (format "2006-01-02") are excluded from checkout attribution — they have
-// already been materialized as editable log entries by the generate command.
-func BuildReport(
-	checkouts []entry.CheckoutEntry,
-	logs []entry.Entry,
-	commits []entry.CommitEntry,
-	daySchedules []schedule.DaySchedule,
-	year int, month time.Month,
-	now time.Time,
-	generatedDays []string,
-	activity ...ActivityEntries,
-) ReportData {
-	daysInMonth := daysIn(year, month)
-
-	generatedSet := make(map[int]bool, len(generatedDays))
-	for _, ds := range generatedDays {
-		t, err := time.Parse("2006-01-02", ds)
-		if err != nil {
-			continue
-		}
-		if t.Year() == year && t.Month() == month {
-			generatedSet[t.Day()] = true
-		}
-	}
-
-	scheduleWindows, _ := buildScheduleLookup(daySchedules, year, month)
-	logBucket, _ := buildLogBucket(logs, year, month)
-
-	loc := now.Location()
-	segments := buildCheckoutSegments(checkouts, commits, year, month, daysInMonth, now)
-	// Trim idle gaps if activity entries provided
-	if len(activity) > 0 && (len(activity[0].Stops) > 0 || len(activity[0].Starts) > 0) {
-		segments = trimSegmentsByIdleGaps(segments, activity[0].Stops, activity[0].Starts)
-	}
-	// Trim manual log time ranges from checkout segments
-	segments = deductLogOverlaps(segments, logs, year, month, loc)
-	checkoutBucket := buildSegmentBucket(segments, year, month, daysInMonth, scheduleWindows, loc)
-
-	// Zero out checkout attribution for generated days
-	for day := range generatedSet {
-		for branch := range checkoutBucket {
-			delete(checkoutBucket[branch], day)
-		}
-	}
-	rows := mergeAndSortRows(checkoutBucket, logBucket)
-
-	return ReportData{
-		Year:        year,
-		Month:       month,
-		DaysInMonth: daysInMonth,
-		Rows:        rows,
-	}
+	ScheduledDays map[time.Time]bool // date -> true if day has scheduled working hours
+	Rounding      rounding.Policy    // applied to cell and day totals
+	Overtime      map[time.Time]int  // date -> minutes outside schedule windows (separate overtime policy)
+	Balance       *Balance           // flextime balance up to now, nil when the project has none
+	ByRepo        bool               // show the per-repository breakdown (RepoBreakdown) below the totals
 }
 
-// BuildCheckoutAttribution computes raw checkout time per branch per day
-// (before schedule deduction). Used by the generate command to materialize
-// checkout time into editable log entries.
-func BuildCheckoutAttribution(
-	checkouts []entry.CheckoutEntry,
-	daySchedules []schedule.DaySchedule,
-	year int, month time.Month,
-	now time.Time,
-) map[string]map[int]int {
-	daysInMonth := daysIn(year, month)
-	scheduleWindows, _ := buildScheduleLookup(daySchedules, year, month)
-	return buildCheckoutBucket(checkouts, year, month, daysInMonth, scheduleWindows, now)
+// RepoRow holds the unrounded minutes attributed to one repository.
+type RepoRow struct {
+	Repo         string // repository path, empty for logged time
+	TotalMinutes int
+	Days         map[time.Time]int // date -> minutes
 }
 
-// buildScheduleLookup builds day -> windows and day -> total scheduled minutes maps.
-func buildScheduleLookup(daySchedules []schedule.DaySchedule, year int, month time.Month) (map[int][]schedule.TimeWindow, map[int]int) {
-	scheduleWindows := make(map[int][]schedule.TimeWindow)
-	scheduledMins := make(map[int]int)
-	for _, ds := range daySchedules {
-		y, m, d := ds.Date.Date()
-		if y == year && m == month {
-			scheduleWindows[d] = ds.Windows
-			total := 0
-			for _, w := range ds.Windows {
-				total += windowMinutes(w)
+// Recount recomputes the cell and row totals from the entries, rounding them
+// per the report's policy. Call it after changing entries.
+func (d *DetailedReportData) Recount() {
+	for i := range d.Rows {
+		row := &d.Rows[i]
+		row.TotalMinutes, row.RawMinutes = 0, 0
+		categories := make(map[string]int)
+		for _, cd := range row.Days {
+			raw, total := 0, 0
+			for _, ce := range cd.Entries {
+				categories[ce.Category] += ce.Minutes
+				raw += ce.Minutes
+				total += d.Rounding.Entry(ce.Minutes)
 			}
-			scheduledMins[d] = total
+			cd.RawMinutes = raw
+			cd.TotalMinutes = d.Rounding.TaskDay(total)
+			row.TotalMinutes += cd.TotalMinutes
+			row.RawMinutes += raw
 		}
+		row.Category = dominantCategory(categories)
 	}
-	return scheduleWindows, scheduledMins
 }
 
-// buildLogBucket buckets manual log entries by (taskKey, day) and totals log minutes per day.
-func buildLogBucket(logs []entry.Entry, year int, month time.Month) (map[string]map[int]int, map[int]int) {
-	logBucket := make(map[string]map[int]int)
-	logMinsByDay := make(map[int]int)
-	for _, l := range logs {
-		if l.Start.Year() != year || l.Start.Month() != month {
+// dominantCategory returns the non-empty category with the most minutes,
+// the alphabetically first on a tie.
+func dominantCategory(minutes map[string]int) string {
+	best := ""
+	for category, mins := range minutes {
+		if category == "" || mins <= 0 {
 			continue
 		}
-		key := logTaskKey(l)
-		day := l.Start.Day()
-		if logBucket[key] == nil {
-			logBucket[key] = make(map[int]int)
+		if best == "" || mins > minutes[best] || (mins == minutes[best] && category < best) {
+			best = category
 		}
-		logBucket[key][day] += l.Minutes
-		logMinsByDay[day] += l.Minutes
 	}
-	return logBucket, logMinsByDay
+	return best
 }
 
-// buildCheckoutBucket computes per-branch, per-day minutes from checkout entries
-// clipped to schedule windows. Schedule window times are interpreted in the
-// timezone of `now` (the user's local timezone).
-func buildCheckoutBucket(
-	checkouts []entry.CheckoutEntry,
-	year int, month time.Month, daysInMonth int,
-	scheduleWindows map[int][]schedule.TimeWindow,
-	now time.Time,
-) map[string]map[int]int {
-	loc := now.Location()
-	sorted := make([]entry.CheckoutEntry, len(checkouts))
-	copy(sorted, checkouts)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
-	})
-
-	// Deduplicate: skip consecutive checkouts to the same branch
-	if len(sorted) > 0 {
-		deduped := []entry.CheckoutEntry{sorted[0]}
-		for i := 1; i < len(sorted); i++ {
-			if cleanBranchName(sorted[i].Next) != cleanBranchName(sorted[i-1].Next) {
-				deduped = append(deduped, sorted[i])
-			}
-		}
-		sorted = deduped
-	}
-
-	monthStart := time.Date(year, month, 1, 0, 0, 0, 0, loc)
-	monthEnd := time.Date(year, month, daysInMonth, 23, 59, 59, 0, loc)
-
-	var pairs []checkoutRange
-	lastBeforeIdx := -1
-	for i, c := range sorted {
-		if !c.Timestamp.After(monthStart) {
-			lastBeforeIdx = i
-		}
-	}
-
-	if lastBeforeIdx >= 0 {
-		pairs = append(pairs, checkoutRange{
-			branch: cleanBranchName(sorted[lastBeforeIdx].Next),
-			from:   monthStart,
-		})
-	}
-
-	for _, c := range sorted {
-		if c.Timestamp.After(monthStart) && !c.Timestamp.After(monthEnd) {
-			pairs = append(pairs, checkoutRange{
-				branch: cleanBranchName(c.Next),
-				from:   c.Timestamp,
-			})
+// DayTotal returns the rounded and raw total of a day across all rows.
+func (d DetailedReportData) DayTotal(day time.Time) (total, raw int) {
+	for _, row := range d.Rows {
+		if cd := row.Days[day]; cd != nil {
+			total += cd.TotalMinutes
+			raw += cd.RawMinutes
 		}
 	}
+	return d.Rounding.Day(total), raw
+}
 
-	lastEnd := monthEnd.Add(time.Second)
-	if now.Before(lastEnd) {
-		lastEnd = now
-	}
-	lastEnd = lastEnd.Truncate(time.Minute)
-	for i := range pairs {
-		if i+1 < len(pairs) {
-			pairs[i].to = pairs[i+1].from
-		} else {
-			pairs[i].to = lastEnd
-		}
-		pairs[i].from = pairs[i].from.Truncate(time.Minute)
-		pairs[i].to = pairs[i].to.Truncate(time.Minute)
+// Total returns the rounded and raw total of the whole report — the sum of
+// its day totals.
+func (d DetailedReportData) Total() (total, raw int) {
+	for _, day := range d.Dates {
+		t, r := d.DayTotal(day)
+		total += t
+		raw += r
 	}
+	return total, raw
+}
 
-	checkoutBucket := make(map[string]map[int]int)
-	for _, p := range pairs {
-		if p.branch == "" {
-			continue
-		}
-		if checkoutBucket[p.branch] == nil {
-			checkoutBucket[p.branch] = make(map[int]int)
-		}
-		for day := 1; day <= daysInMonth; day++ {
-			windows, ok := scheduleWindows[day]
-			if !ok {
-				continue
-			}
-			mins := overlapMinutes(p.from, p.to, year, month, day, windows, loc)
-			if mins > 0 {
-				checkoutBucket[p.branch][day] += mins
-			}
-		}
+// OvertimeTotal returns the overtime of the whole report.
+func (d DetailedReportData) OvertimeTotal() int {
+	total := 0
+	for _, day := range d.Dates {
+		total += d.Overtime[day]
 	}
-
-	return checkoutBucket
+	return total
 }
 
-// mergeAndSortRows merges checkout and log buckets into sorted TaskRows.
-func mergeAndSortRows(checkoutBucket map[string]map[int]int, logBucket map[string]map[int]int) []TaskRow {
-	rowMap := make(map[string]*TaskRow)
-	for branch, dayMap := range checkoutBucket {
-		row := &TaskRow{Name: branch, Days: make(map[int]int)}
-		for day, mins := range dayMap {
-			if mins > 0 {
-				row.Days[day] = mins
-				row.TotalMinutes += mins
+// RepoBreakdown returns the report's unrounded minutes per repository, most
+// time first. Checkout time counts for the repository it was tracked in; logs
+// carry no repository and are grouped under the empty name.
+func (d DetailedReportData) RepoBreakdown() []RepoRow {
+	rowMap := make(map[string]*RepoRow)
+	for _, row := range d.Rows {
+		for day, cd := range row.Days {
+			for _, ce := range cd.Entries {
+				r := rowMap[ce.Repo]
+				if r == nil {
+					r = &RepoRow{Repo: ce.Repo, Days: make(map[time.Time]int)}
+					rowMap[ce.Repo] = r
+				}
+				r.Days[day] += ce.Minutes
+				r.TotalMinutes += ce.Minutes
 			}
 		}
-		if row.TotalMinutes > 0 {
-			rowMap[branch] = row
-		}
-	}
-	for key, dayMap := range logBucket {
-		row, ok := rowMap[key]
-		if !ok {
-			row = &TaskRow{Name: key, Days: make(map[int]int)}
-			rowMap[key] = row
-		}
-		for day, mins := range dayMap {
-			row.Days[day] += mins
-			row.TotalMinutes += mins
-		}
 	}
 
-	rows := make([]TaskRow, 0, len(rowMap))
-	for _, row := range rowMap {
-		rows = append(rows, *row)
+	rows := make([]RepoRow, 0, len(rowMap))
+	for _, r := range rowMap {
+		if r.TotalMinutes > 0 {
+			rows = append(rows, *r)
+		}
 	}
 	sort.Slice(rows, func(i, j int) bool {
 		if rows[i].TotalMinutes != rows[j].TotalMinutes {
 			return rows[i].TotalMinutes > rows[j].TotalMinutes
 		}
-		return rows[i].Name < rows[j].Name
+		return rows[i].Repo < rows[j].Repo
 	})
-
 	return rows
 }
 
-// BuildDetailedReport computes an entry-level report for a date range.
-// Unlike BuildReport which aggregates into minutes-per-day, this preserves
-// individual entries so the interactive table can show and edit them.
+// ActivityEntries holds optional activity entries for precise mode idle trimming.
+type ActivityEntries struct {
+	Stops  []entry.ActivityStopEntry
+	Starts []entry.ActivityStartEntry
+}
+
+// buildScheduleLookup builds date -> windows and date -> total scheduled minutes maps.
+func buildScheduleLookup(daySchedules []schedule.DaySchedule, from, to time.Time) (map[time.Time][]schedule.TimeWindow, map[time.Time]int) {
+	scheduleWindows := make(map[time.Time][]schedule.TimeWindow)
+	scheduledMins := make(map[time.Time]int)
+	for _, ds := range daySchedules {
+		day := DateOf(ds.Date)
+		if inRange(day, from, to) {
+			scheduleWindows[day] = ds.Windows
+			total := 0
+			for _, w := range ds.Windows {
+				total += w.Minutes()
+			}
+			scheduledMins[day] = total
+		}
+	}
+	return scheduleWindows, scheduledMins
+}
+
+// BuildDetailedReport computes an entry-level report for the dates from..to
+// (inclusive), keeping individual entries so the interactive table can show
+// and edit them.
 // Checkout time is split by commits into finer segments with commit messages.
 // Checkout time is generated in-memory (Persisted=false) unless a persisted
 // entry with source="checkout-generated" already covers that (branch, day).
+// Time is attributed and totalled per opts: totals are rounded, entries keep
+// their raw minutes.
 func BuildDetailedReport(
 	checkouts []entry.CheckoutEntry,
 	logs []entry.Entry,
 	commits []entry.CommitEntry,
+	events []entry.GitEventEntry,
 	daySchedules []schedule.DaySchedule,
 	from, to time.Time,
 	now time.Time,
+	opts Options,
 	activity ...ActivityEntries,
 ) DetailedReportData {
-	year := from.Year()
-	month := from.Month()
-	daysInMonth := daysIn(year, month)
+	from, to = DateOf(from), DateOf(to)
+	dates := Dates(from, to)
 
-	scheduleWindows, scheduledMins := buildScheduleLookup(daySchedules, year, month)
+	scheduleWindows, scheduledMins := buildScheduleLookup(daySchedules, from, to)
 
-	scheduledDays := make(map[int]bool, len(scheduledMins))
+	scheduledDays := make(map[time.Time]bool, len(scheduledMins))
 	for day := range scheduledMins {
 		scheduledDays[day] = true
 	}
 
 	// Build segments (checkout sessions split by commits)
-	segments := buildCheckoutSegments(checkouts, commits, year, month, daysInMonth, now)
-	// Trim idle gaps if activity entries provided
-	if len(activity) > 0 && (len(activity[0].Stops) > 0 || len(activity[0].Starts) > 0) {
-		segments = trimSegmentsByIdleGaps(segments, activity[0].Stops, activity[0].Starts)
-	}
 	loc := now.Location()
-	// Trim manual log time ranges from checkout segments
-	segments = deductLogOverlaps(segments, logs, year, month, loc)
+	end := segmentEnd(to, scheduleWindows)
+	segments := buildAttributedSegments(checkouts, commits, events, logs, from, end, now, scheduleWindows, opts, activity)
 
 	// Index persisted checkout-generated entries by (task, day) for deduplication
 	type taskDay struct {
 		task string
-		day  int
+		day  time.Time
 	}
 	persistedCheckoutEntries := make(map[taskDay][]entry.Entry)
 	for _, l := range logs {
 		if l.Source != "checkout-generated" {
 			continue
 		}
-		if l.Start.Year() != year || l.Start.Month() != month {
+		day := DateOf(l.Start.In(loc))
+		if !inRange(day, from, to) {
 			continue
 		}
-		key := taskDay{task: logTaskKey(l), day: l.Start.Day()}
+		key := taskDay{task: logTaskKey(l), day: day}
 		persistedCheckoutEntries[key] = append(persistedCheckoutEntries[key], l)
 	}
 
@@ -372,19 +253,15 @@ func BuildDetailedReport(
 
 	// 1. Add log entries (both manual and checkout-generated)
 	for i, l := range logs {
-		if l.Start.Year() != year || l.Start.Month() != month {
-			continue
-		}
-		day := l.Start.Day()
-		dayDate := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
-		if dayDate.Before(from) || dayDate.After(to) {
+		day := DateOf(l.Start.In(loc))
+		if !inRange(day, from, to) {
 			continue
 		}
 
 		key := logTaskKey(l)
 		row := rowMap[key]
 		if row == nil {
-			row = &DetailedTaskRow{Name: key, Days: make(map[int]*CellData)}
+			row = &DetailedTaskRow{Name: key, Days: make(map[time.Time]*CellData)}
 			rowMap[key] = row
 		}
 		cd := row.Days[day]
@@ -395,10 +272,11 @@ func BuildDetailedReport(
 
 		ce := CellEntry{
 			ID:        l.ID,
-			Start:     l.Start,
+			Start:     l.Start.In(loc),
 			Minutes:   l.Minutes,
 			Message:   l.Message,
 			Task:      l.Task,
+			Category:  l.Category,
 			Source:    l.Source,
 			Persisted: true,
 			Entry:     &logs[i],
@@ -409,17 +287,27 @@ func BuildDetailedReport(
 	}
 
 	// 2. Build segment cell entries for fine-grained in-memory entries
-	segEntries := buildSegmentCellEntries(segments, year, month, daysInMonth, scheduleWindows, loc)
+	segEntries := buildSegmentCellEntries(segments, dates, scheduleWindows, loc)
+
+	// Checkout time outside schedule windows: either regular work or a
+	// separate overtime category
+	overtimeWindows, _ := buildScheduleLookup(daySchedules, from.AddDate(0, 0, -1), to)
+	overtimeEntries := buildOvertimeCellEntries(overtimeSegments(segments, opts.Overtime, activity, now), dates, overtimeWindows, loc)
+	overtimeMins := make(map[time.Time]int)
+	if opts.Overtime == project.OvertimeActivity {
+		segEntries = append(segEntries, overtimeEntries...)
+	} else {
+		for _, oe := range overtimeEntries {
+			if _, exists := persistedCheckoutEntries[taskDay{task: oe.task, day: oe.day}]; !exists {
+				overtimeMins[oe.day] += oe.minutes
+			}
+		}
+	}
 
 	// Add segment entries as in-memory entries (already trimmed by log overlaps)
 	for _, se := range segEntries {
-		dayDate := time.Date(year, month, se.day, 0, 0, 0, 0, time.UTC)
-		if dayDate.Before(from) || dayDate.After(to) {
-			continue
-		}
-
-		// Skip if persisted checkout-generated entry exists for this (branch, day)
-		tdKey := taskDay{task: se.branch, day: se.day}
+		// Skip if persisted checkout-generated entry exists for this (task, day)
+		tdKey := taskDay{task: se.task, day: se.day}
 		if _, exists := persistedCheckoutEntries[tdKey]; exists {
 			continue
 		}
@@ -429,10 +317,10 @@ func BuildDetailedReport(
 			continue
 		}
 
-		row := rowMap[se.branch]
+		row := rowMap[se.task]
 		if row == nil {
-			row = &DetailedTaskRow{Name: se.branch, Days: make(map[int]*CellData)}
-			rowMap[se.branch] = row
+			row = &DetailedTaskRow{Name: se.task, Days: make(map[time.Time]*CellData)}
+			rowMap[se.task] = row
 		}
 		cd := row.Days[se.day]
 		if cd == nil {
@@ -450,8 +338,10 @@ func BuildDetailedReport(
 			Minutes:   mins,
 			Start:     se.start,
 			Message:   message,
-			Task:      cleanBranchName(se.branch),
+			Task:      cleanBranchName(se.task),
+			Category:  se.category,
 			Source:    "checkout",
+			Repo:      se.repo,
 			Persisted: false,
 			Entry:     nil,
 		}
@@ -460,13 +350,24 @@ func BuildDetailedReport(
 		row.TotalMinutes += mins
 	}
 
-	// Sort rows by total descending, then by name
 	rows := make([]DetailedTaskRow, 0, len(rowMap))
 	for _, row := range rowMap {
 		if row.TotalMinutes > 0 {
 			rows = append(rows, *row)
 		}
 	}
+	data := DetailedReportData{
+		From:          from,
+		To:            to,
+		Dates:         dates,
+		Rows:          rows,
+		ScheduledDays: scheduledDays,
+		Rounding:      opts.Rounding,
+		Overtime:      overtimeMins,
+	}
+	data.Recount()
+
+	// Sort rows by total descending, then by name
 	sort.Slice(rows, func(i, j int) bool {
 		if rows[i].TotalMinutes != rows[j].TotalMinutes {
 			return rows[i].TotalMinutes > rows[j].TotalMinutes
@@ -474,21 +375,14 @@ func BuildDetailedReport(
 		return rows[i].Name < rows[j].Name
 	})
 
-	return DetailedReportData{
-		Year:          year,
-		Month:         month,
-		DaysInMonth:   daysInMonth,
-		From:          from,
-		To:            to,
-		Rows:          rows,
-		ScheduledDays: scheduledDays,
-	}
+	return data
 }
 
 type checkoutRange struct {
 	branch string
 	from   time.Time
 	to     time.Time
+	since  time.Time // checkout time; before from when the session started before the range
 }
 
 // logTaskKey returns the grouping key for a manual log entry.
@@ -499,26 +393,61 @@ func logTaskKey(e entry.Entry) string {
 	return "(no task)"
 }
 
-// daysIn returns the number of days in the given month.
-func daysIn(year int, month time.Month) int {
-	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+// MonthRange returns the first and last date of the given month.
+func MonthRange(year int, month time.Month) (from, to time.Time) {
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+}
+
+// DateOf returns the calendar date of t (in t's location) as midnight UTC.
+// Reports key their days by this value; convert t into the report's time zone
+// first.
+func DateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Dates returns every date from from to to (inclusive), oldest first.
+func Dates(from, to time.Time) []time.Time {
+	var dates []time.Time
+	for d := DateOf(from); !d.After(DateOf(to)); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+// inRange reports whether the date day lies within from..to (inclusive).
+func inRange(day, from, to time.Time) bool {
+	return !day.Before(from) && !day.After(to)
 }
 
-// windowMinutes returns the duration of a schedule window in minutes.
-func windowMinutes(w schedule.TimeWindow) int {
-	from := w.From.Hour*60 + w.From.Minute
-	to := w.To.Hour*60 + w.To.Minute
-	return to - from
+// rangeBounds returns the first and last second of the dates from..to in loc.
+func rangeBounds(from, to time.Time, loc *time.Location) (start, end time.Time) {
+	start = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end = time.Date(to.Year(), to.Month(), to.Day(), 23, 59, 59, 0, loc)
+	return start, end
+}
+
+// segmentEnd returns the last date checkout segments are built for: the day
+// after to when a window on to runs past midnight, so the end of that shift
+// is counted too.
+func segmentEnd(to time.Time, scheduleWindows map[time.Time][]schedule.TimeWindow) time.Time {
+	for _, w := range scheduleWindows[to] {
+		if w.Overnight() {
+			return to.AddDate(0, 0, 1)
+		}
+	}
+	return to
 }
 
 // overlapMinutes computes how many minutes of the checkout range [from, to)
-// overlap with the given schedule windows on a specific day. Schedule window
-// times are interpreted in the given location (the user's local timezone).
-func overlapMinutes(from, to time.Time, year int, month time.Month, day int, windows []schedule.TimeWindow, loc *time.Location) int {
+// overlap with the given schedule windows on the date day. Schedule window
+// times are interpreted in the window's own time zone, or in loc (the
+// project's time zone) when it has none. Overnight windows end on the day
+// after day. Window bounds are wall-clock times, so a window spanning a DST
+// change is an hour shorter or longer.
+func overlapMinutes(from, to time.Time, day time.Time, windows []schedule.TimeWindow, loc *time.Location) int {
 	total := 0
 	for _, w := range windows {
-		wStart := time.Date(year, month, day, w.From.Hour, w.From.Minute, 0, 0, loc)
-		wEnd := time.Date(year, month, day, w.To.Hour, w.To.Minute, 0, 0, loc)
+		wStart, wEnd := WindowBounds(day, w, loc)
 
 		// Overlap: max(from, wStart) to min(to, wEnd)
 		overlapStart := from
@@ -536,3 +465,19 @@ func overlapMinutes(from, to time.Time, year int, month time.Month, day int, win
 	}
 	return total
 }
+
+// WindowBounds returns the start and end of the schedule window w on the date
+// day, in the window's own time zone or else loc. Overnight windows end on the
+// day after day.
+func WindowBounds(day time.Time, w schedule.TimeWindow, loc *time.Location) (time.Time, time.Time) {
+	year, month, d := day.Date()
+	if w.Location != nil {
+		loc = w.Location
+	}
+	endDay := d
+	if w.Overnight() {
+		endDay++
+	}
+	start := time.Date(year, month, d, w.From.Hour, w.From.Minute, 0, 0, loc)
+	return start, time.Date(year, month, endDay, w.To.Hour, w.To.Minute, 0, 0, loc)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Flyrell/hourgit/internal/entry"
+	"github.com/Flyrell/hourgit/internal/rounding"
 	"github.com/Flyrell/hourgit/internal/schedule"
 	"github.com/stretchr/testify/assert"
 )
@@ -22,13 +23,29 @@ func workday(year int, month time.Month, day int) schedule.DaySchedule {
 	}
 }
 
+// date returns the report day key for the given date.
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// firstDay and lastDay return the first and last date of the given month.
+func firstDay(year int, month time.Month) time.Time {
+	from, _ := MonthRange(year, month)
+	return from
+}
+
+func lastDay(year int, month time.Month) time.Time {
+	_, to := MonthRange(year, month)
+	return to
+}
+
 // afterMonth returns a time after the end of the given month, for use as the
-// `now` parameter in BuildReport tests where capping is not being tested.
+// `now` parameter in report tests where capping is not being tested.
 func afterMonth(year int, month time.Month) time.Time {
 	return time.Date(year, month+1, 1, 12, 0, 0, 0, time.UTC)
 }
 
-func TestBuildReport_SingleCheckoutFullMonth(t *testing.T) {
+func TestBuildDetailedReport_SingleCheckoutFullMonth(t *testing.T) {
 	year, month := 2025, time.January
 
 	// Checkout before month start: branch "feature-x" active from day 1
@@ -46,7 +63,7 @@ func TestBuildReport_SingleCheckoutFullMonth(t *testing.T) {
 		}
 	}
 
-	report := BuildReport(checkouts, nil, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
 	assert.Equal(t, 1, len(report.Rows))
 	assert.Equal(t, "feature-x", report.Rows[0].Name)
@@ -63,7 +80,7 @@ func TestBuildReport_SingleCheckoutFullMonth(t *testing.T) {
 	assert.Equal(t, workdays*480, report.Rows[0].TotalMinutes)
 }
 
-func TestBuildReport_TwoCheckoutsSplitDay(t *testing.T) {
+func TestBuildDetailedReport_TwoCheckoutsSplitDay(t *testing.T) {
 	year, month := 2025, time.January
 
 	days := []schedule.DaySchedule{workday(year, month, 2)} // Thu Jan 2: 9-17
@@ -73,61 +90,19 @@ func TestBuildReport_TwoCheckoutsSplitDay(t *testing.T) {
 		{ID: "c2", Timestamp: time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC), Previous: "feature-a", Next: "feature-b"},
 	}
 
-	report := BuildReport(checkouts, nil, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
 	assert.Equal(t, 2, len(report.Rows))
 
-	rowA := findRow(report, "feature-a")
-	rowB := findRow(report, "feature-b")
+	rowA := findDetailedRow(report, "feature-a")
+	rowB := findDetailedRow(report, "feature-b")
 	assert.NotNil(t, rowA)
 	assert.NotNil(t, rowB)
-	assert.Equal(t, 240, rowA.Days[2]) // 9:00-13:00 = 4h
-	assert.Equal(t, 240, rowB.Days[2]) // 13:00-17:00 = 4h
-}
-
-func TestBuildReport_ManualLogDeduction(t *testing.T) {
-	year, month := 2025, time.January
-
-	days := []schedule.DaySchedule{workday(year, month, 2)} // 480 min total
-
-	checkouts := []entry.CheckoutEntry{
-		{ID: "c1", Timestamp: time.Date(2024, 12, 31, 10, 0, 0, 0, time.UTC), Previous: "main", Next: "feature-x"},
-	}
-
-	logs := []entry.Entry{
-		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 120, Message: "research", Task: "research"},
-	}
-
-	report := BuildReport(checkouts, logs, nil, days, year, month, afterMonth(year, month), nil)
-
-	rowCheckout := findRow(report, "feature-x")
-	rowLog := findRow(report, "research")
-
-	assert.NotNil(t, rowCheckout)
-	assert.NotNil(t, rowLog)
-
-	// Log takes 120 min, checkout should get 480-120=360 min
-	assert.Equal(t, 360, rowCheckout.Days[2])
-	assert.Equal(t, 120, rowLog.Days[2])
-}
-
-func TestBuildReport_LogTaskKeyFallback(t *testing.T) {
-	year, month := 2025, time.January
-	days := []schedule.DaySchedule{workday(year, month, 2)}
-
-	logs := []entry.Entry{
-		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 60, Message: "did research", Task: ""},
-		{ID: "l2", Start: time.Date(2025, 1, 2, 11, 0, 0, 0, time.UTC), Minutes: 60, Message: "did research", Task: ""},
-	}
-
-	report := BuildReport(nil, logs, nil, days, year, month, afterMonth(year, month), nil)
-
-	assert.Equal(t, 1, len(report.Rows))
-	assert.Equal(t, "(no task)", report.Rows[0].Name)
-	assert.Equal(t, 120, report.Rows[0].TotalMinutes)
+	assert.Equal(t, 240, rowA.Days[date(year, month, 2)].TotalMinutes) // 9:00-13:00 = 4h
+	assert.Equal(t, 240, rowB.Days[date(year, month, 2)].TotalMinutes) // 13:00-17:00 = 4h
 }
 
-func TestBuildReport_NoCheckoutsLogsOnly(t *testing.T) {
+func TestBuildDetailedReport_NoCheckoutsLogsOnly(t *testing.T) {
 	year, month := 2025, time.January
 	days := []schedule.DaySchedule{workday(year, month, 2)}
 
@@ -135,14 +110,14 @@ func TestBuildReport_NoCheckoutsLogsOnly(t *testing.T) {
 		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 180, Message: "analysis", Task: "analysis"},
 	}
 
-	report := BuildReport(nil, logs, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(nil, logs, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
 	assert.Equal(t, 1, len(report.Rows))
 	assert.Equal(t, "analysis", report.Rows[0].Name)
 	assert.Equal(t, 180, report.Rows[0].TotalMinutes)
 }
 
-func TestBuildReport_CheckoutBeforeMonthStart(t *testing.T) {
+func TestBuildDetailedReport_CheckoutBeforeMonthStart(t *testing.T) {
 	year, month := 2025, time.February
 	days := []schedule.DaySchedule{workday(year, month, 3)} // Mon Feb 3
 
@@ -150,43 +125,14 @@ func TestBuildReport_CheckoutBeforeMonthStart(t *testing.T) {
 		{ID: "c1", Timestamp: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), Previous: "main", Next: "feature-y"},
 	}
 
-	report := BuildReport(checkouts, nil, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
 	assert.Equal(t, 1, len(report.Rows))
 	assert.Equal(t, "feature-y", report.Rows[0].Name)
-	assert.Equal(t, 480, report.Rows[0].Days[3])
-}
-
-func TestBuildReport_EmptyMonth(t *testing.T) {
-	year, month := 2025, time.January
-
-	report := BuildReport(nil, nil, nil, nil, year, month, afterMonth(year, month), nil)
-
-	assert.Equal(t, 0, len(report.Rows))
-	assert.Equal(t, 31, report.DaysInMonth)
-}
-
-func TestBuildReport_SortedByTotalDescending(t *testing.T) {
-	year, month := 2025, time.January
-	days := []schedule.DaySchedule{
-		workday(year, month, 2),
-		workday(year, month, 3),
-	}
-
-	logs := []entry.Entry{
-		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 60, Message: "small", Task: "small"},
-		{ID: "l2", Start: time.Date(2025, 1, 2, 11, 0, 0, 0, time.UTC), Minutes: 120, Message: "big", Task: "big"},
-		{ID: "l3", Start: time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC), Minutes: 120, Message: "big", Task: "big"},
-	}
-
-	report := BuildReport(nil, logs, nil, days, year, month, afterMonth(year, month), nil)
-
-	assert.Equal(t, 2, len(report.Rows))
-	assert.Equal(t, "big", report.Rows[0].Name)
-	assert.Equal(t, "small", report.Rows[1].Name)
+	assert.Equal(t, 480, report.Rows[0].Days[date(year, month, 3)].TotalMinutes)
 }
 
-func TestBuildReport_LastCheckoutCappedAtNow(t *testing.T) {
+func TestBuildDetailedReport_LastCheckoutCappedAtNow(t *testing.T) {
 	year, month := 2025, time.January
 
 	// Schedule: Jan 2 (Thu) and Jan 3 (Fri), both 9-17
@@ -202,16 +148,16 @@ func TestBuildReport_LastCheckoutCappedAtNow(t *testing.T) {
 
 	// "now" is Jan 2 at 13:00 — should only get 4h (9-13), not 8h (9-17)
 	now := time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC)
-	report := BuildReport(checkouts, nil, nil, days, year, month, now, nil)
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, firstDay(year, month), lastDay(year, month), now, Options{})
 
 	assert.Equal(t, 1, len(report.Rows))
 	assert.Equal(t, "feature-x", report.Rows[0].Name)
-	assert.Equal(t, 240, report.Rows[0].Days[2]) // 9:00-13:00 = 4h
-	assert.Equal(t, 0, report.Rows[0].Days[3])   // Jan 3 should have no time (now is before it)
+	assert.Equal(t, 240, report.Rows[0].Days[date(year, month, 2)].TotalMinutes) // 9:00-13:00 = 4h
+	assert.Nil(t, report.Rows[0].Days[date(year, month, 3)])                     // Jan 3 should have no time (now is before it)
 	assert.Equal(t, 240, report.Rows[0].TotalMinutes)
 }
 
-func TestBuildReport_ScheduleWindowsInterpretedInLocalTimezone(t *testing.T) {
+func TestBuildDetailedReport_ScheduleWindowsInterpretedInLocalTimezone(t *testing.T) {
 	year, month := 2025, time.January
 	loc := time.FixedZone("UTC+1", 1*60*60)
 
@@ -240,20 +186,11 @@ func TestBuildReport_ScheduleWindowsInterpretedInLocalTimezone(t *testing.T) {
 	// [23:00 UTC, 07:00 UTC] (= 00:00-08:00 UTC+1) = 7h55m (correct).
 	now := time.Date(2025, 1, 2, 7, 55, 0, 0, loc) // = 6:55 UTC
 
-	report := BuildReport(checkouts, nil, nil, days, year, month, now, nil)
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, firstDay(year, month), lastDay(year, month), now, Options{})
 
 	assert.Equal(t, 1, len(report.Rows))
 	assert.Equal(t, "feature-x", report.Rows[0].Name)
-	assert.Equal(t, 475, report.Rows[0].Days[2]) // 7h55m = 475 min
-}
-
-func findRow(report ReportData, name string) *TaskRow {
-	for i := range report.Rows {
-		if report.Rows[i].Name == name {
-			return &report.Rows[i]
-		}
-	}
-	return nil
+	assert.Equal(t, 475, report.Rows[0].Days[date(year, month, 2)].TotalMinutes) // 7h55m = 475 min
 }
 
 func findDetailedRow(report DetailedReportData, name string) *DetailedTaskRow {
@@ -276,14 +213,14 @@ func TestBuildDetailedReport_SingleCheckout(t *testing.T) {
 		{ID: "c1", Timestamp: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "feature-a"},
 	}
 
-	report := BuildDetailedReport(checkouts, nil, nil, days, from, to, afterMonth(year, month))
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, from, to, afterMonth(year, month), Options{})
 
 	assert.Equal(t, 1, len(report.Rows))
 	row := findDetailedRow(report, "feature-a")
 	assert.NotNil(t, row)
 
 	// Day 2: 9-17 = 480 min
-	cd2 := row.Days[2]
+	cd2 := row.Days[date(year, month, 2)]
 	assert.NotNil(t, cd2)
 	assert.Equal(t, 480, cd2.TotalMinutes)
 	assert.Equal(t, 1, len(cd2.Entries))
@@ -303,14 +240,14 @@ func TestBuildDetailedReport_LogEntries(t *testing.T) {
 		{ID: "l2", Start: time.Date(2025, 1, 2, 11, 0, 0, 0, time.UTC), Minutes: 60, Message: "more research", Task: "research"},
 	}
 
-	report := BuildDetailedReport(nil, logs, nil, days, from, to, afterMonth(year, month))
+	report := BuildDetailedReport(nil, logs, nil, nil, days, from, to, afterMonth(year, month), Options{})
 
 	assert.Equal(t, 1, len(report.Rows))
 	row := findDetailedRow(report, "research")
 	assert.NotNil(t, row)
 	assert.Equal(t, 120, row.TotalMinutes)
 
-	cd := row.Days[2]
+	cd := row.Days[date(year, month, 2)]
 	assert.NotNil(t, cd)
 	assert.Equal(t, 120, cd.TotalMinutes)
 	assert.Equal(t, 2, len(cd.Entries))
@@ -330,14 +267,14 @@ func TestBuildDetailedReport_LogTaskKeyFallback(t *testing.T) {
 		{ID: "l2", Start: time.Date(2025, 1, 2, 11, 0, 0, 0, time.UTC), Minutes: 60, Message: "wrote docs", Task: ""},
 	}
 
-	report := BuildDetailedReport(nil, logs, nil, days, from, to, afterMonth(year, month))
+	report := BuildDetailedReport(nil, logs, nil, nil, days, from, to, afterMonth(year, month), Options{})
 
 	assert.Equal(t, 1, len(report.Rows))
 	row := findDetailedRow(report, "(no task)")
 	assert.NotNil(t, row)
 	assert.Equal(t, 120, row.TotalMinutes)
 
-	cd := row.Days[2]
+	cd := row.Days[date(year, month, 2)]
 	assert.NotNil(t, cd)
 	assert.Equal(t, 120, cd.TotalMinutes)
 	assert.Equal(t, 2, len(cd.Entries))
@@ -360,7 +297,7 @@ func TestBuildDetailedReport_CheckoutDeductedByLogs(t *testing.T) {
 		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 120, Message: "research", Task: "research"},
 	}
 
-	report := BuildDetailedReport(checkouts, logs, nil, days, from, to, afterMonth(year, month))
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, from, to, afterMonth(year, month), Options{})
 
 	rowCheckout := findDetailedRow(report, "feature-x")
 	rowLog := findDetailedRow(report, "research")
@@ -368,8 +305,8 @@ func TestBuildDetailedReport_CheckoutDeductedByLogs(t *testing.T) {
 	assert.NotNil(t, rowLog)
 
 	// Log takes 120 min, checkout should get 480-120=360
-	assert.Equal(t, 360, rowCheckout.Days[2].TotalMinutes)
-	assert.Equal(t, 120, rowLog.Days[2].TotalMinutes)
+	assert.Equal(t, 360, rowCheckout.Days[date(year, month, 2)].TotalMinutes)
+	assert.Equal(t, 120, rowLog.Days[date(year, month, 2)].TotalMinutes)
 }
 
 func TestBuildDetailedReport_PersistedCheckoutGeneratedSkipsInMemory(t *testing.T) {
@@ -389,12 +326,12 @@ func TestBuildDetailedReport_PersistedCheckoutGeneratedSkipsInMemory(t *testing.
 			Message: "feature-x", Task: "feature-x", Source: "checkout-generated"},
 	}
 
-	report := BuildDetailedReport(checkouts, logs, nil, days, from, to, afterMonth(year, month))
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, from, to, afterMonth(year, month), Options{})
 
 	row := findDetailedRow(report, "feature-x")
 	assert.NotNil(t, row)
 
-	cd := row.Days[2]
+	cd := row.Days[date(year, month, 2)]
 	assert.NotNil(t, cd)
 	// Should only have the persisted entry, not an in-memory generated one
 	assert.Equal(t, 1, len(cd.Entries))
@@ -408,10 +345,10 @@ func TestBuildDetailedReport_Empty(t *testing.T) {
 	from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
 	to := time.Date(year, month, 31, 0, 0, 0, 0, time.UTC)
 
-	report := BuildDetailedReport(nil, nil, nil, nil, from, to, afterMonth(year, month))
+	report := BuildDetailedReport(nil, nil, nil, nil, nil, from, to, afterMonth(year, month), Options{})
 
 	assert.Equal(t, 0, len(report.Rows))
-	assert.Equal(t, 31, report.DaysInMonth)
+	assert.Len(t, report.Dates, 31)
 }
 
 func TestBuildDetailedReport_SortedByTotalDescending(t *testing.T) {
@@ -426,13 +363,216 @@ func TestBuildDetailedReport_SortedByTotalDescending(t *testing.T) {
 		{ID: "l2", Start: time.Date(2025, 1, 2, 11, 0, 0, 0, time.UTC), Minutes: 120, Message: "big", Task: "big"},
 	}
 
-	report := BuildDetailedReport(nil, logs, nil, days, from, to, afterMonth(year, month))
+	report := BuildDetailedReport(nil, logs, nil, nil, days, from, to, afterMonth(year, month), Options{})
 
 	assert.Equal(t, 2, len(report.Rows))
 	assert.Equal(t, "big", report.Rows[0].Name)
 	assert.Equal(t, "small", report.Rows[1].Name)
 }
 
+func TestBuildDetailedReport_CrossMonthWeek(t *testing.T) {
+	// ISO week 40 of 2025: Mon Sep 29 - Sun Oct 5
+	from, to := date(2025, time.September, 29), date(2025, time.October, 5)
+	days := []schedule.DaySchedule{workday(2025, time.September, 30), workday(2025, time.October, 1)}
+
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 9, 20, 9, 0, 0, 0, time.UTC), Previous: "main", Next: "feature-a"},
+	}
+	logs := []entry.Entry{
+		{ID: "l1", Start: time.Date(2025, 10, 3, 10, 0, 0, 0, time.UTC), Minutes: 60, Message: "review", Task: "review"},
+		{ID: "l2", Start: time.Date(2025, 10, 6, 10, 0, 0, 0, time.UTC), Minutes: 60, Message: "next week", Task: "review"},
+	}
+
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, from, to, afterMonth(2025, time.October), Options{})
+
+	assert.Equal(t, from, report.From)
+	assert.Equal(t, to, report.To)
+	assert.Len(t, report.Dates, 7)
+	assert.True(t, report.ScheduledDays[date(2025, time.September, 30)])
+	assert.True(t, report.ScheduledDays[date(2025, time.October, 1)])
+
+	row := findDetailedRow(report, "feature-a")
+	assert.NotNil(t, row)
+	assert.Equal(t, 960, row.TotalMinutes)
+	assert.Equal(t, 480, row.Days[date(2025, time.September, 30)].TotalMinutes)
+	assert.Equal(t, 480, row.Days[date(2025, time.October, 1)].TotalMinutes)
+
+	review := findDetailedRow(report, "review")
+	assert.NotNil(t, review)
+	assert.Equal(t, 60, review.TotalMinutes, "logs outside the range are ignored")
+}
+
+func TestDates(t *testing.T) {
+	dates := Dates(date(2025, time.December, 30), date(2026, time.January, 2))
+	assert.Equal(t, []time.Time{
+		date(2025, time.December, 30),
+		date(2025, time.December, 31),
+		date(2026, time.January, 1),
+		date(2026, time.January, 2),
+	}, dates)
+
+	from, to := MonthRange(2024, time.February)
+	assert.Len(t, Dates(from, to), 29)
+}
+
+// mustZone loads a time zone for tests.
+func mustZone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+// window returns a DaySchedule with a single window on the given date.
+func window(day time.Time, fromHour, toHour int, loc *time.Location) schedule.DaySchedule {
+	return schedule.DaySchedule{
+		Date: day,
+		Windows: []schedule.TimeWindow{{
+			From:     schedule.TimeOfDay{Hour: fromHour},
+			To:       schedule.TimeOfDay{Hour: toHour},
+			Location: loc,
+		}},
+	}
+}
+
+func TestBuildDetailedReport_LogsBucketedInProjectZone(t *testing.T) {
+	berlin := mustZone(t, "Europe/Berlin")
+	from, to := date(2025, time.March, 31), date(2025, time.March, 31)
+
+	// Stored in UTC, 00:30 on Mar 31 in Berlin (CEST, +2)
+	logs := []entry.Entry{
+		{ID: "l1", Start: time.Date(2025, 3, 30, 22, 30, 0, 0, time.UTC), Minutes: 60, Message: "early", Task: "early"},
+	}
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, berlin)
+
+	report := BuildDetailedReport(nil, logs, nil, nil, nil, from, to, now, Options{})
+	row := findDetailedRow(report, "early")
+	assert.NotNil(t, row)
+	cd := row.Days[date(2025, time.March, 31)]
+	assert.NotNil(t, cd)
+	assert.Equal(t, 60, cd.TotalMinutes)
+	assert.Equal(t, 0, cd.Entries[0].Start.Hour(), "shown in the project's zone")
+
+	// The same entry falls on Mar 30 in UTC
+	report = BuildDetailedReport(nil, logs, nil, nil, nil, from, to, now.UTC(), Options{})
+	assert.Empty(t, report.Rows)
+}
+
+func TestBuildDetailedReport_DSTSpringForward(t *testing.T) {
+	berlin := mustZone(t, "Europe/Berlin")
+	day := date(2025, time.March, 30) // 02:00 CET -> 03:00 CEST
+
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 3, 29, 12, 0, 0, 0, berlin), Previous: "main", Next: "feature-x"},
+	}
+	days := []schedule.DaySchedule{window(day, 1, 5, nil)}
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, berlin)
+
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, day, day, now, Options{})
+	row := findDetailedRow(report, "feature-x")
+	assert.NotNil(t, row)
+	assert.Equal(t, 180, row.Days[day].TotalMinutes, "01:00-05:00 is three hours on the day clocks skip 02:00")
+}
+
+func TestBuildDetailedReport_DSTFallBack(t *testing.T) {
+	newYork := mustZone(t, "America/New_York")
+	day := date(2025, time.November, 2) // 02:00 EDT -> 01:00 EST
+
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 11, 1, 12, 0, 0, 0, newYork), Previous: "main", Next: "feature-x"},
+	}
+	days := []schedule.DaySchedule{window(day, 0, 3, nil)}
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, newYork)
+
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, day, day, now, Options{})
+	row := findDetailedRow(report, "feature-x")
+	assert.NotNil(t, row)
+	assert.Equal(t, 240, row.Days[day].TotalMinutes, "00:00-03:00 is four hours on the day clocks repeat 01:00")
+}
+
+func TestBuildDetailedReport_DSTWeekKeepsDays(t *testing.T) {
+	berlin := mustZone(t, "Europe/Berlin")
+	from, to := date(2025, time.October, 20), date(2025, time.October, 31) // DST ends Oct 26
+
+	var days []schedule.DaySchedule
+	for _, d := range Dates(from, to) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			days = append(days, window(d, 9, 17, nil))
+		}
+	}
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 10, 1, 9, 0, 0, 0, berlin), Previous: "main", Next: "feature-x"},
+	}
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, berlin)
+
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, from, to, now, Options{})
+	row := findDetailedRow(report, "feature-x")
+	assert.NotNil(t, row)
+	for _, ds := range days {
+		assert.Equal(t, 480, row.Days[ds.Date].TotalMinutes, ds.Date.Format("2006-01-02"))
+	}
+}
+
+func TestBuildDetailedReport_ScheduleInOwnZone(t *testing.T) {
+	berlin := mustZone(t, "Europe/Berlin")
+	newYork := mustZone(t, "America/New_York")
+	day := date(2025, time.June, 2)
+
+	// 09:00-17:00 New York (EDT) is 15:00-23:00 in Berlin (CEST)
+	days := []schedule.DaySchedule{window(day, 9, 17, newYork)}
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 6, 2, 18, 0, 0, 0, berlin), Previous: "main", Next: "feature-x"},
+	}
+	now := time.Date(2025, 6, 3, 12, 0, 0, 0, berlin)
+
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, day, day, now, Options{})
+	row := findDetailedRow(report, "feature-x")
+	assert.NotNil(t, row)
+	assert.Equal(t, 300, row.Days[day].TotalMinutes)
+}
+
+func TestBuildDetailedReport_OvernightWindowCountsOnStartDay(t *testing.T) {
+	day := date(2025, time.June, 2)
+
+	// 22:00-06:00 shift; the report ends on the day the shift starts
+	days := []schedule.DaySchedule{window(day, 22, 6, nil)}
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 6, 2, 21, 0, 0, 0, time.UTC), Previous: "main", Next: "on-call"},
+	}
+	logs := []entry.Entry{
+		{ID: "l1", Start: time.Date(2025, 6, 3, 2, 0, 0, 0, time.UTC), Minutes: 60, Message: "incident", Task: "incident"},
+	}
+	now := time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC)
+
+	report := BuildDetailedReport(checkouts, logs, nil, nil, days, day, day, now, Options{})
+	row := findDetailedRow(report, "on-call")
+	assert.NotNil(t, row)
+	assert.Equal(t, 420, row.Days[day].TotalMinutes, "8h shift minus the hour logged the next morning")
+	assert.Nil(t, findDetailedRow(report, "incident"), "the log belongs to the next day")
+}
+
+func TestBuildDetailedReport_OvernightWindowSplitAtMidnight(t *testing.T) {
+	mon, tue := date(2025, time.June, 2), date(2025, time.June, 3)
+	entries := []schedule.ScheduleEntry{
+		{Ranges: []schedule.TimeRange{{From: "22:00", To: "06:00"}}, RRule: "FREQ=WEEKLY;BYDAY=MO", SplitAtMidnight: true},
+	}
+	days, err := schedule.ExpandSchedules(entries, mon, tue)
+	assert.NoError(t, err)
+
+	checkouts := []entry.CheckoutEntry{
+		{ID: "c1", Timestamp: time.Date(2025, 6, 2, 21, 0, 0, 0, time.UTC), Previous: "main", Next: "on-call"},
+	}
+	now := time.Date(2025, 6, 4, 12, 0, 0, 0, time.UTC)
+
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, mon, tue, now, Options{})
+	row := findDetailedRow(report, "on-call")
+	assert.NotNil(t, row)
+	assert.Equal(t, 120, row.Days[mon].TotalMinutes)
+	assert.Equal(t, 360, row.Days[tue].TotalMinutes)
+}
+
 func TestOverlapMinutes_RoundsInsteadOfTruncating(t *testing.T) {
 	year, month := 2025, time.January
 	loc := time.UTC
@@ -446,7 +586,7 @@ func TestOverlapMinutes_RoundsInsteadOfTruncating(t *testing.T) {
 	from := time.Date(year, month, 2, 8, 0, 0, 0, loc)
 	to := time.Date(year, month, 2, 16, 59, 59, 0, loc)
 
-	mins := overlapMinutes(from, to, year, month, 2, windows, loc)
+	mins := overlapMinutes(from, to, date(year, month, 2), windows, loc)
 	assert.Equal(t, 480, mins, "should round 479.98 to 480, not truncate to 479")
 }
 
@@ -462,11 +602,11 @@ func TestOverlapMinutes_RoundsDownSmallFraction(t *testing.T) {
 	from := time.Date(year, month, 2, 9, 0, 0, 0, loc)
 	to := time.Date(year, month, 2, 9, 0, 15, 0, loc)
 
-	mins := overlapMinutes(from, to, year, month, 2, windows, loc)
+	mins := overlapMinutes(from, to, date(year, month, 2), windows, loc)
 	assert.Equal(t, 0, mins, "should round 0.25 to 0")
 }
 
-func TestBuildReport_RoundedCheckoutNeverExceedsSchedule(t *testing.T) {
+func TestBuildDetailedReport_RoundedCheckoutNeverExceedsSchedule(t *testing.T) {
 	year, month := 2025, time.January
 
 	days := []schedule.DaySchedule{workday(year, month, 2)} // 480 min schedule
@@ -478,15 +618,15 @@ func TestBuildReport_RoundedCheckoutNeverExceedsSchedule(t *testing.T) {
 
 	// now with seconds past the schedule end — truncation to 17:00 aligns with window
 	now := time.Date(2025, 1, 2, 17, 0, 35, 0, time.UTC)
-	report := BuildReport(checkouts, nil, nil, days, year, month, now, nil)
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, firstDay(year, month), lastDay(year, month), now, Options{})
 
 	assert.Equal(t, 1, len(report.Rows))
 	// Should be exactly 480 (rounded), not 479 (truncated), and not >480
-	assert.LessOrEqual(t, report.Rows[0].Days[2], 480)
-	assert.Equal(t, 480, report.Rows[0].Days[2])
+	assert.LessOrEqual(t, report.Rows[0].Days[date(year, month, 2)].TotalMinutes, 480)
+	assert.Equal(t, 480, report.Rows[0].Days[date(year, month, 2)].TotalMinutes)
 }
 
-func TestBuildReport_CheckoutSecondsAreTruncated(t *testing.T) {
+func TestBuildDetailedReport_CheckoutSecondsAreTruncated(t *testing.T) {
 	year, month := 2025, time.January
 
 	days := []schedule.DaySchedule{workday(year, month, 2)} // 480 min schedule (9-17)
@@ -499,13 +639,13 @@ func TestBuildReport_CheckoutSecondsAreTruncated(t *testing.T) {
 	}
 
 	now := afterMonth(year, month)
-	report := BuildReport(checkouts, nil, nil, days, year, month, now, nil)
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, firstDay(year, month), lastDay(year, month), now, Options{})
 
 	assert.Equal(t, 1, len(report.Rows))
-	assert.Equal(t, 480, report.Rows[0].Days[2], "checkout seconds should be truncated, giving exactly 8h")
+	assert.Equal(t, 480, report.Rows[0].Days[date(year, month, 2)].TotalMinutes, "checkout seconds should be truncated, giving exactly 8h")
 }
 
-func TestBuildReport_ConsecutiveSameBranchCheckoutsDeduped(t *testing.T) {
+func TestBuildDetailedReport_ConsecutiveSameBranchCheckoutsDeduped(t *testing.T) {
 	year, month := 2025, time.January
 
 	days := []schedule.DaySchedule{
@@ -520,17 +660,61 @@ func TestBuildReport_ConsecutiveSameBranchCheckoutsDeduped(t *testing.T) {
 		{ID: "c3", Timestamp: time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC), Previous: "feature-a", Next: "feature-b"},
 	}
 
-	report := BuildReport(checkouts, nil, nil, days, year, month, afterMonth(year, month), nil)
+	report := BuildDetailedReport(checkouts, nil, nil, nil, days, firstDay(year, month), lastDay(year, month), afterMonth(year, month), Options{})
 
-	rowA := findRow(report, "feature-a")
-	rowB := findRow(report, "feature-b")
+	rowA := findDetailedRow(report, "feature-a")
+	rowB := findDetailedRow(report, "feature-b")
 	assert.NotNil(t, rowA)
 	assert.NotNil(t, rowB)
 
 	// Without dedup, feature-a would lose time (c2 would restart its range at 10:00).
 	// With dedup, c2 is skipped, so feature-a gets 9:00-13:00 = 240 min on day 2.
-	assert.Equal(t, 240, rowA.Days[2])
+	assert.Equal(t, 240, rowA.Days[date(year, month, 2)].TotalMinutes)
 	// feature-b gets 13:00-17:00 = 240 min on day 2, plus full day 3 = 480 min
-	assert.Equal(t, 240, rowB.Days[2])
-	assert.Equal(t, 480, rowB.Days[3])
+	assert.Equal(t, 240, rowB.Days[date(year, month, 2)].TotalMinutes)
+	assert.Equal(t, 480, rowB.Days[date(year, month, 3)].TotalMinutes)
+}
+
+func TestBuildDetailedReport_Rounding(t *testing.T) {
+	year, month := 2025, time.January
+	from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, month, 31, 0, 0, 0, 0, time.UTC)
+	days := []schedule.DaySchedule{workday(year, month, 2)}
+
+	logs := []entry.Entry{
+		{ID: "l1", Start: time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), Minutes: 10, Message: "a", Task: "research"},
+		{ID: "l2", Start: time.Date(2025, 1, 2, 11, 0, 0, 0, time.UTC), Minutes: 10, Message: "b", Task: "research"},
+		{ID: "l3", Start: time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC), Minutes: 10, Message: "c", Task: "review"},
+	}
+	day := date(year, month, 2)
+
+	tests := []struct {
+		scope    string
+		research int
+		total    int
+	}{
+		{rounding.ScopeEntry, 30, 45},   // 15+15, 15
+		{rounding.ScopeTaskDay, 30, 45}, // 20→30, 10→15
+		{rounding.ScopeDay, 20, 30},     // 30 stays 30
+	}
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			policy := rounding.Policy{Increment: 15, Mode: rounding.ModeUp, Scope: tt.scope}
+			report := BuildDetailedReport(nil, logs, nil, nil, days, from, to, afterMonth(year, month), Options{Rounding: policy})
+
+			row := findDetailedRow(report, "research")
+			assert.NotNil(t, row)
+			assert.Equal(t, tt.research, row.TotalMinutes)
+			assert.Equal(t, 20, row.RawMinutes)
+			assert.Equal(t, 20, row.Days[day].RawMinutes)
+			assert.Equal(t, 10, row.Days[day].Entries[0].Minutes, "entries stay raw")
+
+			total, raw := report.DayTotal(day)
+			assert.Equal(t, tt.total, total)
+			assert.Equal(t, 30, raw)
+			total, raw = report.Total()
+			assert.Equal(t, tt.total, total)
+			assert.Equal(t, 30, raw)
+		})
+	}
 }
@@ -11,7 +11,6 @@ import (
 	"time"
 
 	"github.com/Flyrell/hourgit/internal/entry"
-	"github.com/Flyrell/hourgit/internal/hashutil"
 	"github.com/Flyrell/hourgit/internal/project"
 	"github.com/fsnotify/fsnotify"
 )
@@ -268,8 +267,7 @@ func (d *Daemon) watchConfigChanges(ctx context.Context, watcher *fsnotify.Watch
 			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
 				continue
 			}
-			// Small delay to avoid reading partial writes
-			time.Sleep(100 * time.Millisecond)
+			// Config writes are atomic renames, so the file is always complete
 			if err := d.reloadConfig(); err != nil {
 				log.Printf("warning: config reload failed: %v", err)
 			}
@@ -311,6 +309,11 @@ func (d *Daemon) recoverFromCrash() {
 			}
 		}
 
+		ids, err := entry.LoadIDs(d.homeDir, p.Slug)
+		if err != nil {
+			continue
+		}
+
 		// Check each start for a matching stop after it
 		for _, start := range starts {
 			stopAfter := latestStop[start.Repo]
@@ -325,7 +328,7 @@ func (d *Daemon) recoverFromCrash() {
 			}
 
 			_ = d.writer.WriteActivityStop(d.homeDir, p.Slug, entry.ActivityStopEntry{
-				ID:        hashutil.GenerateIDFromSeed(start.Repo + start.ID + stopTime.String() + "recovery"),
+				ID:        ids.Allocate(start.Repo + start.ID + stopTime.String() + "recovery"),
 				Timestamp: stopTime,
 				Repo:      start.Repo,
 			})
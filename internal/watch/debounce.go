@@ -66,7 +66,7 @@ func (d *RepoDebouncer) OnFileEvent(now time.Time) {
 	if d.idle {
 		d.idle = false
 		_ = d.writer.WriteActivityStart(d.homeDir, d.slug, entry.ActivityStartEntry{
-			ID:        hashutil.GenerateID(d.repo + now.String()),
+			ID:        d.newID(d.repo + now.String()),
 			Timestamp: now,
 			Repo:      d.repo,
 		})
@@ -96,7 +96,7 @@ func (d *RepoDebouncer) onIdle() {
 	d.idle = true
 	// Write activity_stop with lastActivity timestamp (not current time)
 	_ = d.writer.WriteActivityStop(d.homeDir, d.slug, entry.ActivityStopEntry{
-		ID:        hashutil.GenerateID(d.repo + d.lastActivity.String()),
+		ID:        d.newID(d.repo + d.lastActivity.String()),
 		Timestamp: d.lastActivity,
 		Repo:      d.repo,
 	})
@@ -115,13 +115,25 @@ func (d *RepoDebouncer) Shutdown() {
 	if !d.idle && !d.lastActivity.IsZero() {
 		d.idle = true
 		_ = d.writer.WriteActivityStop(d.homeDir, d.slug, entry.ActivityStopEntry{
-			ID:        hashutil.GenerateID(d.repo + d.lastActivity.String() + "shutdown"),
+			ID:        d.newID(d.repo + d.lastActivity.String() + "shutdown"),
 			Timestamp: d.lastActivity,
 			Repo:      d.repo,
 		})
 	}
 }
 
+// newID allocates an unused entry ID in the debouncer's project. If the
+// project's IDs cannot be read, an unchecked ID is returned instead so
+// activity is still recorded.
+func (d *RepoDebouncer) newID(name string) string {
+	seed := hashutil.TimeSeed(name)
+	id, err := entry.NewID(d.homeDir, d.slug, seed)
+	if err != nil {
+		return hashutil.Hash(seed)[:hashutil.EntryIDLength]
+	}
+	return id
+}
+
 // IsIdle returns whether the debouncer is in idle state.
 func (d *RepoDebouncer) IsIdle() bool {
 	d.mu.Lock()
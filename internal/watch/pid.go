@@ -7,11 +7,14 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/paths"
 )
 
 // PIDPath returns the path to the PID file.
 func PIDPath(homeDir string) string {
-	return filepath.Join(homeDir, ".hourgit", "watch.pid")
+	return filepath.Join(paths.For(homeDir).Runtime, "watch.pid")
 }
 
 // WritePID writes the current process PID to the PID file.
@@ -20,7 +23,7 @@ func WritePID(homeDir string) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return err
 	}
-	return os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())), 0644)
+	return fsutil.WriteFileAtomic(path, []byte(strconv.Itoa(os.Getpid())), 0644)
 }
 
 // ReadPID reads the PID from the PID file. Returns 0 if the file doesn't exist.
@@ -4,10 +4,14 @@ package watch
 
 import (
 	"fmt"
+	"html"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/paths"
 )
 
 // NewServiceManager returns the macOS (launchd) service manager.
@@ -31,8 +35,18 @@ func newLaunchdManager(homeDir string) *launchdManager {
 	}
 }
 
-// PlistContent generates the launchd plist XML for the watcher daemon.
-func PlistContent(binPath string) string {
+// PlistContent generates the launchd plist XML for the watcher daemon. env
+// holds KEY=value pairs the watcher needs to find the same data as the CLI.
+func PlistContent(binPath string, env []string) string {
+	var environment strings.Builder
+	if len(env) > 0 {
+		environment.WriteString("\t<key>EnvironmentVariables</key>\n\t<dict>\n")
+		for _, kv := range env {
+			key, value, _ := strings.Cut(kv, "=")
+			fmt.Fprintf(&environment, "\t\t<key>%s</key>\n\t\t<string>%s</string>\n", html.EscapeString(key), html.EscapeString(value))
+		}
+		environment.WriteString("\t</dict>\n")
+	}
 	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
 <!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
 <plist version="1.0">
@@ -44,7 +58,7 @@ func PlistContent(binPath string) string {
 		<string>%s</string>
 		<string>watch</string>
 	</array>
-	<key>KeepAlive</key>
+%s	<key>KeepAlive</key>
 	<true/>
 	<key>RunAtLoad</key>
 	<true/>
@@ -54,7 +68,7 @@ func PlistContent(binPath string) string {
 	<string>/tmp/hourgit-watch.log</string>
 </dict>
 </plist>
-`, launchdLabel, binPath)
+`, launchdLabel, binPath, environment.String())
 }
 
 func (m *launchdManager) Install(binPath string) error {
@@ -62,8 +76,8 @@ func (m *launchdManager) Install(binPath string) error {
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
-	content := PlistContent(binPath)
-	return os.WriteFile(m.plistPath, []byte(content), 0644)
+	content := PlistContent(binPath, paths.For(m.homeDir).Env)
+	return fsutil.WriteFileAtomic(m.plistPath, []byte(content), 0644)
 }
 
 func (m *launchdManager) Remove() error {
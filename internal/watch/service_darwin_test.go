@@ -10,7 +10,7 @@ import (
 )
 
 func TestPlistContent(t *testing.T) {
-	content := PlistContent("/usr/local/bin/hourgit")
+	content := PlistContent("/usr/local/bin/hourgit", nil)
 
 	assert.Contains(t, content, "com.hourgit.watch")
 	assert.Contains(t, content, "/usr/local/bin/hourgit")
@@ -19,3 +19,11 @@ func TestPlistContent(t *testing.T) {
 	assert.Contains(t, content, "<key>RunAtLoad</key>")
 	assert.True(t, strings.HasPrefix(content, "<?xml"))
 }
+
+func TestPlistContentEnvironment(t *testing.T) {
+	content := PlistContent("/usr/local/bin/hourgit", []string{"HOURGIT_HOME=/data/hourgit"})
+
+	assert.Contains(t, content, "<key>EnvironmentVariables</key>")
+	assert.Contains(t, content, "<key>HOURGIT_HOME</key>\n\t\t<string>/data/hourgit</string>")
+	assert.NotContains(t, PlistContent("/usr/local/bin/hourgit", nil), "EnvironmentVariables")
+}
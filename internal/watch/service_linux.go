@@ -8,6 +8,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/paths"
 )
 
 // NewServiceManager returns the Linux (systemd) service manager.
@@ -25,25 +28,30 @@ type systemdManager struct {
 func newSystemdManager(homeDir string) *systemdManager {
 	return &systemdManager{
 		homeDir:     homeDir,
-		servicePath: filepath.Join(homeDir, ".config", "systemd", "user", systemdServiceName+".service"),
+		servicePath: filepath.Join(paths.For(homeDir).ConfigHome, "systemd", "user", systemdServiceName+".service"),
 	}
 }
 
-// ServiceFileContent generates the systemd service unit content.
-func ServiceFileContent(binPath string) string {
+// ServiceFileContent generates the systemd service unit content. env holds
+// KEY=value pairs the watcher needs to find the same data as the CLI.
+func ServiceFileContent(binPath string, env []string) string {
+	var environment strings.Builder
+	for _, kv := range env {
+		fmt.Fprintf(&environment, "Environment=%q\n", kv)
+	}
 	return fmt.Sprintf(`[Unit]
 Description=Hourgit File Watcher
 After=default.target
 
 [Service]
 Type=simple
-ExecStart=%s watch
+%sExecStart=%s watch
 Restart=always
 RestartSec=5
 
 [Install]
 WantedBy=default.target
-`, binPath)
+`, environment.String(), binPath)
 }
 
 func (m *systemdManager) Install(binPath string) error {
@@ -51,8 +59,8 @@ func (m *systemdManager) Install(binPath string) error {
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
-	content := ServiceFileContent(binPath)
-	if err := os.WriteFile(m.servicePath, []byte(content), 0644); err != nil {
+	content := ServiceFileContent(binPath, paths.For(m.homeDir).Env)
+	if err := fsutil.WriteFileAtomic(m.servicePath, []byte(content), 0644); err != nil {
 		return err
 	}
 	return exec.Command("systemctl", "--user", "daemon-reload").Run()
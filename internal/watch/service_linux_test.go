@@ -9,7 +9,7 @@ import (
 )
 
 func TestServiceFileContent(t *testing.T) {
-	content := ServiceFileContent("/usr/local/bin/hourgit")
+	content := ServiceFileContent("/usr/local/bin/hourgit", nil)
 
 	assert.Contains(t, content, "ExecStart=/usr/local/bin/hourgit watch")
 	assert.Contains(t, content, "Restart=always")
@@ -17,3 +17,9 @@ func TestServiceFileContent(t *testing.T) {
 	assert.Contains(t, content, "[Service]")
 	assert.Contains(t, content, "[Install]")
 }
+
+func TestServiceFileContentEnvironment(t *testing.T) {
+	content := ServiceFileContent("/usr/local/bin/hourgit", []string{"HOURGIT_HOME=/data/hourgit"})
+
+	assert.Contains(t, content, "Environment=\"HOURGIT_HOME=/data/hourgit\"\nExecStart=")
+}
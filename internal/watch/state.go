@@ -2,17 +2,25 @@ package watch
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/Flyrell/hourgit/internal/fsutil"
+	"github.com/Flyrell/hourgit/internal/paths"
+	"github.com/Flyrell/hourgit/internal/seal"
 )
 
 // StatePath returns the path to the state file.
 func StatePath(homeDir string) string {
-	return filepath.Join(homeDir, ".hourgit", "watch.state")
+	return filepath.Join(paths.For(homeDir).State, "watch.state")
 }
 
+// StateSealContext binds a sealed state file to the watcher state.
+const StateSealContext = "watch.state"
+
 // RepoState holds the last activity time for a single repo.
 type RepoState struct {
 	LastActivity time.Time `json:"last_activity"`
@@ -66,7 +74,10 @@ func (s *WatchState) Flush(homeDir string) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	if data, err = seal.Seal(homeDir, data, StateSealContext); err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, data, 0600)
 }
 
 // LoadWatchState reads the state from disk. Returns a new empty state if the file doesn't exist.
@@ -78,6 +89,9 @@ func LoadWatchState(homeDir string) (*WatchState, error) {
 	if err != nil {
 		return nil, err
 	}
+	if data, err = seal.Open(homeDir, data, StateSealContext); err != nil && !errors.Is(err, seal.ErrCorrupt) {
+		return nil, err
+	}
 	var s WatchState
 	if err := json.Unmarshal(data, &s); err != nil {
 		return NewWatchState(), nil
@@ -88,6 +102,16 @@ func LoadWatchState(homeDir string) (*WatchState, error) {
 	return &s, nil
 }
 
+// ResealState rewrites the state file sealed or plain, whichever homeDir
+// currently uses. Returns whether it changed.
+func ResealState(homeDir string) (bool, error) {
+	sealer, err := seal.Load(homeDir)
+	if err != nil {
+		return false, err
+	}
+	return sealer.RewriteFile(StatePath(homeDir), StateSealContext)
+}
+
 // RemoveState removes the state file.
 func RemoveState(homeDir string) error {
 	err := os.Remove(StatePath(homeDir))